@@ -65,7 +65,7 @@ func main() {
 	logger.Info(ctx, "Successfully connected to database and migrated schemas")
 
 	// Setup routes
-	routes.SetupRoutes(e, db, redisService)
+	routes.SetupRoutes(e, db, redisRepo, redisService)
 
 	// Health check route
 	e.GET("/health", func(c echo.Context) error {
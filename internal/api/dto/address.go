@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+)
+
+// Address represents a postal address with optional coordinates
+type Address struct {
+	Line1      string   `json:"line1,omitempty" validate:"omitempty,max=255"`
+	City       string   `json:"city,omitempty" validate:"omitempty,max=100"`
+	PostalCode string   `json:"postal_code,omitempty" validate:"omitempty,max=20"`
+	Country    string   `json:"country,omitempty" validate:"omitempty,len=2"`
+	Latitude   *float64 `json:"latitude,omitempty" validate:"omitempty,gte=-90,lte=90"`
+	Longitude  *float64 `json:"longitude,omitempty" validate:"omitempty,gte=-180,lte=180"`
+}
+
+// ToModel converts the Address DTO to its model representation
+func (a Address) ToModel() models.Address {
+	return models.Address{
+		Line1:      a.Line1,
+		City:       a.City,
+		PostalCode: a.PostalCode,
+		Country:    a.Country,
+		Latitude:   a.Latitude,
+		Longitude:  a.Longitude,
+	}
+}
+
+// AddressFromModel converts an Address model to an Address DTO
+func AddressFromModel(address models.Address) Address {
+	return Address{
+		Line1:      address.Line1,
+		City:       address.City,
+		PostalCode: address.PostalCode,
+		Country:    address.Country,
+		Latitude:   address.Latitude,
+		Longitude:  address.Longitude,
+	}
+}
@@ -1,17 +1,137 @@
 package dto
 
+import (
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
+)
+
 // UpdateOrderStatusRequest represents a request to update an order's status
 type UpdateOrderStatusRequest struct {
 	Status string `json:"status" validate:"required,oneof=pending processing shipped delivered cancelled"`
+	Reason string `json:"reason,omitempty" validate:"max=500"` // Cancellation reason shown to the customer
+}
+
+// AdminOrderFilter represents the search filters for admin order listings and bulk actions
+type AdminOrderFilter struct {
+	OrderID       uint     `query:"order_id" json:"order_id,omitempty"`
+	Customer      string   `query:"customer" json:"customer,omitempty" validate:"max=100"` // Part of the customer's email or name
+	Status        []string `query:"status" json:"status,omitempty" validate:"dive,oneof=pending backordered processing shipped delivered cancelled"`
+	From          string   `query:"from" json:"from,omitempty"`           // RFC 3339 time or YYYY-MM-DD date, inclusive
+	To            string   `query:"to" json:"to,omitempty"`               // RFC 3339 time (exclusive) or YYYY-MM-DD date (inclusive)
+	MinTotal      string   `query:"min_total" json:"min_total,omitempty"` // In the base currency
+	MaxTotal      string   `query:"max_total" json:"max_total,omitempty"` // In the base currency
+	PaymentStatus []string `query:"payment_status" json:"payment_status,omitempty" validate:"dive,oneof=pending succeeded failed"`
+	SKU           string   `query:"sku" json:"sku,omitempty" validate:"max=50"`
+}
+
+// AdminOrdersQuery represents the search filters, sorting and pagination for listing all orders
+type AdminOrdersQuery struct {
+	AdminOrderFilter
+	Page    int    `query:"page"`
+	PerPage int    `query:"per_page"`
+	Sort    string `query:"sort" validate:"omitempty,oneof=id created_at status total customer_email customer_name payment_status sku"`
+	Order   string `query:"order" validate:"omitempty,oneof=asc desc"`
+}
+
+// Bulk order actions
+const (
+	BulkActionMarkProcessing = "mark-processing"
+	BulkActionMarkShipped    = "mark-shipped"
+	BulkActionCancel         = "cancel"
+)
+
+// BulkOrderActionRequest represents a request to apply an action to many orders, picked
+// either by ID or by a search filter
+type BulkOrderActionRequest struct {
+	Action   string            `json:"action" validate:"required,oneof=mark-processing mark-shipped cancel"`
+	OrderIDs []uint            `json:"order_ids,omitempty" validate:"max=1000,dive,gt=0"`
+	Filter   *AdminOrderFilter `json:"filter,omitempty"`
+	Reason   string            `json:"reason,omitempty" validate:"max=500"` // Cancellation reason, for the cancel action
+}
+
+// BulkOrderResult represents the outcome of a bulk action for one order
+type BulkOrderResult struct {
+	OrderID   uint   `json:"order_id"`
+	Success   bool   `json:"success"`
+	Status    string `json:"status,omitempty"` // The order's status after the action
+	ErrorCode string `json:"error_code,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// BulkOrderActionResponse represents the per-order outcomes of a bulk action
+type BulkOrderActionResponse struct {
+	Action    string            `json:"action"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Results   []BulkOrderResult `json:"results"`
+}
+
+// CustomerSummary represents the customer who placed an order
+type CustomerSummary struct {
+	ID        uint   `json:"id"`
+	Email     string `json:"email"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
 }
 
 // AdminOrderResponse represents an order response with admin-specific fields
 type AdminOrderResponse struct {
-	ID          uint                `json:"id"`
-	UserID      uint                `json:"user_id"`
-	Status      string              `json:"status"`
-	Items       []OrderItemResponse `json:"items"`
-	TotalAmount float64            `json:"total_amount"`
-	CreatedAt   string             `json:"created_at,omitempty"`
-	UpdatedAt   string             `json:"updated_at,omitempty"`
+	ID              uint                `json:"id"`
+	UserID          uint                `json:"user_id"`
+	Customer        *CustomerSummary    `json:"customer,omitempty"`
+	Status          string              `json:"status"`
+	PaymentStatus   string              `json:"payment_status,omitempty"`
+	Items           []OrderItemResponse `json:"items"`
+	TotalAmount     money.Money         `json:"total_amount" swaggertype:"number"`
+	Currency        string              `json:"currency"`
+	BaseTotalAmount money.Money         `json:"base_total_amount" swaggertype:"number"` // In the base currency
+	Cancellation    *CancellationInfo   `json:"cancellation,omitempty"`
+	CreatedAt       string              `json:"created_at,omitempty"`
+	UpdatedAt       string              `json:"updated_at,omitempty"`
+}
+
+// PaginatedAdminOrdersResponse represents a page of orders found by an admin search
+type PaginatedAdminOrdersResponse struct {
+	Orders     []AdminOrderResponse `json:"orders"`
+	Total      int64                `json:"total"`
+	Page       int                  `json:"page"`
+	PerPage    int                  `json:"per_page"`
+	TotalPages int                  `json:"total_pages"`
+}
+
+// AdminOrderToResponse converts an Order model, with its user and payment if loaded, to an AdminOrderResponse DTO
+func AdminOrderToResponse(order *models.Order) AdminOrderResponse {
+	resp := AdminOrderResponse{
+		ID:              order.ID,
+		UserID:          order.UserID,
+		Status:          string(order.Status),
+		TotalAmount:     order.TotalAmount,
+		Currency:        string(order.Currency),
+		BaseTotalAmount: order.BaseTotalAmount,
+		Items:           make([]OrderItemResponse, len(order.OrderItems)),
+		Cancellation:    CancellationFromModel(order),
+		CreatedAt:       order.CreatedAt.Format("2006-01-02 15:04:05"),
+		UpdatedAt:       order.UpdatedAt.Format("2006-01-02 15:04:05"),
+	}
+	if order.User.ID != 0 {
+		resp.Customer = &CustomerSummary{
+			ID:        order.User.ID,
+			Email:     order.User.Email,
+			FirstName: order.User.FirstName,
+			LastName:  order.User.LastName,
+		}
+	}
+	if order.Payment != nil {
+		resp.PaymentStatus = string(order.Payment.Status)
+	}
+	for i, item := range order.OrderItems {
+		resp.Items[i] = OrderItemResponse{
+			ID:                item.ID,
+			ProductID:         item.ProductID,
+			Quantity:          item.Quantity,
+			Price:             item.Price,
+			CancelledQuantity: item.CancelledQuantity,
+		}
+	}
+	return resp
 }
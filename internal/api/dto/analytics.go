@@ -0,0 +1,198 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
+)
+
+// CustomerAnalyticsQuery represents the query parameters of the customer analytics
+type CustomerAnalyticsQuery struct {
+	Months int `query:"months" validate:"omitempty,min=1,max=120"` // Latest cohorts to include; defaults to 12
+}
+
+// CohortRetention represents the customers of a cohort who ordered in a month after their first
+type CohortRetention struct {
+	MonthOffset     int         `json:"month_offset"` // Months since the cohort month
+	ActiveCustomers int         `json:"active_customers"`
+	RetentionRate   float64     `json:"retention_rate"` // Percentage of the cohort
+	Orders          int         `json:"orders"`
+	Revenue         money.Money `json:"revenue" swaggertype:"number"`
+}
+
+// CustomerCohortResponse represents the customers who first ordered in a month and how
+// they kept ordering since
+type CustomerCohortResponse struct {
+	Month         time.Time         `json:"month"`
+	Customers     int               `json:"customers"`
+	LifetimeValue money.Money       `json:"lifetime_value" swaggertype:"number"` // Revenue per customer to date
+	Retention     []CohortRetention `json:"retention"`                           // One entry per month up to the current one
+}
+
+// CustomerSegmentResponse represents the customers of an RFM segment
+type CustomerSegmentResponse struct {
+	Segment            string      `json:"segment"`
+	Customers          int         `json:"customers"`
+	Revenue            money.Money `json:"revenue" swaggertype:"number"`
+	AverageRecencyDays float64     `json:"average_recency_days"`
+	AverageOrders      float64     `json:"average_orders"`
+	AverageMonetary    money.Money `json:"average_monetary" swaggertype:"number"`
+}
+
+// CustomerAnalyticsResponse represents the customer analytics of the latest run. Only
+// orders that weren't cancelled count.
+type CustomerAnalyticsResponse struct {
+	ComputedAt               time.Time                 `json:"computed_at"`
+	Currency                 string                    `json:"currency"`
+	Customers                int                       `json:"customers"`        // With at least one order
+	RepeatCustomers          int                       `json:"repeat_customers"` // With more than one order
+	RepeatPurchaseRate       float64                   `json:"repeat_purchase_rate"`
+	Orders                   int                       `json:"orders"`
+	Revenue                  money.Money               `json:"revenue" swaggertype:"number"`
+	AverageLifetimeValue     money.Money               `json:"average_lifetime_value" swaggertype:"number"`
+	AverageOrdersPerCustomer float64                   `json:"average_orders_per_customer"`
+	Cohorts                  []CustomerCohortResponse  `json:"cohorts"`
+	Segments                 []CustomerSegmentResponse `json:"segments"`
+}
+
+// CustomerRFMQuery represents the query parameters of the customer RFM scores
+type CustomerRFMQuery struct {
+	Segment string `query:"segment" validate:"omitempty,oneof=champions loyal new promising at_risk hibernating lost"`
+	Page    int    `query:"page"`
+	PerPage int    `query:"per_page"`
+}
+
+// CustomerRFMResponse represents a customer's RFM scores
+type CustomerRFMResponse struct {
+	UserID         uint        `json:"user_id"`
+	Email          string      `json:"email"`
+	FirstName      string      `json:"first_name"`
+	LastName       string      `json:"last_name"`
+	Segment        string      `json:"segment"`
+	RecencyDays    int         `json:"recency_days"`
+	Orders         int         `json:"orders"`
+	Monetary       money.Money `json:"monetary" swaggertype:"number"`
+	RecencyScore   int         `json:"recency_score"`
+	FrequencyScore int         `json:"frequency_score"`
+	MonetaryScore  int         `json:"monetary_score"`
+	FirstOrderAt   time.Time   `json:"first_order_at"`
+	LastOrderAt    time.Time   `json:"last_order_at"`
+}
+
+// PaginatedCustomerRFMResponse represents a page of customers' RFM scores
+type PaginatedCustomerRFMResponse struct {
+	Customers  []CustomerRFMResponse `json:"customers"`
+	Currency   string                `json:"currency"`
+	ComputedAt time.Time             `json:"computed_at"`
+	Total      int64                 `json:"total"`
+	Page       int                   `json:"page"`
+	PerPage    int                   `json:"per_page"`
+	TotalPages int                   `json:"total_pages"`
+}
+
+// ProductAnalyticsListQuery represents the query parameters of the product performance ranking
+type ProductAnalyticsListQuery struct {
+	Days       int    `query:"days" validate:"omitempty,min=1,max=366"` // Completed days to cover, up to yesterday; defaults to 30
+	SlowMovers bool   `query:"slow_movers"`                             // Only products whose stock outlasts the slow mover threshold
+	Sort       string `query:"sort" validate:"omitempty,oneof=units_sold revenue orders cancellation_rate sell_through days_of_cover stock"`
+	Order      string `query:"order" validate:"omitempty,oneof=asc desc"`
+	Page       int    `query:"page"`
+	PerPage    int    `query:"per_page"`
+}
+
+// ProductAnalyticsQuery represents the query parameters of a product's performance
+type ProductAnalyticsQuery struct {
+	Days        int    `query:"days" validate:"omitempty,min=1,max=366"` // Completed days to cover, up to yesterday; defaults to 30
+	Granularity string `query:"granularity" validate:"omitempty,oneof=day week month"`
+}
+
+// ProductPerformanceResponse represents a product's sales over a range of days and how
+// long its stock lasts at that pace
+type ProductPerformanceResponse struct {
+	Rank              int         `json:"rank,omitempty"` // Position in the ranking
+	ProductID         uint        `json:"product_id"`
+	Name              string      `json:"name"`
+	SKU               string      `json:"sku"`
+	Discontinued      bool        `json:"discontinued"`
+	Orders            int         `json:"orders"`
+	UnitsOrdered      int         `json:"units_ordered"`
+	UnitsCancelled    int         `json:"units_cancelled"`
+	UnitsSold         int         `json:"units_sold"`
+	Revenue           money.Money `json:"revenue" swaggertype:"number"`
+	Cancellations     money.Money `json:"cancellations" swaggertype:"number"`
+	CancellationRate  float64     `json:"cancellation_rate"` // Percentage of the units ordered
+	Stock             int         `json:"stock"`             // Available now, across warehouses
+	AverageDailySales float64     `json:"average_daily_sales"`
+	SellThroughRate   float64     `json:"sell_through_rate"`       // Percentage of the units sold and in stock that were sold
+	DaysOfCover       *float64    `json:"days_of_cover,omitempty"` // Stock / average daily sales; absent if nothing sold
+	SlowMover         bool        `json:"slow_mover"`
+}
+
+// PaginatedProductPerformanceResponse represents a page of the product performance ranking
+type PaginatedProductPerformanceResponse struct {
+	Products      []ProductPerformanceResponse `json:"products"`
+	From          time.Time                    `json:"from"`
+	To            time.Time                    `json:"to"`
+	Currency      string                       `json:"currency"`
+	SlowMoverDays int                          `json:"slow_mover_days"` // Days of cover beyond which a product is a slow mover
+	Total         int64                        `json:"total"`
+	Page          int                          `json:"page"`
+	PerPage       int                          `json:"per_page"`
+	TotalPages    int                          `json:"total_pages"`
+}
+
+// ProductSalesPeriod represents a product's sales in a day, week or month
+type ProductSalesPeriod struct {
+	From           time.Time   `json:"from"`
+	To             time.Time   `json:"to"`
+	Orders         int         `json:"orders"`
+	UnitsOrdered   int         `json:"units_ordered"`
+	UnitsCancelled int         `json:"units_cancelled"`
+	UnitsSold      int         `json:"units_sold"`
+	Revenue        money.Money `json:"revenue" swaggertype:"number"`
+	Cancellations  money.Money `json:"cancellations" swaggertype:"number"`
+}
+
+// ProductAnalyticsResponse represents a product's performance over a range of days with
+// its sales broken down into periods
+type ProductAnalyticsResponse struct {
+	ProductPerformanceResponse
+	From          time.Time            `json:"from"`
+	To            time.Time            `json:"to"`
+	Granularity   string               `json:"granularity"`
+	Currency      string               `json:"currency"`
+	SlowMoverDays int                  `json:"slow_mover_days"`
+	Periods       []ProductSalesPeriod `json:"periods"`
+}
+
+// CustomerSegmentToResponse converts a CustomerSegmentSummary model to a CustomerSegmentResponse DTO
+func CustomerSegmentToResponse(segment *models.CustomerSegmentSummary) CustomerSegmentResponse {
+	return CustomerSegmentResponse{
+		Segment:            string(segment.Segment),
+		Customers:          segment.Customers,
+		Revenue:            segment.Revenue,
+		AverageRecencyDays: segment.AverageRecencyDays,
+		AverageOrders:      segment.AverageOrders,
+		AverageMonetary:    segment.AverageMonetary,
+	}
+}
+
+// CustomerRFMToResponse converts a CustomerRFMScore model, with its user loaded, to a CustomerRFMResponse DTO
+func CustomerRFMToResponse(score *models.CustomerRFMScore) CustomerRFMResponse {
+	return CustomerRFMResponse{
+		UserID:         score.UserID,
+		Email:          score.User.Email,
+		FirstName:      score.User.FirstName,
+		LastName:       score.User.LastName,
+		Segment:        string(score.Segment),
+		RecencyDays:    score.RecencyDays,
+		Orders:         score.Orders,
+		Monetary:       score.Monetary,
+		RecencyScore:   score.RecencyScore,
+		FrequencyScore: score.FrequencyScore,
+		MonetaryScore:  score.MonetaryScore,
+		FirstOrderAt:   score.FirstOrderAt,
+		LastOrderAt:    score.LastOrderAt,
+	}
+}
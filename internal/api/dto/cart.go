@@ -0,0 +1,76 @@
+package dto
+
+import (
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
+)
+
+// SetCartItemRequest sets the quantity of a product in the cart; zero removes it
+type SetCartItemRequest struct {
+	ProductID uint `json:"product_id" validate:"required"`
+	Quantity  int  `json:"quantity" validate:"gte=0"`
+}
+
+// CheckoutCartRequest represents a request to place an order for the cart's items
+type CheckoutCartRequest struct {
+	ShippingAddress *Address `json:"shipping_address,omitempty"`
+}
+
+// CartItemResponse represents a product in the cart at its current price
+type CartItemResponse struct {
+	ProductID    uint        `json:"product_id"`
+	Name         string      `json:"name"`
+	SKU          string      `json:"sku"`
+	Quantity     int         `json:"quantity"`
+	Price        money.Money `json:"price" swaggertype:"number"`      // Current unit price, including any running sale
+	LineTotal    money.Money `json:"line_total" swaggertype:"number"` // Price times quantity
+	Discontinued bool        `json:"discontinued,omitempty"`          // Can no longer be ordered and has to be removed before checkout
+}
+
+// CartResponse represents the user's cart priced in the requested currency
+type CartResponse struct {
+	Items    []CartItemResponse `json:"items"`
+	Currency string             `json:"currency"`
+	Subtotal money.Money        `json:"subtotal" swaggertype:"number"`
+}
+
+// ReorderRequest represents the optional body of a reorder
+type ReorderRequest struct {
+	ToCart bool `json:"to_cart,omitempty"` // Add the items to the cart instead of placing the order
+}
+
+// Reorder item outcomes
+const (
+	ReorderItemAdded   = "added"   // Reordered in full
+	ReorderItemReduced = "reduced" // Reordered with the quantity still in stock
+	ReorderItemSkipped = "skipped" // Left out, see the reason
+)
+
+// Reasons for reordering less than the original quantity
+const (
+	ReorderReasonDiscontinued      = "discontinued"
+	ReorderReasonOutOfStock        = "out_of_stock"
+	ReorderReasonInsufficientStock = "insufficient_stock"
+)
+
+// ReorderItemResult reports how an item of the original order was reordered and how its
+// price changed since
+type ReorderItemResult struct {
+	ProductID         uint         `json:"product_id"`
+	Name              string       `json:"name,omitempty"`
+	Quantity          int          `json:"quantity"`           // Quantity on the original order
+	ReorderedQuantity int          `json:"reordered_quantity"` // Quantity on the new order or added to the cart
+	Status            string       `json:"status"`             // added, reduced or skipped
+	Reason            string       `json:"reason,omitempty"`   // discontinued, out_of_stock or insufficient_stock
+	OriginalPrice     money.Money  `json:"original_price" swaggertype:"number"`
+	CurrentPrice      *money.Money `json:"current_price,omitempty" swaggertype:"number"`
+	PriceDifference   *money.Money `json:"price_difference,omitempty" swaggertype:"number"` // Current minus original unit price
+}
+
+// ReorderResponse represents the new order, or the cart the items were added to, and the
+// outcome for every item of the original order
+type ReorderResponse struct {
+	Order    *OrderResponse      `json:"order,omitempty"`
+	Cart     *CartResponse       `json:"cart,omitempty"`
+	Currency string              `json:"currency"` // Currency of the prices, the original order's
+	Items    []ReorderItemResult `json:"items"`
+}
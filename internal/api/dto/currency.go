@@ -0,0 +1,57 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
+)
+
+// CreateExchangeRateRequest represents the request body for recording an exchange rate
+type CreateExchangeRateRequest struct {
+	BaseCurrency  string     `json:"base_currency,omitempty" validate:"omitempty,len=3"` // Defaults to the base currency
+	QuoteCurrency string     `json:"quote_currency" validate:"required,len=3"`
+	Rate          money.Rate `json:"rate" swaggertype:"number"` // Units of the quote currency per unit of the base currency
+	EffectiveAt   *time.Time `json:"effective_at,omitempty"`    // Defaults to now
+}
+
+// ExchangeRateResponse represents an exchange rate in responses
+type ExchangeRateResponse struct {
+	ID            uint       `json:"id"`
+	BaseCurrency  string     `json:"base_currency"`
+	QuoteCurrency string     `json:"quote_currency"`
+	Rate          money.Rate `json:"rate" swaggertype:"number"`
+	EffectiveAt   time.Time  `json:"effective_at"`
+}
+
+// SetProductPriceRequest represents the request body for setting a product's list price in a currency
+type SetProductPriceRequest struct {
+	Price money.Money `json:"price" swaggertype:"number" validate:"required,gt=0"`
+}
+
+// ProductPriceResponse represents a product list price in responses
+type ProductPriceResponse struct {
+	ProductID uint        `json:"product_id"`
+	Currency  string      `json:"currency"`
+	Price     money.Money `json:"price" swaggertype:"number"`
+}
+
+// ExchangeRateToResponse converts an ExchangeRate model to an ExchangeRateResponse DTO
+func ExchangeRateToResponse(rate *models.ExchangeRate) ExchangeRateResponse {
+	return ExchangeRateResponse{
+		ID:            rate.ID,
+		BaseCurrency:  string(rate.BaseCurrency),
+		QuoteCurrency: string(rate.QuoteCurrency),
+		Rate:          rate.Rate,
+		EffectiveAt:   rate.EffectiveAt,
+	}
+}
+
+// ProductPriceToResponse converts a ProductPrice model to a ProductPriceResponse DTO
+func ProductPriceToResponse(price *models.ProductPrice) ProductPriceResponse {
+	return ProductPriceResponse{
+		ProductID: price.ProductID,
+		Currency:  string(price.Currency),
+		Price:     price.Price,
+	}
+}
@@ -1,11 +1,130 @@
 package dto
 
-// LowStockAlertResponse represents a product with low stock levels
+import (
+	"time"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
+)
+
+// LowStockAlertResponse represents a product with low stock levels in a warehouse
 type LowStockAlertResponse struct {
-	ProductID   uint    `json:"product_id"`
-	Name        string  `json:"name"`
-	SKU         string  `json:"sku"`
-	StockLevel  int     `json:"stock_level"`
-	MinimumStock int    `json:"minimum_stock"`
-	Price       float64 `json:"price"`
+	AlertID                  uint        `json:"alert_id"`
+	ProductID                uint        `json:"product_id"`
+	Name                     string      `json:"name"`
+	SKU                      string      `json:"sku"`
+	WarehouseID              uint        `json:"warehouse_id"`
+	WarehouseCode            string      `json:"warehouse_code,omitempty"`
+	StockLevel               int         `json:"stock_level"`
+	Reserved                 int         `json:"reserved"`
+	MinimumStock             int         `json:"minimum_stock"`
+	Price                    money.Money `json:"price" swaggertype:"number"`
+	DailySalesVelocity       float64     `json:"daily_sales_velocity"`
+	SuggestedReorderQuantity int         `json:"suggested_reorder_quantity"`
+	PreferredSupplierID      *uint       `json:"preferred_supplier_id,omitempty"` // Set when a draft purchase order can be created from the alert
+	TriggeredAt              time.Time   `json:"triggered_at"`
+}
+
+// InventoryAdjustmentItem represents a single stock change in a batch adjustment
+type InventoryAdjustmentItem struct {
+	ProductID     uint   `json:"product_id" validate:"required"`
+	WarehouseID   uint   `json:"warehouse_id,omitempty"` // Defaults to the default warehouse
+	QuantityDelta int    `json:"quantity_delta" validate:"required,ne=0"`
+	ReasonCode    string `json:"reason_code" validate:"required,oneof=receipt adjustment return shrinkage"`
+	Note          string `json:"note,omitempty" validate:"omitempty,max=500"`
+}
+
+// InventoryAdjustmentRequest represents a batch of inventory adjustments applied atomically
+type InventoryAdjustmentRequest struct {
+	Reference string                    `json:"reference,omitempty" validate:"omitempty,max=100"`
+	Items     []InventoryAdjustmentItem `json:"items" validate:"required,min=1,max=100,dive"`
+}
+
+// InventoryAdjustmentResponse represents the ledger entries created by a batch adjustment
+type InventoryAdjustmentResponse struct {
+	Reference string                  `json:"reference,omitempty"`
+	Movements []StockMovementResponse `json:"movements"`
+}
+
+// StockMovementResponse represents a single entry of the stock movement ledger
+type StockMovementResponse struct {
+	ID            uint      `json:"id"`
+	ProductID     uint      `json:"product_id"`
+	WarehouseID   uint      `json:"warehouse_id"`
+	Type          string    `json:"type"`
+	QuantityDelta int       `json:"quantity_delta"`
+	ReservedDelta int       `json:"reserved_delta"`
+	QuantityAfter int       `json:"quantity_after"`
+	ReservedAfter int       `json:"reserved_after"`
+	ReferenceType string    `json:"reference_type,omitempty"`
+	ReferenceID   *uint     `json:"reference_id,omitempty"`
+	Reference     string    `json:"reference,omitempty"`
+	Note          string    `json:"note,omitempty"`
+	ActorID       *uint     `json:"actor_id,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// PaginatedStockMovementsResponse represents a paginated movement history for a product
+type PaginatedStockMovementsResponse struct {
+	Movements []StockMovementResponse `json:"movements"`
+	Total     int64                   `json:"total"`
+	Page      int                     `json:"page"`
+	Limit     int                     `json:"limit"`
+}
+
+// InventoryReconciliationResponse compares stored inventory with the stock movement ledger
+type InventoryReconciliationResponse struct {
+	ProductID        uint `json:"product_id"`
+	WarehouseID      uint `json:"warehouse_id"`
+	StockLevel       int  `json:"stock_level"`
+	Reserved         int  `json:"reserved"`
+	LedgerStockLevel int  `json:"ledger_stock_level"`
+	LedgerReserved   int  `json:"ledger_reserved"`
+	StockDrift       int  `json:"stock_drift"`
+	ReservedDrift    int  `json:"reserved_drift"`
+}
+
+// StockMovementToResponse converts a StockMovement model to a StockMovementResponse DTO
+func StockMovementToResponse(movement *models.StockMovement) StockMovementResponse {
+	return StockMovementResponse{
+		ID:            movement.ID,
+		ProductID:     movement.ProductID,
+		WarehouseID:   movement.WarehouseID,
+		Type:          string(movement.Type),
+		QuantityDelta: movement.QuantityDelta,
+		ReservedDelta: movement.ReservedDelta,
+		QuantityAfter: movement.QuantityAfter,
+		ReservedAfter: movement.ReservedAfter,
+		ReferenceType: movement.ReferenceType,
+		ReferenceID:   movement.ReferenceID,
+		Reference:     movement.Reference,
+		Note:          movement.Note,
+		ActorID:       movement.ActorID,
+		CreatedAt:     movement.CreatedAt,
+	}
+}
+
+// StockAlertToResponse converts a StockAlert model with its product and warehouse loaded to a response DTO
+func StockAlertToResponse(alert *models.StockAlert) LowStockAlertResponse {
+	resp := LowStockAlertResponse{
+		AlertID:                  alert.ID,
+		ProductID:                alert.ProductID,
+		WarehouseID:              alert.WarehouseID,
+		StockLevel:               alert.StockLevel,
+		Reserved:                 alert.Reserved,
+		MinimumStock:             alert.MinimumStock,
+		DailySalesVelocity:       alert.DailySalesVelocity,
+		SuggestedReorderQuantity: alert.SuggestedReorderQuantity,
+		TriggeredAt:              alert.TriggeredAt,
+	}
+	if alert.Product != nil {
+		resp.Name = alert.Product.Name
+		resp.SKU = alert.Product.SKU
+		resp.Price = alert.Product.Price
+		resp.PreferredSupplierID = alert.Product.PreferredSupplierID
+	}
+	if alert.Warehouse != nil {
+		resp.WarehouseCode = alert.Warehouse.Code
+	}
+	return resp
 }
@@ -4,53 +4,136 @@ import (
 	"time"
 
 	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
 )
 
 type CreateOrderItemRequest struct {
-	ProductID uint    `json:"product_id" validate:"required"`
-	Quantity  int     `json:"quantity" validate:"required,gt=0"`
+	ProductID uint `json:"product_id" validate:"required"`
+	Quantity  int  `json:"quantity" validate:"required,gt=0"`
 }
 
 type CreateOrderRequest struct {
-	Items []CreateOrderItemRequest `json:"items" validate:"required,min=1,dive"`
+	Items           []CreateOrderItemRequest `json:"items" validate:"required,min=1,dive"`
+	ShippingAddress *Address                 `json:"shipping_address,omitempty"`
+}
+
+// CancelOrderRequest represents the optional body of an order cancellation
+type CancelOrderRequest struct {
+	Reason string `json:"reason,omitempty" validate:"max=500"`
+}
+
+// AdjustOrderItemRequest sets the new, lower quantity of an order item; zero removes it
+type AdjustOrderItemRequest struct {
+	ItemID   uint `json:"item_id" validate:"required"`
+	Quantity int  `json:"quantity" validate:"gte=0"`
+}
+
+// AdjustOrderItemsRequest represents a request to reduce or remove items of an order
+type AdjustOrderItemsRequest struct {
+	Items  []AdjustOrderItemRequest `json:"items" validate:"required,min=1,dive"`
+	Reason string                   `json:"reason,omitempty" validate:"max=500"`
+}
+
+// ListOrdersQuery represents the filters, sorting and cursor for listing a user's orders
+type ListOrdersQuery struct {
+	Status    []string `query:"status" validate:"dive,oneof=pending backordered processing shipped delivered cancelled"`
+	From      string   `query:"from"` // RFC 3339 time or YYYY-MM-DD date, inclusive
+	To        string   `query:"to"`   // RFC 3339 time (exclusive) or YYYY-MM-DD date (inclusive)
+	ProductID uint     `query:"product_id"`
+	Sort      string   `query:"sort" validate:"omitempty,oneof=newest oldest"`
+	Limit     int      `query:"limit" validate:"gte=1,lte=100"`
+	Cursor    string   `query:"cursor"` // next_cursor of the previous page
 }
 
 type OrderResponse struct {
-	ID          uint              `json:"id"`
-	UserID      uint              `json:"user_id"`
-	TotalAmount float64           `json:"total_amount"`
-	Status      string            `json:"status"`
-	Items       []OrderItemResponse `json:"items"`
-	CreatedAt   time.Time         `json:"created_at"`
-	UpdatedAt   time.Time         `json:"updated_at"`
+	ID              uint                `json:"id"`
+	UserID          uint                `json:"user_id"`
+	TotalAmount     money.Money         `json:"total_amount" swaggertype:"number"`
+	Currency        string              `json:"currency"`
+	ExchangeRate    money.Rate          `json:"exchange_rate" swaggertype:"number"` // Base to order currency rate at checkout
+	Status          string              `json:"status"`
+	Items           []OrderItemResponse `json:"items"`
+	ShippingAddress Address             `json:"shipping_address"`
+	Cancellation    *CancellationInfo   `json:"cancellation,omitempty"`
+	CreatedAt       time.Time           `json:"created_at"`
+	UpdatedAt       time.Time           `json:"updated_at"`
+}
+
+// CancellationInfo represents who cancelled an order, when and why
+type CancellationInfo struct {
+	Reason      string    `json:"reason,omitempty"`
+	CancelledBy uint      `json:"cancelled_by"`
+	CancelledAt time.Time `json:"cancelled_at"`
 }
 
 type OrderItemResponse struct {
-	ID        uint    `json:"id"`
-	ProductID uint    `json:"product_id"`
-	Quantity  int     `json:"quantity"`
-	Price     float64 `json:"price"`
+	ID                  uint                          `json:"id"`
+	ProductID           uint                          `json:"product_id"`
+	Quantity            int                           `json:"quantity"`
+	Price               money.Money                   `json:"price" swaggertype:"number"`
+	BackorderedQuantity int                           `json:"backordered_quantity,omitempty"` // Units waiting for stock
+	CancelledQuantity   int                           `json:"cancelled_quantity,omitempty"`   // Units removed after checkout
+	Allocations         []OrderItemAllocationResponse `json:"allocations,omitempty"`
+}
+
+// OrderItemAllocationResponse represents the units of an order item reserved in a warehouse
+type OrderItemAllocationResponse struct {
+	WarehouseID uint `json:"warehouse_id"`
+	Quantity    int  `json:"quantity"`
 }
 
 // OrderToResponse converts an Order model to an OrderResponse DTO
 func OrderToResponse(order *models.Order) *OrderResponse {
 	items := make([]OrderItemResponse, len(order.OrderItems))
 	for i, item := range order.OrderItems {
-		items[i] = OrderItemResponse{
-			ID:        item.ID,
-			ProductID: item.ProductID,
-			Quantity:  item.Quantity,
-			Price:    item.Price,
-		}
+		items[i] = OrderItemToResponse(&item)
 	}
 
 	return &OrderResponse{
-		ID:          order.ID,
-		UserID:      order.UserID,
-		Status:      string(order.Status),
-		TotalAmount: order.TotalAmount,
-		Items:       items,
-		CreatedAt:   order.CreatedAt,
-		UpdatedAt:   order.UpdatedAt,
+		ID:              order.ID,
+		UserID:          order.UserID,
+		Status:          string(order.Status),
+		TotalAmount:     order.TotalAmount,
+		Currency:        string(order.Currency),
+		ExchangeRate:    order.ExchangeRate,
+		Items:           items,
+		ShippingAddress: AddressFromModel(order.ShippingAddress),
+		Cancellation:    CancellationFromModel(order),
+		CreatedAt:       order.CreatedAt,
+		UpdatedAt:       order.UpdatedAt,
+	}
+}
+
+// CancellationFromModel returns the cancellation details of an order, or nil if it was not cancelled
+func CancellationFromModel(order *models.Order) *CancellationInfo {
+	if order.CancelledAt == nil {
+		return nil
+	}
+	info := &CancellationInfo{
+		Reason:      order.CancellationReason,
+		CancelledAt: *order.CancelledAt,
+	}
+	if order.CancelledBy != nil {
+		info.CancelledBy = *order.CancelledBy
+	}
+	return info
+}
+
+// OrderItemToResponse converts an OrderItem model to an OrderItemResponse DTO
+func OrderItemToResponse(item *models.OrderItem) OrderItemResponse {
+	resp := OrderItemResponse{
+		ID:                  item.ID,
+		ProductID:           item.ProductID,
+		Quantity:            item.Quantity,
+		Price:               item.Price,
+		BackorderedQuantity: item.BackorderedQuantity,
+		CancelledQuantity:   item.CancelledQuantity,
+	}
+	for _, allocation := range item.Allocations {
+		resp.Allocations = append(resp.Allocations, OrderItemAllocationResponse{
+			WarehouseID: allocation.WarehouseID,
+			Quantity:    allocation.Quantity,
+		})
 	}
+	return resp
 }
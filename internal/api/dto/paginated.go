@@ -1,10 +1,13 @@
 package dto
 
-// PaginatedOrdersResponse represents a paginated list of orders
+// PaginatedOrdersResponse represents a paginated list of orders, either page based
+// or cursor based; NextCursor is empty on the last page
 type PaginatedOrdersResponse struct {
 	Orders     []OrderResponse `json:"orders"`
-	Total      int64          `json:"total"`
-	Page       int            `json:"page"`
-	PerPage    int            `json:"per_page"`
-	TotalPages int            `json:"total_pages"`
+	Total      int64          `json:"total,omitempty"`
+	Page       int            `json:"page,omitempty"`
+	PerPage    int            `json:"per_page,omitempty"`
+	TotalPages int            `json:"total_pages,omitempty"`
+	Limit      int            `json:"limit,omitempty"`
+	NextCursor string         `json:"next_cursor,omitempty"`
 }
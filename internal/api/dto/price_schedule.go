@@ -0,0 +1,76 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
+)
+
+// CreatePriceScheduleRequest represents the request body for scheduling a product price.
+// With ends_at it is a sale; without it the price replaces the regular price at starts_at.
+type CreatePriceScheduleRequest struct {
+	Price          money.Money  `json:"price" swaggertype:"number" validate:"required,gt=0"`
+	CompareAtPrice *money.Money `json:"compare_at_price,omitempty" swaggertype:"number" validate:"omitempty,gt=0"` // Defaults to the regular price for sales
+	StartsAt       time.Time    `json:"starts_at" validate:"required"`
+	EndsAt         *time.Time   `json:"ends_at,omitempty"`
+	Label          string       `json:"label,omitempty" validate:"max=100"`
+}
+
+// PriceScheduleResponse represents a price schedule in responses
+type PriceScheduleResponse struct {
+	ID             uint         `json:"id"`
+	ProductID      uint         `json:"product_id"`
+	Price          money.Money  `json:"price" swaggertype:"number"`
+	CompareAtPrice *money.Money `json:"compare_at_price,omitempty" swaggertype:"number"`
+	StartsAt       time.Time    `json:"starts_at"`
+	EndsAt         *time.Time   `json:"ends_at,omitempty"`
+	Label          string       `json:"label,omitempty"`
+	Status         string       `json:"status"`
+	CreatedAt      time.Time    `json:"created_at"`
+}
+
+// PriceHistoryResponse represents a product price history entry in responses
+type PriceHistoryResponse struct {
+	Price           money.Money  `json:"price" swaggertype:"number"`
+	CompareAtPrice  *money.Money `json:"compare_at_price,omitempty" swaggertype:"number"`
+	Source          string       `json:"source"`
+	PriceScheduleID *uint        `json:"price_schedule_id,omitempty"`
+	ActorID         *uint        `json:"actor_id,omitempty"`
+	EffectiveAt     time.Time    `json:"effective_at"`
+}
+
+// PriceScheduleToResponse converts a PriceSchedule model to a PriceScheduleResponse DTO
+func PriceScheduleToResponse(schedule *models.PriceSchedule) PriceScheduleResponse {
+	return PriceScheduleResponse{
+		ID:             schedule.ID,
+		ProductID:      schedule.ProductID,
+		Price:          schedule.Price,
+		CompareAtPrice: optionalMoney(schedule.CompareAtPrice),
+		StartsAt:       schedule.StartsAt,
+		EndsAt:         schedule.EndsAt,
+		Label:          schedule.Label,
+		Status:         string(schedule.Status),
+		CreatedAt:      schedule.CreatedAt,
+	}
+}
+
+// PriceHistoryToResponse converts a ProductPriceHistory model to a PriceHistoryResponse DTO
+func PriceHistoryToResponse(entry *models.ProductPriceHistory) PriceHistoryResponse {
+	return PriceHistoryResponse{
+		Price:           entry.Price,
+		CompareAtPrice:  optionalMoney(entry.CompareAtPrice),
+		Source:          string(entry.Source),
+		PriceScheduleID: entry.PriceScheduleID,
+		ActorID:         entry.ActorID,
+		EffectiveAt:     entry.EffectiveAt,
+	}
+}
+
+// optionalMoney returns nil for a zero amount so it is left out of responses
+func optionalMoney(m money.Money) *money.Money {
+	if m.IsZero() {
+		return nil
+	}
+	return &m
+}
@@ -1,60 +1,104 @@
 package dto
 
+import (
+	"time"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
+)
+
 // PaginationQuery represents query parameters for pagination
 type PaginationQuery struct {
-	Page     int `query:"page" validate:"gte=1"`
-	Limit    int `query:"limit" validate:"gte=1,lte=100"`
+	Page  int `query:"page" validate:"gte=1"`
+	Limit int `query:"limit" validate:"gte=1,lte=100"`
 }
 
 // CreateProductRequest represents the request body for creating a product
 type CreateProductRequest struct {
-	Name        string  `json:"name" validate:"required,min=3,max=100"`
-	Description string  `json:"description" validate:"required,min=10,max=1000"`
-	Price       float64 `json:"price" validate:"required,gt=0"`
-	Quantity    int     `json:"quantity" validate:"required,gte=0"`
+	Name                string      `json:"name" validate:"required,min=3,max=100"`
+	Description         string      `json:"description" validate:"required,min=10,max=1000"`
+	Price               money.Money `json:"price" swaggertype:"number" validate:"required,gt=0"`
+	Quantity            int         `json:"quantity" validate:"required,gte=0"`
+	PreferredSupplierID *uint       `json:"preferred_supplier_id,omitempty"`
+	StockPolicy         string      `json:"stock_policy,omitempty" validate:"omitempty,oneof=deny backorder preorder"`
+	BackorderLimit      int         `json:"backorder_limit,omitempty" validate:"gte=0"`
+	PreorderReleaseAt   *time.Time  `json:"preorder_release_at,omitempty"`
 }
 
 type UpdateProductRequest struct {
-	Name        *string  `json:"name,omitempty" validate:"omitempty,min=3,max=100"`
-	Description *string  `json:"description,omitempty" validate:"omitempty,min=10,max=1000"`
-	Price       *float64 `json:"price,omitempty" validate:"omitempty,gt=0"`
-	Quantity    *int     `json:"quantity,omitempty" validate:"omitempty,gte=0"`
+	Name                *string      `json:"name,omitempty" validate:"omitempty,min=3,max=100"`
+	Description         *string      `json:"description,omitempty" validate:"omitempty,min=10,max=1000"`
+	Price               *money.Money `json:"price,omitempty" swaggertype:"number" validate:"omitempty,gt=0"`
+	Quantity            *int         `json:"quantity,omitempty" validate:"omitempty,gte=0"`
+	WarehouseID         *uint        `json:"warehouse_id,omitempty"` // Warehouse whose stock Quantity sets, defaults to the default warehouse
+	PreferredSupplierID *uint        `json:"preferred_supplier_id,omitempty"`
+	StockPolicy         *string      `json:"stock_policy,omitempty" validate:"omitempty,oneof=deny backorder preorder"`
+	BackorderLimit      *int         `json:"backorder_limit,omitempty" validate:"omitempty,gte=0"`
+	PreorderReleaseAt   *time.Time   `json:"preorder_release_at,omitempty"`
+	Discontinued        *bool        `json:"discontinued,omitempty"` // Discontinued products can no longer be ordered
 }
 
 // ProductResponse represents a product in responses
 // ProductResponse represents a product in responses
 type ProductResponse struct {
-	ID          uint    `json:"id"`
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
-	SKU         string  `json:"sku"`
-	StockLevel  int     `json:"stock_level"`
+	ID                  uint         `json:"id"`
+	Name                string       `json:"name"`
+	Description         string       `json:"description"`
+	Price               money.Money  `json:"price" swaggertype:"number"`                      // Current price, including any running sale
+	CompareAtPrice      *money.Money `json:"compare_at_price,omitempty" swaggertype:"number"` // Price before the sale, while one runs
+	Currency            string       `json:"currency"`
+	SKU                 string       `json:"sku"`
+	StockLevel          int          `json:"stock_level"`
+	PreferredSupplierID *uint        `json:"preferred_supplier_id,omitempty"`
+	StockPolicy         string       `json:"stock_policy"`
+	BackorderLimit      int          `json:"backorder_limit,omitempty"`
+	PreorderReleaseAt   *time.Time   `json:"preorder_release_at,omitempty"`
+	Discontinued        bool         `json:"discontinued,omitempty"`
 }
 
-// InventoryResponse represents the current inventory level of a product
+// InventoryResponse represents the current inventory level of a product across all warehouses
 type InventoryResponse struct {
-	ProductID     uint   `json:"product_id"`
-	SKU           string `json:"sku"`
-	StockLevel    int    `json:"stock_level"`
-	MinimumStock  int    `json:"minimum_stock"`
+	ProductID    uint                         `json:"product_id"`
+	SKU          string                       `json:"sku"`
+	StockLevel   int                          `json:"stock_level"`
+	Reserved     int                          `json:"reserved"`
+	MinimumStock int                          `json:"minimum_stock"`
+	Warehouses   []WarehouseInventoryResponse `json:"warehouses"`
 }
 
 // ListProductsResponse represents the response for listing products
 // PaginatedProductsResponse represents a paginated list of products
 type PaginatedProductsResponse struct {
 	Products []ProductResponse `json:"products"`
-	Total    int64            `json:"total"`
-	Page     int              `json:"page"`
-	Limit    int              `json:"limit"`
+	Total    int64             `json:"total"`
+	Page     int               `json:"page"`
+	Limit    int               `json:"limit"`
 }
 
 // CreateProductResponse represents the response body for creating a product
 type CreateProductResponse struct {
-	ID          uint    `json:"id"`
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Price       float64 `json:"price"`
-	Quantity    int     `json:"quantity"`
-	CreatedAt   string  `json:"created_at"`
+	ID          uint        `json:"id"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Price       money.Money `json:"price" swaggertype:"number"`
+	Quantity    int         `json:"quantity"`
+	CreatedAt   string      `json:"created_at"`
+}
+
+// ProductToResponse converts a Product model and its available stock to a ProductResponse DTO
+func ProductToResponse(product *models.Product, stockLevel int) *ProductResponse {
+	return &ProductResponse{
+		ID:                  product.ID,
+		Name:                product.Name,
+		Description:         product.Description,
+		Price:               product.Price,
+		Currency:            string(product.Price.Currency()),
+		SKU:                 product.SKU,
+		StockLevel:          stockLevel,
+		PreferredSupplierID: product.PreferredSupplierID,
+		StockPolicy:         string(product.StockPolicy),
+		BackorderLimit:      product.BackorderLimit,
+		PreorderReleaseAt:   product.PreorderReleaseAt,
+		Discontinued:        product.Discontinued,
+	}
 }
@@ -0,0 +1,118 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
+)
+
+// PurchaseOrderLineRequest represents a product line on a new purchase order
+type PurchaseOrderLineRequest struct {
+	ProductID  uint        `json:"product_id" validate:"required"`
+	Quantity   int         `json:"quantity" validate:"required,gt=0"`
+	UnitCost   money.Money `json:"unit_cost" swaggertype:"number" validate:"gte=0"`
+	ExpectedAt *time.Time  `json:"expected_at,omitempty"` // Defaults to the order's expected date
+}
+
+// CreatePurchaseOrderRequest represents the request body for drafting a purchase order
+type CreatePurchaseOrderRequest struct {
+	SupplierID  uint                       `json:"supplier_id" validate:"required"`
+	WarehouseID uint                       `json:"warehouse_id,omitempty"` // Defaults to the default warehouse
+	ExpectedAt  *time.Time                 `json:"expected_at,omitempty"`  // Defaults to today plus the supplier's lead time
+	Notes       string                     `json:"notes,omitempty" validate:"omitempty,max=1000"`
+	Lines       []PurchaseOrderLineRequest `json:"lines" validate:"required,min=1,max=100,dive"`
+}
+
+// ReceivePurchaseOrderLine represents the quantity delivered for one purchase order line
+type ReceivePurchaseOrderLine struct {
+	LineID   uint `json:"line_id" validate:"required"`
+	Quantity int  `json:"quantity" validate:"required,gt=0"`
+}
+
+// ReceivePurchaseOrderRequest represents a delivery against a sent purchase order.
+// Without lines every outstanding quantity is received.
+type ReceivePurchaseOrderRequest struct {
+	Lines []ReceivePurchaseOrderLine `json:"lines,omitempty" validate:"omitempty,max=100,dive"`
+	Note  string                     `json:"note,omitempty" validate:"omitempty,max=500"`
+}
+
+// PurchaseOrderLineResponse represents a purchase order line in responses
+type PurchaseOrderLineResponse struct {
+	ID               uint        `json:"id"`
+	ProductID        uint        `json:"product_id"`
+	ProductName      string      `json:"product_name,omitempty"`
+	SKU              string      `json:"sku,omitempty"`
+	QuantityOrdered  int         `json:"quantity_ordered"`
+	QuantityReceived int         `json:"quantity_received"`
+	UnitCost         money.Money `json:"unit_cost" swaggertype:"number"`
+	ExpectedAt       *time.Time  `json:"expected_at,omitempty"`
+}
+
+// PurchaseOrderResponse represents a purchase order in responses
+type PurchaseOrderResponse struct {
+	ID           uint                        `json:"id"`
+	Number       string                      `json:"number"`
+	Status       string                      `json:"status"`
+	Supplier     *SupplierResponse           `json:"supplier,omitempty"`
+	SupplierID   uint                        `json:"supplier_id"`
+	WarehouseID  uint                        `json:"warehouse_id"`
+	StockAlertID *uint                       `json:"stock_alert_id,omitempty"`
+	ExpectedAt   *time.Time                  `json:"expected_at,omitempty"`
+	SentAt       *time.Time                  `json:"sent_at,omitempty"`
+	ReceivedAt   *time.Time                  `json:"received_at,omitempty"`
+	Notes        string                      `json:"notes,omitempty"`
+	TotalCost    money.Money                 `json:"total_cost" swaggertype:"number"`
+	Lines        []PurchaseOrderLineResponse `json:"lines"`
+	CreatedAt    time.Time                   `json:"created_at"`
+}
+
+// PaginatedPurchaseOrdersResponse represents a paginated list of purchase orders
+type PaginatedPurchaseOrdersResponse struct {
+	PurchaseOrders []PurchaseOrderResponse `json:"purchase_orders"`
+	Total          int64                   `json:"total"`
+	Page           int                     `json:"page"`
+	Limit          int                     `json:"limit"`
+}
+
+// PurchaseOrderToResponse converts a PurchaseOrder model to a PurchaseOrderResponse DTO
+func PurchaseOrderToResponse(order *models.PurchaseOrder) PurchaseOrderResponse {
+	resp := PurchaseOrderResponse{
+		ID:           order.ID,
+		Number:       order.Number,
+		Status:       string(order.Status),
+		SupplierID:   order.SupplierID,
+		WarehouseID:  order.WarehouseID,
+		StockAlertID: order.StockAlertID,
+		ExpectedAt:   order.ExpectedAt,
+		SentAt:       order.SentAt,
+		ReceivedAt:   order.ReceivedAt,
+		Notes:        order.Notes,
+		Lines:        make([]PurchaseOrderLineResponse, len(order.Lines)),
+		CreatedAt:    order.CreatedAt,
+	}
+	if order.Supplier != nil {
+		supplier := SupplierToResponse(order.Supplier)
+		resp.Supplier = &supplier
+	}
+
+	for i, line := range order.Lines {
+		resp.Lines[i] = PurchaseOrderLineResponse{
+			ID:               line.ID,
+			ProductID:        line.ProductID,
+			QuantityOrdered:  line.QuantityOrdered,
+			QuantityReceived: line.QuantityReceived,
+			UnitCost:         line.UnitCost,
+			ExpectedAt:       line.ExpectedAt,
+		}
+		if line.Product != nil {
+			resp.Lines[i].ProductName = line.Product.Name
+			resp.Lines[i].SKU = line.Product.SKU
+		}
+		// Costs come from decimal(10,2) columns, far below the int64 range of minor units
+		cost, _ := line.UnitCost.Mul(int64(line.QuantityOrdered))
+		resp.TotalCost, _ = resp.TotalCost.Add(cost)
+	}
+
+	return resp
+}
@@ -1,41 +1,198 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
+)
 
 // DailySalesReportResponse represents a daily sales report response
 type DailySalesReportResponse struct {
-	ID                  uint              `json:"id"`
-	Date                time.Time         `json:"date"`
-	TotalOrders         int               `json:"total_orders"`
-	PendingOrders       int               `json:"pending_orders"`
-	ProcessingOrders    int               `json:"processing_orders"`
-	ShippedOrders       int               `json:"shipped_orders"`
-	DeliveredOrders     int               `json:"delivered_orders"`
-	CancelledOrders     int               `json:"cancelled_orders"`
-	TotalRevenue        float64           `json:"total_revenue"`
-	AverageOrderValue   float64           `json:"average_order_value"`
-	UniqueCustomers     int               `json:"unique_customers"`
-	NewCustomers        int               `json:"new_customers"`
-	TopProducts         []TopProductDTO   `json:"top_products"`
-	LowStockProducts    []LowStockAlert   `json:"low_stock_products"`
-	OrderFulfillmentRate float64          `json:"order_fulfillment_rate"`
-	CancellationRate    float64          `json:"cancellation_rate"`
+	ID                   uint            `json:"id"`
+	Date                 time.Time       `json:"date"`
+	TotalOrders          int             `json:"total_orders"`
+	PendingOrders        int             `json:"pending_orders"`
+	ProcessingOrders     int             `json:"processing_orders"`
+	ShippedOrders        int             `json:"shipped_orders"`
+	DeliveredOrders      int             `json:"delivered_orders"`
+	CancelledOrders      int             `json:"cancelled_orders"`
+	Currency             string          `json:"currency"`                                // Reporting currency of the revenue figures
+	GrossBookings        money.Money     `json:"gross_bookings" swaggertype:"number"`     // Orders placed on the day, as booked at checkout
+	Cancellations        money.Money     `json:"cancellations" swaggertype:"number"`      // Part of the bookings cancelled or removed since
+	NetRevenue           money.Money     `json:"net_revenue" swaggertype:"number"`        // Bookings less cancellations
+	RecognisedRevenue    money.Money     `json:"recognised_revenue" swaggertype:"number"` // Orders delivered on the day
+	TotalRevenue         money.Money     `json:"total_revenue" swaggertype:"number"`      // Same as net_revenue
+	AverageOrderValue    money.Money     `json:"average_order_value" swaggertype:"number"`
+	UniqueCustomers      int             `json:"unique_customers"`
+	NewCustomers         int             `json:"new_customers"`
+	TopProducts          []TopProductDTO `json:"top_products"`
+	LowStockProducts     []LowStockAlert `json:"low_stock_products"`
+	OrderFulfillmentRate float64         `json:"order_fulfillment_rate"`
+	CancellationRate     float64         `json:"cancellation_rate"`
 }
 
 // TopProductDTO represents a top product in the sales report
 type TopProductDTO struct {
-	ProductID     uint    `json:"product_id"`
-	ProductName   string  `json:"product_name"`
-	QuantitySold  int     `json:"quantity_sold"`
-	Revenue       float64 `json:"revenue"`
-	StockTurnover float64 `json:"stock_turnover"`
+	ProductID     uint        `json:"product_id"`
+	ProductName   string      `json:"product_name"`
+	QuantitySold  int         `json:"quantity_sold"`
+	Revenue       money.Money `json:"revenue" swaggertype:"number"`
+	StockTurnover float64     `json:"stock_turnover"`
 }
 
 // LowStockAlert represents a low stock alert in the sales report
 type LowStockAlert struct {
-	ProductID      uint    `json:"product_id"`
-	ProductName    string  `json:"product_name"`
-	CurrentStock   int     `json:"current_stock"`
-	ReservedStock  int     `json:"reserved_stock"`
-	ReorderPoint   int     `json:"reorder_point"`
+	ProductID     uint   `json:"product_id"`
+	ProductName   string `json:"product_name"`
+	CurrentStock  int    `json:"current_stock"`
+	ReservedStock int    `json:"reserved_stock"`
+	ReorderPoint  int    `json:"reorder_point"`
+}
+
+// DailyReportQuery represents the query parameters of the daily sales report
+type DailyReportQuery struct {
+	Date string `query:"date" validate:"omitempty,datetime=2006-01-02"` // Defaults to today (UTC)
+}
+
+// SalesReportRangeQuery represents the query parameters of a date range sales report
+type SalesReportRangeQuery struct {
+	From        string `query:"from" validate:"required,datetime=2006-01-02"`
+	To          string `query:"to" validate:"required,datetime=2006-01-02"` // Inclusive
+	Granularity string `query:"granularity" validate:"omitempty,oneof=day week month"`
+}
+
+// GenerateReportsRequest represents a request to generate the reports of past days
+type GenerateReportsRequest struct {
+	From        string `json:"from" validate:"required,datetime=2006-01-02"`
+	To          string `json:"to" validate:"required,datetime=2006-01-02"` // Inclusive; must be before today
+	OnlyMissing bool   `json:"only_missing"`                               // Keep reports already stored instead of regenerating them
+}
+
+// ReportGenerationResponse represents the days whose reports were generated
+type ReportGenerationResponse struct {
+	Generated []time.Time `json:"generated"`
+	Existing  []time.Time `json:"existing"` // Already stored and kept
+	Skipped   []time.Time `json:"skipped"`  // Being generated by another instance
+}
+
+// SalesPeriodChange represents how a sales period compares with the one before it. The
+// percentages are null when the previous period's figure was zero.
+type SalesPeriodChange struct {
+	TotalOrders              int         `json:"total_orders"`
+	TotalOrdersPercent       *float64    `json:"total_orders_percent"`
+	TotalRevenue             money.Money `json:"total_revenue" swaggertype:"number"`
+	TotalRevenuePercent      *float64    `json:"total_revenue_percent"`
+	AverageOrderValue        money.Money `json:"average_order_value" swaggertype:"number"`
+	AverageOrderValuePercent *float64    `json:"average_order_value_percent"`
+	UniqueCustomers          int         `json:"unique_customers"`
+	UniqueCustomersPercent   *float64    `json:"unique_customers_percent"`
+	OrderFulfillmentRate     float64     `json:"order_fulfillment_rate"` // Percentage points
+	CancellationRate         float64     `json:"cancellation_rate"`      // Percentage points
+}
+
+// SalesPeriodSummary represents the sales of the days from From through To
+type SalesPeriodSummary struct {
+	From                 time.Time          `json:"from"`
+	To                   time.Time          `json:"to"`
+	TotalOrders          int                `json:"total_orders"`
+	PendingOrders        int                `json:"pending_orders"`
+	ProcessingOrders     int                `json:"processing_orders"`
+	ShippedOrders        int                `json:"shipped_orders"`
+	DeliveredOrders      int                `json:"delivered_orders"`
+	CancelledOrders      int                `json:"cancelled_orders"`
+	GrossBookings        money.Money        `json:"gross_bookings" swaggertype:"number"`
+	Cancellations        money.Money        `json:"cancellations" swaggertype:"number"`
+	NetRevenue           money.Money        `json:"net_revenue" swaggertype:"number"`
+	RecognisedRevenue    money.Money        `json:"recognised_revenue" swaggertype:"number"`
+	TotalRevenue         money.Money        `json:"total_revenue" swaggertype:"number"` // Same as net_revenue
+	AverageOrderValue    money.Money        `json:"average_order_value" swaggertype:"number"`
+	UniqueCustomers      int                `json:"unique_customers"`
+	NewCustomers         int                `json:"new_customers"` // First order ever placed in the period
+	OrderFulfillmentRate float64            `json:"order_fulfillment_rate"`
+	CancellationRate     float64            `json:"cancellation_rate"`
+	Change               *SalesPeriodChange `json:"change,omitempty"` // Against the previous period
+}
+
+// SalesReportRangeResponse represents the sales of a date range broken down into periods.
+// Totals are compared with the same number of days right before the range, and every
+// period with the period before it.
+type SalesReportRangeResponse struct {
+	From           time.Time            `json:"from"`
+	To             time.Time            `json:"to"`
+	Granularity    string               `json:"granularity"`
+	Currency       string               `json:"currency"`
+	Totals         SalesPeriodSummary   `json:"totals"`
+	PreviousTotals SalesPeriodSummary   `json:"previous_totals"`
+	Periods        []SalesPeriodSummary `json:"periods"`
+}
+
+// DailySalesReportToResponse converts a DailySalesReport model to a DailySalesReportResponse DTO
+func DailySalesReportToResponse(report *models.DailySalesReport) DailySalesReportResponse {
+	resp := DailySalesReportResponse{
+		ID:                   report.ID,
+		Date:                 report.Date,
+		TotalOrders:          report.TotalOrders,
+		PendingOrders:        report.PendingOrders,
+		ProcessingOrders:     report.ProcessingOrders,
+		ShippedOrders:        report.ShippedOrders,
+		DeliveredOrders:      report.DeliveredOrders,
+		CancelledOrders:      report.CancelledOrders,
+		Currency:             string(report.Currency),
+		GrossBookings:        report.GrossBookings,
+		Cancellations:        report.Cancellations,
+		NetRevenue:           report.NetRevenue,
+		RecognisedRevenue:    report.RecognisedRevenue,
+		TotalRevenue:         report.TotalRevenue,
+		AverageOrderValue:    report.AverageOrderValue,
+		UniqueCustomers:      report.UniqueCustomers,
+		NewCustomers:         report.NewCustomers,
+		TopProducts:          make([]TopProductDTO, len(report.TopProducts)),
+		LowStockProducts:     make([]LowStockAlert, len(report.LowStockProducts)),
+		OrderFulfillmentRate: report.OrderFulfillmentRate,
+		CancellationRate:     report.CancellationRate,
+	}
+	for i, product := range report.TopProducts {
+		resp.TopProducts[i] = TopProductDTO{
+			ProductID:     product.ProductID,
+			ProductName:   product.ProductName,
+			QuantitySold:  product.QuantitySold,
+			Revenue:       product.Revenue,
+			StockTurnover: product.StockTurnover,
+		}
+	}
+	for i, alert := range report.LowStockProducts {
+		resp.LowStockProducts[i] = LowStockAlert{
+			ProductID:     alert.ProductID,
+			ProductName:   alert.ProductName,
+			CurrentStock:  alert.CurrentStock,
+			ReservedStock: alert.ReservedStock,
+			ReorderPoint:  alert.ReorderPoint,
+		}
+	}
+	return resp
+}
+
+// ReportExportResponse represents a report export job. The download URL is signed and
+// only given once the file is ready.
+type ReportExportResponse struct {
+	ID                uint       `json:"id"`
+	Status            string     `json:"status"` // pending, running, completed or failed
+	From              time.Time  `json:"from"`
+	To                time.Time  `json:"to"`
+	Granularity       string     `json:"granularity"`
+	Format            string     `json:"format"`
+	FileName          string     `json:"file_name,omitempty"`
+	Error             string     `json:"error,omitempty"`
+	DownloadURL       string     `json:"download_url,omitempty"`
+	DownloadExpiresAt *time.Time `json:"download_expires_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	CompletedAt       *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt         *time.Time `json:"expires_at,omitempty"` // When the file is deleted
+}
+
+// ReportDownloadQuery represents the signed query parameters of a report download link
+type ReportDownloadQuery struct {
+	Expires   int64  `query:"expires" validate:"required"` // Unix time the link expires at
+	Signature string `query:"signature" validate:"required,hexadecimal"`
 }
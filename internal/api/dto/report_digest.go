@@ -0,0 +1,75 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+)
+
+// CreateReportDigestRequest represents a request to email an admin a report digest
+type CreateReportDigestRequest struct {
+	UserID    uint   `json:"user_id"` // Admin to email; defaults to the requesting admin
+	Frequency string `json:"frequency" validate:"required,oneof=daily weekly"`
+}
+
+// UpdateReportDigestRequest represents a request to pause or resume a report digest
+type UpdateReportDigestRequest struct {
+	Active *bool `json:"active" validate:"required"`
+}
+
+// ReportDigestDeliveryResponse represents the email of a digest for one period
+type ReportDigestDeliveryResponse struct {
+	ID          uint       `json:"id"`
+	PeriodStart time.Time  `json:"period_start"`
+	PeriodEnd   time.Time  `json:"period_end"`
+	Email       string     `json:"email"`
+	Status      string     `json:"status"` // pending, sent or failed
+	Attempts    int        `json:"attempts"`
+	LastError   string     `json:"last_error,omitempty"`
+	SentAt      *time.Time `json:"sent_at,omitempty"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// ReportDigestSubscriptionResponse represents an admin's report digest and its latest delivery
+type ReportDigestSubscriptionResponse struct {
+	ID           uint                          `json:"id"`
+	UserID       uint                          `json:"user_id"`
+	Email        string                        `json:"email"`
+	Frequency    string                        `json:"frequency"`
+	Active       bool                          `json:"active"`
+	LastDelivery *ReportDigestDeliveryResponse `json:"last_delivery,omitempty"`
+	CreatedAt    time.Time                     `json:"created_at"`
+}
+
+// ReportDigestDeliveryToResponse converts a ReportDigestDelivery model to a ReportDigestDeliveryResponse DTO
+func ReportDigestDeliveryToResponse(delivery *models.ReportDigestDelivery) ReportDigestDeliveryResponse {
+	return ReportDigestDeliveryResponse{
+		ID:          delivery.ID,
+		PeriodStart: delivery.PeriodStart,
+		PeriodEnd:   delivery.PeriodEnd,
+		Email:       delivery.Email,
+		Status:      string(delivery.Status),
+		Attempts:    delivery.Attempts,
+		LastError:   delivery.LastError,
+		SentAt:      delivery.SentAt,
+		UpdatedAt:   delivery.UpdatedAt,
+	}
+}
+
+// ReportDigestSubscriptionToResponse converts a ReportDigestSubscription model, with its
+// user loaded, to a ReportDigestSubscriptionResponse DTO
+func ReportDigestSubscriptionToResponse(subscription *models.ReportDigestSubscription, lastDelivery *models.ReportDigestDelivery) ReportDigestSubscriptionResponse {
+	resp := ReportDigestSubscriptionResponse{
+		ID:        subscription.ID,
+		UserID:    subscription.UserID,
+		Email:     subscription.User.Email,
+		Frequency: string(subscription.Frequency),
+		Active:    subscription.Active,
+		CreatedAt: subscription.CreatedAt,
+	}
+	if lastDelivery != nil {
+		delivery := ReportDigestDeliveryToResponse(lastDelivery)
+		resp.LastDelivery = &delivery
+	}
+	return resp
+}
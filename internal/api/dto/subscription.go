@@ -0,0 +1,77 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+)
+
+// SubscriptionItemRequest represents a product and quantity ordered on every renewal
+type SubscriptionItemRequest struct {
+	ProductID uint `json:"product_id" validate:"required"`
+	Quantity  int  `json:"quantity" validate:"required,gt=0"`
+}
+
+// CreateSubscriptionRequest represents a request to subscribe to recurring orders
+type CreateSubscriptionRequest struct {
+	Items              []SubscriptionItemRequest `json:"items" validate:"required,min=1,max=50,dive"`
+	Cadence            string                    `json:"cadence" validate:"required,oneof=weekly biweekly monthly"`
+	StartsAt           *time.Time                `json:"starts_at,omitempty"` // First renewal; defaults to now
+	ShippingAddress    *Address                  `json:"shipping_address,omitempty"`
+	PaymentMethodToken string                    `json:"payment_method_token" validate:"required,max=100"` // Stored payment method to charge
+}
+
+// SubscriptionItemResponse represents a product and quantity of a subscription
+type SubscriptionItemResponse struct {
+	ProductID uint   `json:"product_id"`
+	Name      string `json:"name,omitempty"`
+	Quantity  int    `json:"quantity"`
+}
+
+// SubscriptionResponse represents a subscription and the state of its renewals
+type SubscriptionResponse struct {
+	ID                uint                       `json:"id"`
+	Status            string                     `json:"status"`
+	Cadence           string                     `json:"cadence"`
+	Currency          string                     `json:"currency"`
+	Items             []SubscriptionItemResponse `json:"items"`
+	ShippingAddress   Address                    `json:"shipping_address"`
+	NextRenewalAt     time.Time                  `json:"next_renewal_at"`
+	RetryAt           *time.Time                 `json:"retry_at,omitempty"` // When a failed renewal is tried again
+	FailureCount      int                        `json:"failure_count,omitempty"`
+	LastFailureReason string                     `json:"last_failure_reason,omitempty"`
+	LastOrderID       *uint                      `json:"last_order_id,omitempty"`
+	LastRenewedAt     *time.Time                 `json:"last_renewed_at,omitempty"`
+	PausedAt          *time.Time                 `json:"paused_at,omitempty"`
+	CancelledAt       *time.Time                 `json:"cancelled_at,omitempty"`
+	CreatedAt         time.Time                  `json:"created_at"`
+}
+
+// SubscriptionToResponse converts a Subscription model to a SubscriptionResponse DTO
+func SubscriptionToResponse(subscription *models.Subscription) SubscriptionResponse {
+	resp := SubscriptionResponse{
+		ID:                subscription.ID,
+		Status:            string(subscription.Status),
+		Cadence:           string(subscription.Cadence),
+		Currency:          string(subscription.Currency),
+		Items:             make([]SubscriptionItemResponse, len(subscription.Items)),
+		ShippingAddress:   AddressFromModel(subscription.ShippingAddress),
+		NextRenewalAt:     subscription.NextRenewalAt,
+		RetryAt:           subscription.RetryAt,
+		FailureCount:      subscription.FailureCount,
+		LastFailureReason: subscription.LastFailureReason,
+		LastOrderID:       subscription.LastOrderID,
+		LastRenewedAt:     subscription.LastRenewedAt,
+		PausedAt:          subscription.PausedAt,
+		CancelledAt:       subscription.CancelledAt,
+		CreatedAt:         subscription.CreatedAt,
+	}
+	for i, item := range subscription.Items {
+		resp.Items[i] = SubscriptionItemResponse{
+			ProductID: item.ProductID,
+			Name:      item.Product.Name,
+			Quantity:  item.Quantity,
+		}
+	}
+	return resp
+}
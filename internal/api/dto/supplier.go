@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+)
+
+// CreateSupplierRequest represents the request body for creating a supplier
+type CreateSupplierRequest struct {
+	Name         string `json:"name" validate:"required,min=2,max=100"`
+	Email        string `json:"email,omitempty" validate:"omitempty,email"`
+	Phone        string `json:"phone,omitempty" validate:"omitempty,max=50"`
+	LeadTimeDays int    `json:"lead_time_days" validate:"gte=0,lte=365"`
+}
+
+// UpdateSupplierRequest represents the request body for updating a supplier
+type UpdateSupplierRequest struct {
+	Name         *string `json:"name,omitempty" validate:"omitempty,min=2,max=100"`
+	Email        *string `json:"email,omitempty" validate:"omitempty,email"`
+	Phone        *string `json:"phone,omitempty" validate:"omitempty,max=50"`
+	LeadTimeDays *int    `json:"lead_time_days,omitempty" validate:"omitempty,gte=0,lte=365"`
+	Active       *bool   `json:"active,omitempty"`
+}
+
+// SupplierResponse represents a supplier in responses
+type SupplierResponse struct {
+	ID           uint   `json:"id"`
+	Name         string `json:"name"`
+	Email        string `json:"email,omitempty"`
+	Phone        string `json:"phone,omitempty"`
+	LeadTimeDays int    `json:"lead_time_days"`
+	Active       bool   `json:"active"`
+}
+
+// SupplierToResponse converts a Supplier model to a SupplierResponse DTO
+func SupplierToResponse(supplier *models.Supplier) SupplierResponse {
+	return SupplierResponse{
+		ID:           supplier.ID,
+		Name:         supplier.Name,
+		Email:        supplier.Email,
+		Phone:        supplier.Phone,
+		LeadTimeDays: supplier.LeadTimeDays,
+		Active:       supplier.Active,
+	}
+}
@@ -28,6 +28,7 @@ type UserProfileResponse struct {
 	LastName  string         `json:"last_name"`
 	Role      models.UserRole `json:"role"`
 	Active    bool           `json:"active"`
+	PreferredCurrency string `json:"preferred_currency,omitempty"`
 }
 
 // UpdateUserProfileRequest represents the request body for updating a user profile
@@ -36,4 +37,5 @@ type UpdateUserProfileRequest struct {
 	Password  *string `json:"password" validate:"omitempty,min=8,max=100"`
 	FirstName *string `json:"first_name" validate:"omitempty,min=2,max=50"`
 	LastName  *string `json:"last_name" validate:"omitempty,min=2,max=50"`
+	PreferredCurrency *string `json:"preferred_currency" validate:"omitempty,len=3"` // Used for orders that don't send a currency
 }
@@ -0,0 +1,70 @@
+package dto
+
+import (
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+)
+
+// CreateWarehouseRequest represents the request body for creating a warehouse
+type CreateWarehouseRequest struct {
+	Code      string  `json:"code" validate:"required,min=2,max=20"`
+	Name      string  `json:"name" validate:"required,min=2,max=100"`
+	Address   Address `json:"address"`
+	IsDefault bool    `json:"is_default"`
+	Active    *bool   `json:"active,omitempty"`
+}
+
+// UpdateWarehouseRequest represents the request body for updating a warehouse
+type UpdateWarehouseRequest struct {
+	Name      *string  `json:"name,omitempty" validate:"omitempty,min=2,max=100"`
+	Address   *Address `json:"address,omitempty"`
+	IsDefault *bool    `json:"is_default,omitempty"`
+	Active    *bool    `json:"active,omitempty"`
+}
+
+// WarehouseResponse represents a warehouse in responses
+type WarehouseResponse struct {
+	ID        uint    `json:"id"`
+	Code      string  `json:"code"`
+	Name      string  `json:"name"`
+	Address   Address `json:"address"`
+	IsDefault bool    `json:"is_default"`
+	Active    bool    `json:"active"`
+}
+
+// WarehouseInventoryResponse represents a product's stock in a single warehouse
+type WarehouseInventoryResponse struct {
+	WarehouseID   uint   `json:"warehouse_id"`
+	WarehouseCode string `json:"warehouse_code"`
+	WarehouseName string `json:"warehouse_name"`
+	StockLevel    int    `json:"stock_level"`
+	Reserved      int    `json:"reserved"`
+	MinimumStock  int    `json:"minimum_stock"`
+}
+
+// StockTransferRequest represents a transfer of stock between two warehouses
+type StockTransferRequest struct {
+	ProductID       uint   `json:"product_id" validate:"required"`
+	FromWarehouseID uint   `json:"from_warehouse_id" validate:"required"`
+	ToWarehouseID   uint   `json:"to_warehouse_id" validate:"required,nefield=FromWarehouseID"`
+	Quantity        int    `json:"quantity" validate:"required,gt=0"`
+	Reference       string `json:"reference,omitempty" validate:"omitempty,max=100"`
+	Note            string `json:"note,omitempty" validate:"omitempty,max=500"`
+}
+
+// StockTransferResponse represents the pair of ledger entries created by a transfer
+type StockTransferResponse struct {
+	Reference string                  `json:"reference"`
+	Movements []StockMovementResponse `json:"movements"`
+}
+
+// WarehouseToResponse converts a Warehouse model to a WarehouseResponse DTO
+func WarehouseToResponse(warehouse *models.Warehouse) WarehouseResponse {
+	return WarehouseResponse{
+		ID:        warehouse.ID,
+		Code:      warehouse.Code,
+		Name:      warehouse.Name,
+		Address:   AddressFromModel(warehouse.Address),
+		IsDefault: warehouse.IsDefault,
+		Active:    warehouse.Active,
+	}
+}
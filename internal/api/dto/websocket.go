@@ -0,0 +1,8 @@
+package dto
+
+// UserPresenceResponse represents whether a user is connected over WebSocket to any
+// instance of the API
+type UserPresenceResponse struct {
+	UserID uint `json:"user_id"`
+	Online bool `json:"online"`
+}
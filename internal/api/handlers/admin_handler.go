@@ -9,80 +9,90 @@ import (
 	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
 	"github.com/Ahmed1monm/backend-golang-task-2025/internal/service"
 	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/errors"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/export"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/validator"
 	"github.com/labstack/echo/v4"
 )
 
 type AdminHandler struct {
-	orderService  *service.OrderService
-	reportService *service.ReportService
+	orderService        *service.OrderService
+	reportService       *service.ReportService
+	reportExportService service.ReportExportService
+	lowStockService     service.LowStockService
 }
 
-func NewAdminHandler(orderService *service.OrderService, reportService *service.ReportService) *AdminHandler {
+func NewAdminHandler(orderService *service.OrderService, reportService *service.ReportService, reportExportService service.ReportExportService, lowStockService service.LowStockService) *AdminHandler {
 	return &AdminHandler{
-		orderService:  orderService,
-		reportService: reportService,
+		orderService:        orderService,
+		reportService:       reportService,
+		reportExportService: reportExportService,
+		lowStockService:     lowStockService,
 	}
 }
 
 // ListAllOrders godoc
-// @Summary List all orders (admin only)
-// @Description Get a paginated list of all orders in the system
+// @Summary Search all orders (admin only)
+// @Description Get a paginated list of all orders in the system with their customers, filtered and sorted by any of the search fields
 // @Tags admin,orders
 // @Accept json
 // @Produce json
 // @Param page query int false "Page number (default: 1)"
 // @Param per_page query int false "Items per page (default: 10)"
-// @Success 200 {object} dto.PaginatedOrdersResponse
+// @Param order_id query int false "Order ID"
+// @Param customer query string false "Part of the customer's email or name"
+// @Param status query []string false "Order statuses to include" collectionFormat(multi)
+// @Param from query string false "Created at or after (RFC 3339 time or YYYY-MM-DD)"
+// @Param to query string false "Created before (RFC 3339 time) or on (YYYY-MM-DD)"
+// @Param min_total query number false "Minimum total in the base currency"
+// @Param max_total query number false "Maximum total in the base currency"
+// @Param payment_status query []string false "Payment statuses to include" collectionFormat(multi)
+// @Param sku query string false "Only orders containing the product with this SKU"
+// @Param sort query string false "Sort field: id, created_at (default), status, total, customer_email, customer_name, payment_status or sku"
+// @Param order query string false "Sort direction: asc or desc (default)"
+// @Success 200 {object} dto.PaginatedAdminOrdersResponse
+// @Failure 400 {object} errors.AppError
 // @Failure 401 {object} errors.AppError
 // @Failure 403 {object} errors.AppError
 // @Failure 500 {object} errors.AppError
 // @Router /admin/orders [get]
 // @Security BearerAuth
 func (h *AdminHandler) ListAllOrders(c echo.Context) error {
-	// Parse pagination parameters with defaults
-	page, _ := strconv.Atoi(c.QueryParam("page"))
-	perPage, _ := strconv.Atoi(c.QueryParam("per_page"))
+	var query dto.AdminOrdersQuery
+	if err := c.Bind(&query); err != nil {
+		return errors.NewValidationError("Invalid query parameters", nil, http.StatusBadRequest)
+	}
 
-	if page < 1 {
-		page = 1
+	// Fall back to defaults for missing or out of range pagination
+	if query.Page < 1 {
+		query.Page = 1
 	}
-	if perPage < 1 || perPage > 100 {
-		perPage = 10
+	if query.PerPage < 1 || query.PerPage > 100 {
+		query.PerPage = 10
+	}
+
+	if errs := validator.Validate(query); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"errors": errs})
 	}
 
 	// Get orders from service
-	orders, total, err := h.orderService.ListAllOrders(c.Request().Context(), page, perPage)
+	orders, total, err := h.orderService.ListAllOrders(c.Request().Context(), &query)
 	if err != nil {
-		return errors.NewServerError("Failed to list orders", err, http.StatusInternalServerError)
+		return handleServiceError(err, "Failed to list orders")
 	}
 
 	// Convert to response DTOs
-	responses := make([]dto.OrderResponse, len(orders))
-	for i, order := range orders {
-		responses[i] = dto.OrderResponse{
-			ID:          order.ID,
-			UserID:      order.UserID,
-			TotalAmount: order.TotalAmount,
-			Status:      string(order.Status),
-			Items:       make([]dto.OrderItemResponse, len(order.OrderItems)),
-		}
-
-		for j, item := range order.OrderItems {
-			responses[i].Items[j] = dto.OrderItemResponse{
-				ProductID: item.ProductID,
-				Quantity:  item.Quantity,
-				Price:     item.Price,
-			}
-		}
+	resp := dto.PaginatedAdminOrdersResponse{
+		Orders:     make([]dto.AdminOrderResponse, len(orders)),
+		Total:      total,
+		Page:       query.Page,
+		PerPage:    query.PerPage,
+		TotalPages: (int(total) + query.PerPage - 1) / query.PerPage,
+	}
+	for i := range orders {
+		resp.Orders[i] = dto.AdminOrderToResponse(&orders[i])
 	}
 
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"orders":      responses,
-		"total":       total,
-		"page":        page,
-		"per_page":    perPage,
-		"total_pages": (int(total) + perPage - 1) / perPage,
-	})
+	return c.JSON(http.StatusOK, resp)
 }
 
 // UpdateOrderStatus godoc
@@ -118,76 +128,270 @@ func (h *AdminHandler) UpdateOrderStatus(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
 
+	// Get authenticated admin ID from context
+	actorID := c.Get("user_id").(uint)
+
 	// Update order status
-	order, err := h.orderService.UpdateOrderStatus(c.Request().Context(), uint(orderID), models.OrderStatus(req.Status))
+	order, err := h.orderService.UpdateOrderStatus(c.Request().Context(), uint(orderID), actorID, models.OrderStatus(req.Status), req.Reason)
 	if err != nil {
 		// Check for validation error
 		if verr, ok := err.(*errors.ValidationError); ok {
 			return c.JSON(http.StatusBadRequest, map[string]string{"error": verr.Error()})
 		}
+		if berr, ok := err.(*errors.BusinessError); ok {
+			return c.JSON(berr.StatusCode, map[string]string{"error": berr.Error()})
+		}
 		// Handle other errors
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to update order status"})
 	}
 
 	// Convert to response DTO
-	resp := &dto.AdminOrderResponse{
-		ID:          order.ID,
-		UserID:      order.UserID,
-		Status:      string(order.Status),
-		TotalAmount: order.TotalAmount,
-		CreatedAt:   order.CreatedAt.Format("2006-01-02 15:04:05"),
-		UpdatedAt:   order.UpdatedAt.Format("2006-01-02 15:04:05"),
-	}
-
-	// Convert order items
-	resp.Items = make([]dto.OrderItemResponse, len(order.OrderItems))
-	for i, item := range order.OrderItems {
-		resp.Items[i] = dto.OrderItemResponse{
-			ProductID: item.ProductID,
-			Quantity:  item.Quantity,
-			Price:     item.Price,
+	resp := dto.AdminOrderToResponse(order)
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// AdjustOrderItems godoc
+// @Summary Reduce or remove order items (admin only)
+// @Description Lower the quantities of items of any order, or remove them with a quantity of 0, while the order
+// @Description is pending or processing. The removed units are released back to stock, the total is recomputed
+// @Description and the difference is refunded if the order was paid. Orders placed at checkout
+// @Description aren't charged, so only their total changes.
+// @Tags admin,orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param request body dto.AdjustOrderItemsRequest true "New item quantities"
+// @Success 200 {object} dto.AdminOrderResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/orders/{id}/items [patch]
+// @Security BearerAuth
+func (h *AdminHandler) AdjustOrderItems(c echo.Context) error {
+	orderID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid order ID"})
+	}
+
+	var req dto.AdjustOrderItemsRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request body"})
+	}
+	if errs := validator.Validate(req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"errors": errs})
+	}
+
+	input := adjustOrderInput(&req)
+	input.ActorID = c.Get("user_id").(uint)
+
+	order, err := h.orderService.AdjustOrderItems(c.Request().Context(), uint(orderID), input)
+	if err != nil {
+		if verr, ok := err.(*errors.ValidationError); ok {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": verr.Error()})
 		}
+		if berr, ok := err.(*errors.BusinessError); ok {
+			return c.JSON(berr.StatusCode, map[string]string{"error": berr.Error()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to update order items"})
+	}
+
+	return c.JSON(http.StatusOK, dto.AdminOrderToResponse(order))
+}
+
+// BulkOrderAction godoc
+// @Summary Apply an action to many orders (admin only)
+// @Description Mark orders processing or shipped, or cancel them, picked by ID or by the order search filter. Each order is checked and processed independently and gets its own result.
+// @Tags admin,orders
+// @Accept json
+// @Produce json
+// @Param request body dto.BulkOrderActionRequest true "Action and orders"
+// @Success 200 {object} dto.BulkOrderActionResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/orders/bulk [post]
+// @Security BearerAuth
+func (h *AdminHandler) BulkOrderAction(c echo.Context) error {
+	req := new(dto.BulkOrderActionRequest)
+	if err := c.Bind(req); err != nil {
+		return errors.NewValidationError("Invalid request body", nil, http.StatusBadRequest)
+	}
+
+	if errs := validator.Validate(req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"errors": errs})
+	}
+
+	actorID := c.Get("user_id").(uint)
+
+	resp, err := h.orderService.BulkOrderAction(c.Request().Context(), actorID, req)
+	if err != nil {
+		return handleServiceError(err, "Failed to apply bulk order action")
 	}
 
 	return c.JSON(http.StatusOK, resp)
 }
 
 // GetDailySalesReport godoc
-// @Summary Get today's sales report
-// @Description Get the daily sales report for today. Returns an empty report if not yet generated.
+// @Summary Get a daily sales report
+// @Description Get the sales report of a day, today by default. A missing report of a past day is generated on demand; today's report is computed live until the day is over.
+// @Description The report is returned as JSON, or as a CSV, XLSX or PDF file picked by the format parameter or else the Accept header.
 // @Tags admin,reports
 // @Accept json
-// @Produce json
+// @Produce json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
+// @Param date query string false "Day of the report (YYYY-MM-DD, UTC)"
+// @Param format query string false "File format, overriding the Accept header" Enums(json, csv, xlsx, pdf)
 // @Success 200 {object} dto.DailySalesReportResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 406 {object} errors.AppError
 // @Failure 500 {object} errors.AppError
 // @Router /admin/reports/daily [get]
 // @Security BearerAuth
 func (h *AdminHandler) GetDailySalesReport(c echo.Context) error {
-	ctx := c.Request().Context()
-	today := time.Now().UTC().Truncate(24 * time.Hour)
+	var query dto.DailyReportQuery
+	if err := c.Bind(&query); err != nil {
+		return errors.NewValidationError("Invalid query parameters", nil, http.StatusBadRequest)
+	}
+
+	if errs := validator.Validate(query); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"errors": errs})
+	}
+
+	format, err := reportFormat(c)
+	if err != nil {
+		return err
+	}
+
+	date := time.Now().UTC().Truncate(24 * time.Hour)
+	if query.Date != "" {
+		date, _ = time.Parse(time.DateOnly, query.Date)
+	}
+
+	if format != export.FormatJSON {
+		file, err := h.reportExportService.ExportDailyReport(c.Request().Context(), date, format)
+		if err != nil {
+			return handleServiceError(err, "Failed to export daily report")
+		}
+		return sendReportFile(c, file)
+	}
 
-	// Get today's report from service
-	report, err := h.reportService.GetDailyReport(ctx, today)
+	report, err := h.reportService.GetDailyReport(c.Request().Context(), date)
 	if err != nil {
-		return errors.NewServerError("Failed to get daily report", err, http.StatusInternalServerError)
+		return handleServiceError(err, "Failed to get daily report")
 	}
 
-	return c.JSON(http.StatusOK, report)
+	return c.JSON(http.StatusOK, dto.DailySalesReportToResponse(report))
+}
+
+// GetSalesReportRange godoc
+// @Summary Get sales over a date range
+// @Description Aggregate the daily sales reports of a date range of up to 366 days into day, week or month periods.
+// @Description Every period is compared with the one before it, and the totals with the same number of days right before the range.
+// @Description The report is returned as JSON, or as a CSV, XLSX or PDF file picked by the format parameter or else the Accept header.
+// @Description Files of long ranges are rendered in the background: the export job is returned with 202, to be polled until its download link is ready.
+// @Tags admin,reports
+// @Accept json
+// @Produce json,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
+// @Param from query string true "First day (YYYY-MM-DD, UTC)"
+// @Param to query string true "Last day, inclusive (YYYY-MM-DD, UTC)"
+// @Param granularity query string false "Period length" Enums(day, week, month) default(day)
+// @Param format query string false "File format, overriding the Accept header" Enums(json, csv, xlsx, pdf)
+// @Success 200 {object} dto.SalesReportRangeResponse
+// @Success 202 {object} dto.ReportExportResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 406 {object} errors.AppError
+// @Failure 409 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/reports/range [get]
+// @Security BearerAuth
+func (h *AdminHandler) GetSalesReportRange(c echo.Context) error {
+	var query dto.SalesReportRangeQuery
+	if err := c.Bind(&query); err != nil {
+		return errors.NewValidationError("Invalid query parameters", nil, http.StatusBadRequest)
+	}
+
+	if errs := validator.Validate(query); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"errors": errs})
+	}
+
+	format, err := reportFormat(c)
+	if err != nil {
+		return err
+	}
+
+	if format != export.FormatJSON {
+		actorID := c.Get("user_id").(uint)
+		file, job, err := h.reportExportService.ExportRangeReport(c.Request().Context(), actorID, &query, format)
+		if err != nil {
+			return handleServiceError(err, "Failed to export sales report")
+		}
+		if job != nil {
+			return c.JSON(http.StatusAccepted, job)
+		}
+		return sendReportFile(c, file)
+	}
+
+	resp, err := h.reportService.GetRangeReport(c.Request().Context(), &query)
+	if err != nil {
+		return handleServiceError(err, "Failed to get sales report")
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GenerateReports godoc
+// @Summary Generate past daily sales reports
+// @Description Generate and store the daily sales reports of completed days, up to 366 at a time, replacing stored reports unless only_missing is set.
+// @Description Days another instance is generating at the same time are skipped.
+// @Tags admin,reports
+// @Accept json
+// @Produce json
+// @Param request body dto.GenerateReportsRequest true "Days to generate"
+// @Success 200 {object} dto.ReportGenerationResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/reports/generate [post]
+// @Security BearerAuth
+func (h *AdminHandler) GenerateReports(c echo.Context) error {
+	req := new(dto.GenerateReportsRequest)
+	if err := c.Bind(req); err != nil {
+		return errors.NewValidationError("Invalid request body", nil, http.StatusBadRequest)
+	}
+
+	if errs := validator.Validate(req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"errors": errs})
+	}
+
+	from, _ := time.Parse(time.DateOnly, req.From)
+	to, _ := time.Parse(time.DateOnly, req.To)
+	resp, err := h.reportService.GenerateReports(c.Request().Context(), from, to, req.OnlyMissing)
+	if err != nil {
+		return handleServiceError(err, "Failed to generate reports")
+	}
+
+	return c.JSON(http.StatusOK, resp)
 }
 
 // GetLowStockAlerts godoc
 // @Summary Get low stock alerts (admin only)
-// @Description Get a list of products with low stock levels that require attention
+// @Description Get the open low stock alerts, one per product and warehouse whose available stock is at or below its minimum, with suggested reorder quantities based on recent sales velocity
 // @Tags admin,inventory
 // @Accept json
 // @Produce json
 // @Success 200 {array} dto.LowStockAlertResponse
-// @Failure 404 {object} errors.AppError
 // @Failure 401 {object} errors.AppError
 // @Failure 403 {object} errors.AppError
 // @Failure 500 {object} errors.AppError
 // @Router /admin/inventory/low-stock [get]
 // @Security BearerAuth
 func (h *AdminHandler) GetLowStockAlerts(c echo.Context) error {
-	return errors.NewBusinessError("No low stock items found", "NO_LOW_STOCK", http.StatusNotFound)
+	alerts, err := h.lowStockService.ListAlerts(c.Request().Context())
+	if err != nil {
+		return errors.NewServerError("Failed to get low stock alerts", err, http.StatusInternalServerError)
+	}
+
+	return c.JSON(http.StatusOK, alerts)
 }
@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/api/dto"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/service"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/errors"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/validator"
+	"github.com/labstack/echo/v4"
+)
+
+type AnalyticsHandler struct {
+	customerAnalyticsService service.CustomerAnalyticsService
+	productAnalyticsService  service.ProductAnalyticsService
+}
+
+func NewAnalyticsHandler(customerAnalyticsService service.CustomerAnalyticsService, productAnalyticsService service.ProductAnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		customerAnalyticsService: customerAnalyticsService,
+		productAnalyticsService:  productAnalyticsService,
+	}
+}
+
+// GetCustomerAnalytics godoc
+// @Summary Get customer analytics
+// @Description Get the repeat purchase rate, average lifetime value, monthly acquisition cohorts with their retention curves, and RFM segments of customers.
+// @Description Figures are computed every night from the orders that weren't cancelled, with amounts in the reporting currency at the rate of the day they were computed.
+// @Tags admin,analytics
+// @Accept json
+// @Produce json
+// @Param months query int false "Latest cohorts to include (default: 12, max: 120)"
+// @Success 200 {object} dto.CustomerAnalyticsResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/analytics/customers [get]
+// @Security BearerAuth
+func (h *AnalyticsHandler) GetCustomerAnalytics(c echo.Context) error {
+	var query dto.CustomerAnalyticsQuery
+	if err := c.Bind(&query); err != nil {
+		return errors.NewValidationError("Invalid query parameters", nil, http.StatusBadRequest)
+	}
+
+	if errs := validator.Validate(query); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"errors": errs})
+	}
+
+	resp, err := h.customerAnalyticsService.GetCustomerAnalytics(c.Request().Context(), &query)
+	if err != nil {
+		return handleServiceError(err, "Failed to get customer analytics")
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// ListCustomerRFM godoc
+// @Summary List customers' RFM scores
+// @Description Get a page of customers with their recency, frequency and monetary scores from 1 to 5 and their segment, the most valuable customers first
+// @Tags admin,analytics
+// @Accept json
+// @Produce json
+// @Param segment query string false "Only customers of this segment" Enums(champions, loyal, new, promising, at_risk, hibernating, lost)
+// @Param page query int false "Page number (default: 1)"
+// @Param per_page query int false "Items per page (default: 10)"
+// @Success 200 {object} dto.PaginatedCustomerRFMResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/analytics/customers/rfm [get]
+// @Security BearerAuth
+func (h *AnalyticsHandler) ListCustomerRFM(c echo.Context) error {
+	var query dto.CustomerRFMQuery
+	if err := c.Bind(&query); err != nil {
+		return errors.NewValidationError("Invalid query parameters", nil, http.StatusBadRequest)
+	}
+
+	// Fall back to defaults for missing or out of range pagination
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.PerPage < 1 || query.PerPage > 100 {
+		query.PerPage = 10
+	}
+
+	if errs := validator.Validate(query); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"errors": errs})
+	}
+
+	resp, err := h.customerAnalyticsService.ListCustomerRFM(c.Request().Context(), &query)
+	if err != nil {
+		return handleServiceError(err, "Failed to list customer scores")
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// ListProductPerformance godoc
+// @Summary Rank products by performance
+// @Description Rank products by their sales over the last completed days, up to yesterday, with their sell-through rate and days of cover at the current stock.
+// @Description Sell-through is the units sold as a percentage of the units sold and still in stock. Days of cover is the stock divided by the average daily sales; a product in stock that sold nothing has no days of cover and sorts as if it had infinite cover.
+// @Description A slow mover is a product in stock whose days of cover exceed slow_mover_days. Revenue is in the reporting currency at today's rate.
+// @Tags admin,analytics
+// @Accept json
+// @Produce json
+// @Param days query int false "Completed days to cover (default: 30, max: 366)"
+// @Param slow_movers query bool false "Only slow movers, sorted by days of cover unless asked otherwise"
+// @Param sort query string false "Sort field (default: units_sold)" Enums(units_sold, revenue, orders, cancellation_rate, sell_through, days_of_cover, stock)
+// @Param order query string false "Sort direction (default: desc)" Enums(asc, desc)
+// @Param page query int false "Page number (default: 1)"
+// @Param per_page query int false "Items per page (default: 10)"
+// @Success 200 {object} dto.PaginatedProductPerformanceResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/analytics/products [get]
+// @Security BearerAuth
+func (h *AnalyticsHandler) ListProductPerformance(c echo.Context) error {
+	var query dto.ProductAnalyticsListQuery
+	if err := c.Bind(&query); err != nil {
+		return errors.NewValidationError("Invalid query parameters", nil, http.StatusBadRequest)
+	}
+
+	// Fall back to defaults for missing or out of range pagination
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.PerPage < 1 || query.PerPage > 100 {
+		query.PerPage = 10
+	}
+
+	if errs := validator.Validate(query); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"errors": errs})
+	}
+
+	resp, err := h.productAnalyticsService.ListProductPerformance(c.Request().Context(), &query)
+	if err != nil {
+		return handleServiceError(err, "Failed to list product performance")
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetProductAnalytics godoc
+// @Summary Get a product's performance
+// @Description Get a product's units ordered, sold and cancelled and its revenue over the last completed days, up to yesterday, by day, ISO week or month, with its sell-through rate and days of cover at the current stock.
+// @Description Units are counted against the day they were ordered, including those cancelled later. Revenue is in the reporting currency at today's rate.
+// @Tags admin,analytics
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param days query int false "Completed days to cover (default: 30, max: 366)"
+// @Param granularity query string false "Period to break sales down into (default: day)" Enums(day, week, month)
+// @Success 200 {object} dto.ProductAnalyticsResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/analytics/products/{id} [get]
+// @Security BearerAuth
+func (h *AnalyticsHandler) GetProductAnalytics(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return errors.NewValidationError("Invalid product ID", nil, http.StatusBadRequest)
+	}
+
+	var query dto.ProductAnalyticsQuery
+	if err := c.Bind(&query); err != nil {
+		return errors.NewValidationError("Invalid query parameters", nil, http.StatusBadRequest)
+	}
+
+	if errs := validator.Validate(query); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"errors": errs})
+	}
+
+	resp, err := h.productAnalyticsService.GetProductAnalytics(c.Request().Context(), uint(id), &query)
+	if err != nil {
+		return handleServiceError(err, "Failed to get product analytics")
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/api/dto"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/api/middleware"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/service"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/errors"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/validator"
+	"github.com/labstack/echo/v4"
+)
+
+type CartHandler struct {
+	cartService  service.CartService
+	orderService *service.OrderService
+}
+
+func NewCartHandler(cartService service.CartService, orderService *service.OrderService) *CartHandler {
+	return &CartHandler{cartService: cartService, orderService: orderService}
+}
+
+// GetCart godoc
+// @Summary Get the cart
+// @Description Get the authenticated user's cart at current prices
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Param X-Currency header string false "Currency to price the cart in"
+// @Success 200 {object} dto.CartResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /cart [get]
+// @Security BearerAuth
+func (h *CartHandler) GetCart(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+
+	resp, err := h.cartService.GetCart(c.Request().Context(), userID, c.Request().Header.Get(middleware.CurrencyHeader))
+	if err != nil {
+		return handleServiceError(err, "Failed to get cart")
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// SetCartItem godoc
+// @Summary Set a cart item
+// @Description Set the quantity of a product in the authenticated user's cart; a quantity of 0 removes it
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Param X-Currency header string false "Currency to price the cart in"
+// @Param request body dto.SetCartItemRequest true "Product and quantity"
+// @Success 200 {object} dto.CartResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /cart/items [put]
+// @Security BearerAuth
+func (h *CartHandler) SetCartItem(c echo.Context) error {
+	req := new(dto.SetCartItemRequest)
+	if err := c.Bind(req); err != nil {
+		return errors.NewValidationError("Invalid request body", nil, http.StatusBadRequest)
+	}
+
+	if errs := validator.Validate(req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"errors": errs})
+	}
+
+	ctx := c.Request().Context()
+	userID := c.Get("user_id").(uint)
+	if err := h.cartService.SetItem(ctx, userID, req); err != nil {
+		return handleServiceError(err, "Failed to update cart")
+	}
+
+	resp, err := h.cartService.GetCart(ctx, userID, c.Request().Header.Get(middleware.CurrencyHeader))
+	if err != nil {
+		return handleServiceError(err, "Failed to get cart")
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// ClearCart godoc
+// @Summary Clear the cart
+// @Description Remove every item from the authenticated user's cart
+// @Tags cart
+// @Success 204
+// @Failure 401 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /cart [delete]
+// @Security BearerAuth
+func (h *CartHandler) ClearCart(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+
+	if err := h.cartService.ClearCart(c.Request().Context(), userID); err != nil {
+		return handleServiceError(err, "Failed to clear cart")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// Checkout godoc
+// @Summary Check out the cart
+// @Description Place an order for the items in the authenticated user's cart at current prices and empty the cart
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Param X-Currency header string false "Currency to charge the order in"
+// @Param request body dto.CheckoutCartRequest false "Checkout details"
+// @Success 201 {object} dto.OrderResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /cart/checkout [post]
+// @Security BearerAuth
+func (h *CartHandler) Checkout(c echo.Context) error {
+	req := new(dto.CheckoutCartRequest)
+	if err := c.Bind(req); err != nil {
+		return errors.NewValidationError("Invalid request body", nil, http.StatusBadRequest)
+	}
+
+	if errs := validator.Validate(req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"errors": errs})
+	}
+
+	input := service.CreateOrderInput{
+		Currency: c.Request().Header.Get(middleware.CurrencyHeader),
+	}
+	if req.ShippingAddress != nil {
+		input.ShippingAddress = req.ShippingAddress.ToModel()
+	}
+
+	userID := c.Get("user_id").(uint)
+	order, err := h.orderService.CheckoutCart(c.Request().Context(), userID, input)
+	if err != nil {
+		return handleServiceError(err, "Failed to check out cart")
+	}
+
+	return c.JSON(http.StatusCreated, dto.OrderToResponse(order))
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/api/dto"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/service"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/errors"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/logger"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/redis"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/validator"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type CurrencyHandler struct {
+	currencyService service.CurrencyService
+	redisService    redis.Service
+}
+
+func NewCurrencyHandler(currencyService service.CurrencyService, redisService redis.Service) *CurrencyHandler {
+	return &CurrencyHandler{
+		currencyService: currencyService,
+		redisService:    redisService,
+	}
+}
+
+// CreateExchangeRate godoc
+// @Summary Record an exchange rate (admin only)
+// @Description Record the rate from the base currency to a quote currency, effective from the given time (default now)
+// @Tags admin,currencies
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateExchangeRateRequest true "Exchange rate details"
+// @Success 201 {object} dto.ExchangeRateResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/exchange-rates [post]
+// @Security BearerAuth
+func (h *CurrencyHandler) CreateExchangeRate(c echo.Context) error {
+	req := new(dto.CreateExchangeRateRequest)
+	if err := c.Bind(req); err != nil {
+		return errors.NewValidationError("Invalid request body", nil, http.StatusBadRequest)
+	}
+
+	if errs := validator.Validate(req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"errors": errs})
+	}
+
+	resp, err := h.currencyService.CreateExchangeRate(c.Request().Context(), req)
+	if err != nil {
+		return handleServiceError(err, "Failed to create exchange rate")
+	}
+
+	h.invalidateProductCache()
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// ListExchangeRates godoc
+// @Summary List exchange rates (admin only)
+// @Description Get the recorded exchange rates, newest first, optionally filtered by currency pair
+// @Tags admin,currencies
+// @Accept json
+// @Produce json
+// @Param base query string false "Base currency code"
+// @Param quote query string false "Quote currency code"
+// @Success 200 {array} dto.ExchangeRateResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/exchange-rates [get]
+// @Security BearerAuth
+func (h *CurrencyHandler) ListExchangeRates(c echo.Context) error {
+	resp, err := h.currencyService.ListExchangeRates(c.Request().Context(), c.QueryParam("base"), c.QueryParam("quote"))
+	if err != nil {
+		return handleServiceError(err, "Failed to list exchange rates")
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// ListProductPrices godoc
+// @Summary List a product's price list (admin only)
+// @Description Get the explicit prices set for a product in non-base currencies
+// @Tags admin,currencies
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {array} dto.ProductPriceResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/products/{id}/prices [get]
+// @Security BearerAuth
+func (h *CurrencyHandler) ListProductPrices(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return errors.NewValidationError("Invalid product ID", map[string]string{"id": "must be a valid number"}, http.StatusBadRequest)
+	}
+
+	resp, err := h.currencyService.ListProductPrices(c.Request().Context(), uint(id))
+	if err != nil {
+		return handleServiceError(err, "Failed to list product prices")
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// SetProductPrice godoc
+// @Summary Set a product's price in a currency (admin only)
+// @Description Set an explicit price that overrides the converted base price for the currency
+// @Tags admin,currencies
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param currency path string true "Currency code"
+// @Param request body dto.SetProductPriceRequest true "Price details"
+// @Success 200 {object} dto.ProductPriceResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/products/{id}/prices/{currency} [put]
+// @Security BearerAuth
+func (h *CurrencyHandler) SetProductPrice(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return errors.NewValidationError("Invalid product ID", map[string]string{"id": "must be a valid number"}, http.StatusBadRequest)
+	}
+
+	req := new(dto.SetProductPriceRequest)
+	if err := c.Bind(req); err != nil {
+		return errors.NewValidationError("Invalid request body", nil, http.StatusBadRequest)
+	}
+
+	if errs := validator.Validate(req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"errors": errs})
+	}
+
+	resp, err := h.currencyService.SetProductPrice(c.Request().Context(), uint(id), c.Param("currency"), req)
+	if err != nil {
+		return handleServiceError(err, "Failed to set product price")
+	}
+
+	h.invalidateProductCache()
+	return c.JSON(http.StatusOK, resp)
+}
+
+// DeleteProductPrice godoc
+// @Summary Remove a product's price in a currency (admin only)
+// @Description Remove an explicit price so the product is priced by converting its base price again
+// @Tags admin,currencies
+// @Param id path int true "Product ID"
+// @Param currency path string true "Currency code"
+// @Success 204
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/products/{id}/prices/{currency} [delete]
+// @Security BearerAuth
+func (h *CurrencyHandler) DeleteProductPrice(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return errors.NewValidationError("Invalid product ID", map[string]string{"id": "must be a valid number"}, http.StatusBadRequest)
+	}
+
+	if err := h.currencyService.DeleteProductPrice(c.Request().Context(), uint(id), c.Param("currency")); err != nil {
+		return handleServiceError(err, "Failed to delete product price")
+	}
+
+	h.invalidateProductCache()
+	return c.NoContent(http.StatusNoContent)
+}
+
+// invalidateProductCache clears cached product responses, whose localised prices depend
+// on price lists and exchange rates
+func (h *CurrencyHandler) invalidateProductCache() {
+	go func() {
+		ctx := context.Background()
+		if err := h.redisService.InvalidatePattern(ctx, "/api/v1/products*"); err != nil {
+			logger.Error(ctx, "Failed to invalidate products cache", zap.Error(err))
+		}
+	}()
+}
@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/api/dto"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/service"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/errors"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/validator"
+	"github.com/labstack/echo/v4"
+)
+
+type InventoryHandler struct {
+	inventoryService service.InventoryService
+}
+
+func NewInventoryHandler(inventoryService service.InventoryService) *InventoryHandler {
+	return &InventoryHandler{inventoryService: inventoryService}
+}
+
+// AdjustInventory godoc
+// @Summary Apply batch inventory adjustments (admin only)
+// @Description Apply a batch of stock adjustments with reason codes. All lines are recorded in the stock movement ledger atomically.
+// @Tags admin,inventory
+// @Accept json
+// @Produce json
+// @Param request body dto.InventoryAdjustmentRequest true "Inventory adjustments"
+// @Success 201 {object} dto.InventoryAdjustmentResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Failure 409 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/inventory/adjustments [post]
+// @Security BearerAuth
+func (h *InventoryHandler) AdjustInventory(c echo.Context) error {
+	// Parse request body
+	req := new(dto.InventoryAdjustmentRequest)
+	if err := c.Bind(req); err != nil {
+		return errors.NewValidationError("Invalid request body", nil, http.StatusBadRequest)
+	}
+
+	// Validate request
+	if errs := validator.Validate(req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"errors": errs})
+	}
+
+	// Get authenticated admin ID from context
+	actorID := c.Get("user_id").(uint)
+
+	resp, err := h.inventoryService.AdjustInventory(c.Request().Context(), actorID, req)
+	if err != nil {
+		return handleServiceError(err, "Failed to adjust inventory")
+	}
+
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// ListStockMovements godoc
+// @Summary Get stock movement history for a product (admin only)
+// @Description Get the paginated stock movement ledger of a product, newest first
+// @Tags admin,inventory
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Items per page (default: 20)"
+// @Success 200 {object} dto.PaginatedStockMovementsResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/inventory/products/{id}/movements [get]
+// @Security BearerAuth
+func (h *InventoryHandler) ListStockMovements(c echo.Context) error {
+	// Parse product ID
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return errors.NewValidationError("Invalid product ID", map[string]string{"id": "must be a valid number"}, http.StatusBadRequest)
+	}
+
+	// Parse pagination query
+	var query dto.PaginationQuery
+	if err := c.Bind(&query); err != nil {
+		return errors.NewValidationError("Invalid pagination parameters", nil, http.StatusBadRequest)
+	}
+
+	// Set defaults if not provided
+	if query.Page == 0 {
+		query.Page = 1
+	}
+	if query.Limit == 0 {
+		query.Limit = 20
+	}
+
+	// Validate query
+	if errs := validator.Validate(query); len(errs) > 0 {
+		return errors.NewValidationError("Invalid pagination parameters", nil, http.StatusBadRequest)
+	}
+
+	resp, err := h.inventoryService.ListMovements(c.Request().Context(), uint(productID), query.Page, query.Limit)
+	if err != nil {
+		return handleServiceError(err, "Failed to list stock movements")
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetReconciliation godoc
+// @Summary Compare inventory with the stock ledger (admin only)
+// @Description List inventory rows whose stored stock differs from the sum of their stock movements
+// @Tags admin,inventory
+// @Accept json
+// @Produce json
+// @Success 200 {array} dto.InventoryReconciliationResponse
+// @Failure 401 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/inventory/reconciliation [get]
+// @Security BearerAuth
+func (h *InventoryHandler) GetReconciliation(c echo.Context) error {
+	actorID := c.Get("user_id").(uint)
+
+	resp, err := h.inventoryService.Reconcile(c.Request().Context(), actorID, false)
+	if err != nil {
+		return handleServiceError(err, "Failed to reconcile inventory")
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// ApplyReconciliation godoc
+// @Summary Reconcile the stock ledger with inventory (admin only)
+// @Description Append balancing ledger entries for every inventory row that drifted from its stock movements
+// @Tags admin,inventory
+// @Accept json
+// @Produce json
+// @Success 200 {array} dto.InventoryReconciliationResponse
+// @Failure 401 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/inventory/reconciliation [post]
+// @Security BearerAuth
+func (h *InventoryHandler) ApplyReconciliation(c echo.Context) error {
+	actorID := c.Get("user_id").(uint)
+
+	resp, err := h.inventoryService.Reconcile(c.Request().Context(), actorID, true)
+	if err != nil {
+		return handleServiceError(err, "Failed to reconcile inventory")
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// TransferStock godoc
+// @Summary Transfer stock between warehouses (admin only)
+// @Description Move available stock of a product from one warehouse to another. The transfer is recorded as a pair of stock movements sharing one reference.
+// @Tags admin,inventory
+// @Accept json
+// @Produce json
+// @Param request body dto.StockTransferRequest true "Stock transfer"
+// @Success 201 {object} dto.StockTransferResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Failure 409 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/inventory/transfers [post]
+// @Security BearerAuth
+func (h *InventoryHandler) TransferStock(c echo.Context) error {
+	req := new(dto.StockTransferRequest)
+	if err := c.Bind(req); err != nil {
+		return errors.NewValidationError("Invalid request body", nil, http.StatusBadRequest)
+	}
+
+	if errs := validator.Validate(req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"errors": errs})
+	}
+
+	actorID := c.Get("user_id").(uint)
+
+	resp, err := h.inventoryService.TransferStock(c.Request().Context(), actorID, req)
+	if err != nil {
+		return handleServiceError(err, "Failed to transfer stock")
+	}
+
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// handleServiceError passes typed application errors through and wraps anything else
+func handleServiceError(err error, message string) error {
+	switch e := err.(type) {
+	case *errors.ValidationError:
+		return e
+	case *errors.BusinessError:
+		return e
+	default:
+		return errors.NewServerError(message, err, http.StatusInternalServerError)
+	}
+}
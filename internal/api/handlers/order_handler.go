@@ -7,9 +7,11 @@ import (
 	"github.com/labstack/echo/v4"
 
 	"github.com/Ahmed1monm/backend-golang-task-2025/internal/api/dto"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/api/middleware"
 	"github.com/Ahmed1monm/backend-golang-task-2025/internal/service"
 	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/contextkey"
 	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/errors"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/validator"
 )
 
 type OrderHandler struct{
@@ -27,6 +29,7 @@ func NewOrderHandler(orderService *service.OrderService) *OrderHandler {
 // @Accept json
 // @Produce json
 // @Param request body dto.CreateOrderRequest true "Order creation details"
+// @Param X-Currency header string false "Currency to charge in (default: the user's preferred currency)"
 // @Success 201 {object} dto.OrderResponse
 // @Failure 400 {object} errors.AppError
 // @Failure 401 {object} errors.AppError
@@ -55,23 +58,23 @@ func (h *OrderHandler) CreateOrder(c echo.Context) error {
 		}, http.StatusBadRequest)
 	}
 
-	// Convert request items to service format
-	items := make([]struct {
-		ProductID uint
-		Quantity  int
-	}, len(req.Items))
+	// Convert request to service input
+	input := service.CreateOrderInput{
+		Items:    make([]service.OrderItemInput, len(req.Items)),
+		Currency: c.Request().Header.Get(middleware.CurrencyHeader),
+	}
 	for i, item := range req.Items {
-		items[i] = struct {
-			ProductID uint
-			Quantity  int
-		}{
+		input.Items[i] = service.OrderItemInput{
 			ProductID: item.ProductID,
 			Quantity:  item.Quantity,
 		}
 	}
+	if req.ShippingAddress != nil {
+		input.ShippingAddress = req.ShippingAddress.ToModel()
+	}
 
 	// Create order
-	order, err := h.orderService.CreateOrder(ctx, userID, items)
+	order, err := h.orderService.CreateOrder(ctx, userID, input)
 	if err != nil {
 		switch e := err.(type) {
 		case *errors.ValidationError:
@@ -82,32 +85,26 @@ func (h *OrderHandler) CreateOrder(c echo.Context) error {
 	}
 
 	// Convert order to response
-	response := dto.OrderResponse{
-		ID:          order.ID,
-		UserID:      order.UserID,
-		TotalAmount: order.TotalAmount,
-		Status:      string(order.Status),
-		Items:       make([]dto.OrderItemResponse, len(order.OrderItems)),
-	}
-
-	for i, item := range order.OrderItems {
-		response.Items[i] = dto.OrderItemResponse{
-			ProductID: item.ProductID,
-			Quantity:  item.Quantity,
-			Price:     item.Price,
-		}
-	}
+	response := dto.OrderToResponse(order)
 
 	return c.JSON(http.StatusCreated, response)
 }
 
 // ListOrders godoc
 // @Summary List user's orders
-// @Description Get a list of all orders for the authenticated user
+// @Description Get the authenticated user's orders, newest first by default, filtered and paginated with a cursor
 // @Tags orders
 // @Accept json
 // @Produce json
-// @Success 200 {array} dto.OrderResponse
+// @Param status query []string false "Order statuses to include" collectionFormat(multi)
+// @Param from query string false "Created at or after (RFC 3339 time or YYYY-MM-DD)"
+// @Param to query string false "Created before (RFC 3339 time) or on (YYYY-MM-DD)"
+// @Param product_id query int false "Only orders containing this product"
+// @Param sort query string false "Sort by creation time: newest (default) or oldest"
+// @Param limit query int false "Orders per page (default: 20, max: 100)"
+// @Param cursor query string false "next_cursor from the previous page"
+// @Success 200 {object} dto.PaginatedOrdersResponse
+// @Failure 400 {object} errors.AppError
 // @Failure 401 {object} errors.AppError
 // @Failure 500 {object} errors.AppError
 // @Router /orders [get]
@@ -121,35 +118,32 @@ func (h *OrderHandler) ListOrders(c echo.Context) error {
 		return errors.NewAuthorizationError("User not authenticated", nil, http.StatusUnauthorized)
 	}
 
-	// Get orders for the user
-	orders, err := h.orderService.ListOrdersByUserID(ctx, userID)
-	if err != nil {
-		return errors.NewServerError("Failed to list orders", err, http.StatusInternalServerError)
+	// Parse filters and pagination
+	var query dto.ListOrdersQuery
+	if err := c.Bind(&query); err != nil {
+		return errors.NewValidationError("Invalid query parameters", nil, http.StatusBadRequest)
+	}
+	if query.Limit == 0 {
+		query.Limit = 20
+	}
+	if errs := validator.Validate(query); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"errors": errs})
 	}
 
-	// If no orders found, return empty array instead of error
-	if len(orders) == 0 {
-		return c.JSON(http.StatusOK, []dto.OrderResponse{})
+	// Get a page of the user's orders
+	orders, nextCursor, err := h.orderService.ListUserOrders(ctx, userID, &query)
+	if err != nil {
+		return handleServiceError(err, "Failed to list orders")
 	}
 
 	// Convert orders to response format
-	response := make([]dto.OrderResponse, len(orders))
-	for i, order := range orders {
-		response[i] = dto.OrderResponse{
-			ID:          order.ID,
-			UserID:      order.UserID,
-			TotalAmount: order.TotalAmount,
-			Status:      string(order.Status),
-			Items:       make([]dto.OrderItemResponse, len(order.OrderItems)),
-		}
-
-		for j, item := range order.OrderItems {
-			response[i].Items[j] = dto.OrderItemResponse{
-				ProductID: item.ProductID,
-				Quantity:  item.Quantity,
-				Price:     item.Price,
-			}
-		}
+	response := dto.PaginatedOrdersResponse{
+		Orders:     make([]dto.OrderResponse, len(orders)),
+		Limit:      query.Limit,
+		NextCursor: nextCursor,
+	}
+	for i := range orders {
+		response.Orders[i] = *dto.OrderToResponse(&orders[i])
 	}
 
 	return c.JSON(http.StatusOK, response)
@@ -201,32 +195,20 @@ func (h *OrderHandler) GetOrder(c echo.Context) error {
 	}
 
 	// Convert order to response format
-	response := dto.OrderResponse{
-		ID:          order.ID,
-		UserID:      order.UserID,
-		TotalAmount: order.TotalAmount,
-		Status:      string(order.Status),
-		Items:       make([]dto.OrderItemResponse, len(order.OrderItems)),
-	}
-
-	for i, item := range order.OrderItems {
-		response.Items[i] = dto.OrderItemResponse{
-			ProductID: item.ProductID,
-			Quantity:  item.Quantity,
-			Price:     item.Price,
-		}
-	}
+	response := dto.OrderToResponse(order)
 
 	return c.JSON(http.StatusOK, response)
 }
 
 // CancelOrder godoc
 // @Summary Cancel an order
-// @Description Cancel an order if it's in a cancellable state and belongs to the authenticated user
+// @Description Cancel an order if it's in a cancellable state and belongs to the authenticated user.
+// @Description Reserved stock is released and a captured payment is refunded.
 // @Tags orders
 // @Accept json
 // @Produce json
 // @Param id path int true "Order ID"
+// @Param request body dto.CancelOrderRequest false "Cancellation reason"
 // @Success 200 {object} dto.OrderResponse
 // @Failure 400 {object} errors.AppError
 // @Failure 401 {object} errors.AppError
@@ -248,8 +230,21 @@ func (h *OrderHandler) CancelOrder(c echo.Context) error {
 	// Get user ID from context
 	userID := c.Get("user_id").(uint)
 
+	// The reason is optional, so an empty body is fine
+	var req dto.CancelOrderRequest
+	if err := c.Bind(&req); err != nil {
+		return errors.NewValidationError("Invalid request body", nil, http.StatusBadRequest)
+	}
+	if errs := validator.Validate(req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"errors": errs})
+	}
+
 	// Cancel order
-	order, err := h.orderService.CancelOrder(c.Request().Context(), uint(orderID), userID)
+	order, err := h.orderService.CancelOrder(c.Request().Context(), uint(orderID), service.CancelOrderInput{
+		ActorID: userID,
+		OwnerID: userID,
+		Reason:  req.Reason,
+	})
 	if err != nil {
 		return err // Service errors are already properly formatted
 	}
@@ -259,6 +254,118 @@ func (h *OrderHandler) CancelOrder(c echo.Context) error {
 	return c.JSON(http.StatusOK, resp)
 }
 
+// AdjustOrderItems godoc
+// @Summary Reduce or remove order items
+// @Description Lower the quantities of items of the authenticated user's order, or remove them with a quantity of 0,
+// @Description while the order is pending or processing. The removed units are released back to stock, the total
+// @Description is recomputed and the difference is refunded if the order was paid. Orders placed at checkout
+// @Description aren't charged, so only their total changes.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param request body dto.AdjustOrderItemsRequest true "New item quantities"
+// @Success 200 {object} dto.OrderResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /orders/{id}/items [patch]
+// @Security BearerAuth
+func (h *OrderHandler) AdjustOrderItems(c echo.Context) error {
+	orderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return errors.NewValidationError(
+			"Invalid order ID",
+			map[string]string{"id": "must be a valid number"},
+			http.StatusBadRequest,
+		)
+	}
+
+	var req dto.AdjustOrderItemsRequest
+	if err := c.Bind(&req); err != nil {
+		return errors.NewValidationError("Invalid request body", nil, http.StatusBadRequest)
+	}
+	if errs := validator.Validate(req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"errors": errs})
+	}
+
+	userID := c.Get("user_id").(uint)
+	input := adjustOrderInput(&req)
+	input.ActorID, input.OwnerID = userID, userID
+
+	order, err := h.orderService.AdjustOrderItems(c.Request().Context(), uint(orderID), input)
+	if err != nil {
+		return err // Service errors are already properly formatted
+	}
+
+	return c.JSON(http.StatusOK, dto.OrderToResponse(order))
+}
+
+// adjustOrderInput converts an item adjustment request to service input
+func adjustOrderInput(req *dto.AdjustOrderItemsRequest) service.AdjustOrderInput {
+	input := service.AdjustOrderInput{
+		Reason: req.Reason,
+		Items:  make([]service.OrderItemAdjustment, len(req.Items)),
+	}
+	for i, item := range req.Items {
+		input.Items[i] = service.OrderItemAdjustment{
+			ItemID:   item.ItemID,
+			Quantity: item.Quantity,
+		}
+	}
+	return input
+}
+
+// Reorder godoc
+// @Summary Reorder a past order
+// @Description Place a new order with the items of one of the authenticated user's orders at current prices, or add
+// @Description them to the cart. Discontinued and out of stock products are skipped and items with too little stock
+// @Description are reduced; every item's outcome and price change since the original order is reported.
+// @Tags orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Order ID"
+// @Param request body dto.ReorderRequest false "Reorder options"
+// @Success 201 {object} dto.ReorderResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Failure 409 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /orders/{id}/reorder [post]
+// @Security BearerAuth
+func (h *OrderHandler) Reorder(c echo.Context) error {
+	orderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return errors.NewValidationError(
+			"Invalid order ID",
+			map[string]string{"id": "must be a valid number"},
+			http.StatusBadRequest,
+		)
+	}
+
+	// The options are optional, so an empty body is fine
+	var req dto.ReorderRequest
+	if err := c.Bind(&req); err != nil {
+		return errors.NewValidationError("Invalid request body", nil, http.StatusBadRequest)
+	}
+
+	userID := c.Get("user_id").(uint)
+	resp, err := h.orderService.Reorder(c.Request().Context(), uint(orderID), userID, req.ToCart)
+	if err != nil {
+		return handleServiceError(err, "Failed to reorder")
+	}
+
+	status := http.StatusCreated
+	if req.ToCart {
+		status = http.StatusOK
+	}
+	return c.JSON(status, resp)
+}
+
 // GetOrderStatus godoc
 // @Summary Get order status
 // @Description Get the current status of an order
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/api/dto"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/service"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/errors"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/logger"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/redis"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/validator"
+	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
+)
+
+type PriceScheduleHandler struct {
+	scheduleService service.PriceScheduleService
+	redisService    redis.Service
+}
+
+func NewPriceScheduleHandler(scheduleService service.PriceScheduleService, redisService redis.Service) *PriceScheduleHandler {
+	return &PriceScheduleHandler{
+		scheduleService: scheduleService,
+		redisService:    redisService,
+	}
+}
+
+// CreatePriceSchedule godoc
+// @Summary Schedule a product price (admin only)
+// @Description Schedule a sale price between starts_at and ends_at, or a regular price change from starts_at when ends_at is omitted
+// @Tags admin,products
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param request body dto.CreatePriceScheduleRequest true "Price schedule details"
+// @Success 201 {object} dto.PriceScheduleResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Failure 409 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/products/{id}/price-schedules [post]
+// @Security BearerAuth
+func (h *PriceScheduleHandler) CreatePriceSchedule(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return errors.NewValidationError("Invalid product ID", map[string]string{"id": "must be a valid number"}, http.StatusBadRequest)
+	}
+
+	req := new(dto.CreatePriceScheduleRequest)
+	if err := c.Bind(req); err != nil {
+		return errors.NewValidationError("Invalid request body", nil, http.StatusBadRequest)
+	}
+
+	if errs := validator.Validate(req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"errors": errs})
+	}
+
+	actorID := c.Get("user_id").(uint)
+
+	resp, err := h.scheduleService.CreateSchedule(c.Request().Context(), uint(id), actorID, req)
+	if err != nil {
+		return handleServiceError(err, "Failed to create price schedule")
+	}
+
+	// The schedule may already be in effect
+	h.invalidateProductCache()
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// ListPriceSchedules godoc
+// @Summary List a product's price schedules (admin only)
+// @Description Get the product's sales and planned price changes, latest start first
+// @Tags admin,products
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {array} dto.PriceScheduleResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/products/{id}/price-schedules [get]
+// @Security BearerAuth
+func (h *PriceScheduleHandler) ListPriceSchedules(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return errors.NewValidationError("Invalid product ID", map[string]string{"id": "must be a valid number"}, http.StatusBadRequest)
+	}
+
+	resp, err := h.scheduleService.ListSchedules(c.Request().Context(), uint(id))
+	if err != nil {
+		return handleServiceError(err, "Failed to list price schedules")
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// CancelPriceSchedule godoc
+// @Summary Cancel a price schedule (admin only)
+// @Description Cancel a scheduled or running price schedule; a running sale ends immediately
+// @Tags admin,products
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Param scheduleId path int true "Price schedule ID"
+// @Success 200 {object} dto.PriceScheduleResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Failure 409 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/products/{id}/price-schedules/{scheduleId}/cancel [post]
+// @Security BearerAuth
+func (h *PriceScheduleHandler) CancelPriceSchedule(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return errors.NewValidationError("Invalid product ID", map[string]string{"id": "must be a valid number"}, http.StatusBadRequest)
+	}
+	scheduleID, err := strconv.ParseUint(c.Param("scheduleId"), 10, 32)
+	if err != nil {
+		return errors.NewValidationError("Invalid price schedule ID", map[string]string{"scheduleId": "must be a valid number"}, http.StatusBadRequest)
+	}
+
+	actorID := c.Get("user_id").(uint)
+
+	resp, err := h.scheduleService.CancelSchedule(c.Request().Context(), uint(id), uint(scheduleID), actorID)
+	if err != nil {
+		return handleServiceError(err, "Failed to cancel price schedule")
+	}
+
+	h.invalidateProductCache()
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetPriceHistory godoc
+// @Summary Get a product's price history (admin only)
+// @Description Get every price the product has sold at, newest first
+// @Tags admin,products
+// @Accept json
+// @Produce json
+// @Param id path int true "Product ID"
+// @Success 200 {array} dto.PriceHistoryResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/products/{id}/price-history [get]
+// @Security BearerAuth
+func (h *PriceScheduleHandler) GetPriceHistory(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return errors.NewValidationError("Invalid product ID", map[string]string{"id": "must be a valid number"}, http.StatusBadRequest)
+	}
+
+	resp, err := h.scheduleService.ListPriceHistory(c.Request().Context(), uint(id))
+	if err != nil {
+		return handleServiceError(err, "Failed to get price history")
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+func (h *PriceScheduleHandler) invalidateProductCache() {
+	go func() {
+		ctx := context.Background()
+		if err := h.redisService.InvalidatePattern(ctx, "/api/v1/products*"); err != nil {
+			logger.Error(ctx, "Failed to invalidate products cache", zap.Error(err))
+		}
+	}()
+}
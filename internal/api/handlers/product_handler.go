@@ -6,6 +6,7 @@ import (
 	"strconv"
 
 	"github.com/Ahmed1monm/backend-golang-task-2025/internal/api/dto"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/api/middleware"
 	"github.com/Ahmed1monm/backend-golang-task-2025/internal/service"
 	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/logger"
 	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/redis"
@@ -34,6 +35,7 @@ func NewProductHandler(productService service.ProductService, redisService redis
 // @Produce json
 // @Param page query int false "Page number (default: 1)"
 // @Param limit query int false "Items per page (default: 10)"
+// @Param X-Currency header string false "Currency to show prices in (default: base currency)"
 // @Success 200 {object} dto.PaginatedProductsResponse
 // @Failure 400 {object} errors.AppError
 // @Failure 500 {object} errors.AppError
@@ -59,9 +61,9 @@ func (h *ProductHandler) ListProducts(c echo.Context) error {
 	}
 
 	// Get products from service
-	resp, err := h.productService.ListProducts(c.Request().Context(), query.Page, query.Limit)
+	resp, err := h.productService.ListProducts(c.Request().Context(), query.Page, query.Limit, c.Request().Header.Get(middleware.CurrencyHeader))
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list products")
+		return handleServiceError(err, "Failed to list products")
 	}
 
 	return c.JSON(http.StatusOK, resp)
@@ -74,6 +76,7 @@ func (h *ProductHandler) ListProducts(c echo.Context) error {
 // @Accept json
 // @Produce json
 // @Param id path int true "Product ID"
+// @Param X-Currency header string false "Currency to show prices in (default: base currency)"
 // @Success 200 {object} dto.ProductResponse
 // @Failure 400 {object} errors.AppError
 // @Failure 404 {object} errors.AppError
@@ -87,9 +90,9 @@ func (h *ProductHandler) GetProduct(c echo.Context) error {
 	}
 
 	// Get product from service
-	resp, err := h.productService.GetProduct(c.Request().Context(), uint(id))
+	resp, err := h.productService.GetProduct(c.Request().Context(), uint(id), c.Request().Header.Get(middleware.CurrencyHeader))
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get product")
+		return handleServiceError(err, "Failed to get product")
 	}
 
 	if resp == nil {
@@ -129,7 +132,7 @@ func (h *ProductHandler) CreateProduct(c echo.Context) error {
 	// Create product
 	resp, err := h.productService.CreateProduct(ctx, req)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create product")
+		return handleServiceError(err, "Failed to create product")
 	}
 
 	return c.JSON(http.StatusCreated, resp)
@@ -168,10 +171,13 @@ func (h *ProductHandler) UpdateProduct(c echo.Context) error {
 		return c.JSON(http.StatusBadRequest, echo.Map{"errors": errs})
 	}
 
+	// Get authenticated user ID from context, recorded as the actor of stock changes
+	actorID := c.Get("user_id").(uint)
+
 	// Update product
-	resp, err := h.productService.UpdateProduct(c.Request().Context(), uint(id), req)
+	resp, err := h.productService.UpdateProduct(c.Request().Context(), uint(id), actorID, req)
 	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update product")
+		return handleServiceError(err, "Failed to update product")
 	}
 
 	if resp == nil {
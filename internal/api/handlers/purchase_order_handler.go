@@ -0,0 +1,277 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/api/dto"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/service"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/errors"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/validator"
+	"github.com/labstack/echo/v4"
+)
+
+type PurchaseOrderHandler struct {
+	purchaseOrderService service.PurchaseOrderService
+}
+
+func NewPurchaseOrderHandler(purchaseOrderService service.PurchaseOrderService) *PurchaseOrderHandler {
+	return &PurchaseOrderHandler{purchaseOrderService: purchaseOrderService}
+}
+
+// CreatePurchaseOrder godoc
+// @Summary Draft a purchase order (admin only)
+// @Description Create a draft purchase order with a supplier for delivery to a warehouse
+// @Tags admin,purchase-orders
+// @Accept json
+// @Produce json
+// @Param request body dto.CreatePurchaseOrderRequest true "Purchase order details"
+// @Success 201 {object} dto.PurchaseOrderResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/purchase-orders [post]
+// @Security BearerAuth
+func (h *PurchaseOrderHandler) CreatePurchaseOrder(c echo.Context) error {
+	req := new(dto.CreatePurchaseOrderRequest)
+	if err := c.Bind(req); err != nil {
+		return errors.NewValidationError("Invalid request body", nil, http.StatusBadRequest)
+	}
+
+	if errs := validator.Validate(req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"errors": errs})
+	}
+
+	actorID := c.Get("user_id").(uint)
+
+	resp, err := h.purchaseOrderService.CreatePurchaseOrder(c.Request().Context(), actorID, req)
+	if err != nil {
+		return handleServiceError(err, "Failed to create purchase order")
+	}
+
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// CreatePurchaseOrderFromAlert godoc
+// @Summary Draft a purchase order from a low stock alert (admin only)
+// @Description Create a draft purchase order for the alerted product from its preferred supplier, for the suggested reorder quantity. If the alert already has an open purchase order it is returned instead.
+// @Tags admin,purchase-orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Low stock alert ID"
+// @Success 201 {object} dto.PurchaseOrderResponse
+// @Success 200 {object} dto.PurchaseOrderResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Failure 409 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/inventory/low-stock/{id}/purchase-order [post]
+// @Security BearerAuth
+func (h *PurchaseOrderHandler) CreatePurchaseOrderFromAlert(c echo.Context) error {
+	alertID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return errors.NewValidationError("Invalid alert ID", map[string]string{"id": "must be a valid number"}, http.StatusBadRequest)
+	}
+
+	actorID := c.Get("user_id").(uint)
+
+	resp, created, err := h.purchaseOrderService.CreateFromStockAlert(c.Request().Context(), actorID, uint(alertID))
+	if err != nil {
+		return handleServiceError(err, "Failed to create purchase order")
+	}
+
+	if !created {
+		return c.JSON(http.StatusOK, resp)
+	}
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// ListPurchaseOrders godoc
+// @Summary List purchase orders (admin only)
+// @Description Get a paginated list of purchase orders, newest first
+// @Tags admin,purchase-orders
+// @Accept json
+// @Produce json
+// @Param status query string false "Filter by status" Enums(draft, sent, partially_received, received, cancelled)
+// @Param page query int false "Page number (default: 1)"
+// @Param limit query int false "Items per page (default: 20)"
+// @Success 200 {object} dto.PaginatedPurchaseOrdersResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/purchase-orders [get]
+// @Security BearerAuth
+func (h *PurchaseOrderHandler) ListPurchaseOrders(c echo.Context) error {
+	var query dto.PaginationQuery
+	if err := c.Bind(&query); err != nil {
+		return errors.NewValidationError("Invalid pagination parameters", nil, http.StatusBadRequest)
+	}
+
+	// Set defaults if not provided
+	if query.Page == 0 {
+		query.Page = 1
+	}
+	if query.Limit == 0 {
+		query.Limit = 20
+	}
+
+	if errs := validator.Validate(query); len(errs) > 0 {
+		return errors.NewValidationError("Invalid pagination parameters", nil, http.StatusBadRequest)
+	}
+
+	status := models.PurchaseOrderStatus(c.QueryParam("status"))
+	switch status {
+	case "", models.PurchaseOrderStatusDraft, models.PurchaseOrderStatusSent, models.PurchaseOrderStatusPartiallyReceived,
+		models.PurchaseOrderStatusReceived, models.PurchaseOrderStatusCancelled:
+	default:
+		return errors.NewValidationError("Invalid status", map[string]string{"status": "unknown purchase order status"}, http.StatusBadRequest)
+	}
+
+	resp, err := h.purchaseOrderService.ListPurchaseOrders(c.Request().Context(), status, query.Page, query.Limit)
+	if err != nil {
+		return handleServiceError(err, "Failed to list purchase orders")
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetPurchaseOrder godoc
+// @Summary Get a purchase order (admin only)
+// @Description Get a purchase order with its lines and received quantities
+// @Tags admin,purchase-orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Purchase order ID"
+// @Success 200 {object} dto.PurchaseOrderResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/purchase-orders/{id} [get]
+// @Security BearerAuth
+func (h *PurchaseOrderHandler) GetPurchaseOrder(c echo.Context) error {
+	id, err := parsePurchaseOrderID(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.purchaseOrderService.GetPurchaseOrder(c.Request().Context(), id)
+	if err != nil {
+		return handleServiceError(err, "Failed to get purchase order")
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// SendPurchaseOrder godoc
+// @Summary Mark a purchase order as sent (admin only)
+// @Description Move a draft purchase order to sent once it has been placed with the supplier
+// @Tags admin,purchase-orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Purchase order ID"
+// @Success 200 {object} dto.PurchaseOrderResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/purchase-orders/{id}/send [post]
+// @Security BearerAuth
+func (h *PurchaseOrderHandler) SendPurchaseOrder(c echo.Context) error {
+	id, err := parsePurchaseOrderID(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.purchaseOrderService.SendPurchaseOrder(c.Request().Context(), id)
+	if err != nil {
+		return handleServiceError(err, "Failed to send purchase order")
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// ReceivePurchaseOrder godoc
+// @Summary Receive goods against a purchase order (admin only)
+// @Description Book delivered quantities into the purchase order's warehouse as stock receipts. Without lines every outstanding quantity is received.
+// @Tags admin,purchase-orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Purchase order ID"
+// @Param request body dto.ReceivePurchaseOrderRequest false "Delivered quantities"
+// @Success 200 {object} dto.PurchaseOrderResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/purchase-orders/{id}/receive [post]
+// @Security BearerAuth
+func (h *PurchaseOrderHandler) ReceivePurchaseOrder(c echo.Context) error {
+	id, err := parsePurchaseOrderID(c)
+	if err != nil {
+		return err
+	}
+
+	req := new(dto.ReceivePurchaseOrderRequest)
+	if err := c.Bind(req); err != nil {
+		return errors.NewValidationError("Invalid request body", nil, http.StatusBadRequest)
+	}
+
+	if errs := validator.Validate(req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"errors": errs})
+	}
+
+	actorID := c.Get("user_id").(uint)
+
+	resp, err := h.purchaseOrderService.ReceivePurchaseOrder(c.Request().Context(), actorID, id, req)
+	if err != nil {
+		return handleServiceError(err, "Failed to receive purchase order")
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// CancelPurchaseOrder godoc
+// @Summary Cancel a purchase order (admin only)
+// @Description Cancel a draft or sent purchase order that has not received any goods
+// @Tags admin,purchase-orders
+// @Accept json
+// @Produce json
+// @Param id path int true "Purchase order ID"
+// @Success 200 {object} dto.PurchaseOrderResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/purchase-orders/{id}/cancel [post]
+// @Security BearerAuth
+func (h *PurchaseOrderHandler) CancelPurchaseOrder(c echo.Context) error {
+	id, err := parsePurchaseOrderID(c)
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.purchaseOrderService.CancelPurchaseOrder(c.Request().Context(), id)
+	if err != nil {
+		return handleServiceError(err, "Failed to cancel purchase order")
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+func parsePurchaseOrderID(c echo.Context) (uint, error) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return 0, errors.NewValidationError("Invalid purchase order ID", map[string]string{"id": "must be a valid number"}, http.StatusBadRequest)
+	}
+	return uint(id), nil
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/api/dto"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/service"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/errors"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/validator"
+	"github.com/labstack/echo/v4"
+)
+
+type ReportDigestHandler struct {
+	reportDigestService service.ReportDigestService
+}
+
+func NewReportDigestHandler(reportDigestService service.ReportDigestService) *ReportDigestHandler {
+	return &ReportDigestHandler{reportDigestService: reportDigestService}
+}
+
+// CreateReportDigest godoc
+// @Summary Subscribe an admin to a report digest
+// @Description Email an admin the sales report of every day, the next morning, or of every ISO week, on Monday. The email has an HTML summary and the full report attached as CSV.
+// @Tags admin,reports
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateReportDigestRequest true "Admin and frequency"
+// @Success 201 {object} dto.ReportDigestSubscriptionResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 409 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/report-digests [post]
+// @Security BearerAuth
+func (h *ReportDigestHandler) CreateReportDigest(c echo.Context) error {
+	req := new(dto.CreateReportDigestRequest)
+	if err := c.Bind(req); err != nil {
+		return errors.NewValidationError("Invalid request body", nil, http.StatusBadRequest)
+	}
+
+	if errs := validator.Validate(req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"errors": errs})
+	}
+
+	actorID := c.Get("user_id").(uint)
+	resp, err := h.reportDigestService.CreateSubscription(c.Request().Context(), actorID, req)
+	if err != nil {
+		return handleServiceError(err, "Failed to create report digest")
+	}
+
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// ListReportDigests godoc
+// @Summary List report digests
+// @Description Get every admin's report digest subscriptions with the status of their latest delivery
+// @Tags admin,reports
+// @Accept json
+// @Produce json
+// @Success 200 {array} dto.ReportDigestSubscriptionResponse
+// @Failure 500 {object} errors.AppError
+// @Router /admin/report-digests [get]
+// @Security BearerAuth
+func (h *ReportDigestHandler) ListReportDigests(c echo.Context) error {
+	resp, err := h.reportDigestService.ListSubscriptions(c.Request().Context())
+	if err != nil {
+		return handleServiceError(err, "Failed to list report digests")
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// UpdateReportDigest godoc
+// @Summary Pause or resume a report digest
+// @Description Stop or restart emailing a report digest. Periods that end while it is paused are not sent.
+// @Tags admin,reports
+// @Accept json
+// @Produce json
+// @Param id path int true "Report digest ID"
+// @Param request body dto.UpdateReportDigestRequest true "Whether the digest is sent"
+// @Success 200 {object} dto.ReportDigestSubscriptionResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/report-digests/{id} [put]
+// @Security BearerAuth
+func (h *ReportDigestHandler) UpdateReportDigest(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return errors.NewValidationError("Invalid report digest ID", nil, http.StatusBadRequest)
+	}
+
+	req := new(dto.UpdateReportDigestRequest)
+	if err := c.Bind(req); err != nil {
+		return errors.NewValidationError("Invalid request body", nil, http.StatusBadRequest)
+	}
+
+	if errs := validator.Validate(req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"errors": errs})
+	}
+
+	resp, err := h.reportDigestService.UpdateSubscription(c.Request().Context(), uint(id), req)
+	if err != nil {
+		return handleServiceError(err, "Failed to update report digest")
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// DeleteReportDigest godoc
+// @Summary Unsubscribe from a report digest
+// @Description Stop emailing a report digest for good. Its delivery history is kept.
+// @Tags admin,reports
+// @Accept json
+// @Produce json
+// @Param id path int true "Report digest ID"
+// @Success 204
+// @Failure 400 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/report-digests/{id} [delete]
+// @Security BearerAuth
+func (h *ReportDigestHandler) DeleteReportDigest(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return errors.NewValidationError("Invalid report digest ID", nil, http.StatusBadRequest)
+	}
+
+	if err := h.reportDigestService.DeleteSubscription(c.Request().Context(), uint(id)); err != nil {
+		return handleServiceError(err, "Failed to delete report digest")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListReportDigestDeliveries godoc
+// @Summary List the deliveries of a report digest
+// @Description Get the emails of a report digest's latest periods, newest first, with whether they were sent, how many attempts were made and the last error
+// @Tags admin,reports
+// @Accept json
+// @Produce json
+// @Param id path int true "Report digest ID"
+// @Success 200 {array} dto.ReportDigestDeliveryResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/report-digests/{id}/deliveries [get]
+// @Security BearerAuth
+func (h *ReportDigestHandler) ListReportDigestDeliveries(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return errors.NewValidationError("Invalid report digest ID", nil, http.StatusBadRequest)
+	}
+
+	resp, err := h.reportDigestService.ListDeliveries(c.Request().Context(), uint(id))
+	if err != nil {
+		return handleServiceError(err, "Failed to list report digest deliveries")
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/api/dto"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/service"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/errors"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/export"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/validator"
+	"github.com/labstack/echo/v4"
+)
+
+type ReportExportHandler struct {
+	reportExportService service.ReportExportService
+}
+
+func NewReportExportHandler(reportExportService service.ReportExportService) *ReportExportHandler {
+	return &ReportExportHandler{reportExportService: reportExportService}
+}
+
+// GetReportExport godoc
+// @Summary Get a report export
+// @Description Get the status of a report export job. Once completed it includes a signed download link, valid for a limited time; fetch the export again for a new one.
+// @Tags admin,reports
+// @Accept json
+// @Produce json
+// @Param id path int true "Export ID"
+// @Success 200 {object} dto.ReportExportResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/reports/exports/{id} [get]
+// @Security BearerAuth
+func (h *ReportExportHandler) GetReportExport(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return errors.NewValidationError("Invalid export ID", nil, http.StatusBadRequest)
+	}
+
+	resp, err := h.reportExportService.GetExport(c.Request().Context(), uint(id))
+	if err != nil {
+		return handleServiceError(err, "Failed to get report export")
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// DownloadReportExport godoc
+// @Summary Download a report export
+// @Description Download the file of a completed report export through the signed link given by the export. No other authentication is needed.
+// @Tags reports
+// @Produce text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/pdf
+// @Param id path int true "Export ID"
+// @Param expires query int true "Unix time the link expires at"
+// @Param signature query string true "Link signature"
+// @Success 200 {file} file
+// @Failure 400 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /reports/exports/{id}/download [get]
+func (h *ReportExportHandler) DownloadReportExport(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return errors.NewValidationError("Invalid export ID", nil, http.StatusBadRequest)
+	}
+
+	var query dto.ReportDownloadQuery
+	if err := c.Bind(&query); err != nil {
+		return errors.NewValidationError("Invalid query parameters", nil, http.StatusBadRequest)
+	}
+
+	if errs := validator.Validate(query); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"errors": errs})
+	}
+
+	file, err := h.reportExportService.DownloadExport(c.Request().Context(), uint(id), query.Expires, query.Signature)
+	if err != nil {
+		return handleServiceError(err, "Failed to download report export")
+	}
+
+	return sendReportFile(c, file)
+}
+
+// reportFormat picks the format of a report from the format query parameter, or else the
+// Accept header
+func reportFormat(c echo.Context) (export.Format, error) {
+	if name := c.QueryParam("format"); name != "" {
+		format, ok := export.ParseFormat(name)
+		if !ok {
+			return "", errors.NewValidationError("Invalid query parameters", map[string]string{"format": "must be one of json, csv, xlsx, pdf"}, http.StatusBadRequest)
+		}
+		return format, nil
+	}
+
+	c.Response().Header().Add(echo.HeaderVary, echo.HeaderAccept)
+	format, ok := export.NegotiateFormat(c.Request().Header.Get(echo.HeaderAccept))
+	if !ok {
+		return "", errors.NewBusinessError("Reports are available as JSON, CSV, XLSX or PDF", "NOT_ACCEPTABLE", http.StatusNotAcceptable)
+	}
+	return format, nil
+}
+
+// sendReportFile responds with a report file as an attachment
+func sendReportFile(c echo.Context, file *service.ReportFile) error {
+	c.Response().Header().Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": file.Name}))
+	return c.Blob(http.StatusOK, file.ContentType, file.Content)
+}
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/api/dto"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/api/middleware"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/service"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/errors"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/validator"
+	"github.com/labstack/echo/v4"
+)
+
+type SubscriptionHandler struct {
+	subscriptionService service.SubscriptionService
+}
+
+func NewSubscriptionHandler(subscriptionService service.SubscriptionService) *SubscriptionHandler {
+	return &SubscriptionHandler{subscriptionService: subscriptionService}
+}
+
+// CreateSubscription godoc
+// @Summary Subscribe to recurring orders
+// @Description Order the same products on a weekly, biweekly or monthly cadence, charged to a stored payment method.
+// @Description The first order is placed at starts_at, or right away if omitted, and a reminder is sent before every renewal.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param X-Currency header string false "Currency to charge the orders in"
+// @Param request body dto.CreateSubscriptionRequest true "Subscription details"
+// @Success 201 {object} dto.SubscriptionResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /subscriptions [post]
+// @Security BearerAuth
+func (h *SubscriptionHandler) CreateSubscription(c echo.Context) error {
+	req := new(dto.CreateSubscriptionRequest)
+	if err := c.Bind(req); err != nil {
+		return errors.NewValidationError("Invalid request body", nil, http.StatusBadRequest)
+	}
+
+	if errs := validator.Validate(req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"errors": errs})
+	}
+
+	userID := c.Get("user_id").(uint)
+	resp, err := h.subscriptionService.CreateSubscription(c.Request().Context(), userID, c.Request().Header.Get(middleware.CurrencyHeader), req)
+	if err != nil {
+		return handleServiceError(err, "Failed to create subscription")
+	}
+
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// ListSubscriptions godoc
+// @Summary List subscriptions
+// @Description Get the authenticated user's subscriptions, newest first
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Success 200 {array} dto.SubscriptionResponse
+// @Failure 401 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /subscriptions [get]
+// @Security BearerAuth
+func (h *SubscriptionHandler) ListSubscriptions(c echo.Context) error {
+	userID := c.Get("user_id").(uint)
+
+	resp, err := h.subscriptionService.ListSubscriptions(c.Request().Context(), userID)
+	if err != nil {
+		return handleServiceError(err, "Failed to list subscriptions")
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetSubscription godoc
+// @Summary Get a subscription
+// @Description Get one of the authenticated user's subscriptions and the state of its renewals
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param id path int true "Subscription ID"
+// @Success 200 {object} dto.SubscriptionResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /subscriptions/{id} [get]
+// @Security BearerAuth
+func (h *SubscriptionHandler) GetSubscription(c echo.Context) error {
+	return h.subscriptionAction(c, h.subscriptionService.GetSubscription, "Failed to get subscription")
+}
+
+// PauseSubscription godoc
+// @Summary Pause a subscription
+// @Description Stop an active subscription from renewing until it is resumed
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param id path int true "Subscription ID"
+// @Success 200 {object} dto.SubscriptionResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /subscriptions/{id}/pause [post]
+// @Security BearerAuth
+func (h *SubscriptionHandler) PauseSubscription(c echo.Context) error {
+	return h.subscriptionAction(c, h.subscriptionService.PauseSubscription, "Failed to pause subscription")
+}
+
+// ResumeSubscription godoc
+// @Summary Resume a subscription
+// @Description Resume a paused subscription. Renewals missed while paused are not made up; the next one is the first on the cadence from now.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param id path int true "Subscription ID"
+// @Success 200 {object} dto.SubscriptionResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /subscriptions/{id}/resume [post]
+// @Security BearerAuth
+func (h *SubscriptionHandler) ResumeSubscription(c echo.Context) error {
+	return h.subscriptionAction(c, h.subscriptionService.ResumeSubscription, "Failed to resume subscription")
+}
+
+// SkipNextRenewal godoc
+// @Summary Skip the next renewal
+// @Description Move the next renewal of an active subscription, or a pending retry of a failed one, to the following period
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param id path int true "Subscription ID"
+// @Success 200 {object} dto.SubscriptionResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /subscriptions/{id}/skip [post]
+// @Security BearerAuth
+func (h *SubscriptionHandler) SkipNextRenewal(c echo.Context) error {
+	return h.subscriptionAction(c, h.subscriptionService.SkipNextRenewal, "Failed to skip renewal")
+}
+
+// CancelSubscription godoc
+// @Summary Cancel a subscription
+// @Description Cancel a subscription for good; orders already placed are not affected
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param id path int true "Subscription ID"
+// @Success 200 {object} dto.SubscriptionResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /subscriptions/{id}/cancel [post]
+// @Security BearerAuth
+func (h *SubscriptionHandler) CancelSubscription(c echo.Context) error {
+	return h.subscriptionAction(c, h.subscriptionService.CancelSubscription, "Failed to cancel subscription")
+}
+
+// subscriptionAction runs a service call on the subscription in the path for the
+// authenticated user and responds with the subscription
+func (h *SubscriptionHandler) subscriptionAction(
+	c echo.Context,
+	action func(ctx context.Context, userID, id uint) (*dto.SubscriptionResponse, error),
+	message string,
+) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return errors.NewValidationError(
+			"Invalid subscription ID",
+			map[string]string{"id": "must be a valid number"},
+			http.StatusBadRequest,
+		)
+	}
+
+	userID := c.Get("user_id").(uint)
+	resp, err := action(c.Request().Context(), userID, uint(id))
+	if err != nil {
+		return handleServiceError(err, message)
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
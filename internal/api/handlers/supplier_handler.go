@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/api/dto"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/service"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/errors"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/validator"
+	"github.com/labstack/echo/v4"
+)
+
+type SupplierHandler struct {
+	supplierService service.SupplierService
+}
+
+func NewSupplierHandler(supplierService service.SupplierService) *SupplierHandler {
+	return &SupplierHandler{supplierService: supplierService}
+}
+
+// CreateSupplier godoc
+// @Summary Create a supplier (admin only)
+// @Description Create a supplier that purchase orders can be placed with
+// @Tags admin,suppliers
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateSupplierRequest true "Supplier details"
+// @Success 201 {object} dto.SupplierResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/suppliers [post]
+// @Security BearerAuth
+func (h *SupplierHandler) CreateSupplier(c echo.Context) error {
+	req := new(dto.CreateSupplierRequest)
+	if err := c.Bind(req); err != nil {
+		return errors.NewValidationError("Invalid request body", nil, http.StatusBadRequest)
+	}
+
+	if errs := validator.Validate(req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"errors": errs})
+	}
+
+	resp, err := h.supplierService.CreateSupplier(c.Request().Context(), req)
+	if err != nil {
+		return handleServiceError(err, "Failed to create supplier")
+	}
+
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// ListSuppliers godoc
+// @Summary List suppliers (admin only)
+// @Description Get all suppliers
+// @Tags admin,suppliers
+// @Accept json
+// @Produce json
+// @Success 200 {array} dto.SupplierResponse
+// @Failure 401 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/suppliers [get]
+// @Security BearerAuth
+func (h *SupplierHandler) ListSuppliers(c echo.Context) error {
+	resp, err := h.supplierService.ListSuppliers(c.Request().Context())
+	if err != nil {
+		return handleServiceError(err, "Failed to list suppliers")
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// UpdateSupplier godoc
+// @Summary Update a supplier (admin only)
+// @Description Update a supplier's contact details, lead time or active flag
+// @Tags admin,suppliers
+// @Accept json
+// @Produce json
+// @Param id path int true "Supplier ID"
+// @Param request body dto.UpdateSupplierRequest true "Supplier update details"
+// @Success 200 {object} dto.SupplierResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/suppliers/{id} [put]
+// @Security BearerAuth
+func (h *SupplierHandler) UpdateSupplier(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return errors.NewValidationError("Invalid supplier ID", map[string]string{"id": "must be a valid number"}, http.StatusBadRequest)
+	}
+
+	req := new(dto.UpdateSupplierRequest)
+	if err := c.Bind(req); err != nil {
+		return errors.NewValidationError("Invalid request body", nil, http.StatusBadRequest)
+	}
+
+	if errs := validator.Validate(req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"errors": errs})
+	}
+
+	resp, err := h.supplierService.UpdateSupplier(c.Request().Context(), uint(id), req)
+	if err != nil {
+		return handleServiceError(err, "Failed to update supplier")
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
@@ -141,7 +141,7 @@ func (h *UserHandler) UpdateUserProfile(c echo.Context) error {
 		case "email is already taken":
 			return echo.NewHTTPError(http.StatusConflict, "Email is already taken")
 		default:
-			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update user profile")
+			return handleServiceError(err, "Failed to update user profile")
 		}
 	}
 
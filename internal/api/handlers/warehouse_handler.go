@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/api/dto"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/service"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/errors"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/validator"
+	"github.com/labstack/echo/v4"
+)
+
+type WarehouseHandler struct {
+	warehouseService service.WarehouseService
+}
+
+func NewWarehouseHandler(warehouseService service.WarehouseService) *WarehouseHandler {
+	return &WarehouseHandler{warehouseService: warehouseService}
+}
+
+// CreateWarehouse godoc
+// @Summary Create a warehouse (admin only)
+// @Description Create a warehouse that can hold inventory. Marking it as default moves the default flag from the current default warehouse.
+// @Tags admin,warehouses
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateWarehouseRequest true "Warehouse details"
+// @Success 201 {object} dto.WarehouseResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/warehouses [post]
+// @Security BearerAuth
+func (h *WarehouseHandler) CreateWarehouse(c echo.Context) error {
+	req := new(dto.CreateWarehouseRequest)
+	if err := c.Bind(req); err != nil {
+		return errors.NewValidationError("Invalid request body", nil, http.StatusBadRequest)
+	}
+
+	if errs := validator.Validate(req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"errors": errs})
+	}
+
+	resp, err := h.warehouseService.CreateWarehouse(c.Request().Context(), req)
+	if err != nil {
+		return handleServiceError(err, "Failed to create warehouse")
+	}
+
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// ListWarehouses godoc
+// @Summary List warehouses (admin only)
+// @Description Get all warehouses
+// @Tags admin,warehouses
+// @Accept json
+// @Produce json
+// @Success 200 {array} dto.WarehouseResponse
+// @Failure 401 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/warehouses [get]
+// @Security BearerAuth
+func (h *WarehouseHandler) ListWarehouses(c echo.Context) error {
+	resp, err := h.warehouseService.ListWarehouses(c.Request().Context())
+	if err != nil {
+		return handleServiceError(err, "Failed to list warehouses")
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// UpdateWarehouse godoc
+// @Summary Update a warehouse (admin only)
+// @Description Update a warehouse's name, address, active flag or promote it to default
+// @Tags admin,warehouses
+// @Accept json
+// @Produce json
+// @Param id path int true "Warehouse ID"
+// @Param request body dto.UpdateWarehouseRequest true "Warehouse update details"
+// @Success 200 {object} dto.WarehouseResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 401 {object} errors.AppError
+// @Failure 403 {object} errors.AppError
+// @Failure 404 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/warehouses/{id} [put]
+// @Security BearerAuth
+func (h *WarehouseHandler) UpdateWarehouse(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return errors.NewValidationError("Invalid warehouse ID", map[string]string{"id": "must be a valid number"}, http.StatusBadRequest)
+	}
+
+	req := new(dto.UpdateWarehouseRequest)
+	if err := c.Bind(req); err != nil {
+		return errors.NewValidationError("Invalid request body", nil, http.StatusBadRequest)
+	}
+
+	if errs := validator.Validate(req); len(errs) > 0 {
+		return c.JSON(http.StatusBadRequest, echo.Map{"errors": errs})
+	}
+
+	resp, err := h.warehouseService.UpdateWarehouse(c.Request().Context(), uint(id), req)
+	if err != nil {
+		return handleServiceError(err, "Failed to update warehouse")
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
@@ -1,21 +1,32 @@
 package handlers
 
 import (
+	"context"
 	"net/http"
+	"strconv"
 
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/api/dto"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/api/middleware"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/service"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/errors"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/logger"
 	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/websocket"
 	"github.com/google/uuid"
 	gorillaws "github.com/gorilla/websocket"
 	"github.com/labstack/echo/v4"
+	"go.uber.org/zap"
 )
 
 type WebSocketHandler struct {
-	manager *websocket.Manager
+	manager      *websocket.Manager
+	topicService service.TopicService
 }
 
-func NewWebSocketHandler(manager *websocket.Manager) *WebSocketHandler {
+func NewWebSocketHandler(manager *websocket.Manager, topicService service.TopicService) *WebSocketHandler {
 	return &WebSocketHandler{
-		manager: manager,
+		manager:      manager,
+		topicService: topicService,
 	}
 }
 
@@ -29,7 +40,10 @@ var upgrader = gorillaws.Upgrader{
 
 // HandleWebSocket godoc
 // @Summary Connect to WebSocket
-// @Description Upgrade HTTP connection to WebSocket for real-time updates
+// @Description Upgrade HTTP connection to WebSocket for real-time updates.
+// @Description Clients subscribe to topics by sending {"action": "subscribe", "topic": "order:42", "id": "1"}, unsubscribe with the unsubscribe action and check the connection with ping. Each message is answered with a subscribed, unsubscribed, pong or error event echoing its id; events published to a topic carry it in their topic field.
+// @Description Topics: order:<id> (order_created, order_status_changed, order_cancelled, order_updated and backorder_allocated events) for the order's customer and admins; product:<id>:stock (a product_stock event with the available stock on subscribing and whenever it changes) for everyone; admin:orders (the events of every order) and admin:dashboard for admins.
+// @Description Connections of admins are subscribed to the admin dashboard feed on admin:dashboard as they connect: a dashboard_snapshot event with today's order count and revenue, the latest orders and the open low stock alerts, followed by dashboard_order_created, dashboard_order_status_changed and low_stock_alert events. Order events carry today's updated totals.
 // @Tags websocket
 // @Accept json
 // @Produce json
@@ -40,8 +54,11 @@ var upgrader = gorillaws.Upgrader{
 // @Router /ws [get]
 // @Security BearerAuth
 func (h *WebSocketHandler) HandleWebSocket(c echo.Context) error {
-	// Get user ID from JWT token
-	userID := c.Get("user_id").(uint)
+	// Get user ID and role from JWT token
+	claims, err := middleware.GetAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
 
 	// Upgrade connection
 	conn, err := upgrader.Upgrade(c.Response(), c.Request(), nil)
@@ -52,7 +69,8 @@ func (h *WebSocketHandler) HandleWebSocket(c echo.Context) error {
 	// Create new client
 	client := &websocket.Client{
 		ID:      uuid.New().String(),
-		UserID:  userID,
+		UserID:  claims.UserID,
+		Role:    string(claims.Role),
 		Conn:    conn,
 		Manager: h.manager,
 	}
@@ -60,19 +78,46 @@ func (h *WebSocketHandler) HandleWebSocket(c echo.Context) error {
 	// Register client
 	h.manager.Register <- client
 
-	// Start listening for messages from this client
-	go func() {
-		defer func() {
-			client.Conn.Close()
-		}()
-
-		for {
-			_, _, err := client.Conn.ReadMessage()
-			if err != nil {
-				break
-			}
+	// The connection outlives the upgrade request
+	ctx := context.WithoutCancel(c.Request().Context())
+
+	// Admins get the dashboard feed, starting with its current state
+	if claims.Role == models.RoleAdmin {
+		if err := h.manager.SubscribeAuthorized(ctx, client, websocket.TopicAdminDashboard, h.topicService); err != nil {
+			logger.Error(ctx, "Failed to send admin dashboard snapshot",
+				zap.Error(err),
+				zap.Uint("user_id", claims.UserID))
 		}
-	}()
+	}
+
+	// Start listening for messages from this client
+	go h.manager.Listen(ctx, client, h.topicService)
 
 	return nil
 }
+
+// GetUserPresence godoc
+// @Summary Check whether a user is online
+// @Description Check whether a user has a WebSocket connection open on any instance of the API. Instances renew their users' presence in Redis as long as they run, so a user of an instance that stopped shows as online until WS_PRESENCE_TTL passes.
+// @Tags admin,websocket
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} dto.UserPresenceResponse
+// @Failure 400 {object} errors.AppError
+// @Failure 500 {object} errors.AppError
+// @Router /admin/users/{id}/presence [get]
+// @Security BearerAuth
+func (h *WebSocketHandler) GetUserPresence(c echo.Context) error {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return errors.NewValidationError("Invalid user ID", nil, http.StatusBadRequest)
+	}
+
+	online, err := h.manager.IsOnline(c.Request().Context(), uint(id))
+	if err != nil {
+		return handleServiceError(err, "Failed to check user presence")
+	}
+
+	return c.JSON(http.StatusOK, dto.UserPresenceResponse{UserID: uint(id), Online: online})
+}
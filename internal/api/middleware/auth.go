@@ -1,10 +1,12 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"strings"
 
 	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/contextkey"
 	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/jwt"
 	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/logger"
 	"github.com/labstack/echo/v4"
@@ -39,6 +41,8 @@ func JWTAuthentication() echo.MiddlewareFunc {
 
 			// Store user information in context
 			c.Set(UserContext, claims)
+			c.Set(string(contextkey.UserIDKey), claims.UserID)
+			c.SetRequest(c.Request().WithContext(context.WithValue(ctx, contextkey.UserIDKey, claims.UserID)))
 			return next(c)
 		}
 	}
@@ -11,6 +11,9 @@ import (
 	"go.uber.org/zap"
 )
 
+// CurrencyHeader lets clients pick the currency prices are shown and charged in
+const CurrencyHeader = "X-Currency"
+
 type CachedResponse struct {
 	Status int         `json:"status"`
 	Header http.Header `json:"header"`
@@ -26,7 +29,11 @@ func WithCache(redisService redis.Service, next echo.HandlerFunc) echo.HandlerFu
 		}
 
 		// Try to get from cache first
+		// Prices differ per currency, so each currency gets its own entry
 		key := c.Request().URL.Path
+		if currency := c.Request().Header.Get(CurrencyHeader); currency != "" {
+			key += "?currency=" + currency
+		}
 		var cachedResponse CachedResponse
 		err := redisService.GetCached(c.Request().Context(), key, &cachedResponse)
 		if err == redis.ErrNil {
@@ -2,6 +2,8 @@ package routes
 
 import (
 	"log"
+	"strings"
+	"time"
 
 	"github.com/Ahmed1monm/backend-golang-task-2025/internal/api/handlers"
 	"github.com/Ahmed1monm/backend-golang-task-2025/internal/api/middleware"
@@ -9,6 +11,10 @@ import (
 	"github.com/Ahmed1monm/backend-golang-task-2025/internal/repository"
 	"github.com/Ahmed1monm/backend-golang-task-2025/internal/service"
 	"github.com/Ahmed1monm/backend-golang-task-2025/internal/workers"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/mail"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/payment"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/utils"
 	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/redis"
 	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/websocket"
 	"github.com/labstack/echo/v4"
@@ -18,7 +24,7 @@ import (
 )
 
 // SetupRoutes configures all API routes
-func SetupRoutes(e *echo.Echo, db *gorm.DB, redisService redis.Service) {
+func SetupRoutes(e *echo.Echo, db *gorm.DB, redisRepo redis.Repository, redisService redis.Service) {
 	// Initialize repositories
 	userRepo := repository.NewUserRepository(db)
 	productRepo := repository.NewProductRepository(db)
@@ -26,30 +32,154 @@ func SetupRoutes(e *echo.Echo, db *gorm.DB, redisService redis.Service) {
 	inventoryRepo := repository.NewInventoryRepository(db)
 	reportRepo := repository.NewReportRepository(db)
 	notificationRepo := repository.NewNotificationRepository(db)
+	stockMovementRepo := repository.NewStockMovementRepository(db)
+	warehouseRepo := repository.NewWarehouseRepository(db)
+	stockAlertRepo := repository.NewStockAlertRepository(db)
+	supplierRepo := repository.NewSupplierRepository(db)
+	purchaseOrderRepo := repository.NewPurchaseOrderRepository(db)
+	currencyRepo := repository.NewCurrencyRepository(db)
+	priceScheduleRepo := repository.NewPriceScheduleRepository(db)
+	cartRepo := repository.NewCartRepository(db)
+	subscriptionRepo := repository.NewSubscriptionRepository(db)
+	reportExportRepo := repository.NewReportExportRepository(db)
+	reportDigestRepo := repository.NewReportDigestRepository(db)
+	analyticsRepo := repository.NewAnalyticsRepository(db)
 
-	// Initialize WebSocket manager
-	wsManager := websocket.NewManager()
+	// Initialize WebSocket manager, sharing events and presence with the other instances through Redis
+	wsManager := websocket.NewClusterManager(redisRepo, websocket.ClusterConfig{
+		NodeID:      utils.GetEnv("WS_NODE_ID", ""),
+		PresenceTTL: utils.GetEnvAsDuration("WS_PRESENCE_TTL", 30*time.Second),
+	})
 	go wsManager.Start()
 
+	// Initialize services
+	paymentService := payment.NewMockService()
+	mailSender, err := mail.NewSender(mail.Config{
+		Driver: utils.GetEnv("MAIL_DRIVER", "console"),
+		From:   utils.GetEnv("MAIL_FROM", "reports@localhost"),
+		Dir:    utils.GetEnv("MAIL_DIR", "tmp/mail"),
+		SMTP: mail.SMTPConfig{
+			Host:     utils.GetEnv("SMTP_HOST", ""),
+			Port:     utils.GetEnvAsInt("SMTP_PORT", 587),
+			Username: utils.GetEnv("SMTP_USERNAME", ""),
+			Password: utils.GetEnv("SMTP_PASSWORD", ""),
+		},
+	})
+	if err != nil {
+		log.Printf("Invalid mail configuration, logging emails instead of sending them: %v", err)
+		mailSender = mail.NewConsoleSender(utils.GetEnv("MAIL_FROM", "reports@localhost"))
+	}
+	priceScheduleService := service.NewPriceScheduleService(db, priceScheduleRepo, productRepo)
+	currencyService := service.NewCurrencyService(db, CurrencyConfig(), currencyRepo, productRepo, userRepo, priceScheduleService)
+	userService := service.NewUserService(userRepo, currencyService)
+	notificationService := service.NewNotificationService(db, notificationRepo, wsManager)
+	dashboardService := service.NewDashboardService(db, orderRepo, stockAlertRepo, currencyService, wsManager)
+	lowStockService := service.NewLowStockService(db, service.LowStockConfig{
+		VelocityWindowDays: utils.GetEnvAsInt("LOW_STOCK_VELOCITY_WINDOW_DAYS", 30),
+		CoverDays:          utils.GetEnvAsInt("LOW_STOCK_COVER_DAYS", 30),
+	}, stockAlertRepo, inventoryRepo, stockMovementRepo, productRepo, userRepo, notificationService, dashboardService)
+	inventoryService := service.NewInventoryService(db, inventoryRepo, stockMovementRepo, productRepo, warehouseRepo)
+	warehouseService := service.NewWarehouseService(db, warehouseRepo)
+	productService := service.NewProductService(productRepo, orderRepo, inventoryRepo, supplierRepo, inventoryService, currencyService, priceScheduleService, db)
+	allocationStrategy, err := service.NewAllocationStrategy(utils.GetEnv("ALLOCATION_STRATEGY", service.AllocationNearest))
+	if err != nil {
+		log.Printf("Invalid allocation strategy, falling back to %s: %v", service.AllocationNearest, err)
+		allocationStrategy, _ = service.NewAllocationStrategy(service.AllocationNearest)
+	}
+	cartService := service.NewCartService(db, cartRepo, productRepo, currencyService)
+	orderService := service.NewOrderService(db, orderRepo, inventoryRepo, productRepo, inventoryService, currencyService, allocationStrategy, notificationService, paymentService, cartService, wsManager, dashboardService)
+	reportService := service.NewReportService(db, reportRepo, orderRepo, userRepo, productRepo, currencyService, redisService)
+	reportSigningKey := utils.GetEnv("REPORT_EXPORT_SIGNING_KEY", "")
+	if reportSigningKey == "" {
+		log.Fatal("REPORT_EXPORT_SIGNING_KEY must be set to sign report download links")
+	}
+	reportExportService := service.NewReportExportService(db, service.ReportExportConfig{
+		SyncMaxDays: utils.GetEnvAsInt("REPORT_EXPORT_SYNC_MAX_DAYS", 31),
+		LinkTTL:     utils.GetEnvAsDuration("REPORT_EXPORT_LINK_TTL", 24*time.Hour),
+		Retention:   utils.GetEnvAsDuration("REPORT_EXPORT_RETENTION", 7*24*time.Hour),
+		SigningKey:  reportSigningKey,
+		BaseURL:     utils.GetEnv("PUBLIC_BASE_URL", ""),
+	}, reportExportRepo, reportService)
+	reportDigestService := service.NewReportDigestService(db, service.ReportDigestConfig{
+		MaxAttempts: utils.GetEnvAsInt("REPORT_DIGEST_MAX_ATTEMPTS", 3),
+	}, reportDigestRepo, userRepo, reportService, mailSender)
+	customerAnalyticsService := service.NewCustomerAnalyticsService(db, analyticsRepo, currencyService, redisService)
+	productAnalyticsService := service.NewProductAnalyticsService(db, service.ProductAnalyticsConfig{
+		SlowMoverDays: utils.GetEnvAsInt("ANALYTICS_SLOW_MOVER_DAYS", 90),
+	}, analyticsRepo, currencyService, redisService)
+	supplierService := service.NewSupplierService(db, supplierRepo)
+	subscriptionService := service.NewSubscriptionService(db, service.SubscriptionConfig{
+		ReminderLead:       utils.GetEnvAsDuration("SUBSCRIPTION_REMINDER_LEAD", 24*time.Hour),
+		RetryDelay:         utils.GetEnvAsDuration("SUBSCRIPTION_RETRY_DELAY", 24*time.Hour),
+		MaxRenewalAttempts: utils.GetEnvAsInt("SUBSCRIPTION_MAX_RENEWAL_ATTEMPTS", 3),
+	}, subscriptionRepo, productRepo, currencyService, orderService, notificationService)
+	purchaseOrderService := service.NewPurchaseOrderService(db, purchaseOrderRepo, supplierRepo, warehouseRepo, productRepo, stockAlertRepo, inventoryService)
+	topicService := service.NewTopicService(db, orderRepo, productRepo, inventoryRepo, dashboardService, wsManager)
+
+	// React to committed stock changes: fill backorders first, then check and publish what is left
+	inventoryService.OnStockChanged(orderService.AllocateBackorders)
+	inventoryService.OnStockChanged(lowStockService.CheckProducts)
+	inventoryService.OnStockChanged(topicService.PublishStock)
+
 	// Initialize report worker
-	reportWorker := workers.NewReportWorker(db, reportRepo, orderRepo, userRepo, productRepo)
+	reportWorker := workers.NewReportWorker(reportService, productAnalyticsService, utils.GetEnvAsInt("REPORT_CATCH_UP_DAYS", 7))
 	if err := reportWorker.Start(); err != nil {
 		log.Printf("Failed to start report worker: %v", err)
 	}
 
-	// Initialize services
-	userService := service.NewUserService(userRepo)
-	productService := service.NewProductService(productRepo, orderRepo, inventoryRepo, db)
-	notificationService := service.NewNotificationService(db, notificationRepo, wsManager)
-	orderService := service.NewOrderService(db, orderRepo, inventoryRepo, productRepo, notificationService, wsManager)
-	reportService := service.NewReportService(reportRepo)
+	// Initialize report export worker
+	reportExportWorker := workers.NewReportExportWorker(reportExportService)
+	if err := reportExportWorker.Start(); err != nil {
+		log.Printf("Failed to start report export worker: %v", err)
+	}
+
+	// Initialize report digest worker
+	reportDigestWorker := workers.NewReportDigestWorker(reportDigestService)
+	if err := reportDigestWorker.Start(); err != nil {
+		log.Printf("Failed to start report digest worker: %v", err)
+	}
+
+	// Initialize analytics worker
+	analyticsWorker := workers.NewAnalyticsWorker(customerAnalyticsService)
+	if err := analyticsWorker.Start(); err != nil {
+		log.Printf("Failed to start analytics worker: %v", err)
+	}
+
+	// Initialize price schedule worker
+	priceScheduleWorker := workers.NewPriceScheduleWorker(priceScheduleService, redisService)
+	if err := priceScheduleWorker.Start(); err != nil {
+		log.Printf("Failed to start price schedule worker: %v", err)
+	}
+
+	// Initialize subscription worker
+	subscriptionWorker := workers.NewSubscriptionWorker(subscriptionService)
+	if err := subscriptionWorker.Start(); err != nil {
+		log.Printf("Failed to start subscription worker: %v", err)
+	}
+
+	// Initialize refund worker
+	refundWorker := workers.NewRefundWorker(orderService)
+	if err := refundWorker.Start(); err != nil {
+		log.Printf("Failed to start refund worker: %v", err)
+	}
 
 	// Initialize handlers
 	userHandler := handlers.NewUserHandler(userService)
 	productHandler := handlers.NewProductHandler(productService, redisService)
 	orderHandler := handlers.NewOrderHandler(orderService)
-	adminHandler := handlers.NewAdminHandler(orderService, reportService)
-	wsHandler := handlers.NewWebSocketHandler(wsManager)
+	cartHandler := handlers.NewCartHandler(cartService, orderService)
+	subscriptionHandler := handlers.NewSubscriptionHandler(subscriptionService)
+	adminHandler := handlers.NewAdminHandler(orderService, reportService, reportExportService, lowStockService)
+	reportExportHandler := handlers.NewReportExportHandler(reportExportService)
+	reportDigestHandler := handlers.NewReportDigestHandler(reportDigestService)
+	analyticsHandler := handlers.NewAnalyticsHandler(customerAnalyticsService, productAnalyticsService)
+	inventoryHandler := handlers.NewInventoryHandler(inventoryService)
+	warehouseHandler := handlers.NewWarehouseHandler(warehouseService)
+	supplierHandler := handlers.NewSupplierHandler(supplierService)
+	purchaseOrderHandler := handlers.NewPurchaseOrderHandler(purchaseOrderService)
+	currencyHandler := handlers.NewCurrencyHandler(currencyService, redisService)
+	priceScheduleHandler := handlers.NewPriceScheduleHandler(priceScheduleService, redisService)
+	wsHandler := handlers.NewWebSocketHandler(wsManager, topicService)
 
 	// Swagger route
 	e.GET("/api/docs/*", echoSwagger.WrapHandler)
@@ -77,7 +207,29 @@ func SetupRoutes(e *echo.Echo, db *gorm.DB, redisService redis.Service) {
 	orders.GET("", orderHandler.ListOrders, middleware.JWTAuthentication())
 	orders.GET("/:id", orderHandler.GetOrder, middleware.JWTAuthentication())
 	orders.PUT("/:id/cancel", orderHandler.CancelOrder, middleware.JWTAuthentication())
+	orders.PATCH("/:id/items", orderHandler.AdjustOrderItems, middleware.JWTAuthentication())
 	orders.GET("/:id/status", orderHandler.GetOrderStatus, middleware.JWTAuthentication())
+	orders.POST("/:id/reorder", orderHandler.Reorder, middleware.JWTAuthentication())
+
+	// Cart routes
+	cart := v1.Group("/cart", middleware.JWTAuthentication())
+	cart.GET("", cartHandler.GetCart)
+	cart.PUT("/items", cartHandler.SetCartItem)
+	cart.DELETE("", cartHandler.ClearCart)
+	cart.POST("/checkout", cartHandler.Checkout)
+
+	// Subscription routes
+	subscriptions := v1.Group("/subscriptions", middleware.JWTAuthentication())
+	subscriptions.POST("", subscriptionHandler.CreateSubscription)
+	subscriptions.GET("", subscriptionHandler.ListSubscriptions)
+	subscriptions.GET("/:id", subscriptionHandler.GetSubscription)
+	subscriptions.POST("/:id/pause", subscriptionHandler.PauseSubscription)
+	subscriptions.POST("/:id/resume", subscriptionHandler.ResumeSubscription)
+	subscriptions.POST("/:id/skip", subscriptionHandler.SkipNextRenewal)
+	subscriptions.POST("/:id/cancel", subscriptionHandler.CancelSubscription)
+
+	// Report downloads, authorized by the signed link instead of a token
+	v1.GET("/reports/exports/:id/download", reportExportHandler.DownloadReportExport)
 
 	// WebSocket route
 	v1.GET("/ws", wsHandler.HandleWebSocket, middleware.JWTAuthentication())
@@ -85,7 +237,61 @@ func SetupRoutes(e *echo.Echo, db *gorm.DB, redisService redis.Service) {
 	// Admin routes - protected with JWT auth and admin role requirement
 	admin := v1.Group("/admin", middleware.JWTAuthentication(), middleware.RequireRoles(models.RoleAdmin))
 	admin.GET("/orders", adminHandler.ListAllOrders)
+	admin.POST("/orders/bulk", adminHandler.BulkOrderAction)
 	admin.PUT("/orders/:id/status", adminHandler.UpdateOrderStatus)
+	admin.PATCH("/orders/:id/items", adminHandler.AdjustOrderItems)
 	admin.GET("/reports/daily", adminHandler.GetDailySalesReport)
+	admin.GET("/reports/range", adminHandler.GetSalesReportRange)
+	admin.POST("/reports/generate", adminHandler.GenerateReports)
+	admin.GET("/reports/exports/:id", reportExportHandler.GetReportExport)
+	admin.GET("/report-digests", reportDigestHandler.ListReportDigests)
+	admin.POST("/report-digests", reportDigestHandler.CreateReportDigest)
+	admin.PUT("/report-digests/:id", reportDigestHandler.UpdateReportDigest)
+	admin.DELETE("/report-digests/:id", reportDigestHandler.DeleteReportDigest)
+	admin.GET("/report-digests/:id/deliveries", reportDigestHandler.ListReportDigestDeliveries)
+	admin.GET("/analytics/customers", analyticsHandler.GetCustomerAnalytics)
+	admin.GET("/analytics/customers/rfm", analyticsHandler.ListCustomerRFM)
+	admin.GET("/analytics/products", analyticsHandler.ListProductPerformance)
+	admin.GET("/analytics/products/:id", analyticsHandler.GetProductAnalytics)
 	admin.GET("/inventory/low-stock", adminHandler.GetLowStockAlerts)
+	admin.POST("/inventory/low-stock/:id/purchase-order", purchaseOrderHandler.CreatePurchaseOrderFromAlert)
+	admin.POST("/inventory/adjustments", inventoryHandler.AdjustInventory)
+	admin.GET("/inventory/products/:id/movements", inventoryHandler.ListStockMovements)
+	admin.GET("/inventory/reconciliation", inventoryHandler.GetReconciliation)
+	admin.POST("/inventory/reconciliation", inventoryHandler.ApplyReconciliation)
+	admin.POST("/inventory/transfers", inventoryHandler.TransferStock)
+	admin.GET("/warehouses", warehouseHandler.ListWarehouses)
+	admin.POST("/warehouses", warehouseHandler.CreateWarehouse)
+	admin.PUT("/warehouses/:id", warehouseHandler.UpdateWarehouse)
+	admin.GET("/suppliers", supplierHandler.ListSuppliers)
+	admin.POST("/suppliers", supplierHandler.CreateSupplier)
+	admin.PUT("/suppliers/:id", supplierHandler.UpdateSupplier)
+	admin.GET("/purchase-orders", purchaseOrderHandler.ListPurchaseOrders)
+	admin.POST("/purchase-orders", purchaseOrderHandler.CreatePurchaseOrder)
+	admin.GET("/purchase-orders/:id", purchaseOrderHandler.GetPurchaseOrder)
+	admin.POST("/purchase-orders/:id/send", purchaseOrderHandler.SendPurchaseOrder)
+	admin.POST("/purchase-orders/:id/receive", purchaseOrderHandler.ReceivePurchaseOrder)
+	admin.POST("/purchase-orders/:id/cancel", purchaseOrderHandler.CancelPurchaseOrder)
+	admin.GET("/exchange-rates", currencyHandler.ListExchangeRates)
+	admin.POST("/exchange-rates", currencyHandler.CreateExchangeRate)
+	admin.GET("/products/:id/prices", currencyHandler.ListProductPrices)
+	admin.PUT("/products/:id/prices/:currency", currencyHandler.SetProductPrice)
+	admin.DELETE("/products/:id/prices/:currency", currencyHandler.DeleteProductPrice)
+	admin.GET("/products/:id/price-schedules", priceScheduleHandler.ListPriceSchedules)
+	admin.POST("/products/:id/price-schedules", priceScheduleHandler.CreatePriceSchedule)
+	admin.POST("/products/:id/price-schedules/:scheduleId/cancel", priceScheduleHandler.CancelPriceSchedule)
+	admin.GET("/products/:id/price-history", priceScheduleHandler.GetPriceHistory)
+	admin.GET("/users/:id/presence", wsHandler.GetUserPresence)
+}
+
+// CurrencyConfig reads the supported and reporting currencies from the environment
+func CurrencyConfig() service.CurrencyConfig {
+	var config service.CurrencyConfig
+	for _, code := range strings.Split(utils.GetEnv("SUPPORTED_CURRENCIES", "USD,EUR,GBP,EGP,SAR,AED"), ",") {
+		if code = strings.TrimSpace(code); code != "" {
+			config.Supported = append(config.Supported, money.Currency(strings.ToUpper(code)))
+		}
+	}
+	config.Reporting = money.Currency(strings.ToUpper(utils.GetEnv("REPORTING_CURRENCY", string(money.DefaultCurrency))))
+	return config
 }
@@ -0,0 +1,11 @@
+package models
+
+// Address is a postal address with optional coordinates, embedded in other models
+type Address struct {
+	Line1      string   `gorm:"size:255"`
+	City       string   `gorm:"size:100"`
+	PostalCode string   `gorm:"size:20"`
+	Country    string   `gorm:"size:2"` // ISO 3166-1 alpha-2
+	Latitude   *float64 `gorm:"type:decimal(9,6)"`
+	Longitude  *float64 `gorm:"type:decimal(9,6)"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// Cart holds the products a customer plans to order; each customer has one cart
+type Cart struct {
+	gorm.Model
+	UserID uint       `gorm:"uniqueIndex;not null"`
+	User   User       `gorm:"foreignKey:UserID"`
+	Items  []CartItem `gorm:"foreignKey:CartID"`
+}
+
+// CartItem is a product and quantity in a cart. Prices are resolved when the cart is
+// shown or checked out, never stored.
+type CartItem struct {
+	gorm.Model
+	CartID    uint    `gorm:"not null;uniqueIndex:idx_cart_items_cart_product"`
+	ProductID uint    `gorm:"not null;uniqueIndex:idx_cart_items_cart_product"`
+	Product   Product `gorm:"foreignKey:ProductID"`
+	Quantity  int     `gorm:"not null"`
+}
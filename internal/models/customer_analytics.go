@@ -0,0 +1,109 @@
+package models
+
+import (
+	"time"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
+	"gorm.io/gorm"
+)
+
+// CustomerSegment is an RFM segment of customers
+type CustomerSegment string
+
+const (
+	CustomerSegmentChampions   CustomerSegment = "champions"   // Ordered recently and often
+	CustomerSegmentLoyal       CustomerSegment = "loyal"       // Order regularly
+	CustomerSegmentNew         CustomerSegment = "new"         // First ordered recently
+	CustomerSegmentPromising   CustomerSegment = "promising"   // Ordered recently, a few times
+	CustomerSegmentAtRisk      CustomerSegment = "at_risk"     // Used to order often, not lately
+	CustomerSegmentHibernating CustomerSegment = "hibernating" // Haven't ordered for a while
+	CustomerSegmentLost        CustomerSegment = "lost"        // Haven't ordered for the longest
+)
+
+// CustomerAnalyticsSummary holds the customer figures of the latest analytics run. Only
+// orders that weren't cancelled count.
+type CustomerAnalyticsSummary struct {
+	gorm.Model
+	ComputedAt               time.Time      `gorm:"not null"`
+	Currency                 money.Currency `gorm:"type:varchar(3);not null"`
+	Customers                int            `gorm:"not null"`                   // With at least one order
+	RepeatCustomers          int            `gorm:"not null"`                   // With more than one order
+	RepeatPurchaseRate       float64        `gorm:"type:decimal(5,2);not null"` // Percentage
+	Orders                   int            `gorm:"not null"`
+	Revenue                  money.Money    `gorm:"type:decimal(14,2);not null"`
+	AverageLifetimeValue     money.Money    `gorm:"type:decimal(12,2);not null"` // Revenue per customer to date
+	AverageOrdersPerCustomer float64        `gorm:"type:decimal(10,2);not null"`
+}
+
+// AfterFind tags the amounts with the summary's currency once the row is read
+func (s *CustomerAnalyticsSummary) AfterFind(tx *gorm.DB) (err error) {
+	if s.Revenue, err = s.Revenue.As(s.Currency); err != nil {
+		return err
+	}
+	s.AverageLifetimeValue, err = s.AverageLifetimeValue.As(s.Currency)
+	return err
+}
+
+// CustomerCohort is the activity, in a month, of the customers who first ordered in the
+// cohort month
+type CustomerCohort struct {
+	gorm.Model
+	CohortMonth     time.Time      `gorm:"type:date;not null;uniqueIndex:idx_customer_cohort_month_offset"`
+	MonthOffset     int            `gorm:"not null;uniqueIndex:idx_customer_cohort_month_offset"` // Months since the cohort month
+	CohortSize      int            `gorm:"not null"`
+	ActiveCustomers int            `gorm:"not null"` // Ordered in the month
+	Orders          int            `gorm:"not null"`
+	Currency        money.Currency `gorm:"type:varchar(3);not null"`
+	Revenue         money.Money    `gorm:"type:decimal(14,2);not null"`
+}
+
+// AfterFind tags the revenue with the cohort's currency once the row is read
+func (c *CustomerCohort) AfterFind(tx *gorm.DB) (err error) {
+	c.Revenue, err = c.Revenue.As(c.Currency)
+	return err
+}
+
+// CustomerRFMScore scores a customer's recency, frequency and monetary value from 1 to 5
+// and puts them in a segment
+type CustomerRFMScore struct {
+	gorm.Model
+	UserID         uint            `gorm:"not null;uniqueIndex"`
+	User           User            `gorm:"foreignKey:UserID"`
+	RecencyDays    int             `gorm:"not null"` // Days since the last order
+	Orders         int             `gorm:"not null"`
+	Currency       money.Currency  `gorm:"type:varchar(3);not null"`
+	Monetary       money.Money     `gorm:"type:decimal(12,2);not null"` // Spent to date
+	RecencyScore   int             `gorm:"not null"`
+	FrequencyScore int             `gorm:"not null"`
+	MonetaryScore  int             `gorm:"not null"`
+	Segment        CustomerSegment `gorm:"type:varchar(20);not null;index"`
+	FirstOrderAt   time.Time       `gorm:"not null"`
+	LastOrderAt    time.Time       `gorm:"not null"`
+}
+
+// AfterFind tags the monetary value with the score's currency once the row is read
+func (s *CustomerRFMScore) AfterFind(tx *gorm.DB) (err error) {
+	s.Monetary, err = s.Monetary.As(s.Currency)
+	return err
+}
+
+// CustomerSegmentSummary sums up the customers of an RFM segment
+type CustomerSegmentSummary struct {
+	gorm.Model
+	Segment            CustomerSegment `gorm:"type:varchar(20);not null;uniqueIndex"`
+	Customers          int             `gorm:"not null"`
+	Currency           money.Currency  `gorm:"type:varchar(3);not null"`
+	Revenue            money.Money     `gorm:"type:decimal(14,2);not null"`
+	AverageRecencyDays float64         `gorm:"type:decimal(10,2);not null"`
+	AverageOrders      float64         `gorm:"type:decimal(10,2);not null"`
+	AverageMonetary    money.Money     `gorm:"type:decimal(12,2);not null"`
+}
+
+// AfterFind tags the amounts with the summary's currency once the row is read
+func (s *CustomerSegmentSummary) AfterFind(tx *gorm.DB) (err error) {
+	if s.Revenue, err = s.Revenue.As(s.Currency); err != nil {
+		return err
+	}
+	s.AverageMonetary, err = s.AverageMonetary.As(s.Currency)
+	return err
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
+	"gorm.io/gorm"
+)
+
+// ExchangeRate is the rate from a base currency to a quote currency from EffectiveAt
+// onwards, until a later rate for the same pair takes effect
+type ExchangeRate struct {
+	gorm.Model
+	BaseCurrency  money.Currency `gorm:"type:varchar(3);not null;uniqueIndex:idx_exchange_rates_pair_effective"`
+	QuoteCurrency money.Currency `gorm:"type:varchar(3);not null;uniqueIndex:idx_exchange_rates_pair_effective"`
+	Rate          money.Rate     `gorm:"type:decimal(18,8);not null"` // Units of the quote currency per unit of the base currency
+	EffectiveAt   time.Time      `gorm:"not null;uniqueIndex:idx_exchange_rates_pair_effective"`
+}
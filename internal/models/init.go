@@ -2,18 +2,168 @@ package models
 
 import (
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // AutoMigrate automatically migrates all models
 func AutoMigrate(db *gorm.DB) error {
-	return db.AutoMigrate(
+	if err := backfillWarehouseColumns(db); err != nil {
+		return err
+	}
+
+	if err := db.AutoMigrate(
 		&User{},
+		&Supplier{},
 		&Product{},
+		&Warehouse{},
 		&Order{},
 		&OrderItem{},
+		&OrderItemAllocation{},
 		&Payment{},
+		&Refund{},
 		&Inventory{},
 		&Notification{},
 		&AuditLog{},
-	)
+		&StockMovement{},
+		&StockAlert{},
+		&PurchaseOrder{},
+		&PurchaseOrderLine{},
+		&ExchangeRate{},
+		&ProductPrice{},
+		&PriceSchedule{},
+		&ProductPriceHistory{},
+		&Cart{},
+		&CartItem{},
+		&Subscription{},
+		&SubscriptionItem{},
+		&DailySalesReport{},
+		&TopProduct{},
+		&LowStockAlert{},
+		&ReportExport{},
+		&ReportDigestSubscription{},
+		&ReportDigestDelivery{},
+		&CustomerAnalyticsSummary{},
+		&CustomerCohort{},
+		&CustomerRFMScore{},
+		&CustomerSegmentSummary{},
+		&ProductDailySales{},
+	); err != nil {
+		return err
+	}
+
+	if err := migrateInventoryWarehouses(db); err != nil {
+		return err
+	}
+	if err := migrateOrderCurrencies(db); err != nil {
+		return err
+	}
+	return createOrderListingIndexes(db)
+}
+
+// backfillWarehouseColumns adds the warehouse of inventory and ledger rows recorded
+// before warehouses existed, pointing them at the default warehouse. It runs before
+// AutoMigrate, which would otherwise add the columns as 0 and then fail to add their
+// foreign keys.
+func backfillWarehouseColumns(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var tables []string
+		for _, model := range []interface{}{&Inventory{}, &StockMovement{}} {
+			if tx.Migrator().HasTable(model) && !tx.Migrator().HasColumn(model, "WarehouseID") {
+				stmt := &gorm.Statement{DB: tx}
+				if err := stmt.Parse(model); err != nil {
+					return err
+				}
+				tables = append(tables, stmt.Schema.Table)
+			}
+		}
+		if len(tables) == 0 {
+			return nil
+		}
+
+		if err := tx.AutoMigrate(&Warehouse{}); err != nil {
+			return err
+		}
+		warehouse, err := defaultWarehouse(tx)
+		if err != nil {
+			return err
+		}
+
+		// Added as nullable, filled in, then made NOT NULL; AutoMigrate adds the rest
+		for _, table := range tables {
+			if err := tx.Exec("ALTER TABLE ? ADD COLUMN warehouse_id bigint", clause.Table{Name: table}).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("UPDATE ? SET warehouse_id = ?", clause.Table{Name: table}, warehouse.ID).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec("ALTER TABLE ? ALTER COLUMN warehouse_id SET NOT NULL", clause.Table{Name: table}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// defaultWarehouse returns the default warehouse, creating it if there is none
+func defaultWarehouse(tx *gorm.DB) (*Warehouse, error) {
+	var warehouse Warehouse
+	err := tx.Where(Warehouse{IsDefault: true}).
+		Attrs(Warehouse{Code: DefaultWarehouseCode, Name: "Main warehouse", Active: true}).
+		FirstOrCreate(&warehouse).Error
+	if err != nil {
+		return nil, err
+	}
+	return &warehouse, nil
+}
+
+// migrateInventoryWarehouses moves single-warehouse data onto the default warehouse:
+// it drops the old one-row-per-product index, ensures a default warehouse exists and
+// allocates existing order items to it. Inventory and ledger rows are assigned to it by
+// backfillWarehouseColumns.
+func migrateInventoryWarehouses(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if tx.Migrator().HasIndex(&Inventory{}, "idx_inventories_product_id") {
+			if err := tx.Migrator().DropIndex(&Inventory{}, "idx_inventories_product_id"); err != nil {
+				return err
+			}
+		}
+
+		warehouse, err := defaultWarehouse(tx)
+		if err != nil {
+			return err
+		}
+
+		return tx.Exec(`
+			INSERT INTO order_item_allocations (created_at, updated_at, order_item_id, warehouse_id, quantity)
+			SELECT NOW(), NOW(), order_items.id, ?, order_items.quantity
+			FROM order_items
+			WHERE order_items.deleted_at IS NULL
+			AND NOT EXISTS (
+				SELECT 1 FROM order_item_allocations WHERE order_item_allocations.order_item_id = order_items.id
+			)`, warehouse.ID).Error
+	})
+}
+
+// migrateOrderCurrencies fills the base currency amounts of orders placed before
+// multi-currency support, when every order was in the base currency
+func migrateOrderCurrencies(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			UPDATE orders SET base_total_amount = total_amount
+			WHERE base_total_amount = 0 AND total_amount <> 0`).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`
+			UPDATE order_items SET base_price = price
+			WHERE base_price = 0 AND price <> 0`).Error
+	})
+}
+
+// createOrderListingIndexes adds the indexes behind keyset pagination of a user's orders
+// and the product filter, which can't be declared on the embedded gorm.Model fields
+func createOrderListingIndexes(db *gorm.DB) error {
+	if err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_orders_user_created_id ON orders (user_id, created_at, id)`).Error; err != nil {
+		return err
+	}
+	return db.Exec(`CREATE INDEX IF NOT EXISTS idx_order_items_order_product ON order_items (order_id, product_id)`).Error
 }
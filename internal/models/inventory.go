@@ -6,9 +6,11 @@ import (
 
 type Inventory struct {
 	gorm.Model
-	ProductID    uint    `gorm:"uniqueIndex;not null"`
-	Product      *Product `gorm:"foreignKey:ProductID"`
-	Quantity     int     `gorm:"not null"`
-	Reserved     int     `gorm:"not null;default:0"`     // Quantity reserved for pending orders
-	MinimumStock int     `gorm:"not null;default:10"`  // Minimum stock level before alerts
+	ProductID    uint       `gorm:"uniqueIndex:idx_inventories_product_warehouse,priority:1;not null"`
+	Product      *Product   `gorm:"foreignKey:ProductID"`
+	WarehouseID  uint       `gorm:"uniqueIndex:idx_inventories_product_warehouse,priority:2;not null"`
+	Warehouse    *Warehouse `gorm:"foreignKey:WarehouseID"`
+	Quantity     int        `gorm:"not null"`
+	Reserved     int        `gorm:"not null;default:0"`  // Quantity reserved for pending orders
+	MinimumStock int        `gorm:"not null;default:10"` // Minimum stock level before alerts
 }
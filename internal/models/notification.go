@@ -12,6 +12,7 @@ const (
 	NotificationTypeOrder    NotificationType = "order"
 	NotificationTypePayment  NotificationType = "payment"
 	NotificationTypeInventory NotificationType = "inventory"
+	NotificationTypeSubscription NotificationType = "subscription"
 )
 
 type Notification struct {
@@ -1,26 +1,44 @@
 package models
 
 import (
+	"time"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
 	"gorm.io/gorm"
 )
 
 type OrderStatus string
 
 const (
-	OrderStatusPending    OrderStatus = "pending"
-	OrderStatusProcessing OrderStatus = "processing"
-	OrderStatusShipped    OrderStatus = "shipped"
-	OrderStatusDelivered  OrderStatus = "delivered"
-	OrderStatusCancelled  OrderStatus = "cancelled"
+	OrderStatusPending     OrderStatus = "pending"
+	OrderStatusBackordered OrderStatus = "backordered" // Waiting for stock of at least one item
+	OrderStatusProcessing  OrderStatus = "processing"
+	OrderStatusShipped     OrderStatus = "shipped"
+	OrderStatusDelivered   OrderStatus = "delivered"
+	OrderStatusCancelled   OrderStatus = "cancelled"
 )
 
 type Order struct {
 	gorm.Model
-	UserID      uint        `gorm:"not null"`
-	User        User        `gorm:"foreignKey:UserID"`
-	OrderItems  []OrderItem `gorm:"foreignKey:OrderID"`
-	TotalAmount float64     `gorm:"type:decimal(10,2);not null"`
-	Status      OrderStatus `gorm:"type:varchar(20);not null;default:'pending'"`
-	PaymentID   *uint
-	Payment     *Payment
+	UserID             uint           `gorm:"not null"`
+	User               User           `gorm:"foreignKey:UserID"`
+	OrderItems         []OrderItem    `gorm:"foreignKey:OrderID"`
+	Currency           money.Currency `gorm:"type:varchar(3);not null;default:'USD'"`
+	ExchangeRate       money.Rate     `gorm:"type:decimal(18,8)"`                    // Base to order currency rate at checkout, if prices were converted
+	TotalAmount        money.Money    `gorm:"type:decimal(10,2);not null"`           // In the order currency
+	BaseTotalAmount    money.Money    `gorm:"type:decimal(10,2);not null;default:0"` // In the base currency, used for reporting
+	Status             OrderStatus    `gorm:"type:varchar(20);not null;default:'pending'"`
+	PaymentID          *uint
+	Payment            *Payment
+	ShippingAddress    Address `gorm:"embedded;embeddedPrefix:shipping_"`
+	CancellationReason string  `gorm:"size:500"`
+	CancelledBy        *uint   // User who cancelled the order, the customer or an admin
+	CancelledAt        *time.Time
+	DeliveredAt        *time.Time // When the order was marked delivered; revenue is recognised then
+}
+
+// AfterFind tags the order total with the order's currency once the row is read
+func (o *Order) AfterFind(tx *gorm.DB) (err error) {
+	o.TotalAmount, err = o.TotalAmount.As(o.Currency)
+	return err
 }
@@ -1,15 +1,28 @@
 package models
 
 import (
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
 	"gorm.io/gorm"
 )
 
 type OrderItem struct {
 	gorm.Model
-	OrderID   uint    `gorm:"not null"`
-	Order     Order   `gorm:"foreignKey:OrderID"`
-	ProductID uint    `gorm:"not null"`
-	Product   Product `gorm:"foreignKey:ProductID"`
-	Quantity  int     `gorm:"not null"`
-	Price     float64 `gorm:"type:decimal(10,2);not null"`
+	OrderID             uint                  `gorm:"not null"`
+	Order               Order                 `gorm:"foreignKey:OrderID"`
+	ProductID           uint                  `gorm:"not null"`
+	Product             Product               `gorm:"foreignKey:ProductID"`
+	Quantity            int                   `gorm:"not null"`
+	Currency            money.Currency        `gorm:"type:varchar(3);not null;default:'USD'"`
+	Price               money.Money           `gorm:"type:decimal(10,2);not null"`           // Unit price in the order currency
+	BasePrice           money.Money           `gorm:"type:decimal(10,2);not null;default:0"` // Unit price in the base currency
+	BackorderedQuantity int                   `gorm:"not null;default:0"`                    // Units still waiting for stock
+	CancelledQuantity   int                   `gorm:"not null;default:0"`                    // Units removed from the order after checkout
+	PriceScheduleID     *uint                 // Sale or scheduled price the item was sold at, if any
+	Allocations         []OrderItemAllocation `gorm:"foreignKey:OrderItemID"`
+}
+
+// AfterFind tags the unit price with the order currency once the row is read
+func (i *OrderItem) AfterFind(tx *gorm.DB) (err error) {
+	i.Price, err = i.Price.As(i.Currency)
+	return err
 }
@@ -0,0 +1,14 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// OrderItemAllocation records how many units of an order item are reserved in which warehouse
+type OrderItemAllocation struct {
+	gorm.Model
+	OrderItemID uint       `gorm:"not null;index"`
+	WarehouseID uint       `gorm:"not null;index"`
+	Warehouse   *Warehouse `gorm:"foreignKey:WarehouseID"`
+	Quantity    int        `gorm:"not null"`
+}
@@ -1,6 +1,9 @@
 package models
 
 import (
+	"time"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
 	"gorm.io/gorm"
 )
 
@@ -10,14 +13,48 @@ const (
 	PaymentStatusPending   PaymentStatus = "pending"
 	PaymentStatusSucceeded PaymentStatus = "succeeded"
 	PaymentStatusFailed    PaymentStatus = "failed"
+	PaymentStatusRefunded  PaymentStatus = "refunded"
 )
 
 type Payment struct {
 	gorm.Model
-	OrderID     uint          `gorm:"uniqueIndex;not null"`
-	Order       Order         `gorm:"foreignKey:OrderID"`
-	Amount      float64       `gorm:"type:decimal(10,2);not null"`
-	Status      PaymentStatus `gorm:"type:varchar(20);default:'pending'"`
-	PaymentMethod string      `gorm:"size:50;not null"`
-	TransactionID string      `gorm:"size:100"`
+	OrderID        uint          `gorm:"uniqueIndex;not null"`
+	Order          Order         `gorm:"foreignKey:OrderID"`
+	Amount         money.Money   `gorm:"type:decimal(10,2);not null"`
+	Status         PaymentStatus `gorm:"type:varchar(20);default:'pending'"`
+	PaymentMethod  string        `gorm:"size:50;not null"`
+	TransactionID  string        `gorm:"size:100"`
+	RefundID       string        `gorm:"size:100"`                              // Transaction of the latest refund
+	RefundedAmount money.Money   `gorm:"type:decimal(10,2);not null;default:0"` // Refunded so far, in the order currency
+	RefundedAt     *time.Time
+}
+
+type RefundStatus string
+
+const (
+	RefundStatusPending   RefundStatus = "pending" // Owed to the customer, not confirmed by the provider yet
+	RefundStatusSucceeded RefundStatus = "succeeded"
+)
+
+// Refund is part of a payment owed back to the customer. It is recorded in the transaction
+// of the order change that owes it, then sent to the payment provider once that change is
+// committed and retried until the provider confirms it.
+type Refund struct {
+	gorm.Model
+	PaymentID     uint           `gorm:"not null;index"`
+	OrderID       uint           `gorm:"not null;index"`
+	Currency      money.Currency `gorm:"type:varchar(3);not null"`
+	Amount        money.Money    `gorm:"type:decimal(10,2);not null"`
+	Reason        string         `gorm:"type:text"`
+	Status        RefundStatus   `gorm:"type:varchar(20);not null;default:'pending';index"`
+	TransactionID string         `gorm:"size:100"` // Refund transaction at the provider
+	Attempts      int            `gorm:"not null;default:0"`
+	LastError     string         `gorm:"type:text"`
+	RefundedAt    *time.Time
+}
+
+// AfterFind tags the amount with the order currency once the row is read
+func (r *Refund) AfterFind(tx *gorm.DB) (err error) {
+	r.Amount, err = r.Amount.As(r.Currency)
+	return err
 }
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
+	"gorm.io/gorm"
+)
+
+// PriceChangeSource says what changed a product's price
+type PriceChangeSource string
+
+const (
+	PriceChangeManual   PriceChangeSource = "manual"   // Product created or updated by an admin
+	PriceChangeSchedule PriceChangeSource = "schedule" // A price schedule started, ended or was cancelled
+)
+
+// ProductPriceHistory records the base price a product sold at from EffectiveAt
+type ProductPriceHistory struct {
+	gorm.Model
+	ProductID       uint              `gorm:"not null;index:idx_product_price_histories_product_effective"`
+	Price           money.Money       `gorm:"type:decimal(10,2);not null"`
+	CompareAtPrice  money.Money       `gorm:"type:decimal(10,2);not null;default:0"` // Zero when not on sale
+	Source          PriceChangeSource `gorm:"type:varchar(20);not null"`
+	PriceScheduleID *uint
+	ActorID         *uint
+	EffectiveAt     time.Time `gorm:"not null;index:idx_product_price_histories_product_effective"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
+	"gorm.io/gorm"
+)
+
+// PriceScheduleStatus tracks where a price schedule is in its lifecycle
+type PriceScheduleStatus string
+
+const (
+	PriceScheduleScheduled PriceScheduleStatus = "scheduled"
+	PriceScheduleActive    PriceScheduleStatus = "active"
+	PriceScheduleEnded     PriceScheduleStatus = "ended"
+	PriceScheduleCancelled PriceScheduleStatus = "cancelled"
+)
+
+// PriceSchedule sets a product's base price from StartsAt. With an EndsAt it is a sale
+// that overrides the regular price until then; without one it is a planned price change
+// that replaces Product.Price once it starts.
+type PriceSchedule struct {
+	gorm.Model
+	ProductID      uint                `gorm:"not null;index"`
+	Product        Product             `gorm:"foreignKey:ProductID"`
+	Price          money.Money         `gorm:"type:decimal(10,2);not null"`
+	CompareAtPrice money.Money         `gorm:"type:decimal(10,2);not null;default:0"` // Shown as the "was" price; zero means the regular price
+	StartsAt       time.Time           `gorm:"not null;index"`
+	EndsAt         *time.Time          `gorm:"index"`
+	Label          string              `gorm:"size:100"`
+	Status         PriceScheduleStatus `gorm:"type:varchar(20);not null;default:'scheduled';index"`
+	CreatedBy      *uint
+}
+
+// IsSale reports whether the schedule reverts to the regular price when it ends
+func (s *PriceSchedule) IsSale() bool {
+	return s.EndsAt != nil
+}
+
+// InEffect reports whether the schedule sets the product's price at the given time
+func (s *PriceSchedule) InEffect(at time.Time) bool {
+	if s.Status != PriceScheduleScheduled && s.Status != PriceScheduleActive {
+		return false
+	}
+	return !at.Before(s.StartsAt) && (s.EndsAt == nil || at.Before(*s.EndsAt))
+}
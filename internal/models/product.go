@@ -1,16 +1,54 @@
 package models
 
 import (
+	"math"
+	"time"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
 	"gorm.io/gorm"
 )
 
+// StockPolicy decides what happens when an order asks for more than is in stock
+type StockPolicy string
+
+const (
+	StockPolicyDeny      StockPolicy = "deny"      // Reject the order
+	StockPolicyBackorder StockPolicy = "backorder" // Accept up to BackorderLimit units on backorder
+	StockPolicyPreorder  StockPolicy = "preorder"  // Accept pre-orders until PreorderReleaseAt
+)
+
 type Product struct {
 	gorm.Model
-	Name        string  `gorm:"size:100;not null"`
-	Description string  `gorm:"type:text"`
-	Price       float64 `gorm:"type:decimal(10,2);not null"`
-	Quantity    int     `gorm:"not null;default:0"`
-	SKU         string  `gorm:"uniqueIndex;size:50;not null"`
-	Inventory   *Inventory
-	OrderItems  []OrderItem `gorm:"foreignKey:ProductID"`
+	Name                string      `gorm:"size:100;not null"`
+	Description         string      `gorm:"type:text"`
+	Price               money.Money `gorm:"type:decimal(10,2);not null"`
+	Quantity            int         `gorm:"not null;default:0"`
+	SKU                 string      `gorm:"uniqueIndex;size:50;not null"`
+	PreferredSupplierID *uint
+	PreferredSupplier   *Supplier   `gorm:"foreignKey:PreferredSupplierID"`
+	StockPolicy         StockPolicy `gorm:"type:varchar(20);not null;default:'deny'"`
+	BackorderLimit      int         `gorm:"not null;default:0"` // Maximum units outstanding on backorder; 0 means unlimited for pre-orders
+	PreorderReleaseAt   *time.Time
+	Discontinued        bool `gorm:"not null;default:false"` // No longer sold; kept for past orders
+	Inventory           *Inventory
+	OrderItems          []OrderItem `gorm:"foreignKey:ProductID"`
+}
+
+// BackorderAllowance returns how many units in total may be outstanding on backorder
+// at the given time, and whether the product accepts backorders at all
+func (p *Product) BackorderAllowance(now time.Time) (int, bool) {
+	switch p.StockPolicy {
+	case StockPolicyBackorder:
+		return p.BackorderLimit, p.BackorderLimit > 0
+	case StockPolicyPreorder:
+		if p.PreorderReleaseAt != nil && !now.Before(*p.PreorderReleaseAt) {
+			return 0, false
+		}
+		if p.BackorderLimit == 0 {
+			return math.MaxInt, true
+		}
+		return p.BackorderLimit, true
+	default:
+		return 0, false
+	}
 }
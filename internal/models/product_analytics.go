@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
+	"gorm.io/gorm"
+)
+
+// ProductDailySales rolls up the order items of a product in the orders placed on a UTC
+// day. Rows are rebuilt whenever an order of the day changes, so cancellations made
+// later count against the day the units were ordered, as in the daily sales report.
+type ProductDailySales struct {
+	gorm.Model
+	Date           time.Time   `gorm:"uniqueIndex:idx_product_daily_sales_date_product,priority:1;not null"`
+	ProductID      uint        `gorm:"uniqueIndex:idx_product_daily_sales_date_product,priority:2;index;not null"`
+	Product        *Product    `gorm:"foreignKey:ProductID"`
+	Orders         int         `gorm:"not null"`                              // Orders still holding units of the product
+	UnitsOrdered   int         `gorm:"not null"`                              // As ordered at checkout
+	UnitsCancelled int         `gorm:"not null"`                              // Cancelled or removed from orders since
+	UnitsSold      int         `gorm:"not null"`                              // Ordered less cancelled
+	Revenue        money.Money `gorm:"type:decimal(12,2);not null;default:0"` // Units sold, in the base currency
+	Cancellations  money.Money `gorm:"type:decimal(12,2);not null;default:0"` // Units cancelled, in the base currency
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
+	"gorm.io/gorm"
+)
+
+// ProductPrice is a list price for a product in a currency other than the base
+// currency. It takes precedence over converting the base price.
+type ProductPrice struct {
+	gorm.Model
+	ProductID uint           `gorm:"not null;uniqueIndex:idx_product_prices_product_currency"`
+	Currency  money.Currency `gorm:"type:varchar(3);not null;uniqueIndex:idx_product_prices_product_currency"`
+	Price     money.Money    `gorm:"type:decimal(10,2);not null"`
+}
+
+// AfterFind tags the price with its currency once the row is read
+func (p *ProductPrice) AfterFind(tx *gorm.DB) (err error) {
+	p.Price, err = p.Price.As(p.Currency)
+	return err
+}
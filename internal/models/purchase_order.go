@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
+	"gorm.io/gorm"
+)
+
+type PurchaseOrderStatus string
+
+const (
+	PurchaseOrderStatusDraft             PurchaseOrderStatus = "draft"
+	PurchaseOrderStatusSent              PurchaseOrderStatus = "sent"
+	PurchaseOrderStatusPartiallyReceived PurchaseOrderStatus = "partially_received"
+	PurchaseOrderStatusReceived          PurchaseOrderStatus = "received"
+	PurchaseOrderStatusCancelled         PurchaseOrderStatus = "cancelled"
+)
+
+// PurchaseOrder is an order placed with a supplier to restock a warehouse
+type PurchaseOrder struct {
+	gorm.Model
+	Number       string              `gorm:"uniqueIndex;size:30;not null"`
+	SupplierID   uint                `gorm:"not null;index"`
+	Supplier     *Supplier           `gorm:"foreignKey:SupplierID"`
+	WarehouseID  uint                `gorm:"not null;index"` // Warehouse the goods are delivered to
+	Warehouse    *Warehouse          `gorm:"foreignKey:WarehouseID"`
+	Status       PurchaseOrderStatus `gorm:"type:varchar(20);not null;index"`
+	ExpectedAt   *time.Time
+	SentAt       *time.Time
+	ReceivedAt   *time.Time
+	Notes        string `gorm:"type:text"`
+	StockAlertID *uint  `gorm:"index"` // Low stock alert the order was drafted from
+	CreatedByID  *uint
+	CreatedBy    *User               `gorm:"foreignKey:CreatedByID"`
+	Lines        []PurchaseOrderLine `gorm:"foreignKey:PurchaseOrderID"`
+}
+
+type PurchaseOrderLine struct {
+	gorm.Model
+	PurchaseOrderID  uint        `gorm:"not null;index"`
+	ProductID        uint        `gorm:"not null;index"`
+	Product          *Product    `gorm:"foreignKey:ProductID"`
+	QuantityOrdered  int         `gorm:"not null"`
+	QuantityReceived int         `gorm:"not null;default:0"`
+	UnitCost         money.Money `gorm:"type:decimal(10,2);not null;default:0"`
+	ExpectedAt       *time.Time
+}
+
+// Outstanding returns the quantity still to be delivered on the line
+func (l *PurchaseOrderLine) Outstanding() int {
+	return l.QuantityOrdered - l.QuantityReceived
+}
@@ -3,44 +3,62 @@ package models
 import (
 	"time"
 
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
 	"gorm.io/gorm"
 )
 
 type DailySalesReport struct {
 	gorm.Model
 	Date                 time.Time       `gorm:"uniqueIndex;not null"`
-	TotalOrders         int             `gorm:"not null"`
-	PendingOrders       int             `gorm:"not null"`
-	ProcessingOrders    int             `gorm:"not null"`
-	ShippedOrders       int             `gorm:"not null"`
-	DeliveredOrders     int             `gorm:"not null"`
-	CancelledOrders     int             `gorm:"not null"`
-	TotalRevenue        float64         `gorm:"type:decimal(10,2);not null"`
-	AverageOrderValue   float64         `gorm:"type:decimal(10,2);not null"`
-	UniqueCustomers     int             `gorm:"not null"`
-	NewCustomers        int             `gorm:"not null"`
-	TopProducts         []TopProduct    `gorm:"foreignKey:ReportID"`
-	LowStockProducts    []LowStockAlert `gorm:"foreignKey:ReportID"`
-	OrderFulfillmentRate float64        `gorm:"type:decimal(5,2);not null"` // Percentage
-	CancellationRate    float64        `gorm:"type:decimal(5,2);not null"` // Percentage
+	TotalOrders          int             `gorm:"not null"`
+	PendingOrders        int             `gorm:"not null"`
+	ProcessingOrders     int             `gorm:"not null"`
+	ShippedOrders        int             `gorm:"not null"`
+	DeliveredOrders      int             `gorm:"not null"`
+	CancelledOrders      int             `gorm:"not null"`
+	Currency             money.Currency  `gorm:"type:varchar(3);not null;default:'USD'"` // Reporting currency of the revenue figures
+	GrossBookings        money.Money     `gorm:"type:decimal(10,2);not null;default:0"`  // Orders placed on the day, as booked at checkout
+	Cancellations        money.Money     `gorm:"type:decimal(10,2);not null;default:0"`  // Part of the bookings cancelled or removed since
+	NetRevenue           money.Money     `gorm:"type:decimal(10,2);not null;default:0"`  // Bookings less cancellations
+	RecognisedRevenue    money.Money     `gorm:"type:decimal(10,2);not null;default:0"`  // Orders delivered on the day
+	TotalRevenue         money.Money     `gorm:"type:decimal(10,2);not null"`            // Same as NetRevenue, kept for existing clients
+	AverageOrderValue    money.Money     `gorm:"type:decimal(10,2);not null"`
+	UniqueCustomers      int             `gorm:"not null"`
+	NewCustomers         int             `gorm:"not null"`
+	TopProducts          []TopProduct    `gorm:"foreignKey:ReportID"`
+	LowStockProducts     []LowStockAlert `gorm:"foreignKey:ReportID"`
+	OrderFulfillmentRate float64         `gorm:"type:decimal(5,2);not null"` // Percentage
+	CancellationRate     float64         `gorm:"type:decimal(5,2);not null"` // Percentage
+}
+
+// AfterFind tags the revenue figures with the report's currency once the row is read
+func (r *DailySalesReport) AfterFind(tx *gorm.DB) (err error) {
+	for _, amount := range []*money.Money{
+		&r.GrossBookings, &r.Cancellations, &r.NetRevenue, &r.RecognisedRevenue, &r.TotalRevenue, &r.AverageOrderValue,
+	} {
+		if *amount, err = amount.As(r.Currency); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 type TopProduct struct {
 	gorm.Model
-	ReportID      uint    `gorm:"not null"`
-	ProductID     uint    `gorm:"not null"`
-	ProductName   string  `gorm:"size:100;not null"`
-	QuantitySold  int     `gorm:"not null"`
-	Revenue       float64 `gorm:"type:decimal(10,2);not null"`
-	StockTurnover float64 `gorm:"type:decimal(5,2);not null"` // Sales quantity / Average inventory
+	ReportID      uint        `gorm:"not null"`
+	ProductID     uint        `gorm:"not null"`
+	ProductName   string      `gorm:"size:100;not null"`
+	QuantitySold  int         `gorm:"not null"`
+	Revenue       money.Money `gorm:"type:decimal(10,2);not null"`
+	StockTurnover float64     `gorm:"type:decimal(10,2);not null"` // Sales quantity / Average inventory
 }
 
 type LowStockAlert struct {
 	gorm.Model
-	ReportID       uint    `gorm:"not null"`
-	ProductID      uint    `gorm:"not null"`
-	ProductName    string  `gorm:"size:100;not null"`
-	CurrentStock   int     `gorm:"not null"`
-	ReservedStock  int     `gorm:"not null"`
-	ReorderPoint   int     `gorm:"not null"`
+	ReportID      uint   `gorm:"not null"`
+	ProductID     uint   `gorm:"not null"`
+	ProductName   string `gorm:"size:100;not null"`
+	CurrentStock  int    `gorm:"not null"`
+	ReservedStock int    `gorm:"not null"`
+	ReorderPoint  int    `gorm:"not null"`
 }
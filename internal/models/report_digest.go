@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ReportDigestFrequency is how often a report digest is emailed
+type ReportDigestFrequency string
+
+const (
+	ReportDigestDaily  ReportDigestFrequency = "daily"  // Yesterday's report, every morning
+	ReportDigestWeekly ReportDigestFrequency = "weekly" // Last week's report, every Monday
+)
+
+// ReportDigestDeliveryStatus tracks the email of a digest
+type ReportDigestDeliveryStatus string
+
+const (
+	ReportDigestDeliveryPending ReportDigestDeliveryStatus = "pending"
+	ReportDigestDeliverySent    ReportDigestDeliveryStatus = "sent"
+	ReportDigestDeliveryFailed  ReportDigestDeliveryStatus = "failed"
+)
+
+// ReportDigestSubscription emails an admin the sales report of every day or week
+type ReportDigestSubscription struct {
+	gorm.Model
+	UserID    uint                  `gorm:"not null;uniqueIndex:idx_report_digest_user_frequency"`
+	User      User                  `gorm:"foreignKey:UserID"`
+	Frequency ReportDigestFrequency `gorm:"type:varchar(10);not null;uniqueIndex:idx_report_digest_user_frequency"`
+	Active    bool                  `gorm:"not null;default:true"`
+}
+
+// ReportDigestDelivery records the email of a subscription's digest for one period
+type ReportDigestDelivery struct {
+	gorm.Model
+	SubscriptionID uint                       `gorm:"not null;uniqueIndex:idx_report_digest_delivery_period"`
+	UserID         uint                       `gorm:"not null;index"`
+	PeriodStart    time.Time                  `gorm:"type:date;not null;uniqueIndex:idx_report_digest_delivery_period"`
+	PeriodEnd      time.Time                  `gorm:"type:date;not null"` // Inclusive
+	Email          string                     `gorm:"size:255;not null"`
+	Status         ReportDigestDeliveryStatus `gorm:"type:varchar(20);not null;default:'pending'"`
+	Attempts       int                        `gorm:"not null;default:0"`
+	LastError      string                     `gorm:"type:text"`
+	SentAt         *time.Time
+}
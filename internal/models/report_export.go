@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ReportExportStatus tracks a report export job
+type ReportExportStatus string
+
+const (
+	ReportExportStatusPending   ReportExportStatus = "pending"
+	ReportExportStatusRunning   ReportExportStatus = "running"
+	ReportExportStatusCompleted ReportExportStatus = "completed"
+	ReportExportStatusFailed    ReportExportStatus = "failed"
+)
+
+// ReportExport is a date range sales report rendered to a file in the background, kept
+// until it expires for download through a signed link
+type ReportExport struct {
+	gorm.Model
+	RequestedBy uint               `gorm:"not null;index"`
+	StartDate   time.Time          `gorm:"type:date;not null"`
+	EndDate     time.Time          `gorm:"type:date;not null"` // Inclusive
+	Granularity string             `gorm:"type:varchar(10);not null"`
+	Format      string             `gorm:"type:varchar(10);not null"`
+	Status      ReportExportStatus `gorm:"type:varchar(20);not null;default:'pending';index"`
+	FileName    string             `gorm:"size:255"`
+	ContentType string             `gorm:"size:100"`
+	Content     []byte             `gorm:"type:bytea"`
+	Error       string             `gorm:"type:text"`
+	StartedAt   *time.Time
+	CompletedAt *time.Time
+	ExpiresAt   *time.Time `gorm:"index"` // When the file is deleted; set once completed
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type StockAlertStatus string
+
+const (
+	StockAlertStatusOpen     StockAlertStatus = "open"
+	StockAlertStatusResolved StockAlertStatus = "resolved"
+)
+
+// StockAlert records a product running low in a warehouse. At most one alert per
+// product and warehouse is open at a time; it is resolved once stock recovers.
+type StockAlert struct {
+	gorm.Model
+	ProductID                uint             `gorm:"not null;index;uniqueIndex:idx_stock_alerts_open,where:status = 'open' AND deleted_at IS NULL"`
+	Product                  *Product         `gorm:"foreignKey:ProductID"`
+	WarehouseID              uint             `gorm:"not null;uniqueIndex:idx_stock_alerts_open"`
+	Warehouse                *Warehouse       `gorm:"foreignKey:WarehouseID"`
+	Status                   StockAlertStatus `gorm:"type:varchar(20);not null;index"`
+	StockLevel               int              `gorm:"not null"`
+	Reserved                 int              `gorm:"not null"`
+	MinimumStock             int              `gorm:"not null"`
+	DailySalesVelocity       float64          `gorm:"type:decimal(10,2);not null"`
+	SuggestedReorderQuantity int              `gorm:"not null"`
+	TriggeredAt              time.Time        `gorm:"not null"`
+	ResolvedAt               *time.Time
+}
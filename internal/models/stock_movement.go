@@ -0,0 +1,51 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+type StockMovementType string
+
+const (
+	StockMovementReceipt     StockMovementType = "receipt"
+	StockMovementSale        StockMovementType = "sale"
+	StockMovementReservation StockMovementType = "reservation"
+	StockMovementRelease     StockMovementType = "release"
+	StockMovementAdjustment  StockMovementType = "adjustment"
+	StockMovementReturn      StockMovementType = "return"
+	StockMovementShrinkage   StockMovementType = "shrinkage"
+	StockMovementTransferOut StockMovementType = "transfer_out"
+	StockMovementTransferIn  StockMovementType = "transfer_in"
+)
+
+// Reference document types recorded on stock movements
+const (
+	StockReferenceOrder      = "order"
+	StockReferenceAdjustment = "adjustment"
+	StockReferenceProduct    = "product"
+	StockReferenceTransfer   = "transfer"
+	StockReferencePurchase   = "purchase_order"
+)
+
+// StockMovement is a single ledger entry explaining a change to an inventory row.
+// QuantityDelta is applied to Inventory.Quantity (available stock) and
+// ReservedDelta to Inventory.Reserved, so summing the movements of a product in a
+// warehouse reproduces that warehouse's current inventory.
+type StockMovement struct {
+	gorm.Model
+	ProductID     uint              `gorm:"not null;index"`
+	Product       *Product          `gorm:"foreignKey:ProductID"`
+	WarehouseID   uint              `gorm:"not null;index"`
+	Warehouse     *Warehouse        `gorm:"foreignKey:WarehouseID"`
+	Type          StockMovementType `gorm:"type:varchar(20);not null;index"`
+	QuantityDelta int               `gorm:"not null"`
+	ReservedDelta int               `gorm:"not null;default:0"`
+	QuantityAfter int               `gorm:"not null"` // Available stock after the movement
+	ReservedAfter int               `gorm:"not null"` // Reserved stock after the movement
+	ReferenceType string            `gorm:"size:50;index:idx_stock_movements_reference,priority:1"`
+	ReferenceID   *uint             `gorm:"index:idx_stock_movements_reference,priority:2"`
+	Reference     string            `gorm:"size:100"` // Free-form document number (e.g. stock count sheet)
+	Note          string            `gorm:"type:text"`
+	ActorID       *uint
+	Actor         *User `gorm:"foreignKey:ActorID"`
+}
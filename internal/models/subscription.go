@@ -0,0 +1,80 @@
+package models
+
+import (
+	"time"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
+	"gorm.io/gorm"
+)
+
+// SubscriptionStatus tracks whether a subscription places orders
+type SubscriptionStatus string
+
+const (
+	SubscriptionStatusActive    SubscriptionStatus = "active"
+	SubscriptionStatusPaused    SubscriptionStatus = "paused"
+	SubscriptionStatusCancelled SubscriptionStatus = "cancelled"
+)
+
+// SubscriptionCadence is how often a subscription renews
+type SubscriptionCadence string
+
+const (
+	SubscriptionCadenceWeekly   SubscriptionCadence = "weekly"
+	SubscriptionCadenceBiweekly SubscriptionCadence = "biweekly"
+	SubscriptionCadenceMonthly  SubscriptionCadence = "monthly"
+)
+
+// Next returns the renewal one cadence period after t
+func (c SubscriptionCadence) Next(t time.Time) time.Time {
+	switch c {
+	case SubscriptionCadenceBiweekly:
+		return t.AddDate(0, 0, 14)
+	case SubscriptionCadenceMonthly:
+		return t.AddDate(0, 1, 0)
+	default:
+		return t.AddDate(0, 0, 7)
+	}
+}
+
+// NextAfter returns the first renewal on the cadence from t that is after now
+func (c SubscriptionCadence) NextAfter(t, now time.Time) time.Time {
+	for !t.After(now) {
+		t = c.Next(t)
+	}
+	return t
+}
+
+// Subscription places an order for the same items on a cadence, charged to a stored
+// payment method
+type Subscription struct {
+	gorm.Model
+	UserID             uint                `gorm:"not null;index"`
+	User               User                `gorm:"foreignKey:UserID"`
+	Items              []SubscriptionItem  `gorm:"foreignKey:SubscriptionID"`
+	Cadence            SubscriptionCadence `gorm:"type:varchar(20);not null"`
+	Status             SubscriptionStatus  `gorm:"type:varchar(20);not null;default:'active';index"`
+	Currency           money.Currency      `gorm:"type:varchar(3);not null"`
+	ShippingAddress    Address             `gorm:"embedded;embeddedPrefix:shipping_"`
+	PaymentMethodToken string              `gorm:"size:100;not null"` // Token of the stored payment method, never card details
+	NextRenewalAt      time.Time           `gorm:"not null;index"`
+	RetryAt            *time.Time          `gorm:"index"` // When a failed renewal is tried again
+	ReminderSentAt     *time.Time          // Reminder for the upcoming renewal
+	FailureCount       int                 `gorm:"not null;default:0"` // Failed attempts of the current renewal
+	LastFailureReason  string              `gorm:"size:500"`
+	ClaimedUntil       *time.Time          // Until when an instance renewing the subscription has it to itself
+	RenewalOrderID     *uint               // Order placed for the renewal in progress, before it is charged
+	LastOrderID        *uint
+	LastRenewedAt      *time.Time
+	PausedAt           *time.Time
+	CancelledAt        *time.Time
+}
+
+// SubscriptionItem is a product and quantity ordered on every renewal
+type SubscriptionItem struct {
+	gorm.Model
+	SubscriptionID uint    `gorm:"not null;index"`
+	ProductID      uint    `gorm:"not null"`
+	Product        Product `gorm:"foreignKey:ProductID"`
+	Quantity       int     `gorm:"not null"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+type Supplier struct {
+	gorm.Model
+	Name         string `gorm:"size:100;not null"`
+	Email        string `gorm:"size:255"`
+	Phone        string `gorm:"size:50"`
+	LeadTimeDays int    `gorm:"not null;default:7"` // Typical days between sending a purchase order and delivery
+	Active       bool   `gorm:"default:true"`
+}
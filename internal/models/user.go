@@ -1,6 +1,9 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
+	"gorm.io/gorm"
+)
 
 type UserRole string
 
@@ -11,11 +14,12 @@ const (
 
 type User struct {
 	gorm.Model
-	Email     string   `gorm:"uniqueIndex;not null"`
-	Password  string   `gorm:"not null"`
-	FirstName string   `gorm:"size:100"`
-	LastName  string   `gorm:"size:100"`
-	Role      UserRole `gorm:"type:varchar(20);default:'customer'"`
-	Active    bool     `gorm:"default:true"`
-	Orders    []Order  `gorm:"foreignKey:UserID"`
+	Email             string         `gorm:"uniqueIndex;not null"`
+	Password          string         `gorm:"not null"`
+	FirstName         string         `gorm:"size:100"`
+	LastName          string         `gorm:"size:100"`
+	Role              UserRole       `gorm:"type:varchar(20);default:'customer'"`
+	Active            bool           `gorm:"default:true"`
+	PreferredCurrency money.Currency `gorm:"type:varchar(3)"` // Used when a request doesn't pick a currency
+	Orders            []Order        `gorm:"foreignKey:UserID"`
 }
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+// DefaultWarehouseCode is the code of the warehouse created for inventory that predates warehouses
+const DefaultWarehouseCode = "MAIN"
+
+type Warehouse struct {
+	gorm.Model
+	Code      string  `gorm:"uniqueIndex;size:20;not null"`
+	Name      string  `gorm:"size:100;not null"`
+	Address   Address `gorm:"embedded"`
+	IsDefault bool    `gorm:"not null;default:false"` // Used when a stock change does not name a warehouse
+	Active    bool    `gorm:"not null;default:true"`  // Inactive warehouses are skipped during allocation
+}
@@ -0,0 +1,389 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
+	"gorm.io/gorm"
+)
+
+// CustomerOrderStats sums up a customer's orders that weren't cancelled
+type CustomerOrderStats struct {
+	UserID       uint
+	Orders       int
+	Revenue      money.Money // In the base currency
+	FirstOrderAt time.Time
+	LastOrderAt  time.Time
+}
+
+// CohortActivity counts the orders placed in a month by the customers who first ordered
+// in the cohort month
+type CohortActivity struct {
+	CohortMonth time.Time
+	Month       time.Time
+	Customers   int
+	Orders      int
+	Revenue     money.Money // In the base currency
+}
+
+// ProductPerformance sums up a product's daily sales over a range of days, with its
+// current available stock across warehouses
+type ProductPerformance struct {
+	ProductID      uint
+	Name           string
+	SKU            string
+	Discontinued   bool
+	Orders         int
+	UnitsOrdered   int
+	UnitsCancelled int
+	UnitsSold      int
+	Revenue        money.Money // In the base currency
+	Cancellations  money.Money // In the base currency
+	Stock          int
+}
+
+// ProductPerformanceFilter picks the products and days of a performance listing
+type ProductPerformanceFilter struct {
+	From, To      time.Time // UTC days, inclusive
+	ProductID     uint      // Only this product, if set
+	SlowMoverDays int       // If set, only products in stock whose stock would last longer than this many days at their sales rate
+}
+
+// ProductPerformanceSort orders a performance listing by one of the ProductSort* fields
+type ProductPerformanceSort struct {
+	Field      string
+	Descending bool
+}
+
+// Product performance sort fields
+const (
+	ProductSortUnitsSold        = "units_sold"
+	ProductSortRevenue          = "revenue"
+	ProductSortOrders           = "orders"
+	ProductSortCancellationRate = "cancellation_rate"
+	ProductSortSellThrough      = "sell_through"
+	ProductSortDaysOfCover      = "days_of_cover"
+	ProductSortStock            = "stock"
+)
+
+// productSortExpressions maps sort fields to SQL over the columns of ProductPerformance.
+// A product in stock that sold nothing has infinite cover.
+var productSortExpressions = map[string]string{
+	ProductSortUnitsSold:        "units_sold",
+	ProductSortRevenue:          "revenue",
+	ProductSortOrders:           "orders",
+	ProductSortCancellationRate: "units_cancelled::float8 / NULLIF(units_ordered, 0)",
+	ProductSortSellThrough:      "units_sold::float8 / NULLIF(units_sold + stock, 0)",
+	ProductSortDaysOfCover:      "CASE WHEN units_sold > 0 THEN stock::float8 / units_sold WHEN stock > 0 THEN 'Infinity'::float8 END",
+	ProductSortStock:            "stock",
+}
+
+func (s ProductPerformanceSort) apply(query *gorm.DB) *gorm.DB {
+	expression, ok := productSortExpressions[s.Field]
+	if !ok {
+		expression = productSortExpressions[ProductSortUnitsSold]
+	}
+	direction := " ASC"
+	if s.Descending {
+		direction = " DESC"
+	}
+	// Break ties by ID so pages are stable
+	return query.Order(expression + direction + " NULLS LAST").Order("product_id" + direction)
+}
+
+// analyticsBatchSize is how many summary rows are inserted per statement
+const analyticsBatchSize = 500
+
+type AnalyticsRepository interface {
+	GetCustomerOrderStats(ctx context.Context, tx *gorm.DB) ([]CustomerOrderStats, error)
+	GetCohortActivity(ctx context.Context, tx *gorm.DB) ([]CohortActivity, error)
+	ReplaceCustomerAnalytics(ctx context.Context, tx *gorm.DB, summary *models.CustomerAnalyticsSummary, cohorts []models.CustomerCohort, scores []models.CustomerRFMScore, segments []models.CustomerSegmentSummary) error
+
+	GetCustomerSummary(ctx context.Context, tx *gorm.DB) (*models.CustomerAnalyticsSummary, error)
+	ListCustomerCohorts(ctx context.Context, tx *gorm.DB, since time.Time) ([]models.CustomerCohort, error)
+	ListCustomerSegments(ctx context.Context, tx *gorm.DB) ([]models.CustomerSegmentSummary, error)
+	ListCustomerRFMScores(ctx context.Context, tx *gorm.DB, segment models.CustomerSegment, offset, limit int) ([]models.CustomerRFMScore, int64, error)
+
+	GetStaleProductSalesDays(ctx context.Context, tx *gorm.DB, before time.Time) ([]time.Time, error)
+	RollupProductSales(ctx context.Context, tx *gorm.DB, day time.Time) error
+	ListProductSales(ctx context.Context, tx *gorm.DB, productID uint, from, to time.Time) ([]models.ProductDailySales, error)
+	ListProductPerformance(ctx context.Context, tx *gorm.DB, filter ProductPerformanceFilter, sort ProductPerformanceSort, offset, limit int) ([]ProductPerformance, int64, error)
+}
+
+type analyticsRepository struct {
+	db *gorm.DB
+}
+
+func NewAnalyticsRepository(db *gorm.DB) AnalyticsRepository {
+	return &analyticsRepository{db: db}
+}
+
+// GetCustomerOrderStats sums up the orders of every customer with an order that wasn't cancelled
+func (r *analyticsRepository) GetCustomerOrderStats(ctx context.Context, tx *gorm.DB) ([]CustomerOrderStats, error) {
+	var stats []CustomerOrderStats
+	err := tx.WithContext(ctx).
+		Model(&models.Order{}).
+		Select("user_id, COUNT(*) AS orders, COALESCE(SUM(base_total_amount), 0) AS revenue, "+
+			"MIN(created_at) AS first_order_at, MAX(created_at) AS last_order_at").
+		Where("status <> ?", models.OrderStatusCancelled).
+		Group("user_id").
+		Order("user_id").
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// GetCohortActivity counts the orders of every monthly acquisition cohort in every UTC
+// month it ordered in. A customer's cohort is the month of their first order that wasn't
+// cancelled.
+func (r *analyticsRepository) GetCohortActivity(ctx context.Context, tx *gorm.DB) ([]CohortActivity, error) {
+	var activity []CohortActivity
+	err := tx.WithContext(ctx).Raw(`
+		WITH customer_orders AS (
+			SELECT user_id, date_trunc('month', created_at AT TIME ZONE 'UTC') AS month, base_total_amount
+			FROM orders
+			WHERE deleted_at IS NULL AND status <> ?
+		), cohorts AS (
+			SELECT user_id, MIN(month) AS cohort_month FROM customer_orders GROUP BY user_id
+		)
+		SELECT cohorts.cohort_month, customer_orders.month,
+			COUNT(DISTINCT customer_orders.user_id) AS customers,
+			COUNT(*) AS orders,
+			COALESCE(SUM(customer_orders.base_total_amount), 0) AS revenue
+		FROM customer_orders
+		JOIN cohorts ON cohorts.user_id = customer_orders.user_id
+		GROUP BY cohorts.cohort_month, customer_orders.month
+		ORDER BY cohorts.cohort_month, customer_orders.month`,
+		models.OrderStatusCancelled,
+	).Scan(&activity).Error
+	if err != nil {
+		return nil, err
+	}
+	return activity, nil
+}
+
+// ReplaceCustomerAnalytics replaces the stored customer analytics with the results of a new run
+func (r *analyticsRepository) ReplaceCustomerAnalytics(ctx context.Context, tx *gorm.DB, summary *models.CustomerAnalyticsSummary, cohorts []models.CustomerCohort, scores []models.CustomerRFMScore, segments []models.CustomerSegmentSummary) error {
+	db := tx.WithContext(ctx)
+	for _, model := range []any{
+		&models.CustomerAnalyticsSummary{}, &models.CustomerCohort{}, &models.CustomerRFMScore{}, &models.CustomerSegmentSummary{},
+	} {
+		if err := db.Unscoped().Where("1 = 1").Delete(model).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := db.Create(summary).Error; err != nil {
+		return err
+	}
+	if len(cohorts) > 0 {
+		if err := db.CreateInBatches(cohorts, analyticsBatchSize).Error; err != nil {
+			return err
+		}
+	}
+	if len(scores) > 0 {
+		if err := db.Omit("User").CreateInBatches(scores, analyticsBatchSize).Error; err != nil {
+			return err
+		}
+	}
+	if len(segments) > 0 {
+		if err := db.CreateInBatches(segments, analyticsBatchSize).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *analyticsRepository) GetCustomerSummary(ctx context.Context, tx *gorm.DB) (*models.CustomerAnalyticsSummary, error) {
+	var summary models.CustomerAnalyticsSummary
+	if err := tx.WithContext(ctx).Order("computed_at DESC").First(&summary).Error; err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// ListCustomerCohorts returns the cohorts of the months since the given one, by cohort
+// month and month offset
+func (r *analyticsRepository) ListCustomerCohorts(ctx context.Context, tx *gorm.DB, since time.Time) ([]models.CustomerCohort, error) {
+	var cohorts []models.CustomerCohort
+	err := tx.WithContext(ctx).
+		Where("cohort_month >= ?", since).
+		Order("cohort_month, month_offset").
+		Find(&cohorts).Error
+	if err != nil {
+		return nil, err
+	}
+	return cohorts, nil
+}
+
+// ListCustomerSegments returns the segment summaries, largest segment first
+func (r *analyticsRepository) ListCustomerSegments(ctx context.Context, tx *gorm.DB) ([]models.CustomerSegmentSummary, error) {
+	var segments []models.CustomerSegmentSummary
+	if err := tx.WithContext(ctx).Order("customers DESC, segment").Find(&segments).Error; err != nil {
+		return nil, err
+	}
+	return segments, nil
+}
+
+// ListCustomerRFMScores returns a page of customers' scores with their users, the most
+// valuable customers first, optionally limited to a segment
+func (r *analyticsRepository) ListCustomerRFMScores(ctx context.Context, tx *gorm.DB, segment models.CustomerSegment, offset, limit int) ([]models.CustomerRFMScore, int64, error) {
+	query := tx.WithContext(ctx).Model(&models.CustomerRFMScore{})
+	if segment != "" {
+		query = query.Where("segment = ?", segment)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var scores []models.CustomerRFMScore
+	err := query.
+		Preload("User").
+		Order("monetary DESC, user_id").
+		Offset(offset).
+		Limit(limit).
+		Find(&scores).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return scores, total, nil
+}
+
+// GetStaleProductSalesDays returns the UTC days before the given time with orders created,
+// changed or deleted since the day's product sales were last rolled up, oldest first.
+// Days never rolled up are stale as soon as they have an order.
+func (r *analyticsRepository) GetStaleProductSalesDays(ctx context.Context, tx *gorm.DB, before time.Time) ([]time.Time, error) {
+	var rows []struct{ Day time.Time }
+	err := tx.WithContext(ctx).Raw(`
+		WITH order_days AS (
+			SELECT date_trunc('day', orders.created_at AT TIME ZONE 'UTC') AT TIME ZONE 'UTC' AS day,
+				GREATEST(orders.updated_at, orders.deleted_at, order_items.updated_at, order_items.deleted_at) AS changed_at
+			FROM orders
+			LEFT JOIN order_items ON order_items.order_id = orders.id
+			WHERE orders.created_at < ?
+		), rollups AS (
+			SELECT date, MAX(updated_at) AS rolled_up_at FROM product_daily_sales GROUP BY date
+		)
+		SELECT DISTINCT order_days.day
+		FROM order_days
+		LEFT JOIN rollups ON rollups.date = order_days.day
+		WHERE rollups.rolled_up_at IS NULL OR order_days.changed_at > rollups.rolled_up_at
+		ORDER BY order_days.day`,
+		before,
+	).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	days := make([]time.Time, len(rows))
+	for i, row := range rows {
+		days[i] = row.Day.UTC()
+	}
+	return days, nil
+}
+
+// RollupProductSales rebuilds the product sales of the orders placed on a UTC day.
+// Bookings and cancellations are counted as in the daily sales report.
+func (r *analyticsRepository) RollupProductSales(ctx context.Context, tx *gorm.DB, day time.Time) error {
+	dayStart := day.UTC().Truncate(24 * time.Hour)
+	db := tx.WithContext(ctx)
+
+	if err := db.Unscoped().Where("date = ?", dayStart).Delete(&models.ProductDailySales{}).Error; err != nil {
+		return err
+	}
+
+	return db.Exec(`
+		INSERT INTO product_daily_sales (created_at, updated_at, date, product_id, orders,
+			units_ordered, units_cancelled, units_sold, revenue, cancellations)
+		SELECT NOW(), NOW(), ?, order_items.product_id,
+			COUNT(DISTINCT CASE WHEN orders.status <> ? AND order_items.quantity > 0 THEN orders.id END),
+			SUM(order_items.quantity + order_items.cancelled_quantity),
+			SUM(CASE WHEN orders.status = ? THEN order_items.quantity + order_items.cancelled_quantity
+				ELSE order_items.cancelled_quantity END),
+			SUM(CASE WHEN orders.status = ? THEN 0 ELSE order_items.quantity END),
+			COALESCE(SUM(CASE WHEN orders.status = ? THEN 0
+				ELSE order_items.quantity * order_items.base_price END), 0),
+			COALESCE(SUM(CASE WHEN orders.status = ? THEN (order_items.quantity + order_items.cancelled_quantity) * order_items.base_price
+				ELSE order_items.cancelled_quantity * order_items.base_price END), 0)
+		FROM order_items
+		JOIN orders ON orders.id = order_items.order_id
+		WHERE order_items.deleted_at IS NULL AND orders.deleted_at IS NULL
+			AND orders.created_at >= ? AND orders.created_at < ?
+		GROUP BY order_items.product_id`,
+		dayStart,
+		models.OrderStatusCancelled, models.OrderStatusCancelled, models.OrderStatusCancelled,
+		models.OrderStatusCancelled, models.OrderStatusCancelled,
+		dayStart, dayStart.AddDate(0, 0, 1),
+	).Error
+}
+
+// ListProductSales returns the daily sales of a product from the first UTC day through
+// the second, by day. Days without orders of the product have no row.
+func (r *analyticsRepository) ListProductSales(ctx context.Context, tx *gorm.DB, productID uint, from, to time.Time) ([]models.ProductDailySales, error) {
+	var sales []models.ProductDailySales
+	err := tx.WithContext(ctx).
+		Where("product_id = ? AND date >= ? AND date <= ?", productID, from, to).
+		Order("date").
+		Find(&sales).Error
+	if err != nil {
+		return nil, err
+	}
+	return sales, nil
+}
+
+// ListProductPerformance returns a page of products with their sales over a range of days
+// and their current stock, and the number of matching products. Products that sold
+// nothing in the range are included with zero sales.
+func (r *analyticsRepository) ListProductPerformance(ctx context.Context, tx *gorm.DB, filter ProductPerformanceFilter, sort ProductPerformanceSort, offset, limit int) ([]ProductPerformance, int64, error) {
+	db := tx.WithContext(ctx)
+
+	sales := db.Model(&models.ProductDailySales{}).
+		Select("product_id, SUM(orders) AS orders, SUM(units_ordered) AS units_ordered, "+
+			"SUM(units_cancelled) AS units_cancelled, SUM(units_sold) AS units_sold, "+
+			"SUM(revenue) AS revenue, SUM(cancellations) AS cancellations").
+		Where("date >= ? AND date <= ?", filter.From, filter.To).
+		Group("product_id")
+	stock := db.Model(&models.Inventory{}).
+		Select("product_id, SUM(quantity) AS stock").
+		Group("product_id")
+
+	performance := db.Table("products").
+		Select("products.id AS product_id, products.name, products.sku, products.discontinued, "+
+			"COALESCE(sales.orders, 0) AS orders, COALESCE(sales.units_ordered, 0) AS units_ordered, "+
+			"COALESCE(sales.units_cancelled, 0) AS units_cancelled, COALESCE(sales.units_sold, 0) AS units_sold, "+
+			"COALESCE(sales.revenue, 0) AS revenue, COALESCE(sales.cancellations, 0) AS cancellations, "+
+			"COALESCE(stock.stock, 0) AS stock").
+		Joins("LEFT JOIN (?) AS sales ON sales.product_id = products.id", sales).
+		Joins("LEFT JOIN (?) AS stock ON stock.product_id = products.id", stock).
+		Where("products.deleted_at IS NULL")
+	if filter.ProductID != 0 {
+		performance = performance.Where("products.id = ?", filter.ProductID)
+	}
+
+	query := db.Table("(?) AS performance", performance)
+	if filter.SlowMoverDays > 0 {
+		days := int(filter.To.Sub(filter.From).Hours()/24) + 1
+		query = query.Where("stock > 0 AND stock::float8 * ? > units_sold::float8 * ?", days, filter.SlowMoverDays)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var products []ProductPerformance
+	err := sort.apply(query).
+		Offset(offset).
+		Limit(limit).
+		Scan(&products).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return products, total, nil
+}
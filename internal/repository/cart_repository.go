@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+)
+
+type CartRepository interface {
+	GetOrCreateForUpdate(ctx context.Context, tx *gorm.DB, userID uint) (*models.Cart, error)
+	GetByUserID(ctx context.Context, tx *gorm.DB, userID uint) (*models.Cart, error)
+	SaveItem(ctx context.Context, tx *gorm.DB, item *models.CartItem) error
+	DeleteItem(ctx context.Context, tx *gorm.DB, cartID, productID uint) error
+	ClearItems(ctx context.Context, tx *gorm.DB, cartID uint) error
+}
+
+type cartRepository struct {
+	db *gorm.DB
+}
+
+func NewCartRepository(db *gorm.DB) CartRepository {
+	return &cartRepository{db: db}
+}
+
+// GetOrCreateForUpdate locks the user's cart, creating it on first use, and loads its items
+func (r *cartRepository) GetOrCreateForUpdate(ctx context.Context, tx *gorm.DB, userID uint) (*models.Cart, error) {
+	cart := models.Cart{UserID: userID}
+	err := tx.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "user_id"}}, DoNothing: true}).
+		Create(&cart).Error
+	if err != nil {
+		return nil, err
+	}
+
+	cart = models.Cart{}
+	err = tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ?", userID).
+		First(&cart).Error
+	if err != nil {
+		return nil, err
+	}
+	err = tx.WithContext(ctx).
+		Where("cart_id = ?", cart.ID).
+		Order("id").
+		Find(&cart.Items).Error
+	if err != nil {
+		return nil, err
+	}
+	return &cart, nil
+}
+
+// GetByUserID returns the user's cart with its items and their products
+func (r *cartRepository) GetByUserID(ctx context.Context, tx *gorm.DB, userID uint) (*models.Cart, error) {
+	var cart models.Cart
+	err := tx.WithContext(ctx).
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Preload("Items.Product").
+		Where("user_id = ?", userID).
+		First(&cart).Error
+	if err != nil {
+		return nil, err
+	}
+	return &cart, nil
+}
+
+func (r *cartRepository) SaveItem(ctx context.Context, tx *gorm.DB, item *models.CartItem) error {
+	return tx.WithContext(ctx).Omit(clause.Associations).Save(item).Error
+}
+
+// DeleteItem removes a product from a cart. Items are deleted for good so the product can
+// be added again.
+func (r *cartRepository) DeleteItem(ctx context.Context, tx *gorm.DB, cartID, productID uint) error {
+	return tx.WithContext(ctx).Unscoped().
+		Where("cart_id = ? AND product_id = ?", cartID, productID).
+		Delete(&models.CartItem{}).Error
+}
+
+func (r *cartRepository) ClearItems(ctx context.Context, tx *gorm.DB, cartID uint) error {
+	return tx.WithContext(ctx).Unscoped().
+		Where("cart_id = ?", cartID).
+		Delete(&models.CartItem{}).Error
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
+)
+
+type CurrencyRepository interface {
+	CreateRate(ctx context.Context, tx *gorm.DB, rate *models.ExchangeRate) error
+	// FindEffectiveRate returns the latest rate for the pair that took effect at or before the given time
+	FindEffectiveRate(ctx context.Context, tx *gorm.DB, base, quote money.Currency, at time.Time) (*models.ExchangeRate, error)
+	ListRates(ctx context.Context, tx *gorm.DB, base, quote money.Currency) ([]models.ExchangeRate, error)
+	// UpsertPrice creates or replaces the list price of a product in the price's currency
+	UpsertPrice(ctx context.Context, tx *gorm.DB, price *models.ProductPrice) error
+	DeletePrice(ctx context.Context, tx *gorm.DB, productID uint, currency money.Currency) (bool, error)
+	FindPrice(ctx context.Context, tx *gorm.DB, productID uint, currency money.Currency) (*models.ProductPrice, error)
+	ListPrices(ctx context.Context, tx *gorm.DB, productID uint) ([]models.ProductPrice, error)
+	ListPricesByCurrency(ctx context.Context, tx *gorm.DB, productIDs []uint, currency money.Currency) ([]models.ProductPrice, error)
+}
+
+type currencyRepository struct {
+	db *gorm.DB
+}
+
+func NewCurrencyRepository(db *gorm.DB) CurrencyRepository {
+	return &currencyRepository{db: db}
+}
+
+func (r *currencyRepository) CreateRate(ctx context.Context, tx *gorm.DB, rate *models.ExchangeRate) error {
+	return tx.WithContext(ctx).Create(rate).Error
+}
+
+func (r *currencyRepository) FindEffectiveRate(ctx context.Context, tx *gorm.DB, base, quote money.Currency, at time.Time) (*models.ExchangeRate, error) {
+	var rate models.ExchangeRate
+	err := tx.WithContext(ctx).
+		Where("base_currency = ? AND quote_currency = ? AND effective_at <= ?", base, quote, at).
+		Order("effective_at DESC").
+		First(&rate).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+func (r *currencyRepository) ListRates(ctx context.Context, tx *gorm.DB, base, quote money.Currency) ([]models.ExchangeRate, error) {
+	query := tx.WithContext(ctx)
+	if base != "" {
+		query = query.Where("base_currency = ?", base)
+	}
+	if quote != "" {
+		query = query.Where("quote_currency = ?", quote)
+	}
+
+	var rates []models.ExchangeRate
+	err := query.Order("base_currency, quote_currency, effective_at DESC").Find(&rates).Error
+	if err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+func (r *currencyRepository) UpsertPrice(ctx context.Context, tx *gorm.DB, price *models.ProductPrice) error {
+	return tx.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "product_id"}, {Name: "currency"}},
+			DoUpdates: clause.AssignmentColumns([]string{"price", "updated_at"}),
+		}).
+		Create(price).Error
+}
+
+func (r *currencyRepository) DeletePrice(ctx context.Context, tx *gorm.DB, productID uint, currency money.Currency) (bool, error) {
+	// Hard delete so the unique index frees up for a new list price
+	result := tx.WithContext(ctx).
+		Unscoped().
+		Where("product_id = ? AND currency = ?", productID, currency).
+		Delete(&models.ProductPrice{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *currencyRepository) FindPrice(ctx context.Context, tx *gorm.DB, productID uint, currency money.Currency) (*models.ProductPrice, error) {
+	var price models.ProductPrice
+	err := tx.WithContext(ctx).
+		Where("product_id = ? AND currency = ?", productID, currency).
+		First(&price).Error
+	if err != nil {
+		return nil, err
+	}
+	return &price, nil
+}
+
+func (r *currencyRepository) ListPrices(ctx context.Context, tx *gorm.DB, productID uint) ([]models.ProductPrice, error) {
+	var prices []models.ProductPrice
+	err := tx.WithContext(ctx).Where("product_id = ?", productID).Order("currency").Find(&prices).Error
+	if err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
+
+func (r *currencyRepository) ListPricesByCurrency(ctx context.Context, tx *gorm.DB, productIDs []uint, currency money.Currency) ([]models.ProductPrice, error) {
+	var prices []models.ProductPrice
+	if len(productIDs) == 0 {
+		return prices, nil
+	}
+	err := tx.WithContext(ctx).Where("product_id IN ? AND currency = ?", productIDs, currency).Find(&prices).Error
+	if err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
@@ -10,7 +10,11 @@ import (
 )
 
 type InventoryRepository interface {
-	GetForUpdate(ctx context.Context, tx *gorm.DB, productID uint) (*models.Inventory, error)
+	GetForUpdate(ctx context.Context, tx *gorm.DB, productID, warehouseID uint) (*models.Inventory, error)
+	ListByProduct(ctx context.Context, tx *gorm.DB, productID uint) ([]models.Inventory, error)
+	ListByProductForUpdate(ctx context.Context, tx *gorm.DB, productID uint) ([]models.Inventory, error)
+	GetStockLevels(ctx context.Context, tx *gorm.DB, productIDs []uint) (map[uint]int, error)
+	Create(ctx context.Context, tx *gorm.DB, inventory *models.Inventory) error
 	Update(ctx context.Context, tx *gorm.DB, inventory *models.Inventory) error
 }
 
@@ -22,15 +26,100 @@ func NewInventoryRepository(db *gorm.DB) InventoryRepository {
 	return &inventoryRepository{db: db}
 }
 
-func (r *inventoryRepository) GetForUpdate(ctx context.Context, tx *gorm.DB, productID uint) (*models.Inventory, error) {
+func (r *inventoryRepository) GetForUpdate(ctx context.Context, tx *gorm.DB, productID, warehouseID uint) (*models.Inventory, error) {
 	var inventory models.Inventory
-	err := tx.WithContext(ctx).Where("product_id = ?", productID).First(&inventory).Error
+	err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("product_id = ? AND warehouse_id = ?", productID, warehouseID).
+		First(&inventory).Error
 	if err != nil {
 		return nil, err
 	}
 	return &inventory, nil
 }
 
+// ListByProduct returns the product's inventory in every warehouse
+func (r *inventoryRepository) ListByProduct(ctx context.Context, tx *gorm.DB, productID uint) ([]models.Inventory, error) {
+	var inventories []models.Inventory
+	err := tx.WithContext(ctx).
+		Preload("Warehouse").
+		Where("product_id = ?", productID).
+		Order("warehouse_id").
+		Find(&inventories).Error
+	if err != nil {
+		return nil, err
+	}
+	return inventories, nil
+}
+
+// ListByProductForUpdate locks the product's inventory rows in warehouse order so
+// concurrent orders always acquire the locks in the same sequence
+func (r *inventoryRepository) ListByProductForUpdate(ctx context.Context, tx *gorm.DB, productID uint) ([]models.Inventory, error) {
+	var inventories []models.Inventory
+	err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("product_id = ?", productID).
+		Order("warehouse_id").
+		Find(&inventories).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if len(inventories) == 0 {
+		return inventories, nil
+	}
+
+	// Warehouses are loaded with a plain query so only the inventory rows are locked
+	warehouseIDs := make([]uint, len(inventories))
+	for i, inventory := range inventories {
+		warehouseIDs[i] = inventory.WarehouseID
+	}
+	var warehouses []models.Warehouse
+	if err := tx.WithContext(ctx).Where("id IN ?", warehouseIDs).Find(&warehouses).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]*models.Warehouse, len(warehouses))
+	for i := range warehouses {
+		byID[warehouses[i].ID] = &warehouses[i]
+	}
+	for i := range inventories {
+		inventories[i].Warehouse = byID[inventories[i].WarehouseID]
+	}
+	return inventories, nil
+}
+
+// GetStockLevels returns the available stock of the products summed over all warehouses.
+// Products without inventory are left out.
+func (r *inventoryRepository) GetStockLevels(ctx context.Context, tx *gorm.DB, productIDs []uint) (map[uint]int, error) {
+	levels := make(map[uint]int, len(productIDs))
+	if len(productIDs) == 0 {
+		return levels, nil
+	}
+
+	var rows []struct {
+		ProductID  uint
+		StockLevel int
+	}
+	err := tx.WithContext(ctx).
+		Model(&models.Inventory{}).
+		Select("product_id, SUM(quantity) AS stock_level").
+		Where("product_id IN ?", productIDs).
+		Group("product_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		levels[row.ProductID] = row.StockLevel
+	}
+	return levels, nil
+}
+
+func (r *inventoryRepository) Create(ctx context.Context, tx *gorm.DB, inventory *models.Inventory) error {
+	return tx.WithContext(ctx).Create(inventory).Error
+}
+
 func (r *inventoryRepository) Update(ctx context.Context, tx *gorm.DB, inventory *models.Inventory) error {
 	// Use FOR UPDATE clause to prevent race conditions
 	return tx.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Save(inventory).Error
@@ -2,22 +2,183 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
 )
 
+// DailyRevenue holds the revenue figures of a day in the base currency
+type DailyRevenue struct {
+	GrossBookings     money.Money // Orders placed on the day, as booked at checkout
+	Cancellations     money.Money // Part of the day's bookings cancelled or removed from orders since
+	RecognisedRevenue money.Money // Orders delivered on the day, net of removed items
+}
+
+// OrderFilter narrows down order listings; zero fields don't filter
+type OrderFilter struct {
+	OrderID         uint
+	UserID          uint
+	Customer        string // Part of the customer's email or name
+	Statuses        []models.OrderStatus
+	From            *time.Time   // Created at or after
+	To              *time.Time   // Created before
+	MinTotal        *money.Money // Base currency total at least
+	MaxTotal        *money.Money // Base currency total at most
+	PaymentStatuses []models.PaymentStatus
+	ProductID       uint   // Orders containing the product
+	ProductSKU      string // Orders containing the product with this SKU, case-insensitive
+}
+
+func (f OrderFilter) apply(query *gorm.DB) *gorm.DB {
+	if f.OrderID != 0 {
+		query = query.Where("orders.id = ?", f.OrderID)
+	}
+	if f.UserID != 0 {
+		query = query.Where("orders.user_id = ?", f.UserID)
+	}
+	if f.Customer != "" {
+		pattern := "%" + escapeLike(f.Customer) + "%"
+		query = query.Where(`EXISTS (
+			SELECT 1 FROM users
+			WHERE users.id = orders.user_id
+			AND (users.email ILIKE @pattern OR users.first_name ILIKE @pattern OR users.last_name ILIKE @pattern
+				OR users.first_name || ' ' || users.last_name ILIKE @pattern)
+		)`, sql.Named("pattern", pattern))
+	}
+	if len(f.Statuses) > 0 {
+		query = query.Where("orders.status IN ?", f.Statuses)
+	}
+	if f.From != nil {
+		query = query.Where("orders.created_at >= ?", *f.From)
+	}
+	if f.To != nil {
+		query = query.Where("orders.created_at < ?", *f.To)
+	}
+	if f.MinTotal != nil {
+		query = query.Where("orders.base_total_amount >= ?", *f.MinTotal)
+	}
+	if f.MaxTotal != nil {
+		query = query.Where("orders.base_total_amount <= ?", *f.MaxTotal)
+	}
+	if len(f.PaymentStatuses) > 0 {
+		query = query.Where(`EXISTS (
+			SELECT 1 FROM payments
+			WHERE payments.order_id = orders.id AND payments.status IN ? AND payments.deleted_at IS NULL
+		)`, f.PaymentStatuses)
+	}
+	if f.ProductID != 0 {
+		query = query.Where(`EXISTS (
+			SELECT 1 FROM order_items
+			WHERE order_items.order_id = orders.id AND order_items.product_id = ? AND order_items.deleted_at IS NULL
+		)`, f.ProductID)
+	}
+	if f.ProductSKU != "" {
+		query = query.Where(`EXISTS (
+			SELECT 1 FROM order_items JOIN products ON products.id = order_items.product_id
+			WHERE order_items.order_id = orders.id AND UPPER(products.sku) = UPPER(?) AND order_items.deleted_at IS NULL
+		)`, f.ProductSKU)
+	}
+	return query
+}
+
+// OrderSort orders a listing by one of the OrderSort* fields
+type OrderSort struct {
+	Field      string
+	Descending bool
+}
+
+const (
+	OrderSortID            = "id"
+	OrderSortCreatedAt     = "created_at"
+	OrderSortStatus        = "status"
+	OrderSortTotal         = "total"
+	OrderSortCustomerEmail = "customer_email"
+	OrderSortCustomerName  = "customer_name"
+	OrderSortPaymentStatus = "payment_status"
+	OrderSortSKU           = "sku"
+)
+
+// orderSortExpressions maps sort fields to SQL; related values are looked up with
+// subqueries so sorting never duplicates orders
+var orderSortExpressions = map[string]string{
+	OrderSortID:            "orders.id",
+	OrderSortCreatedAt:     "orders.created_at",
+	OrderSortStatus:        "orders.status",
+	OrderSortTotal:         "orders.base_total_amount",
+	OrderSortCustomerEmail: "(SELECT users.email FROM users WHERE users.id = orders.user_id)",
+	OrderSortCustomerName:  "(SELECT users.last_name || ' ' || users.first_name FROM users WHERE users.id = orders.user_id)",
+	OrderSortPaymentStatus: "(SELECT payments.status FROM payments WHERE payments.order_id = orders.id AND payments.deleted_at IS NULL)",
+	OrderSortSKU: `(SELECT MIN(products.sku) FROM order_items JOIN products ON products.id = order_items.product_id
+		WHERE order_items.order_id = orders.id AND order_items.deleted_at IS NULL)`,
+}
+
+func (s OrderSort) apply(query *gorm.DB) *gorm.DB {
+	expression, ok := orderSortExpressions[s.Field]
+	if !ok {
+		expression = orderSortExpressions[OrderSortCreatedAt]
+	}
+	direction := " ASC"
+	if s.Descending {
+		direction = " DESC"
+	}
+	// Break ties by ID so pages are stable
+	return query.Order(expression + direction + " NULLS LAST").Order("orders.id" + direction)
+}
+
+// escapeLike escapes the LIKE wildcards in a search term
+func escapeLike(term string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(term)
+}
+
+// OrderCursor is a position in an order listing sorted by creation time and ID
+type OrderCursor struct {
+	CreatedAt time.Time
+	ID        uint
+}
+
 type OrderRepository interface {
 	CreateOrder(ctx context.Context, tx *gorm.DB, order *models.Order) error
-	GetProductByID(ctx context.Context, tx *gorm.DB, productID uint) (*models.Product, error)
+	// GetProductByIDForUpdate locks the product row, serialising the orders and backorder
+	// allocations of a product
+	GetProductByIDForUpdate(ctx context.Context, tx *gorm.DB, productID uint) (*models.Product, error)
 	GetOrderByID(ctx context.Context, tx *gorm.DB, orderID uint) (*models.Order, error)
-	ListOrdersByUserID(ctx context.Context, tx *gorm.DB, userID uint) ([]models.Order, error)
-	ListOrders(ctx context.Context, tx *gorm.DB, offset, limit int) ([]models.Order, error)
-	CountOrders(ctx context.Context, tx *gorm.DB) (int64, error)
+	// ListOrdersAfter returns up to limit orders matching the filter that come after the
+	// cursor, ordered by creation time and ID
+	ListOrdersAfter(ctx context.Context, tx *gorm.DB, filter OrderFilter, after *OrderCursor, ascending bool, limit int) ([]models.Order, error)
+	ListOrders(ctx context.Context, tx *gorm.DB, filter OrderFilter, sort OrderSort, offset, limit int) ([]models.Order, error)
+	CountOrders(ctx context.Context, tx *gorm.DB, filter OrderFilter) (int64, error)
+	ListOrderIDs(ctx context.Context, tx *gorm.DB, filter OrderFilter, limit int) ([]uint, error)
+	CreatePayment(ctx context.Context, tx *gorm.DB, payment *models.Payment) error
+	SetPayment(ctx context.Context, tx *gorm.DB, orderID, paymentID uint) error
+	GetPaymentByOrderID(ctx context.Context, tx *gorm.DB, orderID uint) (*models.Payment, error)
+	// GetPaymentByOrderIDForUpdate locks the payment row, serialising the refunds of an order
+	GetPaymentByOrderIDForUpdate(ctx context.Context, tx *gorm.DB, orderID uint) (*models.Payment, error)
+	UpdatePayment(ctx context.Context, tx *gorm.DB, payment *models.Payment) error
+	CreateRefund(ctx context.Context, tx *gorm.DB, refund *models.Refund) error
+	ListRefundsByPayment(ctx context.Context, tx *gorm.DB, paymentID uint, status models.RefundStatus) ([]models.Refund, error)
+	// ListPendingRefunds returns up to limit refunds recorded before the given time and not
+	// confirmed by the provider yet, oldest first
+	ListPendingRefunds(ctx context.Context, tx *gorm.DB, before time.Time, limit int) ([]models.Refund, error)
+	// CompleteRefund marks a pending refund as succeeded. It returns false if the refund was
+	// no longer pending, because another attempt completed it first.
+	CompleteRefund(ctx context.Context, tx *gorm.DB, refundID uint, transactionID string, refundedAt time.Time) (bool, error)
+	// RecordRefundFailure counts a failed attempt at a pending refund
+	RecordRefundFailure(ctx context.Context, tx *gorm.DB, refundID uint, reason string) error
 	Update(ctx context.Context, tx *gorm.DB, order *models.Order) error
-	GetOrderStatsByDate(ctx context.Context, tx *gorm.DB, date time.Time) (stats map[models.OrderStatus]int, revenue float64, err error)
+	GetOrderStatsByDate(ctx context.Context, tx *gorm.DB, date time.Time) (stats map[models.OrderStatus]int, revenue DailyRevenue, err error)
+	GetOrderByIDForUpdate(ctx context.Context, tx *gorm.DB, orderID uint) (*models.Order, error)
+	UpdateItem(ctx context.Context, tx *gorm.DB, item *models.OrderItem) error
+	CreateAllocations(ctx context.Context, tx *gorm.DB, allocations []models.OrderItemAllocation) error
+	UpdateAllocation(ctx context.Context, tx *gorm.DB, allocation *models.OrderItemAllocation) error
+	DeleteAllocation(ctx context.Context, tx *gorm.DB, allocation *models.OrderItemAllocation) error
+	GetBackorderedQuantity(ctx context.Context, tx *gorm.DB, productID uint) (int, error)
+	ListBackorderedItems(ctx context.Context, tx *gorm.DB, productID uint) ([]models.OrderItem, error)
 }
 
 type orderRepository struct {
@@ -32,9 +193,11 @@ func (r *orderRepository) CreateOrder(ctx context.Context, tx *gorm.DB, order *m
 	return tx.WithContext(ctx).Create(order).Error
 }
 
-func (r *orderRepository) GetProductByID(ctx context.Context, tx *gorm.DB, productID uint) (*models.Product, error) {
+func (r *orderRepository) GetProductByIDForUpdate(ctx context.Context, tx *gorm.DB, productID uint) (*models.Product, error) {
 	var product models.Product
-	err := tx.WithContext(ctx).First(&product, productID).Error
+	err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&product, productID).Error
 	if err != nil {
 		return nil, err
 	}
@@ -43,27 +206,44 @@ func (r *orderRepository) GetProductByID(ctx context.Context, tx *gorm.DB, produ
 
 func (r *orderRepository) GetOrderByID(ctx context.Context, tx *gorm.DB, orderID uint) (*models.Order, error) {
 	var order models.Order
-	err := tx.WithContext(ctx).Preload("OrderItems").First(&order, orderID).Error
+	err := tx.WithContext(ctx).Preload("OrderItems.Allocations").First(&order, orderID).Error
 	if err != nil {
 		return nil, err
 	}
 	return &order, nil
 }
 
-func (r *orderRepository) ListOrdersByUserID(ctx context.Context, tx *gorm.DB, userID uint) ([]models.Order, error) {
+func (r *orderRepository) ListOrdersAfter(ctx context.Context, tx *gorm.DB, filter OrderFilter, after *OrderCursor, ascending bool, limit int) ([]models.Order, error) {
+	query := filter.apply(tx.WithContext(ctx).Model(&models.Order{}))
+
+	direction, comparison := "DESC", "<"
+	if ascending {
+		direction, comparison = "ASC", ">"
+	}
+	if after != nil {
+		query = query.Where("(orders.created_at, orders.id) "+comparison+" (?, ?)", after.CreatedAt, after.ID)
+	}
+
 	var orders []models.Order
-	err := tx.WithContext(ctx).Where("user_id = ?", userID).Find(&orders).Error
+	err := query.
+		Preload("OrderItems.Allocations").
+		Order("orders.created_at " + direction).
+		Order("orders.id " + direction).
+		Limit(limit).
+		Find(&orders).Error
 	if err != nil {
 		return nil, err
 	}
 	return orders, nil
 }
 
-func (r *orderRepository) ListOrders(ctx context.Context, tx *gorm.DB, offset, limit int) ([]models.Order, error) {
+func (r *orderRepository) ListOrders(ctx context.Context, tx *gorm.DB, filter OrderFilter, sort OrderSort, offset, limit int) ([]models.Order, error) {
 	var orders []models.Order
-	err := tx.WithContext(ctx).
+	query := filter.apply(tx.WithContext(ctx).Model(&models.Order{}))
+	err := sort.apply(query).
 		Preload("OrderItems").
 		Preload("User").
+		Preload("Payment").
 		Offset(offset).
 		Limit(limit).
 		Find(&orders).Error
@@ -73,45 +253,247 @@ func (r *orderRepository) ListOrders(ctx context.Context, tx *gorm.DB, offset, l
 	return orders, nil
 }
 
-func (r *orderRepository) CountOrders(ctx context.Context, tx *gorm.DB) (int64, error) {
+func (r *orderRepository) CountOrders(ctx context.Context, tx *gorm.DB, filter OrderFilter) (int64, error) {
 	var total int64
-	err := tx.WithContext(ctx).Model(&models.Order{}).Count(&total).Error
+	err := filter.apply(tx.WithContext(ctx).Model(&models.Order{})).Count(&total).Error
 	if err != nil {
 		return 0, err
 	}
 	return total, nil
 }
 
+func (r *orderRepository) ListOrderIDs(ctx context.Context, tx *gorm.DB, filter OrderFilter, limit int) ([]uint, error) {
+	var ids []uint
+	err := filter.apply(tx.WithContext(ctx).Model(&models.Order{})).
+		Order("orders.id").
+		Limit(limit).
+		Pluck("orders.id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r *orderRepository) CreatePayment(ctx context.Context, tx *gorm.DB, payment *models.Payment) error {
+	return tx.WithContext(ctx).Omit(clause.Associations).Create(payment).Error
+}
+
+// SetPayment links an order to its payment
+func (r *orderRepository) SetPayment(ctx context.Context, tx *gorm.DB, orderID, paymentID uint) error {
+	return tx.WithContext(ctx).Model(&models.Order{}).Where("id = ?", orderID).Update("payment_id", paymentID).Error
+}
+
+func (r *orderRepository) GetPaymentByOrderID(ctx context.Context, tx *gorm.DB, orderID uint) (*models.Payment, error) {
+	var payment models.Payment
+	err := tx.WithContext(ctx).Where("order_id = ?", orderID).First(&payment).Error
+	if err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+func (r *orderRepository) UpdatePayment(ctx context.Context, tx *gorm.DB, payment *models.Payment) error {
+	return tx.WithContext(ctx).Save(payment).Error
+}
+
+func (r *orderRepository) GetPaymentByOrderIDForUpdate(ctx context.Context, tx *gorm.DB, orderID uint) (*models.Payment, error) {
+	var payment models.Payment
+	err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("order_id = ?", orderID).
+		First(&payment).Error
+	if err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+func (r *orderRepository) CreateRefund(ctx context.Context, tx *gorm.DB, refund *models.Refund) error {
+	return tx.WithContext(ctx).Create(refund).Error
+}
+
+func (r *orderRepository) ListRefundsByPayment(ctx context.Context, tx *gorm.DB, paymentID uint, status models.RefundStatus) ([]models.Refund, error) {
+	var refunds []models.Refund
+	err := tx.WithContext(ctx).
+		Where("payment_id = ? AND status = ?", paymentID, status).
+		Order("id").
+		Find(&refunds).Error
+	if err != nil {
+		return nil, err
+	}
+	return refunds, nil
+}
+
+func (r *orderRepository) ListPendingRefunds(ctx context.Context, tx *gorm.DB, before time.Time, limit int) ([]models.Refund, error) {
+	var refunds []models.Refund
+	err := tx.WithContext(ctx).
+		Where("status = ? AND created_at < ?", models.RefundStatusPending, before).
+		Order("id").
+		Limit(limit).
+		Find(&refunds).Error
+	if err != nil {
+		return nil, err
+	}
+	return refunds, nil
+}
+
+func (r *orderRepository) CompleteRefund(ctx context.Context, tx *gorm.DB, refundID uint, transactionID string, refundedAt time.Time) (bool, error) {
+	result := tx.WithContext(ctx).
+		Model(&models.Refund{}).
+		Where("id = ? AND status = ?", refundID, models.RefundStatusPending).
+		Updates(map[string]interface{}{
+			"status":         models.RefundStatusSucceeded,
+			"transaction_id": transactionID,
+			"attempts":       gorm.Expr("attempts + 1"),
+			"last_error":     "",
+			"refunded_at":    refundedAt,
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *orderRepository) RecordRefundFailure(ctx context.Context, tx *gorm.DB, refundID uint, reason string) error {
+	return tx.WithContext(ctx).
+		Model(&models.Refund{}).
+		Where("id = ? AND status = ?", refundID, models.RefundStatusPending).
+		Updates(map[string]interface{}{
+			"attempts":   gorm.Expr("attempts + 1"),
+			"last_error": reason,
+		}).Error
+}
+
 func (r *orderRepository) Update(ctx context.Context, tx *gorm.DB, order *models.Order) error {
 	return tx.WithContext(ctx).Save(order).Error
 }
 
-func (r *orderRepository) GetOrderStatsByDate(ctx context.Context, tx *gorm.DB, date time.Time) (map[models.OrderStatus]int, float64, error) {
+// GetOrderStatsByDate counts the orders placed on a UTC day by current status and sums up
+// the day's revenue figures
+func (r *orderRepository) GetOrderStatsByDate(ctx context.Context, tx *gorm.DB, date time.Time) (map[models.OrderStatus]int, DailyRevenue, error) {
+	dayStart := date.UTC().Truncate(24 * time.Hour)
+	dayEnd := dayStart.AddDate(0, 0, 1)
+
 	type Result struct {
-		Status      models.OrderStatus
-		Count       int
-		TotalAmount float64
+		Status models.OrderStatus
+		Count  int
 	}
 	var results []Result
-
 	err := tx.WithContext(ctx).
 		Model(&models.Order{}).
-		Select("status, COUNT(*) as count, SUM(total_amount) as total_amount").
-		Where("DATE(created_at) = DATE(?)", date).
+		Select("status, COUNT(*) as count").
+		Where("created_at >= ? AND created_at < ?", dayStart, dayEnd).
 		Group("status").
 		Scan(&results).Error
 	if err != nil {
-		return nil, 0, err
+		return nil, DailyRevenue{}, err
 	}
 
 	stats := make(map[models.OrderStatus]int)
-	var totalRevenue float64
 	for _, result := range results {
 		stats[result.Status] = result.Count
-		if result.Status == models.OrderStatusDelivered {
-			totalRevenue = result.TotalAmount
-		}
 	}
 
-	return stats, totalRevenue, nil
+	// Bookings count every unit ordered at checkout, including units removed later
+	var revenue DailyRevenue
+	err = tx.WithContext(ctx).
+		Table("order_items").
+		Select(
+			"COALESCE(SUM((order_items.quantity + order_items.cancelled_quantity) * order_items.base_price), 0) AS gross_bookings,"+
+				"COALESCE(SUM(CASE WHEN orders.status = ? "+
+				"THEN (order_items.quantity + order_items.cancelled_quantity) * order_items.base_price "+
+				"ELSE order_items.cancelled_quantity * order_items.base_price END), 0) AS cancellations",
+			models.OrderStatusCancelled,
+		).
+		Joins("JOIN orders ON orders.id = order_items.order_id").
+		Where("order_items.deleted_at IS NULL AND orders.deleted_at IS NULL").
+		Where("orders.created_at >= ? AND orders.created_at < ?", dayStart, dayEnd).
+		Scan(&revenue).Error
+	if err != nil {
+		return nil, DailyRevenue{}, err
+	}
+
+	// Orders delivered before delivery times were recorded fall back to their last update
+	var delivered struct {
+		RecognisedRevenue money.Money
+	}
+	err = tx.WithContext(ctx).
+		Model(&models.Order{}).
+		Select("COALESCE(SUM(base_total_amount), 0) AS recognised_revenue").
+		Where("status = ?", models.OrderStatusDelivered).
+		Where("COALESCE(delivered_at, updated_at) >= ? AND COALESCE(delivered_at, updated_at) < ?", dayStart, dayEnd).
+		Scan(&delivered).Error
+	if err != nil {
+		return nil, DailyRevenue{}, err
+	}
+	revenue.RecognisedRevenue = delivered.RecognisedRevenue
+
+	return stats, revenue, nil
+}
+
+// GetOrderByIDForUpdate locks the order row and loads its items and allocations
+func (r *orderRepository) GetOrderByIDForUpdate(ctx context.Context, tx *gorm.DB, orderID uint) (*models.Order, error) {
+	var order models.Order
+	err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&order, orderID).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.WithContext(ctx).
+		Preload("Allocations").
+		Where("order_id = ?", order.ID).
+		Order("id").
+		Find(&order.OrderItems).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// UpdateItem saves an order item without touching its associations
+func (r *orderRepository) UpdateItem(ctx context.Context, tx *gorm.DB, item *models.OrderItem) error {
+	return tx.WithContext(ctx).Omit(clause.Associations).Save(item).Error
+}
+
+func (r *orderRepository) CreateAllocations(ctx context.Context, tx *gorm.DB, allocations []models.OrderItemAllocation) error {
+	if len(allocations) == 0 {
+		return nil
+	}
+	return tx.WithContext(ctx).Create(&allocations).Error
+}
+
+func (r *orderRepository) UpdateAllocation(ctx context.Context, tx *gorm.DB, allocation *models.OrderItemAllocation) error {
+	return tx.WithContext(ctx).Omit(clause.Associations).Save(allocation).Error
+}
+
+func (r *orderRepository) DeleteAllocation(ctx context.Context, tx *gorm.DB, allocation *models.OrderItemAllocation) error {
+	return tx.WithContext(ctx).Delete(allocation).Error
+}
+
+// GetBackorderedQuantity returns the units of a product still waiting for stock on backordered orders
+func (r *orderRepository) GetBackorderedQuantity(ctx context.Context, tx *gorm.DB, productID uint) (int, error) {
+	var quantity int
+	err := tx.WithContext(ctx).
+		Model(&models.OrderItem{}).
+		Select("COALESCE(SUM(order_items.backordered_quantity), 0)").
+		Joins("JOIN orders ON orders.id = order_items.order_id AND orders.deleted_at IS NULL").
+		Where("order_items.product_id = ? AND orders.status = ?", productID, models.OrderStatusBackordered).
+		Scan(&quantity).Error
+	return quantity, err
+}
+
+// ListBackorderedItems returns the backordered items of a product, oldest order first,
+// with their order loaded
+func (r *orderRepository) ListBackorderedItems(ctx context.Context, tx *gorm.DB, productID uint) ([]models.OrderItem, error) {
+	var items []models.OrderItem
+	err := tx.WithContext(ctx).
+		Preload("Order").
+		Joins("JOIN orders ON orders.id = order_items.order_id AND orders.deleted_at IS NULL").
+		Where("order_items.product_id = ? AND order_items.backordered_quantity > 0", productID).
+		Where("orders.status = ?", models.OrderStatusBackordered).
+		Order("orders.created_at, orders.id, order_items.id").
+		Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
 }
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
+)
+
+type PriceScheduleRepository interface {
+	Create(ctx context.Context, tx *gorm.DB, schedule *models.PriceSchedule) error
+	Update(ctx context.Context, tx *gorm.DB, schedule *models.PriceSchedule) error
+	FindByIDForUpdate(ctx context.Context, tx *gorm.DB, id uint) (*models.PriceSchedule, error)
+	ListByProduct(ctx context.Context, tx *gorm.DB, productID uint) ([]models.PriceSchedule, error)
+	// ListInEffect returns the schedules of the products that set their price at the given time
+	ListInEffect(ctx context.Context, tx *gorm.DB, productIDs []uint, at time.Time) ([]models.PriceSchedule, error)
+	// HasOverlappingSale reports whether an open sale of the product overlaps the given window
+	HasOverlappingSale(ctx context.Context, tx *gorm.DB, productID uint, startsAt, endsAt time.Time) (bool, error)
+	// ListDue returns the IDs of schedules that should have started or ended by the given time
+	ListDue(ctx context.Context, tx *gorm.DB, at time.Time) ([]uint, error)
+	UpdateProductPrice(ctx context.Context, tx *gorm.DB, productID uint, price money.Money) error
+	CreateHistory(ctx context.Context, tx *gorm.DB, entry *models.ProductPriceHistory) error
+	ListHistory(ctx context.Context, tx *gorm.DB, productID uint) ([]models.ProductPriceHistory, error)
+}
+
+type priceScheduleRepository struct {
+	db *gorm.DB
+}
+
+func NewPriceScheduleRepository(db *gorm.DB) PriceScheduleRepository {
+	return &priceScheduleRepository{db: db}
+}
+
+func (r *priceScheduleRepository) Create(ctx context.Context, tx *gorm.DB, schedule *models.PriceSchedule) error {
+	return tx.WithContext(ctx).Create(schedule).Error
+}
+
+func (r *priceScheduleRepository) Update(ctx context.Context, tx *gorm.DB, schedule *models.PriceSchedule) error {
+	return tx.WithContext(ctx).Save(schedule).Error
+}
+
+// FindByIDForUpdate locks the schedule so the scheduler and admins don't change it concurrently
+func (r *priceScheduleRepository) FindByIDForUpdate(ctx context.Context, tx *gorm.DB, id uint) (*models.PriceSchedule, error) {
+	var schedule models.PriceSchedule
+	err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&schedule, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (r *priceScheduleRepository) ListByProduct(ctx context.Context, tx *gorm.DB, productID uint) ([]models.PriceSchedule, error) {
+	var schedules []models.PriceSchedule
+	err := tx.WithContext(ctx).
+		Where("product_id = ?", productID).
+		Order("starts_at DESC").
+		Find(&schedules).Error
+	if err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+func (r *priceScheduleRepository) ListInEffect(ctx context.Context, tx *gorm.DB, productIDs []uint, at time.Time) ([]models.PriceSchedule, error) {
+	var schedules []models.PriceSchedule
+	if len(productIDs) == 0 {
+		return schedules, nil
+	}
+	err := tx.WithContext(ctx).
+		Where("product_id IN ? AND status IN ?", productIDs,
+			[]models.PriceScheduleStatus{models.PriceScheduleScheduled, models.PriceScheduleActive}).
+		Where("starts_at <= ? AND (ends_at IS NULL OR ends_at > ?)", at, at).
+		Order("starts_at").
+		Find(&schedules).Error
+	if err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+func (r *priceScheduleRepository) HasOverlappingSale(ctx context.Context, tx *gorm.DB, productID uint, startsAt, endsAt time.Time) (bool, error) {
+	var count int64
+	err := tx.WithContext(ctx).
+		Model(&models.PriceSchedule{}).
+		Where("product_id = ? AND status IN ?", productID,
+			[]models.PriceScheduleStatus{models.PriceScheduleScheduled, models.PriceScheduleActive}).
+		Where("ends_at IS NOT NULL AND starts_at < ? AND ends_at > ?", endsAt, startsAt).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *priceScheduleRepository) ListDue(ctx context.Context, tx *gorm.DB, at time.Time) ([]uint, error) {
+	var ids []uint
+	err := tx.WithContext(ctx).
+		Model(&models.PriceSchedule{}).
+		Where("(status = ? AND starts_at <= ?) OR (status = ? AND ends_at <= ?)",
+			models.PriceScheduleScheduled, at, models.PriceScheduleActive, at).
+		Order("starts_at, id").
+		Pluck("id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r *priceScheduleRepository) UpdateProductPrice(ctx context.Context, tx *gorm.DB, productID uint, price money.Money) error {
+	return tx.WithContext(ctx).
+		Model(&models.Product{}).
+		Where("id = ?", productID).
+		Update("price", price).Error
+}
+
+func (r *priceScheduleRepository) CreateHistory(ctx context.Context, tx *gorm.DB, entry *models.ProductPriceHistory) error {
+	return tx.WithContext(ctx).Create(entry).Error
+}
+
+func (r *priceScheduleRepository) ListHistory(ctx context.Context, tx *gorm.DB, productID uint) ([]models.ProductPriceHistory, error) {
+	var history []models.ProductPriceHistory
+	err := tx.WithContext(ctx).
+		Where("product_id = ?", productID).
+		Order("effective_at DESC, id DESC").
+		Find(&history).Error
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
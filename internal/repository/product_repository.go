@@ -10,12 +10,13 @@ import (
 )
 
 type ProductRepository interface {
-	Create(ctx context.Context, product *models.Product, inventory *models.Inventory) error
+	Create(ctx context.Context, product *models.Product) error
+	CreateInTx(ctx context.Context, tx *gorm.DB, product *models.Product) error
 	FindByID(ctx context.Context, id uint) (*models.Product, error)
 	List(ctx context.Context, offset, limit int) ([]models.Product, int64, error)
-	GetInventory(ctx context.Context, productID uint) (*models.Inventory, error)
 	Update(ctx context.Context, product *models.Product, inventory *models.Inventory) error
 	GetTopProducts(ctx context.Context, tx *gorm.DB, date time.Time, limit int) ([]models.TopProduct, error)
+	GetAverageStock(ctx context.Context, tx *gorm.DB, productIDs []uint, date time.Time) (map[uint]float64, error)
 	GetLowStockProducts(ctx context.Context, tx *gorm.DB) ([]models.LowStockAlert, error)
 }
 
@@ -57,33 +58,14 @@ func NewProductRepository(db *gorm.DB) ProductRepository {
 	return &productRepository{db: db}
 }
 
-func (r *productRepository) GetInventory(ctx context.Context, productID uint) (*models.Inventory, error) {
-	var inventory models.Inventory
-	result := r.db.WithContext(ctx).Where("product_id = ?", productID).First(&inventory)
-	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-			return nil, nil
-		}
-		return nil, result.Error
-	}
-	return &inventory, nil
+func (r *productRepository) Create(ctx context.Context, product *models.Product) error {
+	return r.CreateInTx(ctx, r.db, product)
 }
 
-func (r *productRepository) Create(ctx context.Context, product *models.Product, inventory *models.Inventory) error {
-	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		// Create product
-		if err := tx.Create(product).Error; err != nil {
-			return err
-		}
-
-		// Create inventory
-		inventory.ProductID = product.ID
-		if err := tx.Create(inventory).Error; err != nil {
-			return err
-		}
-
-		return nil
-	})
+// CreateInTx creates the product only; its inventory rows are created per warehouse when
+// stock is first booked to them
+func (r *productRepository) CreateInTx(ctx context.Context, tx *gorm.DB, product *models.Product) error {
+	return tx.WithContext(ctx).Create(product).Error
 }
 
 func (r *productRepository) Update(ctx context.Context, product *models.Product, inventory *models.Inventory) error {
@@ -93,6 +75,10 @@ func (r *productRepository) Update(ctx context.Context, product *models.Product,
 			if err := tx.Model(product).Updates(product).Error; err != nil {
 				return err
 			}
+			// Updates skips zero values, so flags that can be switched off are written explicitly
+			if err := tx.Model(product).Update("discontinued", product.Discontinued).Error; err != nil {
+				return err
+			}
 		}
 
 		// Update inventory if provided
@@ -108,28 +94,93 @@ func (r *productRepository) Update(ctx context.Context, product *models.Product,
 	})
 }
 
+// GetTopProducts returns the products that sold the most units in orders placed on a UTC
+// day, leaving out cancelled orders and removed items. Revenue is in the base currency.
 func (r *productRepository) GetTopProducts(ctx context.Context, tx *gorm.DB, date time.Time, limit int) ([]models.TopProduct, error) {
+	dayStart := date.UTC().Truncate(24 * time.Hour)
 	var products []models.TopProduct
 
 	err := tx.WithContext(ctx).
 		Table("order_items").
 		Select(
 			"order_items.product_id,"+
-				"products.name,"+
-				"SUM(order_items.quantity) as total_quantity,"+
-				"SUM(order_items.quantity * order_items.price) as total_revenue",
+				"products.name as product_name,"+
+				"SUM(order_items.quantity) as quantity_sold,"+
+				"SUM(order_items.quantity * order_items.base_price) as revenue",
 		).
 		Joins("JOIN orders ON orders.id = order_items.order_id").
 		Joins("JOIN products ON products.id = order_items.product_id").
-		Where("DATE(orders.created_at) = DATE(?)", date).
+		Where("order_items.deleted_at IS NULL AND orders.deleted_at IS NULL").
+		Where("orders.created_at >= ? AND orders.created_at < ?", dayStart, dayStart.AddDate(0, 0, 1)).
+		Where("orders.status <> ? AND order_items.quantity > 0", models.OrderStatusCancelled).
 		Group("order_items.product_id, products.name").
-		Order("total_quantity DESC").
+		Order("quantity_sold DESC, order_items.product_id").
 		Limit(limit).
 		Scan(&products).Error
 
 	return products, err
 }
 
+// GetAverageStock returns the products' average stock on hand, available and reserved
+// across warehouses, over a UTC day: the mean of the opening and closing stock, worked back
+// from the current stock through the stock movement ledger
+func (r *productRepository) GetAverageStock(ctx context.Context, tx *gorm.DB, productIDs []uint, date time.Time) (map[uint]float64, error) {
+	average := make(map[uint]float64, len(productIDs))
+	if len(productIDs) == 0 {
+		return average, nil
+	}
+	dayStart := date.UTC().Truncate(24 * time.Hour)
+	dayEnd := dayStart.AddDate(0, 0, 1)
+
+	type Row struct {
+		ProductID   uint
+		Stock       int
+		MovedAfter  int // Net movement since the day ended
+		MovedDuring int // Net movement during the day
+	}
+
+	var current []Row
+	err := tx.WithContext(ctx).
+		Model(&models.Inventory{}).
+		Select("product_id, SUM(quantity + reserved) AS stock").
+		Where("product_id IN ?", productIDs).
+		Group("product_id").
+		Scan(&current).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var movements []Row
+	err = tx.WithContext(ctx).
+		Model(&models.StockMovement{}).
+		Select(
+			"product_id,"+
+				"COALESCE(SUM(CASE WHEN created_at >= ? THEN quantity_delta + reserved_delta END), 0) AS moved_after,"+
+				"COALESCE(SUM(CASE WHEN created_at < ? THEN quantity_delta + reserved_delta END), 0) AS moved_during",
+			dayEnd, dayEnd,
+		).
+		Where("product_id IN ? AND created_at >= ?", productIDs, dayStart).
+		Group("product_id").
+		Scan(&movements).Error
+	if err != nil {
+		return nil, err
+	}
+
+	stock := make(map[uint]int, len(current))
+	for _, row := range current {
+		stock[row.ProductID] = row.Stock
+	}
+	for _, id := range productIDs {
+		average[id] = float64(stock[id])
+	}
+	for _, row := range movements {
+		closing := stock[row.ProductID] - row.MovedAfter
+		opening := closing - row.MovedDuring
+		average[row.ProductID] = float64(opening+closing) / 2
+	}
+	return average, nil
+}
+
 func (r *productRepository) GetLowStockProducts(ctx context.Context, tx *gorm.DB) ([]models.LowStockAlert, error) {
 	var alerts []models.LowStockAlert
 
@@ -137,12 +188,16 @@ func (r *productRepository) GetLowStockProducts(ctx context.Context, tx *gorm.DB
 		Table("inventories").
 		Select(
 			"inventories.product_id,"+
-				"products.name,"+
-				"inventories.quantity as current_stock,"+
-				"products.reorder_threshold",
+				"products.name as product_name,"+
+				"SUM(inventories.quantity) as current_stock,"+
+				"SUM(inventories.reserved) as reserved_stock,"+
+				"SUM(inventories.minimum_stock) as reorder_point",
 		).
 		Joins("JOIN products ON products.id = inventories.product_id").
-		Where("inventories.quantity <= products.reorder_threshold").
+		Where("inventories.deleted_at IS NULL AND products.deleted_at IS NULL").
+		Where("inventories.quantity <= inventories.minimum_stock").
+		Group("inventories.product_id, products.name").
+		Order("inventories.product_id").
 		Scan(&alerts).Error
 
 	return alerts, err
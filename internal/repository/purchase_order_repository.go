@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+)
+
+type PurchaseOrderRepository interface {
+	Create(ctx context.Context, tx *gorm.DB, order *models.PurchaseOrder) error
+	Update(ctx context.Context, tx *gorm.DB, order *models.PurchaseOrder) error
+	UpdateLine(ctx context.Context, tx *gorm.DB, line *models.PurchaseOrderLine) error
+	FindByID(ctx context.Context, tx *gorm.DB, id uint) (*models.PurchaseOrder, error)
+	FindByIDForUpdate(ctx context.Context, tx *gorm.DB, id uint) (*models.PurchaseOrder, error)
+	FindOpenByStockAlert(ctx context.Context, tx *gorm.DB, alertID uint) (*models.PurchaseOrder, error)
+	List(ctx context.Context, tx *gorm.DB, status models.PurchaseOrderStatus, offset, limit int) ([]models.PurchaseOrder, int64, error)
+}
+
+type purchaseOrderRepository struct {
+	db *gorm.DB
+}
+
+func NewPurchaseOrderRepository(db *gorm.DB) PurchaseOrderRepository {
+	return &purchaseOrderRepository{db: db}
+}
+
+func (r *purchaseOrderRepository) Create(ctx context.Context, tx *gorm.DB, order *models.PurchaseOrder) error {
+	return tx.WithContext(ctx).Create(order).Error
+}
+
+// Update saves the purchase order header only; lines are updated with UpdateLine
+func (r *purchaseOrderRepository) Update(ctx context.Context, tx *gorm.DB, order *models.PurchaseOrder) error {
+	return tx.WithContext(ctx).Omit(clause.Associations).Save(order).Error
+}
+
+func (r *purchaseOrderRepository) UpdateLine(ctx context.Context, tx *gorm.DB, line *models.PurchaseOrderLine) error {
+	return tx.WithContext(ctx).Omit(clause.Associations).Save(line).Error
+}
+
+func (r *purchaseOrderRepository) FindByID(ctx context.Context, tx *gorm.DB, id uint) (*models.PurchaseOrder, error) {
+	var order models.PurchaseOrder
+	err := withPurchaseOrderDetails(tx.WithContext(ctx)).First(&order, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// FindByIDForUpdate locks the purchase order row so concurrent receipts are serialised
+func (r *purchaseOrderRepository) FindByIDForUpdate(ctx context.Context, tx *gorm.DB, id uint) (*models.PurchaseOrder, error) {
+	var order models.PurchaseOrder
+	err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&order, id).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.WithContext(ctx).Where("purchase_order_id = ?", order.ID).Order("id").Find(&order.Lines).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// FindOpenByStockAlert returns the not yet cancelled purchase order drafted from a low stock alert
+func (r *purchaseOrderRepository) FindOpenByStockAlert(ctx context.Context, tx *gorm.DB, alertID uint) (*models.PurchaseOrder, error) {
+	var order models.PurchaseOrder
+	err := withPurchaseOrderDetails(tx.WithContext(ctx)).
+		Where("stock_alert_id = ? AND status <> ?", alertID, models.PurchaseOrderStatusCancelled).
+		Order("id DESC").
+		First(&order).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *purchaseOrderRepository) List(ctx context.Context, tx *gorm.DB, status models.PurchaseOrderStatus, offset, limit int) ([]models.PurchaseOrder, int64, error) {
+	var orders []models.PurchaseOrder
+	var total int64
+
+	query := tx.WithContext(ctx).Model(&models.PurchaseOrder{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := withPurchaseOrderDetails(query).
+		Order("created_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&orders).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return orders, total, nil
+}
+
+func withPurchaseOrderDetails(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Supplier").
+		Preload("Warehouse").
+		Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Preload("Lines.Product")
+}
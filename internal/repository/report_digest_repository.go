@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReportDigestRepository interface {
+	Create(ctx context.Context, tx *gorm.DB, subscription *models.ReportDigestSubscription) error
+	Update(ctx context.Context, tx *gorm.DB, subscription *models.ReportDigestSubscription) error
+	Delete(ctx context.Context, tx *gorm.DB, id uint) error
+	FindByID(ctx context.Context, tx *gorm.DB, id uint) (*models.ReportDigestSubscription, error)
+	FindByUserAndFrequency(ctx context.Context, tx *gorm.DB, userID uint, frequency models.ReportDigestFrequency) (*models.ReportDigestSubscription, error)
+	List(ctx context.Context, tx *gorm.DB) ([]models.ReportDigestSubscription, error)
+	ListActive(ctx context.Context, tx *gorm.DB) ([]models.ReportDigestSubscription, error)
+
+	ClaimDelivery(ctx context.Context, tx *gorm.DB, delivery *models.ReportDigestDelivery, maxAttempts int) (*models.ReportDigestDelivery, error)
+	UpdateDelivery(ctx context.Context, tx *gorm.DB, delivery *models.ReportDigestDelivery) error
+	ListDeliveries(ctx context.Context, tx *gorm.DB, subscriptionID uint, limit int) ([]models.ReportDigestDelivery, error)
+	LatestDeliveries(ctx context.Context, tx *gorm.DB, subscriptionIDs []uint) (map[uint]*models.ReportDigestDelivery, error)
+}
+
+type reportDigestRepository struct {
+	db *gorm.DB
+}
+
+func NewReportDigestRepository(db *gorm.DB) ReportDigestRepository {
+	return &reportDigestRepository{db: db}
+}
+
+func (r *reportDigestRepository) Create(ctx context.Context, tx *gorm.DB, subscription *models.ReportDigestSubscription) error {
+	return tx.WithContext(ctx).Omit(clause.Associations).Create(subscription).Error
+}
+
+func (r *reportDigestRepository) Update(ctx context.Context, tx *gorm.DB, subscription *models.ReportDigestSubscription) error {
+	return tx.WithContext(ctx).Omit(clause.Associations).Save(subscription).Error
+}
+
+// Delete removes a subscription for good, so the admin can subscribe again later. Its
+// deliveries are kept as history.
+func (r *reportDigestRepository) Delete(ctx context.Context, tx *gorm.DB, id uint) error {
+	return tx.WithContext(ctx).Unscoped().Delete(&models.ReportDigestSubscription{}, id).Error
+}
+
+func (r *reportDigestRepository) FindByID(ctx context.Context, tx *gorm.DB, id uint) (*models.ReportDigestSubscription, error) {
+	var subscription models.ReportDigestSubscription
+	if err := tx.WithContext(ctx).Preload("User").First(&subscription, id).Error; err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (r *reportDigestRepository) FindByUserAndFrequency(ctx context.Context, tx *gorm.DB, userID uint, frequency models.ReportDigestFrequency) (*models.ReportDigestSubscription, error) {
+	var subscription models.ReportDigestSubscription
+	err := tx.WithContext(ctx).
+		Where("user_id = ? AND frequency = ?", userID, frequency).
+		First(&subscription).Error
+	if err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+// List returns every subscription with its user, oldest first
+func (r *reportDigestRepository) List(ctx context.Context, tx *gorm.DB) ([]models.ReportDigestSubscription, error) {
+	var subscriptions []models.ReportDigestSubscription
+	if err := tx.WithContext(ctx).Preload("User").Order("id").Find(&subscriptions).Error; err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+// ListActive returns the subscriptions that are not paused, with their users
+func (r *reportDigestRepository) ListActive(ctx context.Context, tx *gorm.DB) ([]models.ReportDigestSubscription, error) {
+	var subscriptions []models.ReportDigestSubscription
+	err := tx.WithContext(ctx).
+		Preload("User").
+		Where("active = ?", true).
+		Order("id").
+		Find(&subscriptions).Error
+	if err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+// ClaimDelivery records the delivery of a digest period unless it is already recorded,
+// then locks it if it still has to be sent and has attempts left, skipping it if another
+// instance holds the lock. It returns gorm.ErrRecordNotFound if there is nothing to send.
+func (r *reportDigestRepository) ClaimDelivery(ctx context.Context, tx *gorm.DB, delivery *models.ReportDigestDelivery, maxAttempts int) (*models.ReportDigestDelivery, error) {
+	err := tx.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "subscription_id"}, {Name: "period_start"}},
+			DoNothing: true,
+		}).
+		Create(delivery).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var claimed models.ReportDigestDelivery
+	err = tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("subscription_id = ? AND period_start = ?", delivery.SubscriptionID, delivery.PeriodStart).
+		Where("status <> ? AND attempts < ?", models.ReportDigestDeliverySent, maxAttempts).
+		First(&claimed).Error
+	if err != nil {
+		return nil, err
+	}
+	return &claimed, nil
+}
+
+func (r *reportDigestRepository) UpdateDelivery(ctx context.Context, tx *gorm.DB, delivery *models.ReportDigestDelivery) error {
+	return tx.WithContext(ctx).Save(delivery).Error
+}
+
+// ListDeliveries returns the latest deliveries of a subscription, newest period first
+func (r *reportDigestRepository) ListDeliveries(ctx context.Context, tx *gorm.DB, subscriptionID uint, limit int) ([]models.ReportDigestDelivery, error) {
+	var deliveries []models.ReportDigestDelivery
+	err := tx.WithContext(ctx).
+		Where("subscription_id = ?", subscriptionID).
+		Order("period_start DESC").
+		Limit(limit).
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// LatestDeliveries returns the delivery of the latest period of each subscription that has one
+func (r *reportDigestRepository) LatestDeliveries(ctx context.Context, tx *gorm.DB, subscriptionIDs []uint) (map[uint]*models.ReportDigestDelivery, error) {
+	latest := make(map[uint]*models.ReportDigestDelivery, len(subscriptionIDs))
+	if len(subscriptionIDs) == 0 {
+		return latest, nil
+	}
+
+	var deliveries []models.ReportDigestDelivery
+	err := tx.WithContext(ctx).
+		Select("DISTINCT ON (subscription_id) *").
+		Where("subscription_id IN ?", subscriptionIDs).
+		Order("subscription_id, period_start DESC").
+		Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+	for i := range deliveries {
+		latest[deliveries[i].SubscriptionID] = &deliveries[i]
+	}
+	return latest, nil
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReportExportRepository interface {
+	Create(ctx context.Context, tx *gorm.DB, export *models.ReportExport) error
+	Update(ctx context.Context, tx *gorm.DB, export *models.ReportExport) error
+	FindByID(ctx context.Context, tx *gorm.DB, id uint) (*models.ReportExport, error)
+	FindWithContent(ctx context.Context, tx *gorm.DB, id uint) (*models.ReportExport, error)
+	ClaimNext(ctx context.Context, tx *gorm.DB, staleBefore time.Time) (*models.ReportExport, error)
+	DeleteExpired(ctx context.Context, tx *gorm.DB, now time.Time) (int64, error)
+}
+
+type reportExportRepository struct {
+	db *gorm.DB
+}
+
+func NewReportExportRepository(db *gorm.DB) ReportExportRepository {
+	return &reportExportRepository{db: db}
+}
+
+func (r *reportExportRepository) Create(ctx context.Context, tx *gorm.DB, export *models.ReportExport) error {
+	return tx.WithContext(ctx).Create(export).Error
+}
+
+func (r *reportExportRepository) Update(ctx context.Context, tx *gorm.DB, export *models.ReportExport) error {
+	return tx.WithContext(ctx).Omit(clause.Associations).Save(export).Error
+}
+
+// FindByID returns an export without its file
+func (r *reportExportRepository) FindByID(ctx context.Context, tx *gorm.DB, id uint) (*models.ReportExport, error) {
+	var export models.ReportExport
+	if err := tx.WithContext(ctx).Omit("content").First(&export, id).Error; err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+// FindWithContent returns an export along with its file
+func (r *reportExportRepository) FindWithContent(ctx context.Context, tx *gorm.DB, id uint) (*models.ReportExport, error) {
+	var export models.ReportExport
+	if err := tx.WithContext(ctx).First(&export, id).Error; err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+// ClaimNext locks the oldest pending export, or one left running since before staleBefore
+// by an instance that went away, skipping exports other instances hold. It returns
+// gorm.ErrRecordNotFound if there is nothing to run.
+func (r *reportExportRepository) ClaimNext(ctx context.Context, tx *gorm.DB, staleBefore time.Time) (*models.ReportExport, error) {
+	var export models.ReportExport
+	err := tx.WithContext(ctx).
+		Omit("content").
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ? OR (status = ? AND started_at < ?)",
+			models.ReportExportStatusPending, models.ReportExportStatusRunning, staleBefore).
+		Order("id").
+		First(&export).Error
+	if err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+// DeleteExpired deletes the exports whose files expired, returning how many there were
+func (r *reportExportRepository) DeleteExpired(ctx context.Context, tx *gorm.DB, now time.Time) (int64, error) {
+	result := tx.WithContext(ctx).
+		Unscoped().
+		Where("expires_at <= ?", now).
+		Delete(&models.ReportExport{})
+	return result.RowsAffected, result.Error
+}
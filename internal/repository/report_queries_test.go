@@ -0,0 +1,413 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/testutil"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
+)
+
+// reportDay is the UTC day the report queries are run for, far enough in the past not to
+// meet other data in the test database
+var reportDay = time.Date(2001, 2, 3, 0, 0, 0, 0, time.UTC)
+
+// seedItem is an order line: the units kept, the units removed since checkout and the
+// unit price in cents
+type seedItem struct {
+	product   string
+	quantity  int
+	cancelled int
+	price     int64
+}
+
+// seedOrder is an order placed at an offset from the start of the report day
+type seedOrder struct {
+	customer    string
+	placed      time.Duration
+	status      models.OrderStatus
+	delivered   *time.Duration // Offset of the delivery, for delivered orders
+	items       []seedItem
+	description string
+}
+
+// fixtures creates users and products on demand and orders from seedOrders
+type fixtures struct {
+	t        *testing.T
+	tx       *gorm.DB
+	users    map[string]*models.User
+	products map[string]*models.Product
+}
+
+func newFixtures(t *testing.T, tx *gorm.DB) *fixtures {
+	return &fixtures{
+		t:        t,
+		tx:       tx,
+		users:    make(map[string]*models.User),
+		products: make(map[string]*models.Product),
+	}
+}
+
+func (f *fixtures) user(name string) *models.User {
+	f.t.Helper()
+	if user, ok := f.users[name]; ok {
+		return user
+	}
+	user := &models.User{Email: name + "@report-test.example", Password: "x", Role: models.RoleCustomer}
+	if err := f.tx.Create(user).Error; err != nil {
+		f.t.Fatalf("failed to create user %s: %v", name, err)
+	}
+	f.users[name] = user
+	return user
+}
+
+func (f *fixtures) product(name string) *models.Product {
+	f.t.Helper()
+	if product, ok := f.products[name]; ok {
+		return product
+	}
+	product := &models.Product{
+		Name:  name,
+		SKU:   fmt.Sprintf("REPORT-TEST-%s", name),
+		Price: money.New(1000, money.USD),
+	}
+	if err := f.tx.Create(product).Error; err != nil {
+		f.t.Fatalf("failed to create product %s: %v", name, err)
+	}
+	f.products[name] = product
+	return product
+}
+
+func (f *fixtures) orders(orders []seedOrder) {
+	f.t.Helper()
+	for _, seed := range orders {
+		order := &models.Order{
+			UserID:          f.user(seed.customer).ID,
+			Status:          seed.status,
+			Currency:        money.USD,
+			TotalAmount:     money.Zero(money.USD),
+			BaseTotalAmount: money.Zero(money.USD),
+		}
+		order.CreatedAt = reportDay.Add(seed.placed)
+		if seed.delivered != nil {
+			deliveredAt := reportDay.Add(*seed.delivered)
+			order.DeliveredAt = &deliveredAt
+		}
+
+		for _, item := range seed.items {
+			price := money.New(item.price, money.USD)
+			order.OrderItems = append(order.OrderItems, models.OrderItem{
+				ProductID:         f.product(item.product).ID,
+				Quantity:          item.quantity,
+				CancelledQuantity: item.cancelled,
+				Currency:          money.USD,
+				Price:             price,
+				BasePrice:         price,
+			})
+			// Totals hold the units still on the order
+			line := money.New(item.price*int64(item.quantity), money.USD)
+			order.TotalAmount, _ = order.TotalAmount.Add(line)
+			order.BaseTotalAmount, _ = order.BaseTotalAmount.Add(line)
+		}
+
+		if err := f.tx.Create(order).Error; err != nil {
+			f.t.Fatalf("failed to create order %q: %v", seed.description, err)
+		}
+	}
+}
+
+func hours(n int) *time.Duration {
+	d := time.Duration(n) * time.Hour
+	return &d
+}
+
+func usd(cents int64) money.Money {
+	return money.New(cents, money.USD)
+}
+
+func TestGetOrderStatsByDate(t *testing.T) {
+	tests := []struct {
+		name              string
+		orders            []seedOrder
+		wantCounts        map[models.OrderStatus]int
+		wantGross         money.Money
+		wantCancellations money.Money
+		wantRecognised    money.Money
+	}{
+		{
+			name: "zero-order day",
+			orders: []seedOrder{
+				{description: "day before", customer: "ann", placed: -2 * time.Hour, status: models.OrderStatusProcessing,
+					items: []seedItem{{product: "mug", quantity: 1, price: 1000}}},
+				{description: "day after", customer: "ann", placed: 30 * time.Hour, status: models.OrderStatusProcessing,
+					items: []seedItem{{product: "mug", quantity: 1, price: 1000}}},
+			},
+			wantCounts:        map[models.OrderStatus]int{},
+			wantGross:         usd(0),
+			wantCancellations: usd(0),
+			wantRecognised:    usd(0),
+		},
+		{
+			name: "gross, net and recognised revenue",
+			orders: []seedOrder{
+				{description: "partly removed", customer: "ann", placed: 9 * time.Hour, status: models.OrderStatusProcessing,
+					items: []seedItem{{product: "mug", quantity: 2, cancelled: 1, price: 1000}}},
+				{description: "cancelled", customer: "bob", placed: 10 * time.Hour, status: models.OrderStatusCancelled,
+					items: []seedItem{{product: "tea", quantity: 1, price: 2500}}},
+				{description: "placed today, delivered tomorrow", customer: "cat", placed: 11 * time.Hour,
+					status: models.OrderStatusDelivered, delivered: hours(26),
+					items: []seedItem{{product: "pot", quantity: 1, price: 500}}},
+				{description: "placed earlier, delivered today", customer: "dan", placed: -5 * 24 * time.Hour,
+					status: models.OrderStatusDelivered, delivered: hours(10),
+					items: []seedItem{{product: "pot", quantity: 2, cancelled: 1, price: 2000}}},
+			},
+			wantCounts: map[models.OrderStatus]int{
+				models.OrderStatusProcessing: 1,
+				models.OrderStatusCancelled:  1,
+				models.OrderStatusDelivered:  1,
+			},
+			// 3 mugs booked, 1 tea, 1 pot; 1 mug removed and the tea cancelled
+			wantGross:         usd(3*1000 + 2500 + 500),
+			wantCancellations: usd(1000 + 2500),
+			// The earlier order's net total, without the removed pot
+			wantRecognised: usd(2 * 2000),
+		},
+		{
+			name: "removed items of a cancelled order are counted once",
+			orders: []seedOrder{
+				{description: "cancelled after a removal", customer: "ann", placed: time.Hour, status: models.OrderStatusCancelled,
+					items: []seedItem{{product: "mug", quantity: 1, cancelled: 2, price: 1000}}},
+			},
+			wantCounts:        map[models.OrderStatus]int{models.OrderStatusCancelled: 1},
+			wantGross:         usd(3 * 1000),
+			wantCancellations: usd(3 * 1000),
+			wantRecognised:    usd(0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := testutil.OpenTestDB(t)
+			newFixtures(t, tx).orders(tt.orders)
+
+			counts, revenue, err := NewOrderRepository(tx).GetOrderStatsByDate(context.Background(), tx, reportDay.Add(12*time.Hour))
+			if err != nil {
+				t.Fatalf("GetOrderStatsByDate unexpected error: %v", err)
+			}
+
+			if len(counts) != len(tt.wantCounts) {
+				t.Errorf("counts = %v, want %v", counts, tt.wantCounts)
+			}
+			for status, want := range tt.wantCounts {
+				if counts[status] != want {
+					t.Errorf("%s orders = %d, want %d", status, counts[status], want)
+				}
+			}
+			if !revenue.GrossBookings.Equal(tt.wantGross) {
+				t.Errorf("gross bookings = %s, want %s", revenue.GrossBookings, tt.wantGross)
+			}
+			if !revenue.Cancellations.Equal(tt.wantCancellations) {
+				t.Errorf("cancellations = %s, want %s", revenue.Cancellations, tt.wantCancellations)
+			}
+			if !revenue.RecognisedRevenue.Equal(tt.wantRecognised) {
+				t.Errorf("recognised revenue = %s, want %s", revenue.RecognisedRevenue, tt.wantRecognised)
+			}
+		})
+	}
+}
+
+func TestGetCustomerStatsBetween(t *testing.T) {
+	orders := []seedOrder{
+		{description: "returning customer's first order", customer: "ann", placed: -5 * 24 * time.Hour, status: models.OrderStatusDelivered,
+			delivered: hours(-4 * 24), items: []seedItem{{product: "mug", quantity: 1, price: 1000}}},
+		{description: "returning customer", customer: "ann", placed: 8 * time.Hour, status: models.OrderStatusProcessing,
+			items: []seedItem{{product: "mug", quantity: 1, price: 1000}}},
+		{description: "new customer", customer: "bob", placed: 9 * time.Hour, status: models.OrderStatusProcessing,
+			items: []seedItem{{product: "mug", quantity: 1, price: 1000}}},
+		{description: "new customer's first order", customer: "cat", placed: 10 * time.Hour, status: models.OrderStatusProcessing,
+			items: []seedItem{{product: "mug", quantity: 1, price: 1000}}},
+		{description: "new customer's second order", customer: "cat", placed: 11 * time.Hour, status: models.OrderStatusPending,
+			items: []seedItem{{product: "tea", quantity: 1, price: 2500}}},
+		{description: "next day's customer", customer: "dan", placed: 30 * time.Hour, status: models.OrderStatusProcessing,
+			items: []seedItem{{product: "mug", quantity: 1, price: 1000}}},
+	}
+
+	tests := []struct {
+		name      string
+		from, to  time.Time
+		wantTotal int
+		wantNew   int
+	}{
+		{name: "report day", from: reportDay, to: reportDay.AddDate(0, 0, 1), wantTotal: 3, wantNew: 2},
+		{name: "since the first order", from: reportDay.AddDate(0, 0, -5), to: reportDay.AddDate(0, 0, 1), wantTotal: 3, wantNew: 3},
+		{name: "next day", from: reportDay.AddDate(0, 0, 1), to: reportDay.AddDate(0, 0, 2), wantTotal: 1, wantNew: 1},
+		{name: "day without orders", from: reportDay.AddDate(0, 0, -1), to: reportDay, wantTotal: 0, wantNew: 0},
+	}
+
+	tx := testutil.OpenTestDB(t)
+	newFixtures(t, tx).orders(orders)
+	repo := NewUserRepository(tx)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			total, newCustomers, err := repo.GetCustomerStatsBetween(context.Background(), tx, tt.from, tt.to)
+			if err != nil {
+				t.Fatalf("GetCustomerStatsBetween unexpected error: %v", err)
+			}
+			if total != tt.wantTotal || newCustomers != tt.wantNew {
+				t.Errorf("customers = %d total, %d new; want %d total, %d new", total, newCustomers, tt.wantTotal, tt.wantNew)
+			}
+		})
+	}
+}
+
+func TestGetTopProducts(t *testing.T) {
+	orders := []seedOrder{
+		{description: "mugs, one removed", customer: "ann", placed: 9 * time.Hour, status: models.OrderStatusProcessing,
+			items: []seedItem{{product: "mug", quantity: 2, cancelled: 1, price: 1000}, {product: "tea", quantity: 1, price: 2500}}},
+		{description: "pots", customer: "bob", placed: 10 * time.Hour, status: models.OrderStatusShipped,
+			items: []seedItem{{product: "pot", quantity: 3, price: 500}}},
+		{description: "cancelled teas", customer: "cat", placed: 11 * time.Hour, status: models.OrderStatusCancelled,
+			items: []seedItem{{product: "tea", quantity: 5, price: 2500}}},
+		{description: "lid removed entirely", customer: "cat", placed: 12 * time.Hour, status: models.OrderStatusProcessing,
+			items: []seedItem{{product: "lid", quantity: 0, cancelled: 4, price: 100}, {product: "mug", quantity: 1, price: 1000}}},
+		{description: "next day", customer: "dan", placed: 30 * time.Hour, status: models.OrderStatusProcessing,
+			items: []seedItem{{product: "lid", quantity: 9, price: 100}}},
+	}
+
+	type want struct {
+		product string
+		sold    int
+		revenue money.Money
+	}
+	tests := []struct {
+		name  string
+		limit int
+		want  []want
+	}{
+		{
+			name:  "ranked by units sold",
+			limit: 10,
+			want: []want{
+				{product: "pot", sold: 3, revenue: usd(3 * 500)},
+				{product: "mug", sold: 2, revenue: usd(2 * 1000)},
+				{product: "tea", sold: 1, revenue: usd(2500)},
+			},
+		},
+		{
+			name:  "limited",
+			limit: 1,
+			want:  []want{{product: "pot", sold: 3, revenue: usd(3 * 500)}},
+		},
+	}
+
+	tx := testutil.OpenTestDB(t)
+	f := newFixtures(t, tx)
+	f.orders(orders)
+	repo := NewProductRepository(tx)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			products, err := repo.GetTopProducts(context.Background(), tx, reportDay, tt.limit)
+			if err != nil {
+				t.Fatalf("GetTopProducts unexpected error: %v", err)
+			}
+			if len(products) != len(tt.want) {
+				t.Fatalf("GetTopProducts returned %d products, want %d: %+v", len(products), len(tt.want), products)
+			}
+			for i, want := range tt.want {
+				got := products[i]
+				if got.ProductID != f.products[want.product].ID || got.ProductName != want.product {
+					t.Errorf("product %d = %s (%d), want %s", i, got.ProductName, got.ProductID, want.product)
+				}
+				if got.QuantitySold != want.sold || !got.Revenue.Equal(want.revenue) {
+					t.Errorf("%s sold %d for %s, want %d for %s", want.product, got.QuantitySold, got.Revenue, want.sold, want.revenue)
+				}
+			}
+		})
+	}
+}
+
+func TestGetAverageStock(t *testing.T) {
+	type movement struct {
+		at    time.Duration // Offset from the start of the report day
+		delta int           // Change to stock on hand
+	}
+	tests := []struct {
+		name      string
+		stock     *int // Current stock on hand; nil for a product without inventory
+		movements []movement
+		want      float64
+	}{
+		{
+			name:  "no movements",
+			stock: intPtr(10),
+			want:  10,
+		},
+		{
+			name:  "sold during the day, restocked after",
+			stock: intPtr(10),
+			// Closing stock 10-6 = 4, opening stock 4+4 = 8
+			movements: []movement{{at: 3 * time.Hour, delta: -4}, {at: 50 * time.Hour, delta: 6}},
+			want:      6,
+		},
+		{
+			name:      "earlier movements don't count",
+			stock:     intPtr(5),
+			movements: []movement{{at: -3 * time.Hour, delta: 20}, {at: 12 * time.Hour, delta: -2}},
+			want:      6,
+		},
+		{
+			name: "no inventory",
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := testutil.OpenTestDB(t)
+			product := newFixtures(t, tx).product("stocked")
+
+			var warehouse models.Warehouse
+			if err := tx.Where("is_default = ?", true).First(&warehouse).Error; err != nil {
+				t.Fatalf("failed to get default warehouse: %v", err)
+			}
+			if tt.stock != nil {
+				// Stock on hand is available plus reserved
+				inventory := &models.Inventory{ProductID: product.ID, WarehouseID: warehouse.ID, Quantity: *tt.stock - 1, Reserved: 1}
+				if err := tx.Create(inventory).Error; err != nil {
+					t.Fatalf("failed to create inventory: %v", err)
+				}
+			}
+			for _, m := range tt.movements {
+				entry := &models.StockMovement{
+					ProductID:     product.ID,
+					WarehouseID:   warehouse.ID,
+					Type:          models.StockMovementAdjustment,
+					QuantityDelta: m.delta,
+				}
+				entry.CreatedAt = reportDay.Add(m.at)
+				if err := tx.Create(entry).Error; err != nil {
+					t.Fatalf("failed to create stock movement: %v", err)
+				}
+			}
+
+			average, err := NewProductRepository(tx).GetAverageStock(context.Background(), tx, []uint{product.ID}, reportDay)
+			if err != nil {
+				t.Fatalf("GetAverageStock unexpected error: %v", err)
+			}
+			if average[product.ID] != tt.want {
+				t.Errorf("average stock = %v, want %v", average[product.ID], tt.want)
+			}
+		})
+	}
+}
+
+func intPtr(n int) *int {
+	return &n
+}
@@ -6,11 +6,13 @@ import (
 
 	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ReportRepository interface {
-	CreateReport(ctx context.Context, tx *gorm.DB, report *models.DailySalesReport) error
+	SaveReport(ctx context.Context, tx *gorm.DB, report *models.DailySalesReport) error
 	GetReportByDate(ctx context.Context, tx *gorm.DB, date time.Time) (*models.DailySalesReport, error)
+	ListReportsBetween(ctx context.Context, tx *gorm.DB, from, to time.Time) ([]models.DailySalesReport, error)
 }
 
 type reportRepository struct {
@@ -21,8 +23,51 @@ func NewReportRepository(db *gorm.DB) ReportRepository {
 	return &reportRepository{db: db}
 }
 
-func (r *reportRepository) CreateReport(ctx context.Context, tx *gorm.DB, report *models.DailySalesReport) error {
-	return tx.WithContext(ctx).Create(report).Error
+// SaveReport stores the report of a day, replacing the report and product lists already
+// stored for that day if there are any
+func (r *reportRepository) SaveReport(ctx context.Context, tx *gorm.DB, report *models.DailySalesReport) error {
+	err := tx.WithContext(ctx).
+		Omit(clause.Associations).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "date"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"updated_at", "deleted_at", "total_orders", "pending_orders", "processing_orders",
+				"shipped_orders", "delivered_orders", "cancelled_orders", "currency", "gross_bookings",
+				"cancellations", "net_revenue", "recognised_revenue", "total_revenue", "average_order_value",
+				"unique_customers", "new_customers", "order_fulfillment_rate", "cancellation_rate",
+			}),
+		}).
+		Create(report).Error
+	if err != nil {
+		return err
+	}
+
+	// The conflict update returns the stored report's ID, so its lists can be replaced
+	if err := tx.WithContext(ctx).Unscoped().Where("report_id = ?", report.ID).Delete(&models.TopProduct{}).Error; err != nil {
+		return err
+	}
+	if err := tx.WithContext(ctx).Unscoped().Where("report_id = ?", report.ID).Delete(&models.LowStockAlert{}).Error; err != nil {
+		return err
+	}
+	for i := range report.TopProducts {
+		report.TopProducts[i].ID = 0
+		report.TopProducts[i].ReportID = report.ID
+	}
+	for i := range report.LowStockProducts {
+		report.LowStockProducts[i].ID = 0
+		report.LowStockProducts[i].ReportID = report.ID
+	}
+	if len(report.TopProducts) > 0 {
+		if err := tx.WithContext(ctx).Create(&report.TopProducts).Error; err != nil {
+			return err
+		}
+	}
+	if len(report.LowStockProducts) > 0 {
+		if err := tx.WithContext(ctx).Create(&report.LowStockProducts).Error; err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (r *reportRepository) GetReportByDate(ctx context.Context, tx *gorm.DB, date time.Time) (*models.DailySalesReport, error) {
@@ -38,4 +83,16 @@ func (r *reportRepository) GetReportByDate(ctx context.Context, tx *gorm.DB, dat
 	return &report, nil
 }
 
-
+// ListReportsBetween returns the stored reports of the days from the first date through the
+// second, oldest first, without their product lists
+func (r *reportRepository) ListReportsBetween(ctx context.Context, tx *gorm.DB, from, to time.Time) ([]models.DailySalesReport, error) {
+	var reports []models.DailySalesReport
+	err := tx.WithContext(ctx).
+		Where("DATE(date) BETWEEN DATE(?) AND DATE(?)", from, to).
+		Order("date").
+		Find(&reports).Error
+	if err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+)
+
+type StockAlertRepository interface {
+	Create(ctx context.Context, tx *gorm.DB, alert *models.StockAlert) error
+	Update(ctx context.Context, tx *gorm.DB, alert *models.StockAlert) error
+	FindByID(ctx context.Context, tx *gorm.DB, id uint) (*models.StockAlert, error)
+	FindOpen(ctx context.Context, tx *gorm.DB, productID, warehouseID uint) (*models.StockAlert, error)
+	ListOpen(ctx context.Context, tx *gorm.DB) ([]models.StockAlert, error)
+}
+
+type stockAlertRepository struct {
+	db *gorm.DB
+}
+
+func NewStockAlertRepository(db *gorm.DB) StockAlertRepository {
+	return &stockAlertRepository{db: db}
+}
+
+func (r *stockAlertRepository) Create(ctx context.Context, tx *gorm.DB, alert *models.StockAlert) error {
+	return tx.WithContext(ctx).Create(alert).Error
+}
+
+func (r *stockAlertRepository) Update(ctx context.Context, tx *gorm.DB, alert *models.StockAlert) error {
+	return tx.WithContext(ctx).Save(alert).Error
+}
+
+func (r *stockAlertRepository) FindByID(ctx context.Context, tx *gorm.DB, id uint) (*models.StockAlert, error) {
+	var alert models.StockAlert
+	err := tx.WithContext(ctx).
+		Preload("Product").
+		Preload("Warehouse").
+		First(&alert, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &alert, nil
+}
+
+func (r *stockAlertRepository) FindOpen(ctx context.Context, tx *gorm.DB, productID, warehouseID uint) (*models.StockAlert, error) {
+	var alert models.StockAlert
+	err := tx.WithContext(ctx).
+		Where("product_id = ? AND warehouse_id = ? AND status = ?", productID, warehouseID, models.StockAlertStatusOpen).
+		First(&alert).Error
+	if err != nil {
+		return nil, err
+	}
+	return &alert, nil
+}
+
+// ListOpen returns every open alert with its product and warehouse, lowest stock first
+func (r *stockAlertRepository) ListOpen(ctx context.Context, tx *gorm.DB) ([]models.StockAlert, error) {
+	var alerts []models.StockAlert
+	err := tx.WithContext(ctx).
+		Preload("Product").
+		Preload("Warehouse").
+		Where("status = ?", models.StockAlertStatusOpen).
+		Order("stock_level - minimum_stock, id").
+		Find(&alerts).Error
+	if err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
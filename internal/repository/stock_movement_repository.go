@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+)
+
+// StockLedgerBalance compares an inventory row against the sum of its ledger entries
+type StockLedgerBalance struct {
+	ProductID      uint
+	WarehouseID    uint
+	Quantity       int
+	Reserved       int
+	LedgerQuantity int
+	LedgerReserved int
+}
+
+type StockMovementRepository interface {
+	Create(ctx context.Context, tx *gorm.DB, movement *models.StockMovement) error
+	ListByProduct(ctx context.Context, tx *gorm.DB, productID uint, offset, limit int) ([]models.StockMovement, int64, error)
+	GetLedgerBalances(ctx context.Context, tx *gorm.DB) ([]StockLedgerBalance, error)
+	GetLedgerBalance(ctx context.Context, tx *gorm.DB, productID, warehouseID uint) (*StockLedgerBalance, error)
+	GetNetDemand(ctx context.Context, tx *gorm.DB, productID, warehouseID uint, since time.Time) (int, error)
+}
+
+type stockMovementRepository struct {
+	db *gorm.DB
+}
+
+func NewStockMovementRepository(db *gorm.DB) StockMovementRepository {
+	return &stockMovementRepository{db: db}
+}
+
+func (r *stockMovementRepository) Create(ctx context.Context, tx *gorm.DB, movement *models.StockMovement) error {
+	return tx.WithContext(ctx).Create(movement).Error
+}
+
+func (r *stockMovementRepository) ListByProduct(ctx context.Context, tx *gorm.DB, productID uint, offset, limit int) ([]models.StockMovement, int64, error) {
+	var movements []models.StockMovement
+	var total int64
+
+	query := tx.WithContext(ctx).Model(&models.StockMovement{}).Where("product_id = ?", productID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Order("created_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&movements).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return movements, total, nil
+}
+
+func (r *stockMovementRepository) GetLedgerBalances(ctx context.Context, tx *gorm.DB) ([]StockLedgerBalance, error) {
+	var balances []StockLedgerBalance
+	err := ledgerBalances(ctx, tx).
+		Order("inventories.product_id, inventories.warehouse_id").
+		Scan(&balances).Error
+
+	return balances, err
+}
+
+// GetLedgerBalance compares one inventory row against the sum of its ledger entries
+func (r *stockMovementRepository) GetLedgerBalance(ctx context.Context, tx *gorm.DB, productID, warehouseID uint) (*StockLedgerBalance, error) {
+	var balances []StockLedgerBalance
+	err := ledgerBalances(ctx, tx).
+		Where("inventories.product_id = ? AND inventories.warehouse_id = ?", productID, warehouseID).
+		Scan(&balances).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(balances) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &balances[0], nil
+}
+
+// ledgerBalances builds the query joining inventory rows with their ledger sums
+func ledgerBalances(ctx context.Context, tx *gorm.DB) *gorm.DB {
+	ledger := tx.Model(&models.StockMovement{}).
+		Select("product_id, warehouse_id, SUM(quantity_delta) as ledger_quantity, SUM(reserved_delta) as ledger_reserved").
+		Group("product_id, warehouse_id")
+
+	return tx.WithContext(ctx).
+		Table("inventories").
+		Select(
+			"inventories.product_id,"+
+				"inventories.warehouse_id,"+
+				"inventories.quantity,"+
+				"inventories.reserved,"+
+				"COALESCE(ledger.ledger_quantity, 0) as ledger_quantity,"+
+				"COALESCE(ledger.ledger_reserved, 0) as ledger_reserved",
+		).
+		Joins("LEFT JOIN (?) as ledger ON ledger.product_id = inventories.product_id AND ledger.warehouse_id = inventories.warehouse_id", ledger).
+		Where("inventories.deleted_at IS NULL")
+}
+
+// GetNetDemand returns the units reserved for orders since the given time, net of
+// reservations released by cancellations
+func (r *stockMovementRepository) GetNetDemand(ctx context.Context, tx *gorm.DB, productID, warehouseID uint, since time.Time) (int, error) {
+	var demand int
+	err := tx.WithContext(ctx).
+		Model(&models.StockMovement{}).
+		Select("COALESCE(SUM(reserved_delta), 0)").
+		Where("product_id = ? AND warehouse_id = ? AND created_at >= ?", productID, warehouseID, since).
+		Where("type IN ?", []models.StockMovementType{models.StockMovementReservation, models.StockMovementRelease}).
+		Scan(&demand).Error
+	return demand, err
+}
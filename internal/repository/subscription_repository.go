@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+)
+
+type SubscriptionRepository interface {
+	Create(ctx context.Context, tx *gorm.DB, subscription *models.Subscription) error
+	Update(ctx context.Context, tx *gorm.DB, subscription *models.Subscription) error
+	FindByID(ctx context.Context, tx *gorm.DB, id uint) (*models.Subscription, error)
+	FindByIDForUpdate(ctx context.Context, tx *gorm.DB, id uint) (*models.Subscription, error)
+	ListByUser(ctx context.Context, tx *gorm.DB, userID uint) ([]models.Subscription, error)
+	ListDueIDs(ctx context.Context, tx *gorm.DB, now time.Time, limit int) ([]uint, error)
+	ClaimDue(ctx context.Context, tx *gorm.DB, id uint, now time.Time) (*models.Subscription, error)
+	ListReminderDue(ctx context.Context, tx *gorm.DB, before time.Time) ([]models.Subscription, error)
+	// MarkReminderSent records the reminder of an active subscription that hasn't been
+	// reminded yet. It returns false if the subscription no longer needs a reminder, or
+	// another instance is sending it.
+	MarkReminderSent(ctx context.Context, tx *gorm.DB, id uint, sentAt time.Time) (bool, error)
+	// SetRenewalOrder records the order placed for the renewal in progress
+	SetRenewalOrder(ctx context.Context, tx *gorm.DB, id, orderID uint) error
+}
+
+type subscriptionRepository struct {
+	db *gorm.DB
+}
+
+func NewSubscriptionRepository(db *gorm.DB) SubscriptionRepository {
+	return &subscriptionRepository{db: db}
+}
+
+// dueCondition matches active subscriptions whose renewal, or retry of a failed renewal, is
+// due and that no instance is renewing. It takes the status and the current time twice.
+const dueCondition = "status = ? AND COALESCE(retry_at, next_renewal_at) <= ? AND (claimed_until IS NULL OR claimed_until <= ?)"
+
+func (r *subscriptionRepository) Create(ctx context.Context, tx *gorm.DB, subscription *models.Subscription) error {
+	return tx.WithContext(ctx).Create(subscription).Error
+}
+
+func (r *subscriptionRepository) Update(ctx context.Context, tx *gorm.DB, subscription *models.Subscription) error {
+	return tx.WithContext(ctx).Omit(clause.Associations).Save(subscription).Error
+}
+
+func (r *subscriptionRepository) FindByID(ctx context.Context, tx *gorm.DB, id uint) (*models.Subscription, error) {
+	var subscription models.Subscription
+	err := tx.WithContext(ctx).
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Preload("Items.Product").
+		First(&subscription, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+// FindByIDForUpdate locks the subscription row and loads its items and their products
+func (r *subscriptionRepository) FindByIDForUpdate(ctx context.Context, tx *gorm.DB, id uint) (*models.Subscription, error) {
+	var subscription models.Subscription
+	err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&subscription, id).Error
+	if err != nil {
+		return nil, err
+	}
+	if err := r.loadItems(ctx, tx, &subscription); err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+// ListByUser returns the user's subscriptions with their items, newest first
+func (r *subscriptionRepository) ListByUser(ctx context.Context, tx *gorm.DB, userID uint) ([]models.Subscription, error) {
+	var subscriptions []models.Subscription
+	err := tx.WithContext(ctx).
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Preload("Items.Product").
+		Where("user_id = ?", userID).
+		Order("id DESC").
+		Find(&subscriptions).Error
+	if err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+// ListDueIDs returns the IDs of subscriptions due for renewal, longest overdue first
+func (r *subscriptionRepository) ListDueIDs(ctx context.Context, tx *gorm.DB, now time.Time, limit int) ([]uint, error) {
+	var ids []uint
+	err := tx.WithContext(ctx).
+		Model(&models.Subscription{}).
+		Where(dueCondition, models.SubscriptionStatusActive, now, now).
+		Order("COALESCE(retry_at, next_renewal_at), id").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// ClaimDue locks a subscription that is still due for renewal, skipping it if another
+// instance holds the lock. It returns gorm.ErrRecordNotFound if there is nothing to renew.
+func (r *subscriptionRepository) ClaimDue(ctx context.Context, tx *gorm.DB, id uint, now time.Time) (*models.Subscription, error) {
+	var subscription models.Subscription
+	err := tx.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where(dueCondition, models.SubscriptionStatusActive, now, now).
+		First(&subscription, id).Error
+	if err != nil {
+		return nil, err
+	}
+	if err := r.loadItems(ctx, tx, &subscription); err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+// ListReminderDue returns active subscriptions renewing before the given time whose
+// customers haven't been reminded yet
+func (r *subscriptionRepository) ListReminderDue(ctx context.Context, tx *gorm.DB, before time.Time) ([]models.Subscription, error) {
+	var subscriptions []models.Subscription
+	err := tx.WithContext(ctx).
+		Where("status = ? AND reminder_sent_at IS NULL AND retry_at IS NULL AND next_renewal_at <= ?",
+			models.SubscriptionStatusActive, before).
+		Order("next_renewal_at, id").
+		Find(&subscriptions).Error
+	if err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
+
+func (r *subscriptionRepository) MarkReminderSent(ctx context.Context, tx *gorm.DB, id uint, sentAt time.Time) (bool, error) {
+	result := tx.WithContext(ctx).
+		Model(&models.Subscription{}).
+		Where("id = ? AND reminder_sent_at IS NULL AND status = ?", id, models.SubscriptionStatusActive).
+		Update("reminder_sent_at", sentAt)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *subscriptionRepository) SetRenewalOrder(ctx context.Context, tx *gorm.DB, id, orderID uint) error {
+	return tx.WithContext(ctx).
+		Model(&models.Subscription{}).
+		Where("id = ?", id).
+		Update("renewal_order_id", orderID).Error
+}
+
+// loadItems loads the items with a plain query so only the subscription row is locked
+func (r *subscriptionRepository) loadItems(ctx context.Context, tx *gorm.DB, subscription *models.Subscription) error {
+	return tx.WithContext(ctx).
+		Preload("Product").
+		Where("subscription_id = ?", subscription.ID).
+		Order("id").
+		Find(&subscription.Items).Error
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+)
+
+type SupplierRepository interface {
+	Create(ctx context.Context, tx *gorm.DB, supplier *models.Supplier) error
+	Update(ctx context.Context, tx *gorm.DB, supplier *models.Supplier) error
+	FindByID(ctx context.Context, tx *gorm.DB, id uint) (*models.Supplier, error)
+	List(ctx context.Context, tx *gorm.DB) ([]models.Supplier, error)
+}
+
+type supplierRepository struct {
+	db *gorm.DB
+}
+
+func NewSupplierRepository(db *gorm.DB) SupplierRepository {
+	return &supplierRepository{db: db}
+}
+
+func (r *supplierRepository) Create(ctx context.Context, tx *gorm.DB, supplier *models.Supplier) error {
+	return tx.WithContext(ctx).Create(supplier).Error
+}
+
+func (r *supplierRepository) Update(ctx context.Context, tx *gorm.DB, supplier *models.Supplier) error {
+	return tx.WithContext(ctx).Save(supplier).Error
+}
+
+func (r *supplierRepository) FindByID(ctx context.Context, tx *gorm.DB, id uint) (*models.Supplier, error) {
+	var supplier models.Supplier
+	err := tx.WithContext(ctx).First(&supplier, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &supplier, nil
+}
+
+func (r *supplierRepository) List(ctx context.Context, tx *gorm.DB) ([]models.Supplier, error) {
+	var suppliers []models.Supplier
+	err := tx.WithContext(ctx).Order("name, id").Find(&suppliers).Error
+	if err != nil {
+		return nil, err
+	}
+	return suppliers, nil
+}
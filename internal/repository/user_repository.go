@@ -13,7 +13,17 @@ type UserRepository interface {
 	FindByEmail(ctx context.Context, email string) (*models.User, error)
 	FindByID(ctx context.Context, id uint) (*models.User, error)
 	Update(ctx context.Context, user *models.User) error
-	GetUniqueCustomerStats(ctx context.Context, tx *gorm.DB, date time.Time) (total int, new int, err error)
+	ListByRole(ctx context.Context, role models.UserRole) ([]models.User, error)
+	GetCustomerStatsBetween(ctx context.Context, tx *gorm.DB, from, to time.Time) (total int, new int, err error)
+	GetCustomerStatsByPeriod(ctx context.Context, tx *gorm.DB, from, to time.Time, granularity string) ([]CustomerPeriodStats, error)
+}
+
+// CustomerPeriodStats counts the customers who ordered in a report period and those
+// whose first order ever was in it
+type CustomerPeriodStats struct {
+	Period    time.Time
+	Customers int
+	New       int
 }
 
 type userRepository struct {
@@ -46,37 +56,92 @@ func (r *userRepository) FindByID(ctx context.Context, id uint) (*models.User, e
 	return &user, nil
 }
 
-func (r *userRepository) GetUniqueCustomerStats(ctx context.Context, tx *gorm.DB, date time.Time) (total int, new int, err error) {
+// GetCustomerStatsBetween counts the customers who ordered in [from, to) and those whose
+// first order ever was in it
+func (r *userRepository) GetCustomerStatsBetween(ctx context.Context, tx *gorm.DB, from, to time.Time) (total int, new int, err error) {
 	var totalCount, newCount int64
 
-	// Get total unique customers for the day
 	err = tx.WithContext(ctx).
 		Model(&models.Order{}).
-		Where("DATE(created_at) = DATE(?)", date).
+		Where("created_at >= ? AND created_at < ?", from, to).
 		Distinct("user_id").
 		Count(&totalCount).Error
 	if err != nil {
 		return 0, 0, err
 	}
 
-	// Get new customers (first order on this date)
 	err = tx.WithContext(ctx).
-		Model(&models.Order{}).
-		Where("DATE(created_at) = DATE(?)", date).
-		Where("user_id IN (?)",
-			tx.Model(&models.Order{}).
-				Select("user_id").
-				Where("DATE(created_at) = DATE(?)", date).
-				Group("user_id").
-				Having("MIN(DATE(created_at)) = DATE(?)", date),
-		).
-		Distinct("user_id").
+		Table("(?) AS first_orders", firstOrders(tx)).
+		Where("first_order_at >= ? AND first_order_at < ?", from, to).
 		Count(&newCount).Error
 
 	return int(totalCount), int(newCount), err
 }
 
+// GetCustomerStatsByPeriod counts customers like GetCustomerStatsBetween for every UTC
+// day, week or month of [from, to). Periods without orders are left out.
+func (r *userRepository) GetCustomerStatsByPeriod(ctx context.Context, tx *gorm.DB, from, to time.Time, granularity string) ([]CustomerPeriodStats, error) {
+	type Row struct {
+		Period time.Time
+		Count  int
+	}
+
+	var customers []Row
+	err := tx.WithContext(ctx).
+		Model(&models.Order{}).
+		Select("date_trunc(?, created_at AT TIME ZONE 'UTC') AS period, COUNT(DISTINCT user_id) AS count", granularity).
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Group("period").
+		Scan(&customers).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var newCustomers []Row
+	err = tx.WithContext(ctx).
+		Table("(?) AS first_orders", firstOrders(tx)).
+		Select("date_trunc(?, first_order_at AT TIME ZONE 'UTC') AS period, COUNT(*) AS count", granularity).
+		Where("first_order_at >= ? AND first_order_at < ?", from, to).
+		Group("period").
+		Scan(&newCustomers).Error
+	if err != nil {
+		return nil, err
+	}
+
+	byPeriod := make(map[time.Time]*CustomerPeriodStats)
+	var stats []CustomerPeriodStats
+	for _, row := range customers {
+		stats = append(stats, CustomerPeriodStats{Period: row.Period.UTC(), Customers: row.Count})
+	}
+	for i := range stats {
+		byPeriod[stats[i].Period] = &stats[i]
+	}
+	for _, row := range newCustomers {
+		// Every new customer ordered in the period, so it is already listed
+		if s, ok := byPeriod[row.Period.UTC()]; ok {
+			s.New = row.Count
+		}
+	}
+	return stats, nil
+}
+
+// firstOrders selects the time of every customer's first order
+func firstOrders(tx *gorm.DB) *gorm.DB {
+	return tx.Model(&models.Order{}).
+		Select("user_id, MIN(created_at) AS first_order_at").
+		Group("user_id")
+}
+
 // Update updates a user in the database
 func (r *userRepository) Update(ctx context.Context, user *models.User) error {
 	return r.db.WithContext(ctx).Save(user).Error
 }
+
+func (r *userRepository) ListByRole(ctx context.Context, role models.UserRole) ([]models.User, error) {
+	var users []models.User
+	err := r.db.WithContext(ctx).Where("role = ? AND active = ?", role, true).Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+)
+
+type WarehouseRepository interface {
+	Create(ctx context.Context, tx *gorm.DB, warehouse *models.Warehouse) error
+	Update(ctx context.Context, tx *gorm.DB, warehouse *models.Warehouse) error
+	FindByID(ctx context.Context, tx *gorm.DB, id uint) (*models.Warehouse, error)
+	FindByCode(ctx context.Context, tx *gorm.DB, code string) (*models.Warehouse, error)
+	GetDefault(ctx context.Context, tx *gorm.DB) (*models.Warehouse, error)
+	List(ctx context.Context, tx *gorm.DB) ([]models.Warehouse, error)
+	ClearDefault(ctx context.Context, tx *gorm.DB, exceptID uint) error
+}
+
+type warehouseRepository struct {
+	db *gorm.DB
+}
+
+func NewWarehouseRepository(db *gorm.DB) WarehouseRepository {
+	return &warehouseRepository{db: db}
+}
+
+func (r *warehouseRepository) Create(ctx context.Context, tx *gorm.DB, warehouse *models.Warehouse) error {
+	return tx.WithContext(ctx).Create(warehouse).Error
+}
+
+func (r *warehouseRepository) Update(ctx context.Context, tx *gorm.DB, warehouse *models.Warehouse) error {
+	return tx.WithContext(ctx).Save(warehouse).Error
+}
+
+func (r *warehouseRepository) FindByID(ctx context.Context, tx *gorm.DB, id uint) (*models.Warehouse, error) {
+	var warehouse models.Warehouse
+	err := tx.WithContext(ctx).First(&warehouse, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &warehouse, nil
+}
+
+func (r *warehouseRepository) FindByCode(ctx context.Context, tx *gorm.DB, code string) (*models.Warehouse, error) {
+	var warehouse models.Warehouse
+	err := tx.WithContext(ctx).Where("code = ?", code).First(&warehouse).Error
+	if err != nil {
+		return nil, err
+	}
+	return &warehouse, nil
+}
+
+func (r *warehouseRepository) GetDefault(ctx context.Context, tx *gorm.DB) (*models.Warehouse, error) {
+	var warehouse models.Warehouse
+	err := tx.WithContext(ctx).Where("is_default = ?", true).Order("id").First(&warehouse).Error
+	if err != nil {
+		return nil, err
+	}
+	return &warehouse, nil
+}
+
+func (r *warehouseRepository) List(ctx context.Context, tx *gorm.DB) ([]models.Warehouse, error) {
+	var warehouses []models.Warehouse
+	err := tx.WithContext(ctx).Order("id").Find(&warehouses).Error
+	if err != nil {
+		return nil, err
+	}
+	return warehouses, nil
+}
+
+// ClearDefault unsets the default flag on every warehouse except the given one
+func (r *warehouseRepository) ClearDefault(ctx context.Context, tx *gorm.DB, exceptID uint) error {
+	return tx.WithContext(ctx).
+		Model(&models.Warehouse{}).
+		Where("id <> ? AND is_default = ?", exceptID, true).
+		Update("is_default", false).Error
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+)
+
+// Allocation strategy names, selected with the ALLOCATION_STRATEGY environment variable
+const (
+	AllocationNearest   = "nearest"
+	AllocationMostStock = "most_stock"
+	AllocationSplit     = "split"
+)
+
+// AllocationStrategy decides which warehouses fulfil an order item.
+// Inventories are the locked inventory rows of the product with their warehouse loaded.
+type AllocationStrategy interface {
+	Name() string
+	Allocate(destination models.Address, quantity int, inventories []models.Inventory) ([]models.OrderItemAllocation, error)
+}
+
+// ErrAllocationFailed is returned when no combination of warehouses allowed by the strategy can fulfil the quantity
+var ErrAllocationFailed = errors.New("no warehouse can fulfil the requested quantity")
+
+// NewAllocationStrategy returns the strategy registered under name
+func NewAllocationStrategy(name string) (AllocationStrategy, error) {
+	switch strings.ToLower(name) {
+	case AllocationNearest, "":
+		return nearestStrategy{}, nil
+	case AllocationMostStock:
+		return mostStockStrategy{}, nil
+	case AllocationSplit:
+		return splitStrategy{}, nil
+	default:
+		return nil, fmt.Errorf("unknown allocation strategy %q", name)
+	}
+}
+
+// nearestStrategy ships each item from the closest warehouse that holds the full quantity
+type nearestStrategy struct{}
+
+func (nearestStrategy) Name() string { return AllocationNearest }
+
+func (nearestStrategy) Allocate(destination models.Address, quantity int, inventories []models.Inventory) ([]models.OrderItemAllocation, error) {
+	candidates := activeInventories(inventories)
+	sortByDistance(destination, candidates)
+	for _, inventory := range candidates {
+		if inventory.Quantity >= quantity {
+			return []models.OrderItemAllocation{{WarehouseID: inventory.WarehouseID, Quantity: quantity}}, nil
+		}
+	}
+	return nil, ErrAllocationFailed
+}
+
+// mostStockStrategy ships each item from the warehouse with the most available stock
+type mostStockStrategy struct{}
+
+func (mostStockStrategy) Name() string { return AllocationMostStock }
+
+func (mostStockStrategy) Allocate(destination models.Address, quantity int, inventories []models.Inventory) ([]models.OrderItemAllocation, error) {
+	candidates := activeInventories(inventories)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Quantity > candidates[j].Quantity
+	})
+	if len(candidates) == 0 || candidates[0].Quantity < quantity {
+		return nil, ErrAllocationFailed
+	}
+	return []models.OrderItemAllocation{{WarehouseID: candidates[0].WarehouseID, Quantity: quantity}}, nil
+}
+
+// splitStrategy fills the item from the nearest warehouses first and splits the
+// shipment across as many warehouses as needed
+type splitStrategy struct{}
+
+func (splitStrategy) Name() string { return AllocationSplit }
+
+func (splitStrategy) Allocate(destination models.Address, quantity int, inventories []models.Inventory) ([]models.OrderItemAllocation, error) {
+	candidates := activeInventories(inventories)
+	sortByDistance(destination, candidates)
+
+	var allocations []models.OrderItemAllocation
+	remaining := quantity
+	for _, inventory := range candidates {
+		if remaining == 0 {
+			break
+		}
+		take := min(inventory.Quantity, remaining)
+		if take <= 0 {
+			continue
+		}
+		allocations = append(allocations, models.OrderItemAllocation{WarehouseID: inventory.WarehouseID, Quantity: take})
+		remaining -= take
+	}
+	if remaining > 0 {
+		return nil, ErrAllocationFailed
+	}
+	return allocations, nil
+}
+
+// activeInventories returns a copy of the rows whose warehouse accepts allocations
+func activeInventories(inventories []models.Inventory) []models.Inventory {
+	active := make([]models.Inventory, 0, len(inventories))
+	for _, inventory := range inventories {
+		if inventory.Warehouse != nil && !inventory.Warehouse.Active {
+			continue
+		}
+		active = append(active, inventory)
+	}
+	return active
+}
+
+// sortByDistance orders inventories by how close their warehouse is to the destination.
+// Great-circle distance is used when both sides have coordinates; otherwise warehouses
+// in the same postal code, city or country rank ahead of the rest.
+func sortByDistance(destination models.Address, inventories []models.Inventory) {
+	sort.SliceStable(inventories, func(i, j int) bool {
+		return distanceScore(destination, inventories[i].Warehouse) < distanceScore(destination, inventories[j].Warehouse)
+	})
+}
+
+// Scores used when coordinates are missing; larger than any distance on Earth in km
+const (
+	scoreSamePostalCode = 100000
+	scoreSameCity       = 200000
+	scoreSameCountry    = 300000
+	scoreUnknown        = 400000
+)
+
+func distanceScore(destination models.Address, warehouse *models.Warehouse) float64 {
+	if warehouse == nil {
+		return scoreUnknown
+	}
+	origin := warehouse.Address
+	if destination.Latitude != nil && destination.Longitude != nil && origin.Latitude != nil && origin.Longitude != nil {
+		return haversineKm(*destination.Latitude, *destination.Longitude, *origin.Latitude, *origin.Longitude)
+	}
+
+	sameCountry := destination.Country != "" && strings.EqualFold(destination.Country, origin.Country)
+	switch {
+	case sameCountry && destination.PostalCode != "" && strings.EqualFold(destination.PostalCode, origin.PostalCode):
+		return scoreSamePostalCode
+	case sameCountry && destination.City != "" && strings.EqualFold(destination.City, origin.City):
+		return scoreSameCity
+	case sameCountry:
+		return scoreSameCountry
+	default:
+		return scoreUnknown
+	}
+}
+
+// haversineKm returns the great-circle distance between two coordinates in kilometres
+func haversineKm(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadiusKm = 6371.0
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return earthRadiusKm * 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/logger"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/websocket"
+)
+
+// AllocateBackorders reserves newly available stock for backordered items, oldest order
+// first. Orders whose items are all reserved move on to processing and their customers
+// are notified. It is registered as a stock change listener; failures are logged, not returned.
+func (s *OrderService) AllocateBackorders(ctx context.Context, productIDs ...uint) {
+	seen := make(map[uint]bool, len(productIDs))
+	for _, productID := range productIDs {
+		if seen[productID] {
+			continue
+		}
+		seen[productID] = true
+
+		released, err := s.allocateProductBackorders(ctx, productID)
+		if err != nil {
+			logger.Error(ctx, "Failed to allocate backorders",
+				zap.Error(err),
+				zap.Uint("product_id", productID))
+			continue
+		}
+
+		for i := range released {
+			s.dashboardSvc.OrderStatusChanged(ctx, &released[i], models.OrderStatusBackordered)
+			s.publishOrderEvent(websocket.EventBackorderAllocated, &released[i])
+			s.notifyBackorderAllocated(ctx, &released[i])
+		}
+	}
+}
+
+// allocateProductBackorders hands out the product's available stock to its backordered
+// items in FIFO order and returns the orders that became fully allocated
+func (s *OrderService) allocateProductBackorders(ctx context.Context, productID uint) ([]models.Order, error) {
+	var released []models.Order
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Lock the product first, like CreateOrder, so both sides take locks in the same order
+		if _, err := s.orderRepo.GetProductByIDForUpdate(ctx, tx, productID); err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil
+			}
+			return err
+		}
+
+		inventories, err := s.inventoryRepo.ListByProductForUpdate(ctx, tx, productID)
+		if err != nil {
+			return err
+		}
+
+		items, err := s.orderRepo.ListBackorderedItems(ctx, tx, productID)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			available := 0
+			for _, inventory := range activeInventories(inventories) {
+				available += max(inventory.Quantity, 0)
+			}
+			if available == 0 {
+				break
+			}
+
+			take := min(item.BackorderedQuantity, available)
+			allocations, err := splitStrategy{}.Allocate(item.Order.ShippingAddress, take, inventories)
+			if err != nil {
+				return err
+			}
+
+			for i := range allocations {
+				allocations[i].OrderItemID = item.ID
+				if _, err := s.inventorySvc.ApplyMovement(ctx, tx, &models.StockMovement{
+					ProductID:     productID,
+					WarehouseID:   allocations[i].WarehouseID,
+					Type:          models.StockMovementReservation,
+					QuantityDelta: -allocations[i].Quantity,
+					ReservedDelta: allocations[i].Quantity,
+					ReferenceType: models.StockReferenceOrder,
+					ReferenceID:   &item.OrderID,
+				}); err != nil {
+					return err
+				}
+				deductInventory(inventories, allocations[i])
+			}
+			if err := s.orderRepo.CreateAllocations(ctx, tx, allocations); err != nil {
+				return err
+			}
+
+			item.BackorderedQuantity -= take
+			if err := s.orderRepo.UpdateItem(ctx, tx, &item); err != nil {
+				return err
+			}
+
+			// Release the order once none of its items are waiting for stock
+			order, err := s.orderRepo.GetOrderByIDForUpdate(ctx, tx, item.OrderID)
+			if err != nil {
+				return err
+			}
+			if order.Status != models.OrderStatusBackordered || hasBackorderedItems(order) {
+				continue
+			}
+			order.Status = models.OrderStatusProcessing
+			if err := s.orderRepo.Update(ctx, tx, order); err != nil {
+				return err
+			}
+			released = append(released, *order)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return released, nil
+}
+
+// deductInventory mirrors a reservation on the locally held inventory rows
+func deductInventory(inventories []models.Inventory, allocation models.OrderItemAllocation) {
+	for i := range inventories {
+		if inventories[i].WarehouseID == allocation.WarehouseID {
+			inventories[i].Quantity -= allocation.Quantity
+			inventories[i].Reserved += allocation.Quantity
+			return
+		}
+	}
+}
+
+// hasBackorderedItems reports whether any item of the order is still waiting for stock
+func hasBackorderedItems(order *models.Order) bool {
+	for _, item := range order.OrderItems {
+		if item.BackorderedQuantity > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *OrderService) notifyBackorderAllocated(ctx context.Context, order *models.Order) {
+	event := &websocket.Event{
+		Type: websocket.EventBackorderAllocated,
+		Payload: websocket.OrderEventPayload{
+			OrderID:     order.ID,
+			Status:      string(order.Status),
+			TotalAmount: order.TotalAmount,
+			Currency:    string(order.Currency),
+		},
+	}
+
+	if err := s.notificationSvc.CreateNotification(
+		ctx,
+		order.UserID,
+		models.NotificationTypeOrder,
+		"Backordered Items Available",
+		fmt.Sprintf("All items of your order #%d are now in stock and it is being processed.", order.ID),
+		event,
+	); err != nil {
+		logger.Error(ctx, "Failed to create backorder notification",
+			zap.Error(err),
+			zap.Uint("order_id", order.ID),
+			zap.Uint("user_id", order.UserID))
+	}
+}
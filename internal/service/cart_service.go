@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"gorm.io/gorm"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/api/dto"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/repository"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/errors"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
+)
+
+type CartService interface {
+	// GetCart returns the user's cart priced in the requested currency
+	GetCart(ctx context.Context, userID uint, currency string) (*dto.CartResponse, error)
+	// SetItem sets the quantity of a product in the user's cart, removing it at zero
+	SetItem(ctx context.Context, userID uint, req *dto.SetCartItemRequest) error
+	// AddItems adds quantities of products to the user's cart
+	AddItems(ctx context.Context, userID uint, items []OrderItemInput) error
+	// TakeItems locks the user's cart in the transaction and empties it, returning the
+	// products and quantities it held
+	TakeItems(ctx context.Context, tx *gorm.DB, userID uint) ([]OrderItemInput, error)
+	ClearCart(ctx context.Context, userID uint) error
+}
+
+type cartService struct {
+	db          *gorm.DB
+	cartRepo    repository.CartRepository
+	productRepo repository.ProductRepository
+	currencySvc CurrencyService
+}
+
+func NewCartService(db *gorm.DB, cartRepo repository.CartRepository, productRepo repository.ProductRepository, currencySvc CurrencyService) CartService {
+	return &cartService{
+		db:          db,
+		cartRepo:    cartRepo,
+		productRepo: productRepo,
+		currencySvc: currencySvc,
+	}
+}
+
+func (s *cartService) GetCart(ctx context.Context, userID uint, currency string) (*dto.CartResponse, error) {
+	code, err := s.currencySvc.ResolveCurrency(ctx, userID, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.CartResponse{
+		Items:    []dto.CartItemResponse{},
+		Currency: string(code),
+		Subtotal: money.Zero(code),
+	}
+	cart, err := s.cartRepo.GetByUserID(ctx, s.db, userID)
+	if err == gorm.ErrRecordNotFound {
+		return resp, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cart: %w", err)
+	}
+	if len(cart.Items) == 0 {
+		return resp, nil
+	}
+
+	products := make([]*models.Product, len(cart.Items))
+	for i := range cart.Items {
+		products[i] = &cart.Items[i].Product
+	}
+	quotes, _, err := s.currencySvc.PriceProducts(ctx, s.db, code, products)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range cart.Items {
+		price := quotes[item.ProductID].Price
+		line, err := price.Mul(int64(item.Quantity))
+		if err != nil {
+			return nil, err
+		}
+		if resp.Subtotal, err = resp.Subtotal.Add(line); err != nil {
+			return nil, err
+		}
+		resp.Items = append(resp.Items, dto.CartItemResponse{
+			ProductID:    item.ProductID,
+			Name:         item.Product.Name,
+			SKU:          item.Product.SKU,
+			Quantity:     item.Quantity,
+			Price:        price,
+			LineTotal:    line,
+			Discontinued: item.Product.Discontinued,
+		})
+	}
+	return resp, nil
+}
+
+func (s *cartService) SetItem(ctx context.Context, userID uint, req *dto.SetCartItemRequest) error {
+	if req.Quantity > 0 {
+		product, err := s.productRepo.FindByID(ctx, req.ProductID)
+		if err != nil {
+			return err
+		}
+		if product == nil {
+			return errors.NewBusinessError("Product not found", errors.ErrCodeResourceNotFound, http.StatusNotFound)
+		}
+		if product.Discontinued {
+			return errors.NewValidationError(
+				fmt.Sprintf("Product with ID %d is discontinued", req.ProductID),
+				map[string]string{"product_id": "product is discontinued"},
+				http.StatusBadRequest,
+			)
+		}
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		cart, err := s.cartRepo.GetOrCreateForUpdate(ctx, tx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to get cart: %w", err)
+		}
+		if req.Quantity == 0 {
+			return s.cartRepo.DeleteItem(ctx, tx, cart.ID, req.ProductID)
+		}
+
+		item := models.CartItem{CartID: cart.ID, ProductID: req.ProductID}
+		for _, existing := range cart.Items {
+			if existing.ProductID == req.ProductID {
+				item = existing
+			}
+		}
+		item.Quantity = req.Quantity
+		return s.cartRepo.SaveItem(ctx, tx, &item)
+	})
+}
+
+func (s *cartService) AddItems(ctx context.Context, userID uint, items []OrderItemInput) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		cart, err := s.cartRepo.GetOrCreateForUpdate(ctx, tx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to get cart: %w", err)
+		}
+
+		byProduct := make(map[uint]*models.CartItem, len(cart.Items))
+		for i := range cart.Items {
+			byProduct[cart.Items[i].ProductID] = &cart.Items[i]
+		}
+		for _, input := range items {
+			item, ok := byProduct[input.ProductID]
+			if !ok {
+				item = &models.CartItem{CartID: cart.ID, ProductID: input.ProductID}
+				byProduct[input.ProductID] = item
+			}
+			item.Quantity += input.Quantity
+			if err := s.cartRepo.SaveItem(ctx, tx, item); err != nil {
+				return fmt.Errorf("failed to save cart item: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+func (s *cartService) TakeItems(ctx context.Context, tx *gorm.DB, userID uint) ([]OrderItemInput, error) {
+	cart, err := s.cartRepo.GetOrCreateForUpdate(ctx, tx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cart: %w", err)
+	}
+
+	items := make([]OrderItemInput, len(cart.Items))
+	for i, item := range cart.Items {
+		items[i] = OrderItemInput{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+		}
+	}
+	if err := s.cartRepo.ClearItems(ctx, tx, cart.ID); err != nil {
+		return nil, fmt.Errorf("failed to clear cart: %w", err)
+	}
+	return items, nil
+}
+
+func (s *cartService) ClearCart(ctx context.Context, userID uint) error {
+	cart, err := s.cartRepo.GetByUserID(ctx, s.db, userID)
+	if err == gorm.ErrRecordNotFound {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get cart: %w", err)
+	}
+	return s.cartRepo.ClearItems(ctx, s.db, cart.ID)
+}
@@ -0,0 +1,371 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/api/dto"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/repository"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/errors"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
+)
+
+// CurrencyConfig lists the currencies customers can pay in. Product prices are kept in
+// money.DefaultCurrency, the base currency.
+type CurrencyConfig struct {
+	Supported []money.Currency // Currencies customers may choose; the base currency is always allowed
+	Reporting money.Currency   // Currency sales reports are normalised to
+}
+
+// PriceQuote is a product's unit price in a chosen currency
+type PriceQuote struct {
+	Price          money.Money // In the chosen currency
+	BasePrice      money.Money // In the base currency
+	CompareAtPrice money.Money // Price before the sale in the chosen currency; zero when not on sale
+	ScheduleID     *uint       // Price schedule that sets the price, if any
+}
+
+type CurrencyService interface {
+	ReportingCurrency() money.Currency
+	// ValidateCurrency parses a currency code and checks that customers may use it
+	ValidateCurrency(code string) (money.Currency, error)
+	// ResolveCurrency picks the currency of a request: the requested code if given,
+	// otherwise the user's preferred currency, otherwise the base currency
+	ResolveCurrency(ctx context.Context, userID uint, requested string) (money.Currency, error)
+	// Rate returns the base to currency rate in effect at the given time, or a zero rate
+	// if none has been recorded
+	Rate(ctx context.Context, tx *gorm.DB, currency money.Currency, at time.Time) (money.Rate, error)
+	// PriceProducts prices products in a currency at the current time, applying price
+	// schedules and preferring list prices over converting regular base prices. It returns
+	// the quotes by product ID and the rate in effect.
+	PriceProducts(ctx context.Context, tx *gorm.DB, currency money.Currency, products []*models.Product) (map[uint]PriceQuote, money.Rate, error)
+
+	CreateExchangeRate(ctx context.Context, req *dto.CreateExchangeRateRequest) (*dto.ExchangeRateResponse, error)
+	ListExchangeRates(ctx context.Context, base, quote string) ([]dto.ExchangeRateResponse, error)
+	SetProductPrice(ctx context.Context, productID uint, currency string, req *dto.SetProductPriceRequest) (*dto.ProductPriceResponse, error)
+	DeleteProductPrice(ctx context.Context, productID uint, currency string) error
+	ListProductPrices(ctx context.Context, productID uint) ([]dto.ProductPriceResponse, error)
+}
+
+type currencyService struct {
+	db           *gorm.DB
+	config       CurrencyConfig
+	supported    map[money.Currency]bool
+	currencyRepo repository.CurrencyRepository
+	productRepo  repository.ProductRepository
+	userRepo     repository.UserRepository
+	scheduleSvc  PriceScheduleService
+}
+
+func NewCurrencyService(
+	db *gorm.DB,
+	config CurrencyConfig,
+	currencyRepo repository.CurrencyRepository,
+	productRepo repository.ProductRepository,
+	userRepo repository.UserRepository,
+	scheduleSvc PriceScheduleService,
+) CurrencyService {
+	supported := map[money.Currency]bool{money.DefaultCurrency: true}
+	for _, currency := range config.Supported {
+		if currency.Valid() {
+			supported[currency] = true
+		}
+	}
+	if config.Reporting == "" {
+		config.Reporting = money.DefaultCurrency
+	}
+	return &currencyService{
+		db:           db,
+		config:       config,
+		supported:    supported,
+		currencyRepo: currencyRepo,
+		productRepo:  productRepo,
+		userRepo:     userRepo,
+		scheduleSvc:  scheduleSvc,
+	}
+}
+
+func (s *currencyService) ReportingCurrency() money.Currency {
+	return s.config.Reporting
+}
+
+func (s *currencyService) ValidateCurrency(code string) (money.Currency, error) {
+	currency, err := money.ParseCurrency(code)
+	if err != nil || !s.supported[currency] {
+		return "", errors.NewValidationError(
+			fmt.Sprintf("Currency %q is not supported", code),
+			map[string]string{"currency": "unsupported currency"},
+			http.StatusBadRequest,
+		)
+	}
+	return currency, nil
+}
+
+func (s *currencyService) ResolveCurrency(ctx context.Context, userID uint, requested string) (money.Currency, error) {
+	if requested != "" {
+		return s.ValidateCurrency(requested)
+	}
+
+	if userID != 0 {
+		user, err := s.userRepo.FindByID(ctx, userID)
+		if err != nil {
+			return "", err
+		}
+		// A preference that is no longer supported falls back to the base currency
+		if user != nil && s.supported[user.PreferredCurrency] {
+			return user.PreferredCurrency, nil
+		}
+	}
+	return money.DefaultCurrency, nil
+}
+
+func (s *currencyService) Rate(ctx context.Context, tx *gorm.DB, currency money.Currency, at time.Time) (money.Rate, error) {
+	if currency == money.DefaultCurrency {
+		return money.IdentityRate(), nil
+	}
+
+	rate, err := s.currencyRepo.FindEffectiveRate(ctx, tx, money.DefaultCurrency, currency, at)
+	if err == nil {
+		return rate.Rate, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return money.Rate{}, err
+	}
+
+	// Fall back to the inverse of a rate recorded the other way round
+	rate, err = s.currencyRepo.FindEffectiveRate(ctx, tx, currency, money.DefaultCurrency, at)
+	if err == gorm.ErrRecordNotFound {
+		return money.Rate{}, nil
+	}
+	if err != nil {
+		return money.Rate{}, err
+	}
+	return rate.Rate.Inverse()
+}
+
+func (s *currencyService) PriceProducts(ctx context.Context, tx *gorm.DB, currency money.Currency, products []*models.Product) (map[uint]PriceQuote, money.Rate, error) {
+	now := time.Now()
+	quotes := make(map[uint]PriceQuote, len(products))
+	rate, err := s.Rate(ctx, tx, currency, now)
+	if err != nil {
+		return nil, money.Rate{}, err
+	}
+
+	// Apply running sales and planned price changes first
+	effective, err := s.scheduleSvc.ResolvePrices(ctx, tx, products, now)
+	if err != nil {
+		return nil, money.Rate{}, err
+	}
+
+	if currency == money.DefaultCurrency {
+		for _, product := range products {
+			price := effective[product.ID]
+			quotes[product.ID] = PriceQuote{
+				Price:          price.Price,
+				BasePrice:      price.Price,
+				CompareAtPrice: price.CompareAtPrice,
+				ScheduleID:     price.ScheduleID,
+			}
+		}
+		return quotes, rate, nil
+	}
+
+	ids := make([]uint, len(products))
+	for i, product := range products {
+		ids[i] = product.ID
+	}
+	prices, err := s.currencyRepo.ListPricesByCurrency(ctx, tx, ids, currency)
+	if err != nil {
+		return nil, money.Rate{}, err
+	}
+	listPrices := make(map[uint]money.Money, len(prices))
+	for _, price := range prices {
+		listPrices[price.ProductID] = price.Price
+	}
+
+	convert := func(product *models.Product, amount money.Money) (money.Money, error) {
+		if rate.IsZero() {
+			return money.Money{}, errors.NewValidationError(
+				fmt.Sprintf("No exchange rate or list price for product %d in %s", product.ID, currency),
+				map[string]string{"currency": "no exchange rate available"},
+				http.StatusUnprocessableEntity,
+			)
+		}
+		return amount.Convert(rate, currency, money.RoundHalfEven)
+	}
+
+	for _, product := range products {
+		price := effective[product.ID]
+		quote := PriceQuote{BasePrice: price.Price, ScheduleID: price.ScheduleID}
+
+		// List prices are regular prices, so they apply unless a schedule sets the price
+		listPrice, listed := listPrices[product.ID]
+		if price.ScheduleID == nil && listed {
+			quote.Price = listPrice
+		} else if quote.Price, err = convert(product, price.Price); err != nil {
+			return nil, money.Rate{}, err
+		}
+
+		// A sale compared against the regular price shows the list price when there is one
+		if !price.CompareAtPrice.IsZero() {
+			compareAt := listPrice
+			if !listed || !price.CompareAtPrice.Equal(product.Price) {
+				if compareAt, err = convert(product, price.CompareAtPrice); err != nil {
+					return nil, money.Rate{}, err
+				}
+			}
+			if cmp, err := compareAt.Cmp(quote.Price); err == nil && cmp > 0 {
+				quote.CompareAtPrice = compareAt
+			}
+		}
+		quotes[product.ID] = quote
+	}
+	return quotes, rate, nil
+}
+
+func (s *currencyService) CreateExchangeRate(ctx context.Context, req *dto.CreateExchangeRateRequest) (*dto.ExchangeRateResponse, error) {
+	base := money.DefaultCurrency
+	if req.BaseCurrency != "" {
+		var err error
+		if base, err = money.ParseCurrency(req.BaseCurrency); err != nil {
+			return nil, errors.NewValidationError("Invalid exchange rate", map[string]string{"base_currency": "invalid currency code"}, http.StatusBadRequest)
+		}
+	}
+	quote, err := money.ParseCurrency(req.QuoteCurrency)
+	if err != nil {
+		return nil, errors.NewValidationError("Invalid exchange rate", map[string]string{"quote_currency": "invalid currency code"}, http.StatusBadRequest)
+	}
+	if base == quote {
+		return nil, errors.NewValidationError("Invalid exchange rate", map[string]string{"quote_currency": "must differ from base_currency"}, http.StatusBadRequest)
+	}
+	if req.Rate.IsZero() {
+		return nil, errors.NewValidationError("Invalid exchange rate", map[string]string{"rate": "must be greater than 0"}, http.StatusBadRequest)
+	}
+
+	rate := &models.ExchangeRate{
+		BaseCurrency:  base,
+		QuoteCurrency: quote,
+		Rate:          req.Rate,
+		EffectiveAt:   time.Now(),
+	}
+	if req.EffectiveAt != nil {
+		rate.EffectiveAt = *req.EffectiveAt
+	}
+
+	if err := s.currencyRepo.CreateRate(ctx, s.db, rate); err != nil {
+		return nil, err
+	}
+
+	resp := dto.ExchangeRateToResponse(rate)
+	return &resp, nil
+}
+
+func (s *currencyService) ListExchangeRates(ctx context.Context, base, quote string) ([]dto.ExchangeRateResponse, error) {
+	var baseCurrency, quoteCurrency money.Currency
+	var err error
+	if base != "" {
+		if baseCurrency, err = money.ParseCurrency(base); err != nil {
+			return nil, errors.NewValidationError("Invalid filter", map[string]string{"base": "invalid currency code"}, http.StatusBadRequest)
+		}
+	}
+	if quote != "" {
+		if quoteCurrency, err = money.ParseCurrency(quote); err != nil {
+			return nil, errors.NewValidationError("Invalid filter", map[string]string{"quote": "invalid currency code"}, http.StatusBadRequest)
+		}
+	}
+
+	rates, err := s.currencyRepo.ListRates(ctx, s.db, baseCurrency, quoteCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]dto.ExchangeRateResponse, len(rates))
+	for i := range rates {
+		resp[i] = dto.ExchangeRateToResponse(&rates[i])
+	}
+	return resp, nil
+}
+
+func (s *currencyService) SetProductPrice(ctx context.Context, productID uint, currency string, req *dto.SetProductPriceRequest) (*dto.ProductPriceResponse, error) {
+	code, err := s.ValidateCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+	if code == money.DefaultCurrency {
+		return nil, errors.NewValidationError(
+			"Base currency prices are set on the product",
+			map[string]string{"currency": "must differ from the base currency"},
+			http.StatusBadRequest,
+		)
+	}
+	if err := s.ensureProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	// The request is decoded in the base currency's precision; re-tag it with the list currency
+	amount, err := req.Price.As(code)
+	if err != nil {
+		return nil, errors.NewValidationError(
+			"Invalid price",
+			map[string]string{"price": fmt.Sprintf("%s allows %d decimal places", code, code.Exponent())},
+			http.StatusBadRequest,
+		)
+	}
+
+	price := &models.ProductPrice{ProductID: productID, Currency: code, Price: amount}
+	if err := s.currencyRepo.UpsertPrice(ctx, s.db, price); err != nil {
+		return nil, err
+	}
+
+	resp := dto.ProductPriceToResponse(price)
+	return &resp, nil
+}
+
+func (s *currencyService) DeleteProductPrice(ctx context.Context, productID uint, currency string) error {
+	code, err := money.ParseCurrency(currency)
+	if err != nil {
+		return errors.NewValidationError("Invalid currency", map[string]string{"currency": "invalid currency code"}, http.StatusBadRequest)
+	}
+
+	deleted, err := s.currencyRepo.DeletePrice(ctx, s.db, productID, code)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.NewBusinessError("Product price not found", errors.ErrCodeResourceNotFound, http.StatusNotFound)
+	}
+	return nil
+}
+
+func (s *currencyService) ListProductPrices(ctx context.Context, productID uint) ([]dto.ProductPriceResponse, error) {
+	if err := s.ensureProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	prices, err := s.currencyRepo.ListPrices(ctx, s.db, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]dto.ProductPriceResponse, len(prices))
+	for i := range prices {
+		resp[i] = dto.ProductPriceToResponse(&prices[i])
+	}
+	return resp, nil
+}
+
+func (s *currencyService) ensureProduct(ctx context.Context, productID uint) error {
+	product, err := s.productRepo.FindByID(ctx, productID)
+	if err != nil {
+		return err
+	}
+	if product == nil {
+		return errors.NewBusinessError("Product not found", errors.ErrCodeResourceNotFound, http.StatusNotFound)
+	}
+	return nil
+}
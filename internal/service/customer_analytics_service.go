@@ -0,0 +1,371 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/api/dto"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/repository"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/errors"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/logger"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/redis"
+)
+
+// customerAnalyticsLockTTL bounds how long a crashed instance keeps others from
+// recomputing the customer analytics
+const customerAnalyticsLockTTL = 30 * time.Minute
+
+// defaultCohortMonths is how many of the latest cohorts are shown unless asked otherwise
+const defaultCohortMonths = 12
+
+type CustomerAnalyticsService interface {
+	// GetCustomerAnalytics returns the customer figures, cohorts and segments of the latest run
+	GetCustomerAnalytics(ctx context.Context, query *dto.CustomerAnalyticsQuery) (*dto.CustomerAnalyticsResponse, error)
+	// ListCustomerRFM returns a page of customers' RFM scores, the most valuable first
+	ListCustomerRFM(ctx context.Context, query *dto.CustomerRFMQuery) (*dto.PaginatedCustomerRFMResponse, error)
+
+	// RefreshCustomerAnalytics recomputes the customer analytics from all orders and
+	// replaces the stored ones. It returns false if another instance is already at it.
+	RefreshCustomerAnalytics(ctx context.Context, now time.Time) (bool, error)
+}
+
+type customerAnalyticsService struct {
+	db            *gorm.DB
+	analyticsRepo repository.AnalyticsRepository
+	currencySvc   CurrencyService
+	redisService  redis.Service
+}
+
+func NewCustomerAnalyticsService(
+	db *gorm.DB,
+	analyticsRepo repository.AnalyticsRepository,
+	currencySvc CurrencyService,
+	redisService redis.Service,
+) CustomerAnalyticsService {
+	return &customerAnalyticsService{
+		db:            db,
+		analyticsRepo: analyticsRepo,
+		currencySvc:   currencySvc,
+		redisService:  redisService,
+	}
+}
+
+func (s *customerAnalyticsService) GetCustomerAnalytics(ctx context.Context, query *dto.CustomerAnalyticsQuery) (*dto.CustomerAnalyticsResponse, error) {
+	summary, err := s.analyticsRepo.GetCustomerSummary(ctx, s.db)
+	if err != nil {
+		return nil, analyticsLookupError(err)
+	}
+
+	months := query.Months
+	if months <= 0 {
+		months = defaultCohortMonths
+	}
+	lastMonth := analyticsMonth(summary.ComputedAt)
+	cohorts, err := s.analyticsRepo.ListCustomerCohorts(ctx, s.db, lastMonth.AddDate(0, 1-months, 0))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list customer cohorts: %w", err)
+	}
+	segments, err := s.analyticsRepo.ListCustomerSegments(ctx, s.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list customer segments: %w", err)
+	}
+
+	resp := &dto.CustomerAnalyticsResponse{
+		ComputedAt:               summary.ComputedAt,
+		Currency:                 string(summary.Currency),
+		Customers:                summary.Customers,
+		RepeatCustomers:          summary.RepeatCustomers,
+		RepeatPurchaseRate:       summary.RepeatPurchaseRate,
+		Orders:                   summary.Orders,
+		Revenue:                  summary.Revenue,
+		AverageLifetimeValue:     summary.AverageLifetimeValue,
+		AverageOrdersPerCustomer: summary.AverageOrdersPerCustomer,
+		Cohorts:                  []dto.CustomerCohortResponse{},
+		Segments:                 make([]dto.CustomerSegmentResponse, len(segments)),
+	}
+	for i := range segments {
+		resp.Segments[i] = dto.CustomerSegmentToResponse(&segments[i])
+	}
+
+	// Cohort rows are sorted by month and offset; months a cohort didn't order in have no row
+	for start := 0; start < len(cohorts); {
+		end := start
+		for end < len(cohorts) && cohorts[end].CohortMonth.Equal(cohorts[start].CohortMonth) {
+			end++
+		}
+		cohort, err := cohortResponse(cohorts[start:end], lastMonth, summary.Currency)
+		if err != nil {
+			return nil, err
+		}
+		resp.Cohorts = append(resp.Cohorts, cohort)
+		start = end
+	}
+	return resp, nil
+}
+
+func (s *customerAnalyticsService) ListCustomerRFM(ctx context.Context, query *dto.CustomerRFMQuery) (*dto.PaginatedCustomerRFMResponse, error) {
+	summary, err := s.analyticsRepo.GetCustomerSummary(ctx, s.db)
+	if err != nil {
+		return nil, analyticsLookupError(err)
+	}
+
+	scores, total, err := s.analyticsRepo.ListCustomerRFMScores(ctx, s.db, models.CustomerSegment(query.Segment), (query.Page-1)*query.PerPage, query.PerPage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list customer scores: %w", err)
+	}
+
+	resp := &dto.PaginatedCustomerRFMResponse{
+		Customers:  make([]dto.CustomerRFMResponse, len(scores)),
+		Currency:   string(summary.Currency),
+		ComputedAt: summary.ComputedAt,
+		Total:      total,
+		Page:       query.Page,
+		PerPage:    query.PerPage,
+		TotalPages: (int(total) + query.PerPage - 1) / query.PerPage,
+	}
+	for i := range scores {
+		resp.Customers[i] = dto.CustomerRFMToResponse(&scores[i])
+	}
+	return resp, nil
+}
+
+func (s *customerAnalyticsService) RefreshCustomerAnalytics(ctx context.Context, now time.Time) (bool, error) {
+	release, acquired, err := s.redisService.Lock(ctx, "lock:analytics:customers", customerAnalyticsLockTTL)
+	switch {
+	case err != nil:
+		logger.Error(ctx, "Computing customer analytics without a lock", zap.Error(err))
+	case !acquired:
+		return false, nil
+	default:
+		defer release()
+	}
+
+	stats, err := s.analyticsRepo.GetCustomerOrderStats(ctx, s.db)
+	if err != nil {
+		return false, fmt.Errorf("failed to get customer order stats: %w", err)
+	}
+	activity, err := s.analyticsRepo.GetCohortActivity(ctx, s.db)
+	if err != nil {
+		return false, fmt.Errorf("failed to get cohort activity: %w", err)
+	}
+
+	// Revenue to date is presented in the reporting currency at the current rate
+	currency := s.currencySvc.ReportingCurrency()
+	rate, err := s.currencySvc.Rate(ctx, s.db, currency, now)
+	if err != nil {
+		return false, err
+	}
+	if rate.IsZero() {
+		logger.Error(ctx, "No exchange rate for reporting currency, computing analytics in base currency",
+			zap.String("currency", string(currency)))
+		currency, rate = money.DefaultCurrency, money.IdentityRate()
+	}
+	convert := func(amount money.Money) (money.Money, error) {
+		return amount.Convert(rate, currency, money.RoundHalfEven)
+	}
+
+	summary, scores, segments, err := computeCustomerAnalytics(stats, now, currency, convert)
+	if err != nil {
+		return false, err
+	}
+	cohorts, err := computeCustomerCohorts(activity, currency, convert)
+	if err != nil {
+		return false, err
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return s.analyticsRepo.ReplaceCustomerAnalytics(ctx, tx, summary, cohorts, scores, segments)
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to store customer analytics: %w", err)
+	}
+	return true, nil
+}
+
+// computeCustomerAnalytics works out the overall figures, the RFM scores of every customer
+// and the segment summaries from the customers' order stats
+func computeCustomerAnalytics(
+	stats []repository.CustomerOrderStats,
+	now time.Time,
+	currency money.Currency,
+	convert func(money.Money) (money.Money, error),
+) (*models.CustomerAnalyticsSummary, []models.CustomerRFMScore, []models.CustomerSegmentSummary, error) {
+	summary := &models.CustomerAnalyticsSummary{
+		ComputedAt: now,
+		Currency:   currency,
+		Customers:  len(stats),
+	}
+	baseRevenue := money.Zero(money.DefaultCurrency)
+	recency := make([]float64, len(stats))
+	monetary := make([]float64, len(stats))
+	for i, customer := range stats {
+		summary.Orders += customer.Orders
+		if customer.Orders > 1 {
+			summary.RepeatCustomers++
+		}
+		var err error
+		if baseRevenue, err = baseRevenue.Add(customer.Revenue); err != nil {
+			return nil, nil, nil, err
+		}
+		recency[i] = -float64(daysSince(customer.LastOrderAt, now)) // Recent orders score higher
+		monetary[i] = float64(customer.Revenue.Amount())
+	}
+
+	var err error
+	if summary.Revenue, err = convert(baseRevenue); err != nil {
+		return nil, nil, nil, err
+	}
+	if summary.AverageLifetimeValue, err = averageOrderValue(summary.Revenue, summary.Customers); err != nil {
+		return nil, nil, nil, err
+	}
+	summary.RepeatPurchaseRate = percentage(summary.RepeatCustomers, summary.Customers)
+	if summary.Customers > 0 {
+		summary.AverageOrdersPerCustomer = roundPercent(float64(summary.Orders) / float64(summary.Customers))
+	}
+
+	recencyScores := quintileScores(recency)
+	monetaryScores := quintileScores(monetary)
+	scores := make([]models.CustomerRFMScore, len(stats))
+	bySegment := make(map[models.CustomerSegment]*models.CustomerSegmentSummary)
+	var segments []*models.CustomerSegmentSummary
+	for i, customer := range stats {
+		score := models.CustomerRFMScore{
+			UserID:         customer.UserID,
+			RecencyDays:    daysSince(customer.LastOrderAt, now),
+			Orders:         customer.Orders,
+			Currency:       currency,
+			RecencyScore:   recencyScores[i],
+			FrequencyScore: frequencyScore(customer.Orders),
+			MonetaryScore:  monetaryScores[i],
+			FirstOrderAt:   customer.FirstOrderAt,
+			LastOrderAt:    customer.LastOrderAt,
+		}
+		score.Segment = segmentFor(score.RecencyScore, score.FrequencyScore)
+		if score.Monetary, err = convert(customer.Revenue); err != nil {
+			return nil, nil, nil, err
+		}
+		scores[i] = score
+
+		segment, ok := bySegment[score.Segment]
+		if !ok {
+			segment = &models.CustomerSegmentSummary{Segment: score.Segment, Currency: currency, Revenue: money.Zero(currency)}
+			bySegment[score.Segment] = segment
+			segments = append(segments, segment)
+		}
+		segment.Customers++
+		segment.AverageRecencyDays += float64(score.RecencyDays) // Summed until all are in
+		segment.AverageOrders += float64(score.Orders)
+		if segment.Revenue, err = segment.Revenue.Add(score.Monetary); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+
+	summaries := make([]models.CustomerSegmentSummary, len(segments))
+	for i, segment := range segments {
+		segment.AverageRecencyDays = roundPercent(segment.AverageRecencyDays / float64(segment.Customers))
+		segment.AverageOrders = roundPercent(segment.AverageOrders / float64(segment.Customers))
+		if segment.AverageMonetary, err = averageOrderValue(segment.Revenue, segment.Customers); err != nil {
+			return nil, nil, nil, err
+		}
+		summaries[i] = *segment
+	}
+	return summary, scores, summaries, nil
+}
+
+// computeCustomerCohorts turns the monthly activity of the cohorts into cohort rows, the
+// cohort's size being the customers active in its first month
+func computeCustomerCohorts(
+	activity []repository.CohortActivity,
+	currency money.Currency,
+	convert func(money.Money) (money.Money, error),
+) ([]models.CustomerCohort, error) {
+	sizes := make(map[time.Time]int)
+	for _, month := range activity {
+		if month.Month.Equal(month.CohortMonth) {
+			sizes[month.CohortMonth] = month.Customers
+		}
+	}
+
+	cohorts := make([]models.CustomerCohort, len(activity))
+	for i, month := range activity {
+		revenue, err := convert(month.Revenue)
+		if err != nil {
+			return nil, err
+		}
+		cohorts[i] = models.CustomerCohort{
+			CohortMonth:     month.CohortMonth,
+			MonthOffset:     monthsBetween(month.CohortMonth, month.Month),
+			CohortSize:      sizes[month.CohortMonth],
+			ActiveCustomers: month.Customers,
+			Orders:          month.Orders,
+			Currency:        currency,
+			Revenue:         revenue,
+		}
+	}
+	return cohorts, nil
+}
+
+// cohortResponse builds the retention curve of a cohort from its rows, up to lastMonth
+func cohortResponse(rows []models.CustomerCohort, lastMonth time.Time, currency money.Currency) (dto.CustomerCohortResponse, error) {
+	first := rows[0]
+	cohort := dto.CustomerCohortResponse{
+		Month:     first.CohortMonth,
+		Customers: first.CohortSize,
+		Retention: make([]dto.CohortRetention, monthsBetween(first.CohortMonth, lastMonth)+1),
+	}
+	for offset := range cohort.Retention {
+		cohort.Retention[offset] = dto.CohortRetention{MonthOffset: offset, Revenue: money.Zero(currency)}
+	}
+
+	total := money.Zero(currency)
+	for _, row := range rows {
+		if row.MonthOffset >= len(cohort.Retention) {
+			continue
+		}
+		cohort.Retention[row.MonthOffset] = dto.CohortRetention{
+			MonthOffset:     row.MonthOffset,
+			ActiveCustomers: row.ActiveCustomers,
+			RetentionRate:   percentage(row.ActiveCustomers, row.CohortSize),
+			Orders:          row.Orders,
+			Revenue:         row.Revenue,
+		}
+		var err error
+		if total, err = total.Add(row.Revenue); err != nil {
+			return dto.CustomerCohortResponse{}, err
+		}
+	}
+
+	var err error
+	cohort.LifetimeValue, err = averageOrderValue(total, cohort.Customers)
+	return cohort, err
+}
+
+// analyticsMonth returns the first day of the UTC month of t
+func analyticsMonth(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// daysSince returns how many whole days before now t is
+func daysSince(t, now time.Time) int {
+	return int(now.Sub(t).Hours() / 24)
+}
+
+// monthsBetween returns how many calendar months the second month is after the first
+func monthsBetween(from, to time.Time) int {
+	return (to.Year()-from.Year())*12 + int(to.Month()) - int(from.Month())
+}
+
+func analyticsLookupError(err error) error {
+	if err == gorm.ErrRecordNotFound {
+		return errors.NewBusinessError("Analytics have not been computed yet", errors.ErrCodeResourceNotFound, http.StatusNotFound)
+	}
+	return fmt.Errorf("failed to get analytics: %w", err)
+}
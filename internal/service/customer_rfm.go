@@ -0,0 +1,70 @@
+package service
+
+import (
+	"sort"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+)
+
+// Customers are scored from 1 to 5 on each RFM dimension:
+//
+//   - Recency: quintile of the days since their last order, 5 being the most recent.
+//   - Frequency: their number of orders, 1, 2, 3, 4 to 5, or 6 and more. Most customers
+//     order once, so quintiles would split equal counts arbitrarily.
+//   - Monetary: quintile of what they spent to date, 5 being the most.
+//
+// Customers with equal values get the same score. Segments follow from the recency and
+// frequency scores, as in segmentFor.
+
+// quintileScores scores values from 1 to 5 by quintile, higher values scoring higher
+func quintileScores(values []float64) []int {
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return values[order[a]] < values[order[b]] })
+
+	scores := make([]int, len(values))
+	first := 0 // Rank of the first of the values equal to the current one
+	for rank, i := range order {
+		if rank > 0 && values[i] != values[order[rank-1]] {
+			first = rank
+		}
+		scores[i] = 1 + first*5/len(values)
+	}
+	return scores
+}
+
+// frequencyScore scores a number of orders from 1 to 5
+func frequencyScore(orders int) int {
+	switch {
+	case orders >= 6:
+		return 5
+	case orders >= 4:
+		return 4
+	case orders <= 1:
+		return 1
+	default:
+		return orders
+	}
+}
+
+// segmentFor puts a customer in an RFM segment by their recency and frequency scores
+func segmentFor(recency, frequency int) models.CustomerSegment {
+	switch {
+	case recency >= 4 && frequency >= 4:
+		return models.CustomerSegmentChampions
+	case recency >= 3 && frequency >= 3:
+		return models.CustomerSegmentLoyal
+	case recency >= 4 && frequency == 1:
+		return models.CustomerSegmentNew
+	case recency >= 3:
+		return models.CustomerSegmentPromising
+	case frequency >= 3:
+		return models.CustomerSegmentAtRisk
+	case recency == 2:
+		return models.CustomerSegmentHibernating
+	default:
+		return models.CustomerSegmentLost
+	}
+}
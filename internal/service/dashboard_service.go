@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/repository"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/logger"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/websocket"
+)
+
+// dashboardRecentOrders is how many of the latest orders the dashboard snapshot lists
+const dashboardRecentOrders = 10
+
+// DashboardService feeds the admin dashboard over WebSocket. Updates are only built while
+// an admin is connected.
+type DashboardService interface {
+	// Snapshot returns the dashboard's current state
+	Snapshot(ctx context.Context) (*websocket.DashboardSnapshotPayload, error)
+
+	// OrderCreated pushes a newly placed order with today's totals. It returns at once;
+	// the update is built and sent in the background.
+	OrderCreated(ctx context.Context, order *models.Order)
+	// OrderStatusChanged pushes an order's new status with today's totals. It returns at
+	// once; the update is built and sent in the background.
+	OrderStatusChanged(ctx context.Context, order *models.Order, previous models.OrderStatus)
+	// LowStock pushes a newly opened low stock alert
+	LowStock(ctx context.Context, alert websocket.LowStockEventPayload)
+}
+
+type dashboardService struct {
+	db          *gorm.DB
+	orderRepo   repository.OrderRepository
+	alertRepo   repository.StockAlertRepository
+	currencySvc CurrencyService
+	wsManager   *websocket.Manager
+}
+
+func NewDashboardService(
+	db *gorm.DB,
+	orderRepo repository.OrderRepository,
+	alertRepo repository.StockAlertRepository,
+	currencySvc CurrencyService,
+	wsManager *websocket.Manager,
+) DashboardService {
+	return &dashboardService{
+		db:          db,
+		orderRepo:   orderRepo,
+		alertRepo:   alertRepo,
+		currencySvc: currencySvc,
+		wsManager:   wsManager,
+	}
+}
+
+func (s *dashboardService) Snapshot(ctx context.Context) (*websocket.DashboardSnapshotPayload, error) {
+	today, err := s.todayTotals(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	orders, err := s.orderRepo.ListOrders(ctx, s.db, repository.OrderFilter{}, repository.OrderSort{
+		Field:      repository.OrderSortCreatedAt,
+		Descending: true,
+	}, 0, dashboardRecentOrders)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recent orders: %w", err)
+	}
+
+	alerts, err := s.alertRepo.ListOpen(ctx, s.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list low stock alerts: %w", err)
+	}
+
+	snapshot := &websocket.DashboardSnapshotPayload{
+		Today:          *today,
+		RecentOrders:   make([]websocket.DashboardOrder, len(orders)),
+		LowStockAlerts: make([]websocket.LowStockEventPayload, len(alerts)),
+	}
+	for i := range orders {
+		snapshot.RecentOrders[i] = dashboardOrder(&orders[i])
+	}
+	for i, alert := range alerts {
+		snapshot.LowStockAlerts[i] = websocket.LowStockEventPayload{
+			AlertID:                  alert.ID,
+			ProductID:                alert.ProductID,
+			WarehouseID:              alert.WarehouseID,
+			StockLevel:               alert.StockLevel,
+			MinimumStock:             alert.MinimumStock,
+			SuggestedReorderQuantity: alert.SuggestedReorderQuantity,
+		}
+		if alert.Product != nil {
+			snapshot.LowStockAlerts[i].Name = alert.Product.Name
+		}
+	}
+	return snapshot, nil
+}
+
+func (s *dashboardService) OrderCreated(ctx context.Context, order *models.Order) {
+	s.publishOrder(ctx, websocket.EventDashboardOrderCreated, order, "")
+}
+
+func (s *dashboardService) OrderStatusChanged(ctx context.Context, order *models.Order, previous models.OrderStatus) {
+	s.publishOrder(ctx, websocket.EventDashboardOrderStatusChanged, order, previous)
+}
+
+func (s *dashboardService) LowStock(ctx context.Context, alert websocket.LowStockEventPayload) {
+	s.wsManager.Publish(websocket.TopicAdminDashboard, websocket.Event{
+		Type:    websocket.EventLowStockAlert,
+		Payload: alert,
+	})
+}
+
+// publishOrder sends an order update with today's totals to the dashboard. The order is
+// copied right away, since the caller may go on changing it.
+func (s *dashboardService) publishOrder(ctx context.Context, eventType websocket.EventType, order *models.Order, previous models.OrderStatus) {
+	if !s.wsManager.HasSubscribers(websocket.TopicAdminDashboard) {
+		return
+	}
+
+	payload := websocket.DashboardOrderPayload{
+		Order:          dashboardOrder(order),
+		PreviousStatus: string(previous),
+	}
+	go func(ctx context.Context) {
+		today, err := s.todayTotals(ctx)
+		if err != nil {
+			logger.Error(ctx, "Failed to compute dashboard totals",
+				zap.Error(err),
+				zap.Uint("order_id", payload.Order.OrderID))
+			return
+		}
+		payload.Today = *today
+
+		s.wsManager.Publish(websocket.TopicAdminDashboard, websocket.Event{
+			Type:    eventType,
+			Payload: payload,
+		})
+	}(context.WithoutCancel(ctx))
+}
+
+// todayTotals counts the orders placed today and sums their revenue as the daily sales
+// report does, converted to the reporting currency at today's rate
+func (s *dashboardService) todayTotals(ctx context.Context) (*websocket.DashboardTotals, error) {
+	today := reportDay(time.Now())
+	counts, revenue, err := s.orderRepo.GetOrderStatsByDate(ctx, s.db, today)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get today's order stats: %w", err)
+	}
+
+	currency := s.currencySvc.ReportingCurrency()
+	rate, err := s.currencySvc.Rate(ctx, s.db, currency, today)
+	if err != nil {
+		return nil, err
+	}
+	if rate.IsZero() {
+		logger.Error(ctx, "No exchange rate for reporting currency, showing dashboard in base currency",
+			zap.String("currency", string(currency)))
+		currency, rate = money.DefaultCurrency, money.IdentityRate()
+	}
+
+	totals := &websocket.DashboardTotals{
+		Date:           today,
+		OrdersByStatus: make(map[string]int, len(counts)),
+		Currency:       string(currency),
+	}
+	for status, count := range counts {
+		totals.Orders += count
+		totals.OrdersByStatus[string(status)] = count
+	}
+
+	gross, err := revenue.GrossBookings.Convert(rate, currency, money.RoundHalfEven)
+	if err != nil {
+		return nil, err
+	}
+	cancellations, err := revenue.Cancellations.Convert(rate, currency, money.RoundHalfEven)
+	if err != nil {
+		return nil, err
+	}
+	if totals.Revenue, err = gross.Sub(cancellations); err != nil {
+		return nil, err
+	}
+	return totals, nil
+}
+
+func dashboardOrder(order *models.Order) websocket.DashboardOrder {
+	return websocket.DashboardOrder{
+		OrderID:     order.ID,
+		UserID:      order.UserID,
+		Status:      string(order.Status),
+		TotalAmount: order.TotalAmount,
+		Currency:    string(order.Currency),
+		CreatedAt:   order.CreatedAt,
+	}
+}
@@ -0,0 +1,475 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/api/dto"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/repository"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/errors"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type InventoryService interface {
+	ApplyMovement(ctx context.Context, tx *gorm.DB, movement *models.StockMovement) (*models.Inventory, error)
+	SetStockLevel(ctx context.Context, tx *gorm.DB, productID, warehouseID uint, quantity int, actorID uint) (*models.Inventory, error)
+	AdjustInventory(ctx context.Context, actorID uint, req *dto.InventoryAdjustmentRequest) (*dto.InventoryAdjustmentResponse, error)
+	ListMovements(ctx context.Context, productID uint, page, limit int) (*dto.PaginatedStockMovementsResponse, error)
+	Reconcile(ctx context.Context, actorID uint, apply bool) ([]dto.InventoryReconciliationResponse, error)
+	TransferStock(ctx context.Context, actorID uint, req *dto.StockTransferRequest) (*dto.StockTransferResponse, error)
+	// OnStockChanged registers a listener for committed stock changes. Listeners must be
+	// registered during startup and run in registration order.
+	OnStockChanged(listener StockChangeListener)
+	// StockChanged runs the registered listeners in the background for products whose
+	// stock changed. Call it after the transaction with the changes has committed.
+	StockChanged(ctx context.Context, productIDs ...uint)
+}
+
+// StockChangeListener reacts to committed stock changes of the given products
+type StockChangeListener func(ctx context.Context, productIDs ...uint)
+
+type inventoryService struct {
+	db            *gorm.DB
+	inventoryRepo repository.InventoryRepository
+	movementRepo  repository.StockMovementRepository
+	productRepo   repository.ProductRepository
+	warehouseRepo repository.WarehouseRepository
+	listeners     []StockChangeListener
+}
+
+func NewInventoryService(
+	db *gorm.DB,
+	inventoryRepo repository.InventoryRepository,
+	movementRepo repository.StockMovementRepository,
+	productRepo repository.ProductRepository,
+	warehouseRepo repository.WarehouseRepository,
+) InventoryService {
+	return &inventoryService{
+		db:            db,
+		inventoryRepo: inventoryRepo,
+		movementRepo:  movementRepo,
+		productRepo:   productRepo,
+		warehouseRepo: warehouseRepo,
+	}
+}
+
+// ApplyMovement locks the product's inventory row in the movement's warehouse, applies
+// the movement deltas and appends the movement to the ledger. A movement without a
+// warehouse is booked against the default warehouse. It must be called inside a
+// transaction so the inventory change and its ledger entry are committed together.
+func (s *inventoryService) ApplyMovement(ctx context.Context, tx *gorm.DB, movement *models.StockMovement) (*models.Inventory, error) {
+	if movement.WarehouseID == 0 {
+		warehouse, err := s.warehouseRepo.GetDefault(ctx, tx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get default warehouse: %w", err)
+		}
+		movement.WarehouseID = warehouse.ID
+	}
+
+	inventory, err := s.inventoryRepo.GetForUpdate(ctx, tx, movement.ProductID, movement.WarehouseID)
+	if err == gorm.ErrRecordNotFound && movement.QuantityDelta >= 0 && movement.ReservedDelta >= 0 {
+		// First stock of this product in the warehouse
+		inventory, err = s.createInventory(ctx, tx, movement.ProductID, movement.WarehouseID)
+	}
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewBusinessError(
+				fmt.Sprintf("Inventory for product %d in warehouse %d not found", movement.ProductID, movement.WarehouseID),
+				errors.ErrCodeResourceNotFound,
+				http.StatusNotFound,
+			)
+		}
+		return nil, err
+	}
+
+	quantity := inventory.Quantity + movement.QuantityDelta
+	reserved := inventory.Reserved + movement.ReservedDelta
+	if quantity < 0 || reserved < 0 {
+		return nil, errors.NewBusinessError(
+			fmt.Sprintf("Insufficient stock for product %d", movement.ProductID),
+			errors.ErrCodeInsufficientStock,
+			http.StatusConflict,
+		)
+	}
+
+	inventory.Quantity = quantity
+	inventory.Reserved = reserved
+	if err := s.inventoryRepo.Update(ctx, tx, inventory); err != nil {
+		return nil, fmt.Errorf("failed to update inventory: %w", err)
+	}
+
+	movement.QuantityAfter = inventory.Quantity
+	movement.ReservedAfter = inventory.Reserved
+	if err := s.movementRepo.Create(ctx, tx, movement); err != nil {
+		return nil, fmt.Errorf("failed to record stock movement: %w", err)
+	}
+
+	return inventory, nil
+}
+
+// SetStockLevel sets the available stock of a product in a warehouse (the default
+// warehouse when warehouseID is 0) by booking the difference as an adjustment
+func (s *inventoryService) SetStockLevel(ctx context.Context, tx *gorm.DB, productID, warehouseID uint, quantity int, actorID uint) (*models.Inventory, error) {
+	if warehouseID == 0 {
+		warehouse, err := s.warehouseRepo.GetDefault(ctx, tx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get default warehouse: %w", err)
+		}
+		warehouseID = warehouse.ID
+	}
+
+	current := 0
+	inventory, err := s.inventoryRepo.GetForUpdate(ctx, tx, productID, warehouseID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to get inventory: %w", err)
+	}
+	if inventory != nil {
+		current = inventory.Quantity
+	}
+
+	delta := quantity - current
+	if delta == 0 && inventory != nil {
+		return inventory, nil
+	}
+
+	return s.ApplyMovement(ctx, tx, &models.StockMovement{
+		ProductID:     productID,
+		WarehouseID:   warehouseID,
+		Type:          models.StockMovementAdjustment,
+		QuantityDelta: delta,
+		ReferenceType: models.StockReferenceProduct,
+		ReferenceID:   &productID,
+		Note:          "Stock level set through product update",
+		ActorID:       &actorID,
+	})
+}
+
+// createInventory adds an empty inventory row after checking the product and warehouse exist
+func (s *inventoryService) createInventory(ctx context.Context, tx *gorm.DB, productID, warehouseID uint) (*models.Inventory, error) {
+	product, err := s.productRepo.FindByID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	if product == nil {
+		return nil, errors.NewBusinessError(
+			fmt.Sprintf("Product %d not found", productID),
+			errors.ErrCodeResourceNotFound,
+			http.StatusNotFound,
+		)
+	}
+	if _, err := s.warehouseRepo.FindByID(ctx, tx, warehouseID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewBusinessError(
+				fmt.Sprintf("Warehouse %d not found", warehouseID),
+				errors.ErrCodeResourceNotFound,
+				http.StatusNotFound,
+			)
+		}
+		return nil, fmt.Errorf("failed to get warehouse: %w", err)
+	}
+
+	inventory := &models.Inventory{ProductID: productID, WarehouseID: warehouseID}
+	if err := s.inventoryRepo.Create(ctx, tx, inventory); err != nil {
+		return nil, fmt.Errorf("failed to create inventory: %w", err)
+	}
+
+	// Reload under lock so the row is held for the rest of the transaction
+	return s.inventoryRepo.GetForUpdate(ctx, tx, productID, warehouseID)
+}
+
+// AdjustInventory applies a batch of manual adjustments in a single transaction.
+// Either every line is recorded or none is.
+func (s *inventoryService) AdjustInventory(ctx context.Context, actorID uint, req *dto.InventoryAdjustmentRequest) (*dto.InventoryAdjustmentResponse, error) {
+	for i, item := range req.Items {
+		if err := validateAdjustmentDirection(item); err != nil {
+			return nil, errors.NewValidationError(
+				"Invalid inventory adjustment",
+				map[string]string{fmt.Sprintf("items[%d].quantity_delta", i): err.Error()},
+				http.StatusBadRequest,
+			)
+		}
+	}
+
+	movements := make([]models.StockMovement, len(req.Items))
+	for i, item := range req.Items {
+		movements[i] = models.StockMovement{
+			ProductID:     item.ProductID,
+			WarehouseID:   item.WarehouseID,
+			Type:          models.StockMovementType(item.ReasonCode),
+			QuantityDelta: item.QuantityDelta,
+			ReferenceType: models.StockReferenceAdjustment,
+			Reference:     req.Reference,
+			Note:          item.Note,
+			ActorID:       &actorID,
+		}
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var defaultWarehouse *models.Warehouse
+		for i := range movements {
+			if movements[i].WarehouseID != 0 {
+				continue
+			}
+			if defaultWarehouse == nil {
+				warehouse, err := s.warehouseRepo.GetDefault(ctx, tx)
+				if err != nil {
+					return fmt.Errorf("failed to get default warehouse: %w", err)
+				}
+				defaultWarehouse = warehouse
+			}
+			movements[i].WarehouseID = defaultWarehouse.ID
+		}
+
+		// Lock rows in product and warehouse order, the order orders acquire them in, so
+		// batches and checkouts can't deadlock. The response keeps the request order.
+		order := make([]int, len(movements))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(a, b int) bool {
+			x, y := movements[order[a]], movements[order[b]]
+			if x.ProductID != y.ProductID {
+				return x.ProductID < y.ProductID
+			}
+			return x.WarehouseID < y.WarehouseID
+		})
+		for _, i := range order {
+			if _, err := s.ApplyMovement(ctx, tx, &movements[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Error(ctx, "Failed to apply inventory adjustments", zap.Error(err), zap.Uint("actor_id", actorID))
+		return nil, err
+	}
+
+	productIDs := make([]uint, len(req.Items))
+	for i, item := range req.Items {
+		productIDs[i] = item.ProductID
+	}
+	s.StockChanged(ctx, productIDs...)
+
+	resp := &dto.InventoryAdjustmentResponse{
+		Reference: req.Reference,
+		Movements: make([]dto.StockMovementResponse, len(movements)),
+	}
+	for i := range movements {
+		resp.Movements[i] = dto.StockMovementToResponse(&movements[i])
+	}
+
+	return resp, nil
+}
+
+// validateAdjustmentDirection makes sure the sign of the delta matches the reason code
+func validateAdjustmentDirection(item dto.InventoryAdjustmentItem) error {
+	switch models.StockMovementType(item.ReasonCode) {
+	case models.StockMovementReceipt, models.StockMovementReturn:
+		if item.QuantityDelta < 0 {
+			return fmt.Errorf("%s must increase stock", item.ReasonCode)
+		}
+	case models.StockMovementShrinkage:
+		if item.QuantityDelta > 0 {
+			return fmt.Errorf("%s must decrease stock", item.ReasonCode)
+		}
+	}
+	return nil
+}
+
+func (s *inventoryService) ListMovements(ctx context.Context, productID uint, page, limit int) (*dto.PaginatedStockMovementsResponse, error) {
+	product, err := s.productRepo.FindByID(ctx, productID)
+	if err != nil {
+		logger.Error(ctx, "Failed to get product", zap.Error(err))
+		return nil, err
+	}
+	if product == nil {
+		return nil, errors.NewBusinessError("Product not found", errors.ErrCodeResourceNotFound, http.StatusNotFound)
+	}
+
+	offset := (page - 1) * limit
+	movements, total, err := s.movementRepo.ListByProduct(ctx, s.db, productID, offset, limit)
+	if err != nil {
+		logger.Error(ctx, "Failed to list stock movements", zap.Error(err), zap.Uint("product_id", productID))
+		return nil, err
+	}
+
+	resp := &dto.PaginatedStockMovementsResponse{
+		Movements: make([]dto.StockMovementResponse, len(movements)),
+		Total:     total,
+		Page:      page,
+		Limit:     limit,
+	}
+	for i := range movements {
+		resp.Movements[i] = dto.StockMovementToResponse(&movements[i])
+	}
+
+	return resp, nil
+}
+
+// Reconcile compares every inventory row with the sum of its ledger entries and returns
+// the rows that drifted. When apply is true, a balancing adjustment is appended to the
+// ledger for each drifted row so that the ledger matches the stored stock again
+// (for example for inventory created before the ledger existed). Applying locks each
+// drifted row and checks it again first, so movements in flight can't show up as drift
+// and get balanced twice.
+func (s *inventoryService) Reconcile(ctx context.Context, actorID uint, apply bool) ([]dto.InventoryReconciliationResponse, error) {
+	var drifted []dto.InventoryReconciliationResponse
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		balances, err := s.movementRepo.GetLedgerBalances(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		// Balances come in product and warehouse order, the order orders lock rows in
+		for _, balance := range balances {
+			if balance.Quantity == balance.LedgerQuantity && balance.Reserved == balance.LedgerReserved {
+				continue
+			}
+
+			if apply {
+				// Movements update the inventory row and append to the ledger under the row's
+				// lock, so once it is locked the two agree unless they really drifted
+				if _, err := s.inventoryRepo.GetForUpdate(ctx, tx, balance.ProductID, balance.WarehouseID); err != nil {
+					return fmt.Errorf("failed to lock inventory: %w", err)
+				}
+				locked, err := s.movementRepo.GetLedgerBalance(ctx, tx, balance.ProductID, balance.WarehouseID)
+				if err != nil {
+					return err
+				}
+				balance = *locked
+			}
+
+			stockDrift := balance.Quantity - balance.LedgerQuantity
+			reservedDrift := balance.Reserved - balance.LedgerReserved
+			if stockDrift == 0 && reservedDrift == 0 {
+				continue
+			}
+
+			drifted = append(drifted, dto.InventoryReconciliationResponse{
+				ProductID:        balance.ProductID,
+				WarehouseID:      balance.WarehouseID,
+				StockLevel:       balance.Quantity,
+				Reserved:         balance.Reserved,
+				LedgerStockLevel: balance.LedgerQuantity,
+				LedgerReserved:   balance.LedgerReserved,
+				StockDrift:       stockDrift,
+				ReservedDrift:    reservedDrift,
+			})
+
+			if !apply {
+				continue
+			}
+
+			// The stored inventory is authoritative here, so only the ledger is corrected
+			if err := s.movementRepo.Create(ctx, tx, &models.StockMovement{
+				ProductID:     balance.ProductID,
+				WarehouseID:   balance.WarehouseID,
+				Type:          models.StockMovementAdjustment,
+				QuantityDelta: stockDrift,
+				ReservedDelta: reservedDrift,
+				QuantityAfter: balance.Quantity,
+				ReservedAfter: balance.Reserved,
+				ReferenceType: models.StockReferenceAdjustment,
+				Reference:     "reconciliation",
+				Note:          "Balancing entry created by ledger reconciliation",
+				ActorID:       &actorID,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Error(ctx, "Failed to reconcile inventory", zap.Error(err))
+		return nil, err
+	}
+
+	if drifted == nil {
+		drifted = []dto.InventoryReconciliationResponse{}
+	}
+	return drifted, nil
+}
+
+// TransferStock moves available stock between two warehouses. The transfer is recorded
+// as a transfer_out and a transfer_in movement sharing the same reference.
+func (s *inventoryService) TransferStock(ctx context.Context, actorID uint, req *dto.StockTransferRequest) (*dto.StockTransferResponse, error) {
+	reference := req.Reference
+	if reference == "" {
+		reference = "TRF-" + strings.ToUpper(uuid.New().String()[:8])
+	}
+
+	out := models.StockMovement{
+		ProductID:     req.ProductID,
+		WarehouseID:   req.FromWarehouseID,
+		Type:          models.StockMovementTransferOut,
+		QuantityDelta: -req.Quantity,
+		ReferenceType: models.StockReferenceTransfer,
+		Reference:     reference,
+		Note:          req.Note,
+		ActorID:       &actorID,
+	}
+	in := models.StockMovement{
+		ProductID:     req.ProductID,
+		WarehouseID:   req.ToWarehouseID,
+		Type:          models.StockMovementTransferIn,
+		QuantityDelta: req.Quantity,
+		ReferenceType: models.StockReferenceTransfer,
+		Reference:     reference,
+		Note:          req.Note,
+		ActorID:       &actorID,
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Lock rows in warehouse order to avoid deadlocks with concurrent transfers
+		first, second := &out, &in
+		if in.WarehouseID < out.WarehouseID {
+			first, second = &in, &out
+		}
+		if _, err := s.ApplyMovement(ctx, tx, first); err != nil {
+			return err
+		}
+		_, err := s.ApplyMovement(ctx, tx, second)
+		return err
+	})
+	if err != nil {
+		logger.Error(ctx, "Failed to transfer stock", zap.Error(err), zap.Uint("product_id", req.ProductID))
+		return nil, err
+	}
+
+	s.StockChanged(ctx, req.ProductID)
+
+	return &dto.StockTransferResponse{
+		Reference: reference,
+		Movements: []dto.StockMovementResponse{
+			dto.StockMovementToResponse(&out),
+			dto.StockMovementToResponse(&in),
+		},
+	}, nil
+}
+
+func (s *inventoryService) OnStockChanged(listener StockChangeListener) {
+	s.listeners = append(s.listeners, listener)
+}
+
+func (s *inventoryService) StockChanged(ctx context.Context, productIDs ...uint) {
+	if len(productIDs) == 0 || len(s.listeners) == 0 {
+		return
+	}
+
+	// The request may finish before the listeners do
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		for _, listener := range s.listeners {
+			listener(ctx, productIDs...)
+		}
+	}()
+}
@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/api/dto"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/repository"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/logger"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/websocket"
+)
+
+// LowStockConfig controls how suggested reorder quantities are calculated
+type LowStockConfig struct {
+	VelocityWindowDays int // Days of order history used to measure sales velocity
+	CoverDays          int // Days of sales a reorder should cover on top of the minimum stock
+}
+
+type LowStockService interface {
+	// CheckProducts re-evaluates the inventory of the given products, opening, refreshing
+	// or resolving alerts, and notifies admins about newly opened alerts. It is called
+	// after inventory changes are committed; failures are logged, not returned.
+	CheckProducts(ctx context.Context, productIDs ...uint)
+	ListAlerts(ctx context.Context) ([]dto.LowStockAlertResponse, error)
+}
+
+type lowStockService struct {
+	db              *gorm.DB
+	config          LowStockConfig
+	alertRepo       repository.StockAlertRepository
+	inventoryRepo   repository.InventoryRepository
+	movementRepo    repository.StockMovementRepository
+	productRepo     repository.ProductRepository
+	userRepo        repository.UserRepository
+	notificationSvc NotificationService
+	dashboardSvc    DashboardService
+}
+
+func NewLowStockService(
+	db *gorm.DB,
+	config LowStockConfig,
+	alertRepo repository.StockAlertRepository,
+	inventoryRepo repository.InventoryRepository,
+	movementRepo repository.StockMovementRepository,
+	productRepo repository.ProductRepository,
+	userRepo repository.UserRepository,
+	notificationSvc NotificationService,
+	dashboardSvc DashboardService,
+) LowStockService {
+	if config.VelocityWindowDays <= 0 {
+		config.VelocityWindowDays = 30
+	}
+	if config.CoverDays <= 0 {
+		config.CoverDays = 30
+	}
+	return &lowStockService{
+		db:              db,
+		config:          config,
+		alertRepo:       alertRepo,
+		inventoryRepo:   inventoryRepo,
+		movementRepo:    movementRepo,
+		productRepo:     productRepo,
+		userRepo:        userRepo,
+		notificationSvc: notificationSvc,
+		dashboardSvc:    dashboardSvc,
+	}
+}
+
+func (s *lowStockService) CheckProducts(ctx context.Context, productIDs ...uint) {
+	seen := make(map[uint]bool, len(productIDs))
+	for _, productID := range productIDs {
+		if seen[productID] {
+			continue
+		}
+		seen[productID] = true
+
+		opened, err := s.evaluateProduct(ctx, productID)
+		if err != nil {
+			logger.Error(ctx, "Failed to evaluate low stock",
+				zap.Error(err),
+				zap.Uint("product_id", productID))
+			continue
+		}
+
+		for i := range opened {
+			s.notifyAdmins(ctx, &opened[i])
+		}
+	}
+}
+
+// evaluateProduct locks the product's inventory rows so concurrent evaluations of the
+// same product are serialised, then reconciles its alerts with the current stock.
+// It returns the alerts that were opened.
+func (s *lowStockService) evaluateProduct(ctx context.Context, productID uint) ([]models.StockAlert, error) {
+	product, err := s.productRepo.FindByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, nil
+	}
+
+	var opened []models.StockAlert
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		inventories, err := s.inventoryRepo.ListByProductForUpdate(ctx, tx, productID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		for _, inventory := range inventories {
+			alert, err := s.alertRepo.FindOpen(ctx, tx, productID, inventory.WarehouseID)
+			if err != nil && err != gorm.ErrRecordNotFound {
+				return err
+			}
+
+			if inventory.Quantity > inventory.MinimumStock {
+				// Stock recovered: close the open alert, if any
+				if alert != nil {
+					alert.Status = models.StockAlertStatusResolved
+					alert.StockLevel = inventory.Quantity
+					alert.Reserved = inventory.Reserved
+					alert.ResolvedAt = &now
+					if err := s.alertRepo.Update(ctx, tx, alert); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+
+			velocity, err := s.dailyVelocity(ctx, tx, productID, inventory.WarehouseID, now)
+			if err != nil {
+				return err
+			}
+
+			isNew := alert == nil
+			if isNew {
+				alert = &models.StockAlert{
+					ProductID:   productID,
+					WarehouseID: inventory.WarehouseID,
+					Status:      models.StockAlertStatusOpen,
+					TriggeredAt: now,
+				}
+			}
+			alert.StockLevel = inventory.Quantity
+			alert.Reserved = inventory.Reserved
+			alert.MinimumStock = inventory.MinimumStock
+			alert.DailySalesVelocity = math.Round(velocity*100) / 100
+			alert.SuggestedReorderQuantity = s.suggestedReorderQuantity(inventory, velocity)
+
+			if isNew {
+				if err := s.alertRepo.Create(ctx, tx, alert); err != nil {
+					return err
+				}
+				alert.Product = product
+				alert.Warehouse = inventory.Warehouse
+				opened = append(opened, *alert)
+				continue
+			}
+			if err := s.alertRepo.Update(ctx, tx, alert); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return opened, nil
+}
+
+// dailyVelocity returns the average units ordered per day over the velocity window
+func (s *lowStockService) dailyVelocity(ctx context.Context, tx *gorm.DB, productID, warehouseID uint, now time.Time) (float64, error) {
+	since := now.AddDate(0, 0, -s.config.VelocityWindowDays)
+	demand, err := s.movementRepo.GetNetDemand(ctx, tx, productID, warehouseID, since)
+	if err != nil {
+		return 0, err
+	}
+	if demand <= 0 {
+		return 0, nil
+	}
+	return float64(demand) / float64(s.config.VelocityWindowDays), nil
+}
+
+// suggestedReorderQuantity tops stock up to the minimum plus the expected sales over
+// the cover period. Without recent sales it falls back to doubling the minimum stock.
+func (s *lowStockService) suggestedReorderQuantity(inventory models.Inventory, velocity float64) int {
+	cover := int(math.Ceil(velocity * float64(s.config.CoverDays)))
+	target := inventory.MinimumStock + max(cover, inventory.MinimumStock)
+	return max(target-inventory.Quantity, 1)
+}
+
+func (s *lowStockService) notifyAdmins(ctx context.Context, alert *models.StockAlert) {
+	name := fmt.Sprintf("product %d", alert.ProductID)
+	if alert.Product != nil {
+		name = alert.Product.Name
+	}
+	warehouse := fmt.Sprintf("warehouse %d", alert.WarehouseID)
+	if alert.Warehouse != nil {
+		warehouse = alert.Warehouse.Name
+	}
+
+	admins, err := s.userRepo.ListByRole(ctx, models.RoleAdmin)
+	if err != nil {
+		logger.Error(ctx, "Failed to list admins for low stock alert",
+			zap.Error(err),
+			zap.Uint("alert_id", alert.ID))
+	}
+
+	message := fmt.Sprintf("%s is low in %s: %d units left (minimum %d). Suggested reorder: %d units.",
+		name, warehouse, alert.StockLevel, alert.MinimumStock, alert.SuggestedReorderQuantity)
+	for _, admin := range admins {
+		if err := s.notificationSvc.CreateNotification(
+			ctx,
+			admin.ID,
+			models.NotificationTypeInventory,
+			"Low Stock Alert",
+			message,
+			nil,
+		); err != nil {
+			logger.Error(ctx, "Failed to create low stock notification",
+				zap.Error(err),
+				zap.Uint("alert_id", alert.ID),
+				zap.Uint("user_id", admin.ID))
+		}
+	}
+
+	// One push on the admin dashboard instead of one per admin notification
+	s.dashboardSvc.LowStock(ctx, websocket.LowStockEventPayload{
+		AlertID:                  alert.ID,
+		ProductID:                alert.ProductID,
+		Name:                     name,
+		WarehouseID:              alert.WarehouseID,
+		StockLevel:               alert.StockLevel,
+		MinimumStock:             alert.MinimumStock,
+		SuggestedReorderQuantity: alert.SuggestedReorderQuantity,
+	})
+}
+
+func (s *lowStockService) ListAlerts(ctx context.Context) ([]dto.LowStockAlertResponse, error) {
+	alerts, err := s.alertRepo.ListOpen(ctx, s.db)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]dto.LowStockAlertResponse, len(alerts))
+	for i := range alerts {
+		resp[i] = dto.StockAlertToResponse(&alerts[i])
+	}
+	return resp, nil
+}
@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/errors"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/logger"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/websocket"
+)
+
+// OrderItemAdjustment sets the new quantity of an order item; zero removes the item
+type OrderItemAdjustment struct {
+	ItemID   uint
+	Quantity int
+}
+
+// AdjustOrderInput describes which order items to reduce, by whom and why
+type AdjustOrderInput struct {
+	ActorID uint   // User changing the order
+	OwnerID uint   // Restricts the change to this customer's orders; zero for admins
+	Reason  string // Shown to the customer
+	Items   []OrderItemAdjustment
+}
+
+// AdjustOrderItems reduces the quantities of some of an order's items, or removes them,
+// while the order is still pending or processing. The removed units are released back to
+// stock, the order totals are recomputed and a refund of the difference is recorded if the
+// payment was captured, then sent once the change is committed. Orders placed at checkout
+// aren't charged and have no payment, so for them the lower total is the whole change.
+// Removing every item is a cancellation and has to go through CancelOrder.
+func (s *OrderService) AdjustOrderItems(ctx context.Context, orderID uint, input AdjustOrderInput) (*models.Order, error) {
+	var order *models.Order
+	var changed []uint
+	var refund *models.Refund
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		order, err = s.orderRepo.GetOrderByIDForUpdate(ctx, tx, orderID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.NewBusinessError("Order not found", errors.ErrCodeResourceNotFound, http.StatusNotFound)
+			}
+			return fmt.Errorf("failed to get order: %w", err)
+		}
+
+		// Verify order belongs to user
+		if input.OwnerID != 0 && order.UserID != input.OwnerID {
+			return errors.NewBusinessError("Order does not belong to user", "UNAUTHORIZED_ACCESS", http.StatusForbidden)
+		}
+
+		if order.Status != models.OrderStatusPending && order.Status != models.OrderStatusProcessing {
+			return errors.NewBusinessError(
+				fmt.Sprintf("Order items cannot be changed in status %s", order.Status),
+				errors.ErrCodeInvalidOrderStatus,
+				http.StatusBadRequest,
+			)
+		}
+
+		quantities, err := adjustedQuantities(order, input.Items)
+		if err != nil {
+			return err
+		}
+
+		previousTotal := order.TotalAmount
+		for i := range order.OrderItems {
+			item := &order.OrderItems[i]
+			quantity, ok := quantities[item.ID]
+			if !ok || quantity == item.Quantity {
+				continue
+			}
+			if err := s.releaseItemUnits(ctx, tx, order, item, item.Quantity-quantity, input.ActorID); err != nil {
+				return err
+			}
+			changed = append(changed, item.ProductID)
+		}
+		if len(changed) == 0 {
+			return nil
+		}
+
+		if err := sumOrderTotals(order); err != nil {
+			return err
+		}
+		if err := s.orderRepo.Update(ctx, tx, order); err != nil {
+			return fmt.Errorf("failed to update order: %w", err)
+		}
+
+		difference, err := previousTotal.Sub(order.TotalAmount)
+		if err != nil {
+			return err
+		}
+		if !difference.IsPositive() {
+			return nil
+		}
+		refund, err = s.requestRefund(ctx, tx, order, difference, input.Reason)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(changed) == 0 {
+		return order, nil
+	}
+	if refund != nil {
+		if err := s.sendRefund(ctx, refund); err != nil {
+			logger.Error(ctx, "Failed to refund adjusted order, leaving the refund to be retried",
+				zap.Error(err),
+				zap.Uint("order_id", order.ID),
+				zap.Uint("refund_id", refund.ID))
+		}
+	}
+
+	// Released stock may fill backorders and resolve open low stock alerts
+	s.inventorySvc.StockChanged(ctx, changed...)
+
+	s.publishOrderEvent(websocket.EventOrderUpdated, order)
+	go s.notifyOrderUpdated(context.Background(), order, input.Reason)
+
+	return order, nil
+}
+
+// adjustedQuantities validates the adjustments against the order and returns the new
+// quantity of each adjusted item by ID
+func adjustedQuantities(order *models.Order, adjustments []OrderItemAdjustment) (map[uint]int, error) {
+	items := make(map[uint]models.OrderItem, len(order.OrderItems))
+	for _, item := range order.OrderItems {
+		items[item.ID] = item
+	}
+
+	quantities := make(map[uint]int, len(adjustments))
+	for _, adjustment := range adjustments {
+		item, ok := items[adjustment.ItemID]
+		if !ok {
+			return nil, errors.NewValidationError(
+				fmt.Sprintf("Order item %d not found", adjustment.ItemID),
+				map[string]string{"item_id": "must be an item of the order"},
+				http.StatusBadRequest,
+			)
+		}
+		if _, ok := quantities[adjustment.ItemID]; ok {
+			return nil, errors.NewValidationError(
+				fmt.Sprintf("Order item %d is listed more than once", adjustment.ItemID),
+				map[string]string{"item_id": "must be unique"},
+				http.StatusBadRequest,
+			)
+		}
+		if adjustment.Quantity < 0 || adjustment.Quantity > item.Quantity {
+			return nil, errors.NewValidationError(
+				fmt.Sprintf("Quantity of order item %d can only be reduced", adjustment.ItemID),
+				map[string]string{"quantity": fmt.Sprintf("must be between 0 and %d", item.Quantity)},
+				http.StatusBadRequest,
+			)
+		}
+		quantities[adjustment.ItemID] = adjustment.Quantity
+	}
+
+	remaining := 0
+	for _, item := range order.OrderItems {
+		if quantity, ok := quantities[item.ID]; ok {
+			remaining += quantity
+		} else {
+			remaining += item.Quantity
+		}
+	}
+	if remaining == 0 {
+		return nil, errors.NewValidationError(
+			"An order cannot be left without items",
+			map[string]string{"items": "cancel the order to remove every item"},
+			http.StatusBadRequest,
+		)
+	}
+	return quantities, nil
+}
+
+// releaseItemUnits removes units from an order item. Units still on backorder go first,
+// since nothing is reserved for them, then reserved units are released from the item's
+// most recent allocations.
+func (s *OrderService) releaseItemUnits(ctx context.Context, tx *gorm.DB, order *models.Order, item *models.OrderItem, units int, actorID uint) error {
+	item.Quantity -= units
+	item.CancelledQuantity += units
+
+	backordered := min(units, item.BackorderedQuantity)
+	item.BackorderedQuantity -= backordered
+	units -= backordered
+
+	allocations := item.Allocations
+	for i := len(allocations) - 1; i >= 0 && units > 0; i-- {
+		allocation := &allocations[i]
+		released := min(units, allocation.Quantity)
+		if _, err := s.inventorySvc.ApplyMovement(ctx, tx, &models.StockMovement{
+			ProductID:     item.ProductID,
+			WarehouseID:   allocation.WarehouseID,
+			Type:          models.StockMovementRelease,
+			QuantityDelta: released,
+			ReservedDelta: -released,
+			ReferenceType: models.StockReferenceOrder,
+			ReferenceID:   &order.ID,
+			ActorID:       &actorID,
+		}); err != nil {
+			return fmt.Errorf("failed to release inventory: %w", err)
+		}
+
+		allocation.Quantity -= released
+		units -= released
+		if allocation.Quantity == 0 {
+			if err := s.orderRepo.DeleteAllocation(ctx, tx, allocation); err != nil {
+				return fmt.Errorf("failed to delete allocation: %w", err)
+			}
+			allocations = allocations[:i]
+		} else if err := s.orderRepo.UpdateAllocation(ctx, tx, allocation); err != nil {
+			return fmt.Errorf("failed to update allocation: %w", err)
+		}
+	}
+	item.Allocations = allocations
+
+	if err := s.orderRepo.UpdateItem(ctx, tx, item); err != nil {
+		return fmt.Errorf("failed to update order item: %w", err)
+	}
+	return nil
+}
+
+// notifyOrderUpdated tells the customer their order's items were changed
+func (s *OrderService) notifyOrderUpdated(ctx context.Context, order *models.Order, reason string) {
+	event := &websocket.Event{
+		Type: websocket.EventOrderUpdated,
+		Payload: websocket.OrderEventPayload{
+			OrderID:     order.ID,
+			Status:      string(order.Status),
+			TotalAmount: order.TotalAmount,
+			Currency:    string(order.Currency),
+		},
+	}
+
+	message := fmt.Sprintf("Your order #%d has been updated, the new total is %s.", order.ID, order.TotalAmount)
+	if reason != "" {
+		message += " Reason: " + reason
+	}
+	if err := s.notificationSvc.CreateNotification(
+		ctx,
+		order.UserID,
+		models.NotificationTypeOrder,
+		"Order Updated",
+		message,
+		event,
+	); err != nil {
+		logger.Error(ctx, "Failed to create order update notification",
+			zap.Error(err),
+			zap.Uint("order_id", order.ID),
+			zap.Uint("user_id", order.UserID))
+	}
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/api/dto"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/errors"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/logger"
+)
+
+const (
+	// maxBulkOrders caps how many orders one bulk action may touch
+	maxBulkOrders = 1000
+	// bulkOrderConcurrency bounds how many orders are processed at once, keeping
+	// database connections free for regular traffic
+	bulkOrderConcurrency = 8
+)
+
+// BulkOrderAction applies an action to each selected order independently. Every order is
+// checked against the status transition rules on its own and failures are reported per
+// order instead of aborting the whole request.
+func (s *OrderService) BulkOrderAction(ctx context.Context, actorID uint, req *dto.BulkOrderActionRequest) (*dto.BulkOrderActionResponse, error) {
+	orderIDs, err := s.bulkOrderIDs(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]dto.BulkOrderResult, len(orderIDs))
+	semaphore := make(chan struct{}, bulkOrderConcurrency)
+	var wg sync.WaitGroup
+	for i, orderID := range orderIDs {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(i int, orderID uint) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			results[i] = s.applyBulkAction(ctx, actorID, req, orderID)
+		}(i, orderID)
+	}
+	wg.Wait()
+
+	resp := &dto.BulkOrderActionResponse{Action: req.Action, Results: results}
+	for _, result := range results {
+		if result.Success {
+			resp.Succeeded++
+		} else {
+			resp.Failed++
+		}
+	}
+	return resp, nil
+}
+
+// bulkOrderIDs returns the de-duplicated IDs of the orders a bulk action applies to
+func (s *OrderService) bulkOrderIDs(ctx context.Context, req *dto.BulkOrderActionRequest) ([]uint, error) {
+	if (len(req.OrderIDs) == 0) == (req.Filter == nil) {
+		return nil, errors.NewValidationError(
+			"Invalid bulk action",
+			map[string]string{"order_ids": "provide either order_ids or filter"},
+			http.StatusBadRequest,
+		)
+	}
+
+	if req.Filter == nil {
+		seen := make(map[uint]bool, len(req.OrderIDs))
+		ids := make([]uint, 0, len(req.OrderIDs))
+		for _, id := range req.OrderIDs {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		return ids, nil
+	}
+
+	filter, err := adminOrderFilter(req.Filter)
+	if err != nil {
+		return nil, err
+	}
+	// Fetch one more than allowed to tell a full match from one that is too broad
+	ids, err := s.orderRepo.ListOrderIDs(ctx, s.db, filter, maxBulkOrders+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) > maxBulkOrders {
+		return nil, errors.NewValidationError(
+			"Filter matches too many orders",
+			map[string]string{"filter": "must match at most 1000 orders"},
+			http.StatusBadRequest,
+		)
+	}
+	return ids, nil
+}
+
+func (s *OrderService) applyBulkAction(ctx context.Context, actorID uint, req *dto.BulkOrderActionRequest, orderID uint) dto.BulkOrderResult {
+	var order *models.Order
+	var err error
+	switch req.Action {
+	case dto.BulkActionMarkProcessing:
+		order, err = s.UpdateOrderStatus(ctx, orderID, actorID, models.OrderStatusProcessing, "")
+	case dto.BulkActionMarkShipped:
+		order, err = s.UpdateOrderStatus(ctx, orderID, actorID, models.OrderStatusShipped, "")
+	case dto.BulkActionCancel:
+		order, err = s.CancelOrder(ctx, orderID, CancelOrderInput{ActorID: actorID, Reason: req.Reason})
+	}
+
+	result := dto.BulkOrderResult{OrderID: orderID}
+	switch e := err.(type) {
+	case nil:
+		result.Success = true
+		result.Status = string(order.Status)
+	case *errors.BusinessError:
+		result.ErrorCode, result.Error = e.ErrorCode, e.Message
+	case *errors.ValidationError:
+		// Status transition rules report the offending transition in the fields
+		result.ErrorCode, result.Error = errors.ErrCodeInvalidOrderStatus, e.Message
+		if reason := joinFields(e.Fields); reason != "" {
+			result.Error += ": " + reason
+		}
+	default:
+		logger.Error(ctx, "Bulk order action failed",
+			zap.Error(err),
+			zap.String("action", req.Action),
+			zap.Uint("order_id", orderID))
+		result.ErrorCode, result.Error = "INTERNAL_SERVER_ERROR", "Failed to process order"
+	}
+	return result
+}
+
+// joinFields flattens validation error fields into one message
+func joinFields(fields map[string]string) string {
+	messages := make([]string, 0, len(fields))
+	for _, message := range fields {
+		messages = append(messages, message)
+	}
+	sort.Strings(messages)
+	return strings.Join(messages, "; ")
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/errors"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/logger"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/websocket"
+)
+
+// CancelOrderInput describes who cancels an order and why
+type CancelOrderInput struct {
+	ActorID uint   // User cancelling the order
+	OwnerID uint   // Restricts the cancellation to this customer's orders; zero for admins
+	Reason  string // Shown to the customer
+}
+
+// CancelOrder is the single way orders get cancelled, by customers and admins alike. It
+// releases the reserved stock, records who cancelled the order and why, and records a
+// refund of what was captured. Once the cancellation is committed the refund is sent and
+// the customer notified; a refund that fails is retried by RetryPendingRefunds.
+func (s *OrderService) CancelOrder(ctx context.Context, orderID uint, input CancelOrderInput) (*models.Order, error) {
+	var order *models.Order
+	var previous models.OrderStatus
+	var refund *models.Refund
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		order, err = s.orderRepo.GetOrderByIDForUpdate(ctx, tx, orderID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.NewBusinessError("Order not found", errors.ErrCodeResourceNotFound, http.StatusNotFound)
+			}
+			return fmt.Errorf("failed to get order: %w", err)
+		}
+
+		// Verify order belongs to user
+		if input.OwnerID != 0 && order.UserID != input.OwnerID {
+			return errors.NewBusinessError("Order does not belong to user", "UNAUTHORIZED_ACCESS", http.StatusForbidden)
+		}
+
+		// Check if order can be cancelled
+		if !isValidStatusTransition(order.Status, models.OrderStatusCancelled) {
+			return errors.NewBusinessError(
+				fmt.Sprintf("Order cannot be cancelled in status %s", order.Status),
+				"INVALID_STATUS_TRANSITION",
+				http.StatusBadRequest,
+			)
+		}
+
+		now := time.Now()
+		previous = order.Status
+		order.Status = models.OrderStatusCancelled
+		order.CancellationReason = input.Reason
+		order.CancelledBy = &input.ActorID
+		order.CancelledAt = &now
+		if err := s.orderRepo.Update(ctx, tx, order); err != nil {
+			return fmt.Errorf("failed to update order status: %w", err)
+		}
+
+		// Release reserved inventory in the allocated warehouses back to available stock
+		for _, item := range order.OrderItems {
+			for _, allocation := range item.Allocations {
+				if _, err := s.inventorySvc.ApplyMovement(ctx, tx, &models.StockMovement{
+					ProductID:     item.ProductID,
+					WarehouseID:   allocation.WarehouseID,
+					Type:          models.StockMovementRelease,
+					QuantityDelta: allocation.Quantity,
+					ReservedDelta: -allocation.Quantity,
+					ReferenceType: models.StockReferenceOrder,
+					ReferenceID:   &order.ID,
+					ActorID:       &input.ActorID,
+				}); err != nil {
+					return fmt.Errorf("failed to release inventory: %w", err)
+				}
+			}
+		}
+
+		refund, err = s.requestRefund(ctx, tx, order, money.Zero(order.Currency), input.Reason)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if refund != nil {
+		if err := s.sendRefund(ctx, refund); err != nil {
+			logger.Error(ctx, "Failed to refund cancelled order, leaving the refund to be retried",
+				zap.Error(err),
+				zap.Uint("order_id", order.ID),
+				zap.Uint("refund_id", refund.ID))
+		}
+	}
+
+	s.dashboardSvc.OrderStatusChanged(ctx, order, previous)
+	s.publishOrderEvent(websocket.EventOrderCancelled, order)
+
+	// Released stock may fill backorders and resolve open low stock alerts
+	s.inventorySvc.StockChanged(ctx, orderProductIDs(order)...)
+
+	go s.notifyOrderCancelled(context.Background(), order)
+
+	return order, nil
+}
+
+// notifyOrderCancelled tells the customer their order was cancelled and sends the
+// inventory updates of the released products
+func (s *OrderService) notifyOrderCancelled(ctx context.Context, order *models.Order) {
+	orderEvent := &websocket.Event{
+		Type: websocket.EventOrderCancelled,
+		Payload: websocket.OrderEventPayload{
+			OrderID:     order.ID,
+			Status:      string(order.Status),
+			TotalAmount: order.TotalAmount,
+			Currency:    string(order.Currency),
+		},
+	}
+
+	message := fmt.Sprintf("Your order #%d has been cancelled.", order.ID)
+	if order.CancellationReason != "" {
+		message += " Reason: " + order.CancellationReason
+	}
+	if err := s.notificationSvc.CreateNotification(
+		ctx,
+		order.UserID,
+		models.NotificationTypeOrder,
+		"Order Cancelled",
+		message,
+		orderEvent,
+	); err != nil {
+		logger.Error(ctx, "Failed to create order cancellation notification",
+			zap.Error(err),
+			zap.Uint("order_id", order.ID),
+			zap.Uint("user_id", order.UserID))
+	}
+
+	// Send inventory update notification for each product
+	for _, item := range order.OrderItems {
+		product, err := s.productRepo.FindByID(ctx, item.ProductID)
+		if err != nil || product == nil {
+			logger.Error(ctx, "Failed to get product for inventory notification",
+				zap.Error(err),
+				zap.Uint("product_id", item.ProductID))
+			continue
+		}
+
+		stockLevels, err := s.inventoryRepo.GetStockLevels(ctx, s.db, []uint{product.ID})
+		if err != nil {
+			logger.Error(ctx, "Failed to get stock level for inventory notification",
+				zap.Error(err),
+				zap.Uint("product_id", item.ProductID))
+			continue
+		}
+		stockLevel := stockLevels[product.ID]
+
+		inventoryEvent := &websocket.Event{
+			Type: websocket.EventInventoryUpdated,
+			Payload: websocket.InventoryEventPayload{
+				ProductID: product.ID,
+				Quantity:  stockLevel,
+				Name:      product.Name,
+			},
+		}
+
+		if err := s.notificationSvc.CreateNotification(
+			ctx,
+			order.UserID,
+			models.NotificationTypeInventory,
+			"Inventory Update",
+			fmt.Sprintf("Updated inventory for %s: %d units available", product.Name, stockLevel),
+			inventoryEvent,
+		); err != nil {
+			logger.Error(ctx, "Failed to create inventory notification",
+				zap.Error(err),
+				zap.Uint("product_id", item.ProductID),
+				zap.Uint("user_id", order.UserID))
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/api/dto"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/repository"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/errors"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
+)
+
+// ListUserOrders returns a page of the user's orders matching the query and the cursor of
+// the next page, which is empty on the last page. Pages are keyset paginated on the
+// creation time and ID so deep pages cost the same as the first.
+func (s *OrderService) ListUserOrders(ctx context.Context, userID uint, query *dto.ListOrdersQuery) ([]models.Order, string, error) {
+	filter := repository.OrderFilter{UserID: userID, ProductID: query.ProductID}
+	for _, status := range query.Status {
+		filter.Statuses = append(filter.Statuses, models.OrderStatus(status))
+	}
+
+	var err error
+	if filter.From, err = parseTimeParam(query.From, false); err != nil {
+		return nil, "", errors.NewValidationError("Invalid date range", map[string]string{"from": err.Error()}, http.StatusBadRequest)
+	}
+	if filter.To, err = parseTimeParam(query.To, true); err != nil {
+		return nil, "", errors.NewValidationError("Invalid date range", map[string]string{"to": err.Error()}, http.StatusBadRequest)
+	}
+
+	var after *repository.OrderCursor
+	if query.Cursor != "" {
+		if after, err = decodeOrderCursor(query.Cursor); err != nil {
+			return nil, "", errors.NewValidationError("Invalid cursor", map[string]string{"cursor": "must be a next_cursor from a previous page"}, http.StatusBadRequest)
+		}
+	}
+
+	// Fetch one extra order to learn whether there is a next page
+	orders, err := s.orderRepo.ListOrdersAfter(ctx, s.db, filter, after, query.Sort == "oldest", query.Limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(orders) <= query.Limit {
+		return orders, "", nil
+	}
+
+	orders = orders[:query.Limit]
+	last := orders[len(orders)-1]
+	return orders, encodeOrderCursor(repository.OrderCursor{CreatedAt: last.CreatedAt, ID: last.ID}), nil
+}
+
+// ListAllOrders returns a page of all orders in the system matching an admin search,
+// with their items, customers and payments, and the number of matching orders
+func (s *OrderService) ListAllOrders(ctx context.Context, query *dto.AdminOrdersQuery) ([]models.Order, int64, error) {
+	filter, err := adminOrderFilter(&query.AdminOrderFilter)
+	if err != nil {
+		return nil, 0, err
+	}
+	sort := repository.OrderSort{Field: query.Sort, Descending: query.Order != "asc"}
+	if sort.Field == "" {
+		sort.Field = repository.OrderSortCreatedAt
+	}
+
+	// Calculate offset
+	offset := (query.Page - 1) * query.PerPage
+
+	// Get total count
+	total, err := s.orderRepo.CountOrders(ctx, s.db, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count orders: %w", err)
+	}
+
+	// Get paginated orders with their items and user
+	orders, err := s.orderRepo.ListOrders(ctx, s.db, filter, sort, offset, query.PerPage)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch orders: %w", err)
+	}
+
+	return orders, total, nil
+}
+
+// adminOrderFilter turns an admin order search into a repository filter
+func adminOrderFilter(query *dto.AdminOrderFilter) (repository.OrderFilter, error) {
+	filter := repository.OrderFilter{
+		OrderID:    query.OrderID,
+		Customer:   strings.TrimSpace(query.Customer),
+		ProductSKU: strings.TrimSpace(query.SKU),
+	}
+	for _, status := range query.Status {
+		filter.Statuses = append(filter.Statuses, models.OrderStatus(status))
+	}
+	for _, status := range query.PaymentStatus {
+		filter.PaymentStatuses = append(filter.PaymentStatuses, models.PaymentStatus(status))
+	}
+
+	var err error
+	if filter.From, err = parseTimeParam(query.From, false); err != nil {
+		return filter, errors.NewValidationError("Invalid date range", map[string]string{"from": err.Error()}, http.StatusBadRequest)
+	}
+	if filter.To, err = parseTimeParam(query.To, true); err != nil {
+		return filter, errors.NewValidationError("Invalid date range", map[string]string{"to": err.Error()}, http.StatusBadRequest)
+	}
+	if filter.MinTotal, err = parseAmountParam(query.MinTotal); err != nil {
+		return filter, errors.NewValidationError("Invalid total range", map[string]string{"min_total": err.Error()}, http.StatusBadRequest)
+	}
+	if filter.MaxTotal, err = parseAmountParam(query.MaxTotal); err != nil {
+		return filter, errors.NewValidationError("Invalid total range", map[string]string{"max_total": err.Error()}, http.StatusBadRequest)
+	}
+	return filter, nil
+}
+
+// parseAmountParam parses a base currency amount
+func parseAmountParam(value string) (*money.Money, error) {
+	if value == "" {
+		return nil, nil
+	}
+	amount, err := money.Parse(value, money.DefaultCurrency)
+	if err != nil {
+		return nil, fmt.Errorf("must be an amount with at most %d decimal places", money.DefaultCurrency.Exponent())
+	}
+	return &amount, nil
+}
+
+// parseTimeParam parses an RFC 3339 time or a YYYY-MM-DD date. A date used as the end of
+// a range covers the whole day, so it is moved to the start of the next day.
+func parseTimeParam(value string, end bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, fmt.Errorf("must be an RFC 3339 time or a YYYY-MM-DD date")
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
+
+// encodeOrderCursor makes an opaque cursor from an order listing position
+func encodeOrderCursor(cursor repository.OrderCursor) string {
+	raw := cursor.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + strconv.FormatUint(uint64(cursor.ID), 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeOrderCursor(value string) (*repository.OrderCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	createdAt, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return nil, fmt.Errorf("malformed cursor")
+	}
+
+	cursor := &repository.OrderCursor{}
+	if cursor.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAt); err != nil {
+		return nil, err
+	}
+	parsedID, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	cursor.ID = uint(parsedID)
+	return cursor, nil
+}
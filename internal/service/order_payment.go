@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"gorm.io/gorm"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/errors"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/payment"
+)
+
+// ChargeOrder charges the order total to a stored payment method and records the
+// payment, whether it succeeded or not. A declined payment is returned as a business
+// error; the order is left as it is for the caller to cancel or retry. The idempotency key
+// must be the same for every attempt at the same charge; an order already charged
+// successfully isn't charged again.
+func (s *OrderService) ChargeOrder(ctx context.Context, order *models.Order, paymentMethodToken, idempotencyKey string) (*models.Payment, error) {
+	existing, err := s.orderRepo.GetPaymentByOrderID(ctx, s.db, order.ID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to get payment: %w", err)
+	}
+	if err == nil && existing.Status == models.PaymentStatusSucceeded {
+		order.PaymentID = &existing.ID
+		order.Payment = existing
+		return existing, nil
+	}
+
+	result, err := s.paymentSvc.ProcessPayment(ctx, payment.PaymentInfo{
+		OrderID:            order.ID,
+		Amount:             order.TotalAmount,
+		Currency:           string(order.Currency),
+		Description:        fmt.Sprintf("Order #%d", order.ID),
+		PaymentMethodToken: paymentMethodToken,
+		IdempotencyKey:     idempotencyKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to process payment: %w", err)
+	}
+
+	record := &models.Payment{
+		OrderID:       order.ID,
+		Amount:        order.TotalAmount,
+		Status:        models.PaymentStatusSucceeded,
+		PaymentMethod: "stored",
+		TransactionID: result.TransactionID,
+	}
+	if !result.Success {
+		record.Status = models.PaymentStatusFailed
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.orderRepo.CreatePayment(ctx, tx, record); err != nil {
+			return fmt.Errorf("failed to record payment: %w", err)
+		}
+		return s.orderRepo.SetPayment(ctx, tx, order.ID, record.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	order.PaymentID = &record.ID
+	order.Payment = record
+
+	if !result.Success {
+		return record, errors.NewBusinessError("Payment failed: "+result.ErrorMessage, errors.ErrCodePaymentFailed, http.StatusPaymentRequired)
+	}
+	return record, nil
+}
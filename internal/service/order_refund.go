@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/logger"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/payment"
+)
+
+// refundRetryDelay leaves a new refund to the request that recorded it before the retries
+// pick it up
+const refundRetryDelay = time.Minute
+
+// refundRetryBatchSize bounds the pending refunds retried in one run
+const refundRetryBatchSize = 100
+
+// requestRefund records a refund of part of the order's payment, if it was captured, in
+// the transaction of the order change that owes it. A zero amount refunds whatever is not
+// refunded or pending yet. It returns nil if there is nothing to refund, which is always the
+// case for orders placed at checkout: checkout doesn't charge, so they have no payment. The
+// refund is sent with sendRefund once the transaction commits.
+func (s *OrderService) requestRefund(ctx context.Context, tx *gorm.DB, order *models.Order, amount money.Money, reason string) (*models.Refund, error) {
+	captured, err := s.orderRepo.GetPaymentByOrderIDForUpdate(ctx, tx, order.ID)
+	if err == gorm.ErrRecordNotFound {
+		// Unpaid order, such as one placed at checkout: lowering its total settles it
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment: %w", err)
+	}
+	if captured.Status != models.PaymentStatusSucceeded {
+		// A declined charge took nothing, and a refunded payment has nothing left
+		return nil, nil
+	}
+
+	// Payment amounts are stored without their currency, which is the order's
+	paid, err := captured.Amount.As(order.Currency)
+	if err != nil {
+		return nil, err
+	}
+	refunded, err := captured.RefundedAmount.As(order.Currency)
+	if err != nil {
+		return nil, err
+	}
+	remaining, err := paid.Sub(refunded)
+	if err != nil {
+		return nil, err
+	}
+	pending, err := s.orderRepo.ListRefundsByPayment(ctx, tx, captured.ID, models.RefundStatusPending)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending refunds: %w", err)
+	}
+	for _, refund := range pending {
+		if remaining, err = remaining.Sub(refund.Amount); err != nil {
+			return nil, err
+		}
+	}
+
+	if amount.IsZero() {
+		amount = remaining
+	} else if cmp, err := amount.Cmp(remaining); err != nil {
+		return nil, err
+	} else if cmp > 0 {
+		amount = remaining
+	}
+	if !amount.IsPositive() {
+		return nil, nil
+	}
+
+	refund := &models.Refund{
+		PaymentID: captured.ID,
+		OrderID:   order.ID,
+		Currency:  order.Currency,
+		Amount:    amount,
+		Reason:    reason,
+		Status:    models.RefundStatusPending,
+	}
+	if err := s.orderRepo.CreateRefund(ctx, tx, refund); err != nil {
+		return nil, fmt.Errorf("failed to record refund: %w", err)
+	}
+	return refund, nil
+}
+
+// sendRefund asks the payment provider for a pending refund and records it on the payment
+// once confirmed. Every attempt at a refund carries the same idempotency key, so the
+// provider refunds it once however often it is retried. A failed attempt is counted and
+// the refund left pending.
+func (s *OrderService) sendRefund(ctx context.Context, refund *models.Refund) error {
+	captured, err := s.orderRepo.GetPaymentByOrderID(ctx, s.db, refund.OrderID)
+	if err != nil {
+		return fmt.Errorf("failed to get payment: %w", err)
+	}
+
+	result, err := s.paymentSvc.RefundPayment(ctx, payment.RefundInfo{
+		OrderID:        refund.OrderID,
+		TransactionID:  captured.TransactionID,
+		Amount:         refund.Amount,
+		Currency:       string(refund.Currency),
+		Reason:         refund.Reason,
+		IdempotencyKey: fmt.Sprintf("refund-%d", refund.ID),
+	})
+	if err == nil && !result.Success {
+		err = fmt.Errorf("refund declined: %s", result.ErrorMessage)
+	}
+	if err != nil {
+		if recordErr := s.orderRepo.RecordRefundFailure(ctx, s.db, refund.ID, err.Error()); recordErr != nil {
+			logger.Error(ctx, "Failed to record refund failure",
+				zap.Error(recordErr),
+				zap.Uint("refund_id", refund.ID))
+		}
+		return fmt.Errorf("failed to refund payment: %w", err)
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		captured, err := s.orderRepo.GetPaymentByOrderIDForUpdate(ctx, tx, refund.OrderID)
+		if err != nil {
+			return fmt.Errorf("failed to get payment: %w", err)
+		}
+
+		now := time.Now()
+		completed, err := s.orderRepo.CompleteRefund(ctx, tx, refund.ID, result.TransactionID, now)
+		if err != nil {
+			return fmt.Errorf("failed to complete refund: %w", err)
+		}
+		if !completed {
+			// Another attempt already recorded it
+			return nil
+		}
+
+		paid, err := captured.Amount.As(refund.Currency)
+		if err != nil {
+			return err
+		}
+		refunded, err := captured.RefundedAmount.As(refund.Currency)
+		if err != nil {
+			return err
+		}
+		if captured.RefundedAmount, err = refunded.Add(refund.Amount); err != nil {
+			return err
+		}
+		if captured.RefundedAmount.Equal(paid) {
+			captured.Status = models.PaymentStatusRefunded
+		}
+		captured.RefundID = result.TransactionID
+		captured.RefundedAt = &now
+		if err := s.orderRepo.UpdatePayment(ctx, tx, captured); err != nil {
+			return fmt.Errorf("failed to update payment: %w", err)
+		}
+		return nil
+	})
+}
+
+// RetryPendingRefunds sends the refunds that failed, or whose request stopped before
+// sending them, and returns how many went through and how many failed again
+func (s *OrderService) RetryPendingRefunds(ctx context.Context, now time.Time) (refunded, failed int, err error) {
+	refunds, err := s.orderRepo.ListPendingRefunds(ctx, s.db, now.Add(-refundRetryDelay), refundRetryBatchSize)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to list pending refunds: %w", err)
+	}
+
+	for i := range refunds {
+		if err := s.sendRefund(ctx, &refunds[i]); err != nil {
+			logger.Error(ctx, "Failed to retry refund",
+				zap.Error(err),
+				zap.Uint("refund_id", refunds[i].ID),
+				zap.Uint("order_id", refunds[i].OrderID),
+				zap.Int("attempts", refunds[i].Attempts+1))
+			failed++
+			continue
+		}
+		refunded++
+	}
+	return refunded, failed, nil
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/api/dto"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/errors"
+)
+
+// Reorder places a new order, or fills the user's cart, with the items of one of their
+// past orders at current prices and in the original order's currency. Discontinued and
+// out of stock products are skipped and items with too little stock are reduced; the
+// response reports this and the price change of every item since the original order.
+func (s *OrderService) Reorder(ctx context.Context, orderID, userID uint, toCart bool) (*dto.ReorderResponse, error) {
+	original, err := s.orderRepo.GetOrderByID(ctx, s.db, orderID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewBusinessError("Order not found", errors.ErrCodeResourceNotFound, http.StatusNotFound)
+		}
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+	if original.UserID != userID {
+		return nil, errors.NewBusinessError("Order does not belong to user", "UNAUTHORIZED_ACCESS", http.StatusForbidden)
+	}
+
+	resp := &dto.ReorderResponse{Currency: string(original.Currency)}
+	items := make([]OrderItemInput, 0, len(original.OrderItems))
+	products := make([]*models.Product, 0, len(original.OrderItems))
+	for _, item := range original.OrderItems {
+		if item.Quantity == 0 {
+			continue // Removed from the original order
+		}
+
+		result, product, err := s.reorderItem(ctx, item)
+		if err != nil {
+			return nil, err
+		}
+		resp.Items = append(resp.Items, result)
+		if result.ReorderedQuantity > 0 {
+			items = append(items, OrderItemInput{ProductID: item.ProductID, Quantity: result.ReorderedQuantity})
+			products = append(products, product)
+		}
+	}
+	if len(items) == 0 {
+		return nil, errors.NewBusinessError("None of the order's items can be reordered", "NOTHING_TO_REORDER", http.StatusConflict)
+	}
+
+	// Compare the current prices with what was paid
+	quotes, _, err := s.currencySvc.PriceProducts(ctx, s.db, original.Currency, products)
+	if err != nil {
+		return nil, err
+	}
+	for i := range resp.Items {
+		result := &resp.Items[i]
+		quote, ok := quotes[result.ProductID]
+		if !ok {
+			continue
+		}
+		difference, err := quote.Price.Sub(result.OriginalPrice)
+		if err != nil {
+			return nil, err
+		}
+		result.CurrentPrice = &quote.Price
+		result.PriceDifference = &difference
+	}
+
+	if toCart {
+		if err := s.cartSvc.AddItems(ctx, userID, items); err != nil {
+			return nil, err
+		}
+		if resp.Cart, err = s.cartSvc.GetCart(ctx, userID, string(original.Currency)); err != nil {
+			return nil, err
+		}
+		return resp, nil
+	}
+
+	order, err := s.CreateOrder(ctx, userID, CreateOrderInput{
+		Items:           items,
+		ShippingAddress: original.ShippingAddress,
+		Currency:        string(original.Currency),
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp.Order = dto.OrderToResponse(order)
+	return resp, nil
+}
+
+// reorderItem decides how much of an item of a past order can be ordered again
+func (s *OrderService) reorderItem(ctx context.Context, item models.OrderItem) (dto.ReorderItemResult, *models.Product, error) {
+	result := dto.ReorderItemResult{
+		ProductID:     item.ProductID,
+		Quantity:      item.Quantity,
+		Status:        dto.ReorderItemSkipped,
+		OriginalPrice: item.Price,
+	}
+
+	product, err := s.productRepo.FindByID(ctx, item.ProductID)
+	if err != nil {
+		return result, nil, fmt.Errorf("failed to get product: %w", err)
+	}
+	if product == nil || product.Discontinued {
+		result.Reason = dto.ReorderReasonDiscontinued
+		return result, nil, nil
+	}
+	result.Name = product.Name
+
+	// Products that accept backorders or pre-orders can be ordered beyond the stock on hand
+	if _, allowed := product.BackorderAllowance(time.Now()); allowed {
+		result.Status, result.ReorderedQuantity = dto.ReorderItemAdded, item.Quantity
+		return result, product, nil
+	}
+
+	inventories, err := s.inventoryRepo.ListByProduct(ctx, s.db, item.ProductID)
+	if err != nil {
+		return result, nil, fmt.Errorf("failed to get inventory: %w", err)
+	}
+	available := 0
+	for _, inventory := range activeInventories(inventories) {
+		available += max(inventory.Quantity, 0)
+	}
+
+	switch {
+	case available == 0:
+		result.Reason = dto.ReorderReasonOutOfStock
+		return result, nil, nil
+	case available < item.Quantity:
+		result.Status, result.Reason = dto.ReorderItemReduced, dto.ReorderReasonInsufficientStock
+		result.ReorderedQuantity = available
+	default:
+		result.Status, result.ReorderedQuantity = dto.ReorderItemAdded, item.Quantity
+	}
+	return result, product, nil
+}
+
+// CheckoutCart places an order for the items in the user's cart and empties the cart in
+// the same transaction
+func (s *OrderService) CheckoutCart(ctx context.Context, userID uint, input CreateOrderInput) (*models.Order, error) {
+	input.fromCart = true
+	return s.CreateOrder(ctx, userID, input)
+}
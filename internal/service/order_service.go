@@ -4,11 +4,14 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
 	"github.com/Ahmed1monm/backend-golang-task-2025/internal/repository"
 	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/errors"
 	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/logger"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/payment"
 	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/websocket"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
@@ -19,8 +22,14 @@ type OrderService struct {
 	orderRepo       repository.OrderRepository
 	inventoryRepo   repository.InventoryRepository
 	productRepo     repository.ProductRepository
+	inventorySvc    InventoryService
+	currencySvc     CurrencyService
+	allocator       AllocationStrategy
 	notificationSvc NotificationService
+	paymentSvc      payment.Service
+	cartSvc         CartService
 	wsManager       *websocket.Manager
+	dashboardSvc    DashboardService
 }
 
 func NewOrderService(
@@ -28,51 +37,52 @@ func NewOrderService(
 	orderRepo repository.OrderRepository,
 	inventoryRepo repository.InventoryRepository,
 	productRepo repository.ProductRepository,
+	inventorySvc InventoryService,
+	currencySvc CurrencyService,
+	allocator AllocationStrategy,
 	notificationSvc NotificationService,
+	paymentSvc payment.Service,
+	cartSvc CartService,
 	wsManager *websocket.Manager,
+	dashboardSvc DashboardService,
 ) *OrderService {
 	return &OrderService{
 		db:              db,
 		orderRepo:       orderRepo,
 		inventoryRepo:   inventoryRepo,
 		productRepo:     productRepo,
+		inventorySvc:    inventorySvc,
+		currencySvc:     currencySvc,
+		allocator:       allocator,
 		notificationSvc: notificationSvc,
+		paymentSvc:      paymentSvc,
+		cartSvc:         cartSvc,
 		wsManager:       wsManager,
+		dashboardSvc:    dashboardSvc,
 	}
 }
 
-// ListAllOrders returns a paginated list of all orders in the system
-func (s *OrderService) ListAllOrders(ctx context.Context, page, perPage int) ([]models.Order, int64, error) {
-	// Calculate offset
-	offset := (page - 1) * perPage
-
-	// Get total count
-	total, err := s.orderRepo.CountOrders(ctx, s.db)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count orders: %w", err)
-	}
-
-	// Get paginated orders with their items and user
-	orders, err := s.orderRepo.ListOrders(ctx, s.db, offset, perPage)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to fetch orders: %w", err)
+// UpdateOrderStatus updates the status of an order. Cancellations go through CancelOrder
+// with the given reason.
+func (s *OrderService) UpdateOrderStatus(ctx context.Context, orderID uint, actorID uint, status models.OrderStatus, reason string) (*models.Order, error) {
+	if status == models.OrderStatusCancelled {
+		return s.CancelOrder(ctx, orderID, CancelOrderInput{ActorID: actorID, Reason: reason})
 	}
 
-	return orders, total, nil
-}
-
-// UpdateOrderStatus updates the status of an order
-func (s *OrderService) UpdateOrderStatus(ctx context.Context, orderID uint, status models.OrderStatus) (*models.Order, error) {
 	// Start transaction
-	tx := s.db.Begin()
+	tx := s.db.WithContext(ctx).Begin()
 	if tx.Error != nil {
 		return nil, fmt.Errorf("failed to start transaction: %w", tx.Error)
 	}
 	defer tx.Rollback()
 
-	// Get order
-	order, err := s.orderRepo.GetOrderByID(ctx, tx, orderID)
+	// Lock the order so a concurrent cancellation or adjustment can't change it between the
+	// transition check and the stock movements below
+	order, err := s.orderRepo.GetOrderByIDForUpdate(ctx, tx, orderID)
 	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewBusinessError("Order not found", errors.ErrCodeResourceNotFound, http.StatusNotFound)
+		}
 		return nil, fmt.Errorf("failed to get order: %w", err)
 	}
 
@@ -86,16 +96,43 @@ func (s *OrderService) UpdateOrderStatus(ctx context.Context, orderID uint, stat
 	}
 
 	// Update status
+	previous := order.Status
 	order.Status = status
+	if status == models.OrderStatusDelivered {
+		now := time.Now()
+		order.DeliveredAt = &now
+	}
 	if err := s.orderRepo.Update(ctx, tx, order); err != nil {
 		return nil, fmt.Errorf("failed to update order: %w", err)
 	}
 
+	// Reserved stock leaves the allocated warehouses once the order ships
+	if status == models.OrderStatusShipped {
+		for _, item := range order.OrderItems {
+			for _, allocation := range item.Allocations {
+				if _, err := s.inventorySvc.ApplyMovement(ctx, tx, &models.StockMovement{
+					ProductID:     item.ProductID,
+					WarehouseID:   allocation.WarehouseID,
+					Type:          models.StockMovementSale,
+					ReservedDelta: -allocation.Quantity,
+					ReferenceType: models.StockReferenceOrder,
+					ReferenceID:   &order.ID,
+					ActorID:       &actorID,
+				}); err != nil {
+					return nil, fmt.Errorf("failed to record sale: %w", err)
+				}
+			}
+		}
+	}
+
 	// Commit transaction
 	if err := tx.Commit().Error; err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
+	s.dashboardSvc.OrderStatusChanged(ctx, order, previous)
+	s.publishOrderEvent(websocket.EventOrderStatusChanged, order)
+
 	return order, nil
 }
 
@@ -103,11 +140,12 @@ func (s *OrderService) UpdateOrderStatus(ctx context.Context, orderID uint, stat
 func isValidStatusTransition(current, new models.OrderStatus) bool {
 	// Define valid transitions
 	validTransitions := map[models.OrderStatus][]models.OrderStatus{
-		models.OrderStatusPending:    {models.OrderStatusProcessing, models.OrderStatusCancelled},
-		models.OrderStatusProcessing: {models.OrderStatusShipped, models.OrderStatusCancelled},
-		models.OrderStatusShipped:    {models.OrderStatusDelivered},
-		models.OrderStatusDelivered:  {},
-		models.OrderStatusCancelled:  {},
+		models.OrderStatusPending:     {models.OrderStatusProcessing, models.OrderStatusCancelled},
+		models.OrderStatusProcessing:  {models.OrderStatusShipped, models.OrderStatusCancelled},
+		models.OrderStatusBackordered: {models.OrderStatusCancelled},
+		models.OrderStatusShipped:     {models.OrderStatusDelivered},
+		models.OrderStatusDelivered:   {},
+		models.OrderStatusCancelled:   {},
 	}
 
 	// Check if transition is valid
@@ -126,10 +164,26 @@ type orderResult struct {
 	Success bool
 }
 
-func (s *OrderService) CreateOrder(ctx context.Context, userID uint, items []struct {
+// OrderItemInput is a product and quantity requested for an order
+type OrderItemInput struct {
 	ProductID uint
 	Quantity  int
-}) (*models.Order, error) {
+}
+
+// CreateOrderInput holds everything needed to place an order
+type CreateOrderInput struct {
+	Items           []OrderItemInput
+	ShippingAddress models.Address
+	Currency        string // Requested currency code; the user's preference or the base currency if empty
+	fromCart        bool   // Order the items in the user's cart instead, emptying it with the order
+}
+
+func (s *OrderService) CreateOrder(ctx context.Context, userID uint, input CreateOrderInput) (*models.Order, error) {
+	currency, err := s.currencySvc.ResolveCurrency(ctx, userID, input.Currency)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create result channel with buffer to avoid goroutine leak
 	resultChan := make(chan orderResult, 1)
 
@@ -145,19 +199,40 @@ func (s *OrderService) CreateOrder(ctx context.Context, userID uint, items []str
 		}
 		defer tx.Rollback()
 
+		// Take the cart's items under its lock, so items added during checkout wait for it
+		// and stay in the cart rather than being cleared without being ordered
+		if input.fromCart {
+			items, err := s.cartSvc.TakeItems(ctx, tx, userID)
+			if err != nil {
+				resultChan <- orderResult{Error: err}
+				return
+			}
+			if len(items) == 0 {
+				resultChan <- orderResult{Error: errors.NewValidationError(
+					"Cart is empty",
+					map[string]string{"items": "at least one item is required"},
+					400,
+				)}
+				return
+			}
+			input.Items = items
+		}
+
 		// Create order with pending status
 		order := &models.Order{
-			UserID: userID,
-			Status: models.OrderStatusPending,
+			UserID:          userID,
+			Status:          models.OrderStatusPending,
+			ShippingAddress: input.ShippingAddress,
+			Currency:        currency,
 		}
 
-		// Calculate total and validate inventory for each item
-		var totalAmount float64
-		orderItems := make([]models.OrderItem, 0, len(items))
+		// Allocate inventory for each item
+		orderItems := make([]models.OrderItem, 0, len(input.Items))
+		products := make([]*models.Product, 0, len(input.Items))
 
-		for _, item := range items {
+		for _, item := range input.Items {
 			// Get product with lock
-			product, err := s.orderRepo.GetProductByID(ctx, tx, item.ProductID)
+			product, err := s.orderRepo.GetProductByIDForUpdate(ctx, tx, item.ProductID)
 			if err != nil {
 				if err == gorm.ErrRecordNotFound {
 					resultChan <- orderResult{Error: errors.NewValidationError(
@@ -170,52 +245,93 @@ func (s *OrderService) CreateOrder(ctx context.Context, userID uint, items []str
 				resultChan <- orderResult{Error: err}
 				return
 			}
+			if product.Discontinued {
+				resultChan <- orderResult{Error: errors.NewValidationError(
+					fmt.Sprintf("Product with ID %d is discontinued", item.ProductID),
+					map[string]string{"product_id": "product is discontinued"},
+					400,
+				)}
+				return
+			}
 
-			// Get and lock inventory
-			inventory, err := s.inventoryRepo.GetForUpdate(ctx, tx, item.ProductID)
+			// Get and lock inventory in every warehouse
+			inventories, err := s.inventoryRepo.ListByProductForUpdate(ctx, tx, item.ProductID)
 			if err != nil {
 				resultChan <- orderResult{Error: err}
 				return
 			}
 
-			// Check if enough stock
-			if inventory.Quantity < item.Quantity {
-				resultChan <- orderResult{Error: errors.NewValidationError(
-					fmt.Sprintf("Insufficient stock for product %d", item.ProductID),
-					map[string]string{"quantity": "insufficient stock"},
-					400,
-				)}
-				return
+			// Pick warehouses with the configured strategy
+			var backordered int
+			allocations, err := s.allocator.Allocate(input.ShippingAddress, item.Quantity, inventories)
+			if err == ErrAllocationFailed {
+				// Fall back to the product's backorder or pre-order policy
+				allocations, backordered, err = s.allocateWithBackorder(ctx, tx, product, input.ShippingAddress, item.Quantity, inventories)
 			}
-
-			// Update inventory
-			inventory.Quantity -= item.Quantity
-			inventory.Reserved += item.Quantity
-			if err := s.inventoryRepo.Update(ctx, tx, inventory); err != nil {
+			if err != nil {
 				resultChan <- orderResult{Error: err}
 				return
 			}
 
 			// Create order item
 			orderItems = append(orderItems, models.OrderItem{
-				ProductID: item.ProductID,
-				Quantity:  item.Quantity,
-				Price:     product.Price,
+				ProductID:           item.ProductID,
+				Quantity:            item.Quantity,
+				BackorderedQuantity: backordered,
+				Allocations:         allocations,
 			})
-			totalAmount += float64(item.Quantity) * product.Price
+			products = append(products, product)
 		}
-
-		order.TotalAmount = totalAmount
 		order.OrderItems = orderItems
 
+		// Price the items at their current, possibly scheduled, price in the order currency
+		// and record the rate in effect
+		quotes, rate, err := s.currencySvc.PriceProducts(ctx, tx, currency, products)
+		if err != nil {
+			resultChan <- orderResult{Error: err}
+			return
+		}
+		order.ExchangeRate = rate
+		if err := priceOrder(order, quotes); err != nil {
+			resultChan <- orderResult{Error: err}
+			return
+		}
+
 		// Create the order
 		if err := s.orderRepo.CreateOrder(ctx, tx, order); err != nil {
 			resultChan <- orderResult{Error: err}
 			return
 		}
 
-		// Process mock payment (simulated success)
+		// Reserve stock in the allocated warehouses against the created order
+		for _, item := range order.OrderItems {
+			for _, allocation := range item.Allocations {
+				if _, err := s.inventorySvc.ApplyMovement(ctx, tx, &models.StockMovement{
+					ProductID:     item.ProductID,
+					WarehouseID:   allocation.WarehouseID,
+					Type:          models.StockMovementReservation,
+					QuantityDelta: -allocation.Quantity,
+					ReservedDelta: allocation.Quantity,
+					ReferenceType: models.StockReferenceOrder,
+					ReferenceID:   &order.ID,
+					ActorID:       &userID,
+				}); err != nil {
+					resultChan <- orderResult{Error: err}
+					return
+				}
+			}
+		}
+
+		// Checkout takes no payment details, so nothing is charged and no payment is recorded;
+		// only subscription renewals charge a stored payment method, through ChargeOrder.
+		// Orders waiting for stock stay backordered.
 		order.Status = models.OrderStatusProcessing
+		for _, item := range order.OrderItems {
+			if item.BackorderedQuantity > 0 {
+				order.Status = models.OrderStatusBackordered
+				break
+			}
+		}
 		if err := s.orderRepo.Update(ctx, tx, order); err != nil {
 			resultChan <- orderResult{Error: err}
 			return
@@ -230,6 +346,7 @@ func (s *OrderService) CreateOrder(ctx context.Context, userID uint, items []str
 					OrderID:     order.ID,
 					Status:      string(order.Status),
 					TotalAmount: order.TotalAmount,
+					Currency:    string(order.Currency),
 				},
 			}
 
@@ -257,11 +374,20 @@ func (s *OrderService) CreateOrder(ctx context.Context, userID uint, items []str
 					continue
 				}
 
+				stockLevels, err := s.inventoryRepo.GetStockLevels(ctx, s.db, []uint{product.ID})
+				if err != nil {
+					logger.Error(ctx, "Failed to get stock level for inventory notification",
+						zap.Error(err),
+						zap.Uint("product_id", item.ProductID))
+					continue
+				}
+				stockLevel := stockLevels[product.ID]
+
 				inventoryEvent := &websocket.Event{
 					Type: websocket.EventInventoryUpdated,
 					Payload: websocket.InventoryEventPayload{
 						ProductID: product.ID,
-						Quantity:  product.Quantity,
+						Quantity:  stockLevel,
 						Name:      product.Name,
 					},
 				}
@@ -271,7 +397,7 @@ func (s *OrderService) CreateOrder(ctx context.Context, userID uint, items []str
 					userID,
 					models.NotificationTypeInventory,
 					"Inventory Update",
-					fmt.Sprintf("Current inventory for %s: %d units", product.Name, product.Quantity),
+					fmt.Sprintf("Current inventory for %s: %d units", product.Name, stockLevel),
 					inventoryEvent,
 				); err != nil {
 					logger.Error(ctx, "Failed to create inventory notification",
@@ -288,6 +414,12 @@ func (s *OrderService) CreateOrder(ctx context.Context, userID uint, items []str
 			return
 		}
 
+		s.dashboardSvc.OrderCreated(ctx, order)
+		s.publishOrderEvent(websocket.EventOrderCreated, order)
+
+		// Reservations lowered available stock; raise alerts for products running low
+		s.inventorySvc.StockChanged(ctx, orderProductIDs(order)...)
+
 		resultChan <- orderResult{Order: order, Success: true}
 	}()
 
@@ -328,24 +460,6 @@ func (s *OrderService) GetOrderByID(ctx context.Context, orderID uint) (*models.
 	return order, nil
 }
 
-func (s *OrderService) ListOrdersByUserID(ctx context.Context, userID uint) ([]models.Order, error) {
-	var orders []models.Order
-	err := s.db.Transaction(func(tx *gorm.DB) error {
-		var err error
-		orders, err = s.orderRepo.ListOrdersByUserID(ctx, tx, userID)
-		if err != nil {
-			return err
-		}
-		return nil
-	})
-
-	if err != nil {
-		return nil, err
-	}
-
-	return orders, nil
-}
-
 // GetOrderStatus returns the current status of an order and verifies the user has access to it
 func (s *OrderService) GetOrderStatus(ctx context.Context, orderID, userID uint) (models.OrderStatus, error) {
 	var status models.OrderStatus
@@ -383,132 +497,119 @@ func (s *OrderService) GetOrderStatus(ctx context.Context, orderID, userID uint)
 	return status, nil
 }
 
-// CancelOrder cancels an order if it's in a cancellable state and belongs to the given user
-func (s *OrderService) CancelOrder(ctx context.Context, orderID, userID uint) (*models.Order, error) {
-	// Start transaction
-	tx := s.db.Begin()
-	if tx.Error != nil {
-		return nil, fmt.Errorf("failed to start transaction: %w", tx.Error)
+// priceOrder sets the unit prices of the order's items from the quotes and totals the
+// order in both the order currency and the base currency
+func priceOrder(order *models.Order, quotes map[uint]PriceQuote) error {
+	for i := range order.OrderItems {
+		item := &order.OrderItems[i]
+		quote := quotes[item.ProductID]
+		item.Currency = order.Currency
+		item.Price = quote.Price
+		item.BasePrice = quote.BasePrice
+		item.PriceScheduleID = quote.ScheduleID
 	}
-	defer tx.Rollback()
+	return sumOrderTotals(order)
+}
 
-	// Get order
-	order, err := s.orderRepo.GetOrderByID(ctx, tx, orderID)
-	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, errors.NewBusinessError(
-				"Order not found",
-				errors.ErrCodeResourceNotFound,
-				http.StatusNotFound,
-			)
+// sumOrderTotals sets the order totals, in the order and the base currency, from the
+// prices and quantities of its items
+func sumOrderTotals(order *models.Order) error {
+	total := money.Zero(order.Currency)
+	baseTotal := money.Zero(money.DefaultCurrency)
+	for _, item := range order.OrderItems {
+		line, err := item.Price.Mul(int64(item.Quantity))
+		if err != nil {
+			return err
+		}
+		if total, err = total.Add(line); err != nil {
+			return err
+		}
+		baseLine, err := item.BasePrice.Mul(int64(item.Quantity))
+		if err != nil {
+			return err
+		}
+		if baseTotal, err = baseTotal.Add(baseLine); err != nil {
+			return err
 		}
-		return nil, fmt.Errorf("failed to get order: %w", err)
 	}
+	order.TotalAmount = total
+	order.BaseTotalAmount = baseTotal
+	return nil
+}
 
-	// Verify order belongs to user
-	if order.UserID != userID {
-		return nil, errors.NewBusinessError(
-			"Order does not belong to user",
-			"UNAUTHORIZED_ACCESS",
-			http.StatusForbidden,
+// allocateWithBackorder reserves whatever stock is available for an item and puts the rest
+// on backorder, if the product's stock policy allows it. It returns the allocations and
+// the backordered quantity.
+func (s *OrderService) allocateWithBackorder(
+	ctx context.Context,
+	tx *gorm.DB,
+	product *models.Product,
+	destination models.Address,
+	quantity int,
+	inventories []models.Inventory,
+) ([]models.OrderItemAllocation, int, error) {
+	limit, allowed := product.BackorderAllowance(time.Now())
+	if !allowed {
+		return nil, 0, errors.NewValidationError(
+			fmt.Sprintf("Insufficient stock for product %d", product.ID),
+			map[string]string{"quantity": "insufficient stock"},
+			400,
 		)
 	}
 
-	// Check if order can be cancelled
-	if !isValidStatusTransition(order.Status, models.OrderStatusCancelled) {
-		return nil, errors.NewBusinessError(
-			"Order cannot be cancelled",
-			"INVALID_STATUS_TRANSITION",
-			http.StatusBadRequest,
-		)
+	available := 0
+	for _, inventory := range activeInventories(inventories) {
+		available += max(inventory.Quantity, 0)
 	}
+	available = min(available, quantity)
+	backordered := quantity - available
 
-	// Update order status
-	order.Status = models.OrderStatusCancelled
-	if err := s.orderRepo.Update(ctx, tx, order); err != nil {
-		return nil, fmt.Errorf("failed to update order status: %w", err)
+	// The product row is locked, so the outstanding backorders can't change underneath us
+	outstanding, err := s.orderRepo.GetBackorderedQuantity(ctx, tx, product.ID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if outstanding+backordered > limit {
+		return nil, 0, errors.NewValidationError(
+			fmt.Sprintf("Backorder limit reached for product %d", product.ID),
+			map[string]string{"quantity": fmt.Sprintf("only %d units can be backordered", max(limit-outstanding, 0)+available)},
+			400,
+		)
 	}
 
-	// Send order cancellation and inventory update notifications asynchronously
-	go func(orderID, userID uint) {
-		// Send order cancellation notification
-		orderEvent := &websocket.Event{
-			Type: websocket.EventOrderCancelled,
-			Payload: websocket.OrderEventPayload{
-				OrderID:     order.ID,
-				Status:      string(order.Status),
-				TotalAmount: order.TotalAmount,
-			},
-		}
-
-		if err := s.notificationSvc.CreateNotification(
-			ctx,
-			userID,
-			models.NotificationTypeOrder,
-			"Order Cancelled",
-			fmt.Sprintf("Your order #%d has been cancelled.", orderID),
-			orderEvent,
-		); err != nil {
-			logger.Error(ctx, "Failed to create order cancellation notification",
-				zap.Error(err),
-				zap.Uint("order_id", orderID),
-				zap.Uint("user_id", userID))
-		}
-
-		// Send inventory update notification for each product
-		for _, item := range order.OrderItems {
-			product, err := s.productRepo.FindByID(ctx, item.ProductID)
-			if err != nil {
-				logger.Error(ctx, "Failed to get product for inventory notification",
-					zap.Error(err),
-					zap.Uint("product_id", item.ProductID))
-				continue
-			}
-
-			inventoryEvent := &websocket.Event{
-				Type: websocket.EventInventoryUpdated,
-				Payload: websocket.InventoryEventPayload{
-					ProductID: product.ID,
-					Quantity:  product.Quantity,
-					Name:      product.Name,
-				},
-			}
-
-			if err := s.notificationSvc.CreateNotification(
-				ctx,
-				userID,
-				models.NotificationTypeInventory,
-				"Inventory Update",
-				fmt.Sprintf("Updated inventory for %s: %d units available", product.Name, product.Quantity),
-				inventoryEvent,
-			); err != nil {
-				logger.Error(ctx, "Failed to create inventory notification",
-					zap.Error(err),
-					zap.Uint("product_id", item.ProductID),
-					zap.Uint("user_id", userID))
-			}
-		}
-	}(order.ID, userID)
-
-	// Release reserved inventory for all order items
-	for _, item := range order.OrderItems {
-		// Get and lock inventory
-		inventory, err := s.inventoryRepo.GetForUpdate(ctx, tx, item.ProductID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get inventory: %w", err)
-		}
+	if available == 0 {
+		return nil, backordered, nil
+	}
 
-		// Release reserved quantity
-		inventory.Reserved -= item.Quantity
-		if err := s.inventoryRepo.Update(ctx, tx, inventory); err != nil {
-			return nil, fmt.Errorf("failed to update inventory: %w", err)
-		}
+	// Reserve what is on hand, splitting across warehouses if needed
+	allocations, err := splitStrategy{}.Allocate(destination, available, inventories)
+	if err != nil {
+		return nil, 0, err
 	}
+	return allocations, backordered, nil
+}
 
-	// Commit transaction
-	if err := tx.Commit().Error; err != nil {
-		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+// orderProductIDs returns the IDs of the products on an order
+func orderProductIDs(order *models.Order) []uint {
+	ids := make([]uint, len(order.OrderItems))
+	for i, item := range order.OrderItems {
+		ids[i] = item.ProductID
 	}
+	return ids
+}
 
-	return order, nil
+// publishOrderEvent sends an order event to the order's topic and to the admins following
+// every order
+func (s *OrderService) publishOrderEvent(eventType websocket.EventType, order *models.Order) {
+	event := websocket.Event{
+		Type: eventType,
+		Payload: websocket.OrderEventPayload{
+			OrderID:     order.ID,
+			Status:      string(order.Status),
+			TotalAmount: order.TotalAmount,
+			Currency:    string(order.Currency),
+		},
+	}
+	s.wsManager.Publish(websocket.OrderTopic(order.ID), event)
+	s.wsManager.Publish(websocket.TopicAdminOrders, event)
 }
@@ -0,0 +1,358 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/api/dto"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/repository"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/errors"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/logger"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
+)
+
+// EffectivePrice is the base currency price a product sells at once price schedules are applied
+type EffectivePrice struct {
+	Price          money.Money
+	CompareAtPrice money.Money // Price before the sale; zero when not on sale
+	ScheduleID     *uint       // Schedule that sets the price, if any
+}
+
+type PriceScheduleService interface {
+	CreateSchedule(ctx context.Context, productID, actorID uint, req *dto.CreatePriceScheduleRequest) (*dto.PriceScheduleResponse, error)
+	ListSchedules(ctx context.Context, productID uint) ([]dto.PriceScheduleResponse, error)
+	CancelSchedule(ctx context.Context, productID, scheduleID, actorID uint) (*dto.PriceScheduleResponse, error)
+	ListPriceHistory(ctx context.Context, productID uint) ([]dto.PriceHistoryResponse, error)
+	// ResolvePrices returns the effective price of each product at the given time
+	ResolvePrices(ctx context.Context, tx *gorm.DB, products []*models.Product, at time.Time) (map[uint]EffectivePrice, error)
+	// RecordPriceChange adds the product's current effective price to its price history
+	RecordPriceChange(ctx context.Context, tx *gorm.DB, product *models.Product, actorID *uint) error
+	// ApplyDueSchedules starts and ends the schedules whose time has come and returns
+	// the IDs of the products whose price changed
+	ApplyDueSchedules(ctx context.Context, at time.Time) ([]uint, error)
+}
+
+type priceScheduleService struct {
+	db           *gorm.DB
+	scheduleRepo repository.PriceScheduleRepository
+	productRepo  repository.ProductRepository
+}
+
+func NewPriceScheduleService(db *gorm.DB, scheduleRepo repository.PriceScheduleRepository, productRepo repository.ProductRepository) PriceScheduleService {
+	return &priceScheduleService{
+		db:           db,
+		scheduleRepo: scheduleRepo,
+		productRepo:  productRepo,
+	}
+}
+
+func (s *priceScheduleService) CreateSchedule(ctx context.Context, productID, actorID uint, req *dto.CreatePriceScheduleRequest) (*dto.PriceScheduleResponse, error) {
+	schedule := &models.PriceSchedule{
+		ProductID: productID,
+		Price:     req.Price,
+		StartsAt:  req.StartsAt.UTC(),
+		Label:     req.Label,
+		Status:    models.PriceScheduleScheduled,
+		CreatedBy: &actorID,
+	}
+	if req.EndsAt != nil {
+		endsAt := req.EndsAt.UTC()
+		schedule.EndsAt = &endsAt
+	}
+	if req.CompareAtPrice != nil {
+		schedule.CompareAtPrice = *req.CompareAtPrice
+	}
+	if err := validateSchedule(schedule, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.findProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if schedule.IsSale() {
+			overlaps, err := s.scheduleRepo.HasOverlappingSale(ctx, tx, productID, schedule.StartsAt, *schedule.EndsAt)
+			if err != nil {
+				return err
+			}
+			if overlaps {
+				return errors.NewBusinessError("Sale overlaps another sale of the product", "PRICE_SCHEDULE_OVERLAP", http.StatusConflict)
+			}
+		}
+		return s.scheduleRepo.Create(ctx, tx, schedule)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := dto.PriceScheduleToResponse(schedule)
+	return &resp, nil
+}
+
+func (s *priceScheduleService) ListSchedules(ctx context.Context, productID uint) ([]dto.PriceScheduleResponse, error) {
+	if _, err := s.findProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	schedules, err := s.scheduleRepo.ListByProduct(ctx, s.db, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]dto.PriceScheduleResponse, len(schedules))
+	for i := range schedules {
+		resp[i] = dto.PriceScheduleToResponse(&schedules[i])
+	}
+	return resp, nil
+}
+
+func (s *priceScheduleService) CancelSchedule(ctx context.Context, productID, scheduleID, actorID uint) (*dto.PriceScheduleResponse, error) {
+	product, err := s.findProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	var schedule *models.PriceSchedule
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		schedule, err = s.scheduleRepo.FindByIDForUpdate(ctx, tx, scheduleID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.NewBusinessError("Price schedule not found", errors.ErrCodeResourceNotFound, http.StatusNotFound)
+			}
+			return err
+		}
+		if schedule.ProductID != productID {
+			return errors.NewBusinessError("Price schedule not found", errors.ErrCodeResourceNotFound, http.StatusNotFound)
+		}
+		if schedule.Status != models.PriceScheduleScheduled && schedule.Status != models.PriceScheduleActive {
+			return errors.NewBusinessError("Price schedule has already "+string(schedule.Status), "PRICE_SCHEDULE_CLOSED", http.StatusConflict)
+		}
+
+		now := time.Now()
+		inEffect := schedule.InEffect(now)
+		schedule.Status = models.PriceScheduleCancelled
+		if err := s.scheduleRepo.Update(ctx, tx, schedule); err != nil {
+			return err
+		}
+
+		// Cancelling a running sale puts the regular price back right away
+		if !inEffect {
+			return nil
+		}
+		return s.recordPrice(ctx, tx, product, &schedule.ID, &actorID, now)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := dto.PriceScheduleToResponse(schedule)
+	return &resp, nil
+}
+
+func (s *priceScheduleService) ListPriceHistory(ctx context.Context, productID uint) ([]dto.PriceHistoryResponse, error) {
+	if _, err := s.findProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	history, err := s.scheduleRepo.ListHistory(ctx, s.db, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]dto.PriceHistoryResponse, len(history))
+	for i := range history {
+		resp[i] = dto.PriceHistoryToResponse(&history[i])
+	}
+	return resp, nil
+}
+
+func (s *priceScheduleService) ResolvePrices(ctx context.Context, tx *gorm.DB, products []*models.Product, at time.Time) (map[uint]EffectivePrice, error) {
+	ids := make([]uint, len(products))
+	for i, product := range products {
+		ids[i] = product.ID
+	}
+	schedules, err := s.scheduleRepo.ListInEffect(ctx, tx, ids, at)
+	if err != nil {
+		return nil, err
+	}
+
+	// Schedules come oldest first, so the latest planned change wins
+	sales := make(map[uint]*models.PriceSchedule)
+	changes := make(map[uint]*models.PriceSchedule)
+	for i := range schedules {
+		if schedules[i].IsSale() {
+			sales[schedules[i].ProductID] = &schedules[i]
+		} else {
+			changes[schedules[i].ProductID] = &schedules[i]
+		}
+	}
+
+	prices := make(map[uint]EffectivePrice, len(products))
+	for _, product := range products {
+		effective := EffectivePrice{Price: product.Price}
+
+		// A planned change that the scheduler hasn't applied yet is already the regular price
+		if change, ok := changes[product.ID]; ok {
+			effective.Price = change.Price
+			effective.ScheduleID = &change.ID
+		}
+
+		if sale, ok := sales[product.ID]; ok {
+			compareAt := sale.CompareAtPrice
+			if compareAt.IsZero() {
+				compareAt = effective.Price
+			}
+			effective.Price = sale.Price
+			effective.ScheduleID = &sale.ID
+
+			cmp, err := compareAt.Cmp(sale.Price)
+			if err != nil {
+				return nil, err
+			}
+			if cmp > 0 {
+				effective.CompareAtPrice = compareAt
+			}
+		}
+		prices[product.ID] = effective
+	}
+	return prices, nil
+}
+
+func (s *priceScheduleService) RecordPriceChange(ctx context.Context, tx *gorm.DB, product *models.Product, actorID *uint) error {
+	return s.recordPrice(ctx, tx, product, nil, actorID, time.Now())
+}
+
+func (s *priceScheduleService) ApplyDueSchedules(ctx context.Context, at time.Time) ([]uint, error) {
+	ids, err := s.scheduleRepo.ListDue(ctx, s.db, at)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []uint
+	for _, id := range ids {
+		productID, applied, err := s.applySchedule(ctx, id, at)
+		if err != nil {
+			logger.Error(ctx, "Failed to apply price schedule",
+				zap.Error(err),
+				zap.Uint("price_schedule_id", id))
+			continue
+		}
+		if applied {
+			changed = append(changed, productID)
+		}
+	}
+	return changed, nil
+}
+
+// applySchedule moves a due schedule on to its next status and records the resulting price.
+// It reports false if the schedule was already handled, e.g. by another instance.
+func (s *priceScheduleService) applySchedule(ctx context.Context, id uint, at time.Time) (uint, bool, error) {
+	var productID uint
+	applied := false
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		schedule, err := s.scheduleRepo.FindByIDForUpdate(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		productID = schedule.ProductID
+
+		product, err := s.findProduct(ctx, schedule.ProductID)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case schedule.Status == models.PriceScheduleScheduled && !at.Before(schedule.StartsAt) && !schedule.IsSale():
+			// A planned change becomes the product's regular price
+			if err := s.scheduleRepo.UpdateProductPrice(ctx, tx, product.ID, schedule.Price); err != nil {
+				return err
+			}
+			product.Price = schedule.Price
+			schedule.Status = models.PriceScheduleEnded
+		case schedule.Status == models.PriceScheduleScheduled && !at.Before(schedule.StartsAt):
+			schedule.Status = models.PriceScheduleActive
+			if !at.Before(*schedule.EndsAt) {
+				// The whole sale passed while the scheduler wasn't running
+				schedule.Status = models.PriceScheduleEnded
+			}
+		case schedule.Status == models.PriceScheduleActive && schedule.IsSale() && !at.Before(*schedule.EndsAt):
+			schedule.Status = models.PriceScheduleEnded
+		default:
+			return nil
+		}
+
+		if err := s.scheduleRepo.Update(ctx, tx, schedule); err != nil {
+			return err
+		}
+		applied = true
+		return s.recordPrice(ctx, tx, product, &schedule.ID, nil, at)
+	})
+	if err != nil {
+		return 0, false, err
+	}
+	return productID, applied, nil
+}
+
+// recordPrice adds the product's effective price at the given time to its price history.
+// scheduleID is the schedule whose start, end or cancellation caused the change, if any.
+func (s *priceScheduleService) recordPrice(ctx context.Context, tx *gorm.DB, product *models.Product, scheduleID, actorID *uint, at time.Time) error {
+	prices, err := s.ResolvePrices(ctx, tx, []*models.Product{product}, at)
+	if err != nil {
+		return err
+	}
+	effective := prices[product.ID]
+
+	source := models.PriceChangeManual
+	if scheduleID != nil {
+		source = models.PriceChangeSchedule
+	}
+	return s.scheduleRepo.CreateHistory(ctx, tx, &models.ProductPriceHistory{
+		ProductID:       product.ID,
+		Price:           effective.Price,
+		CompareAtPrice:  effective.CompareAtPrice,
+		Source:          source,
+		PriceScheduleID: scheduleID,
+		ActorID:         actorID,
+		EffectiveAt:     at,
+	})
+}
+
+func (s *priceScheduleService) findProduct(ctx context.Context, productID uint) (*models.Product, error) {
+	product, err := s.productRepo.FindByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if product == nil {
+		return nil, errors.NewBusinessError("Product not found", errors.ErrCodeResourceNotFound, http.StatusNotFound)
+	}
+	return product, nil
+}
+
+// validateSchedule checks a new schedule's window and prices
+func validateSchedule(schedule *models.PriceSchedule, now time.Time) error {
+	if schedule.EndsAt != nil {
+		if !schedule.EndsAt.After(schedule.StartsAt) {
+			return errors.NewValidationError("Invalid price schedule", map[string]string{"ends_at": "must be after starts_at"}, http.StatusBadRequest)
+		}
+		if !schedule.EndsAt.After(now) {
+			return errors.NewValidationError("Invalid price schedule", map[string]string{"ends_at": "must be in the future"}, http.StatusBadRequest)
+		}
+	}
+
+	if schedule.CompareAtPrice.IsZero() {
+		return nil
+	}
+	if !schedule.IsSale() {
+		return errors.NewValidationError("Invalid price schedule", map[string]string{"compare_at_price": "only allowed for sales with ends_at"}, http.StatusBadRequest)
+	}
+	if cmp, err := schedule.CompareAtPrice.Cmp(schedule.Price); err != nil || cmp <= 0 {
+		return errors.NewValidationError("Invalid price schedule", map[string]string{"compare_at_price": "must be greater than price"}, http.StatusBadRequest)
+	}
+	return nil
+}
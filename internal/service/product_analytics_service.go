@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/api/dto"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/repository"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/errors"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/logger"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/redis"
+)
+
+// ProductAnalyticsConfig controls when a product counts as a slow mover
+type ProductAnalyticsConfig struct {
+	SlowMoverDays int // Days of cover beyond which a product in stock is a slow mover
+}
+
+// productAnalyticsLockTTL bounds how long a crashed instance keeps others from rolling up
+// product sales
+const productAnalyticsLockTTL = 30 * time.Minute
+
+// defaultProductAnalyticsDays is how many completed days product figures cover unless
+// asked otherwise
+const defaultProductAnalyticsDays = 30
+
+type ProductAnalyticsService interface {
+	// ListProductPerformance ranks the products by their sales over the last completed days
+	ListProductPerformance(ctx context.Context, query *dto.ProductAnalyticsListQuery) (*dto.PaginatedProductPerformanceResponse, error)
+	// GetProductAnalytics returns a product's sales over the last completed days, by period
+	GetProductAnalytics(ctx context.Context, productID uint, query *dto.ProductAnalyticsQuery) (*dto.ProductAnalyticsResponse, error)
+
+	// RollupProductSales rolls up the product sales of the completed days whose orders
+	// changed since they were last rolled up, returning how many days were. It returns
+	// false if another instance is already at it.
+	RollupProductSales(ctx context.Context, now time.Time) (int, bool, error)
+}
+
+type productAnalyticsService struct {
+	db            *gorm.DB
+	config        ProductAnalyticsConfig
+	analyticsRepo repository.AnalyticsRepository
+	currencySvc   CurrencyService
+	redisService  redis.Service
+}
+
+func NewProductAnalyticsService(
+	db *gorm.DB,
+	config ProductAnalyticsConfig,
+	analyticsRepo repository.AnalyticsRepository,
+	currencySvc CurrencyService,
+	redisService redis.Service,
+) ProductAnalyticsService {
+	if config.SlowMoverDays <= 0 {
+		config.SlowMoverDays = 90
+	}
+	return &productAnalyticsService{
+		db:            db,
+		config:        config,
+		analyticsRepo: analyticsRepo,
+		currencySvc:   currencySvc,
+		redisService:  redisService,
+	}
+}
+
+func (s *productAnalyticsService) ListProductPerformance(ctx context.Context, query *dto.ProductAnalyticsListQuery) (*dto.PaginatedProductPerformanceResponse, error) {
+	from, to, days := productAnalyticsRange(query.Days, time.Now())
+	filter := repository.ProductPerformanceFilter{From: from, To: to}
+	sort := repository.ProductPerformanceSort{Field: query.Sort, Descending: query.Order != "asc"}
+	if query.SlowMovers {
+		filter.SlowMoverDays = s.config.SlowMoverDays
+		if sort.Field == "" {
+			sort.Field = repository.ProductSortDaysOfCover
+		}
+	}
+	if sort.Field == "" {
+		sort.Field = repository.ProductSortUnitsSold
+	}
+
+	offset := (query.Page - 1) * query.PerPage
+	products, total, err := s.analyticsRepo.ListProductPerformance(ctx, s.db, filter, sort, offset, query.PerPage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list product performance: %w", err)
+	}
+
+	currency, convert, err := s.reportingConverter(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.PaginatedProductPerformanceResponse{
+		Products:      make([]dto.ProductPerformanceResponse, len(products)),
+		From:          from,
+		To:            to,
+		Currency:      string(currency),
+		SlowMoverDays: s.config.SlowMoverDays,
+		Total:         total,
+		Page:          query.Page,
+		PerPage:       query.PerPage,
+		TotalPages:    (int(total) + query.PerPage - 1) / query.PerPage,
+	}
+	for i := range products {
+		if resp.Products[i], err = s.performanceResponse(&products[i], days, convert); err != nil {
+			return nil, err
+		}
+		resp.Products[i].Rank = offset + i + 1
+	}
+	return resp, nil
+}
+
+func (s *productAnalyticsService) GetProductAnalytics(ctx context.Context, productID uint, query *dto.ProductAnalyticsQuery) (*dto.ProductAnalyticsResponse, error) {
+	from, to, days := productAnalyticsRange(query.Days, time.Now())
+	granularity := query.Granularity
+	if granularity == "" {
+		granularity = ReportGranularityDay
+	}
+
+	products, _, err := s.analyticsRepo.ListProductPerformance(ctx, s.db, repository.ProductPerformanceFilter{
+		From:      from,
+		To:        to,
+		ProductID: productID,
+	}, repository.ProductPerformanceSort{}, 0, 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product performance: %w", err)
+	}
+	if len(products) == 0 {
+		return nil, errors.NewBusinessError("Product not found", errors.ErrCodeResourceNotFound, http.StatusNotFound)
+	}
+	sales, err := s.analyticsRepo.ListProductSales(ctx, s.db, productID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list product sales: %w", err)
+	}
+
+	currency, convert, err := s.reportingConverter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	performance, err := s.performanceResponse(&products[0], days, convert)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.ProductAnalyticsResponse{
+		ProductPerformanceResponse: performance,
+		From:                       from,
+		To:                         to,
+		Granularity:                granularity,
+		Currency:                   string(currency),
+		SlowMoverDays:              s.config.SlowMoverDays,
+		Periods:                    []dto.ProductSalesPeriod{},
+	}
+
+	// Sales rows are sorted by day; days without orders of the product have no row
+	next := 0
+	for start := from; !start.After(to); {
+		end := reportPeriodEnd(reportPeriodStart(start, granularity), granularity)
+		if end.After(to) {
+			end = to
+		}
+
+		period := dto.ProductSalesPeriod{From: start, To: end}
+		revenue, cancellations := money.Zero(money.DefaultCurrency), money.Zero(money.DefaultCurrency)
+		for ; next < len(sales) && !reportDay(sales[next].Date).After(end); next++ {
+			day := sales[next]
+			period.Orders += day.Orders
+			period.UnitsOrdered += day.UnitsOrdered
+			period.UnitsCancelled += day.UnitsCancelled
+			period.UnitsSold += day.UnitsSold
+			if revenue, err = revenue.Add(day.Revenue); err != nil {
+				return nil, err
+			}
+			if cancellations, err = cancellations.Add(day.Cancellations); err != nil {
+				return nil, err
+			}
+		}
+		if period.Revenue, err = convert(revenue); err != nil {
+			return nil, err
+		}
+		if period.Cancellations, err = convert(cancellations); err != nil {
+			return nil, err
+		}
+		resp.Periods = append(resp.Periods, period)
+
+		start = end.AddDate(0, 0, 1)
+	}
+	return resp, nil
+}
+
+func (s *productAnalyticsService) RollupProductSales(ctx context.Context, now time.Time) (int, bool, error) {
+	release, acquired, err := s.redisService.Lock(ctx, "lock:analytics:products", productAnalyticsLockTTL)
+	switch {
+	case err != nil:
+		logger.Error(ctx, "Rolling up product sales without a lock", zap.Error(err))
+	case !acquired:
+		return 0, false, nil
+	default:
+		defer release()
+	}
+
+	days, err := s.analyticsRepo.GetStaleProductSalesDays(ctx, s.db, reportDay(now))
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to find days to roll up: %w", err)
+	}
+	for i, day := range days {
+		err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			return s.analyticsRepo.RollupProductSales(ctx, tx, day)
+		})
+		if err != nil {
+			return i, true, fmt.Errorf("failed to roll up product sales of %s: %w", day.Format(time.DateOnly), err)
+		}
+	}
+	return len(days), true, nil
+}
+
+// reportingConverter returns the reporting currency and a function converting base
+// currency amounts to it at the current rate
+func (s *productAnalyticsService) reportingConverter(ctx context.Context) (money.Currency, func(money.Money) (money.Money, error), error) {
+	currency := s.currencySvc.ReportingCurrency()
+	rate, err := s.currencySvc.Rate(ctx, s.db, currency, time.Now())
+	if err != nil {
+		return "", nil, err
+	}
+	if rate.IsZero() {
+		logger.Error(ctx, "No exchange rate for reporting currency, showing product analytics in base currency",
+			zap.String("currency", string(currency)))
+		currency, rate = money.DefaultCurrency, money.IdentityRate()
+	}
+	return currency, func(amount money.Money) (money.Money, error) {
+		return amount.Convert(rate, currency, money.RoundHalfEven)
+	}, nil
+}
+
+// performanceResponse works out a product's sales rates and stock cover over a number of days
+func (s *productAnalyticsService) performanceResponse(
+	product *repository.ProductPerformance,
+	days int,
+	convert func(money.Money) (money.Money, error),
+) (dto.ProductPerformanceResponse, error) {
+	resp := dto.ProductPerformanceResponse{
+		ProductID:         product.ProductID,
+		Name:              product.Name,
+		SKU:               product.SKU,
+		Discontinued:      product.Discontinued,
+		Orders:            product.Orders,
+		UnitsOrdered:      product.UnitsOrdered,
+		UnitsCancelled:    product.UnitsCancelled,
+		UnitsSold:         product.UnitsSold,
+		CancellationRate:  percentage(product.UnitsCancelled, product.UnitsOrdered),
+		Stock:             product.Stock,
+		AverageDailySales: roundPercent(float64(product.UnitsSold) / float64(days)),
+		SellThroughRate:   percentage(product.UnitsSold, product.UnitsSold+product.Stock),
+	}
+	if product.UnitsSold > 0 {
+		cover := roundPercent(float64(product.Stock) * float64(days) / float64(product.UnitsSold))
+		resp.DaysOfCover = &cover
+	}
+	resp.SlowMover = product.Stock > 0 && float64(product.Stock)*float64(days) > float64(product.UnitsSold)*float64(s.config.SlowMoverDays)
+
+	var err error
+	if resp.Revenue, err = convert(product.Revenue); err != nil {
+		return dto.ProductPerformanceResponse{}, err
+	}
+	if resp.Cancellations, err = convert(product.Cancellations); err != nil {
+		return dto.ProductPerformanceResponse{}, err
+	}
+	return resp, nil
+}
+
+// productAnalyticsRange returns the last number of completed UTC days before now, 30 by
+// default, and how many days that is
+func productAnalyticsRange(days int, now time.Time) (time.Time, time.Time, int) {
+	if days <= 0 {
+		days = defaultProductAnalyticsDays
+	}
+	to := reportDay(now).AddDate(0, 0, -1)
+	return to.AddDate(0, 0, 1-days), to, days
+}
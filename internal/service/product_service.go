@@ -2,31 +2,39 @@ package service
 
 import (
 	"context"
+	"net/http"
 
 	"github.com/Ahmed1monm/backend-golang-task-2025/internal/api/dto"
 	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
 	"github.com/Ahmed1monm/backend-golang-task-2025/internal/repository"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/errors"
 	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/logger"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
 	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
 type ProductService interface {
 	CreateProduct(ctx context.Context, req *dto.CreateProductRequest) (*dto.ProductResponse, error)
-	GetProduct(ctx context.Context, id uint) (*dto.ProductResponse, error)
-	ListProducts(ctx context.Context, page, limit int) (*dto.PaginatedProductsResponse, error)
+	// GetProduct and ListProducts show prices in the given currency code, or the base currency if empty
+	GetProduct(ctx context.Context, id uint, currency string) (*dto.ProductResponse, error)
+	ListProducts(ctx context.Context, page, limit int, currency string) (*dto.PaginatedProductsResponse, error)
 	GetInventory(ctx context.Context, productID uint) (*dto.InventoryResponse, error)
-	UpdateProduct(ctx context.Context, id uint, req *dto.UpdateProductRequest) (*dto.ProductResponse, error)
+	UpdateProduct(ctx context.Context, id uint, actorID uint, req *dto.UpdateProductRequest) (*dto.ProductResponse, error)
 }
 
 type productService struct {
 	productRepo   repository.ProductRepository
 	orderRepo     repository.OrderRepository
 	inventoryRepo repository.InventoryRepository
+	supplierRepo  repository.SupplierRepository
+	inventorySvc  InventoryService
+	currencySvc   CurrencyService
+	scheduleSvc   PriceScheduleService
 	db            *gorm.DB
 }
 
-func (s *productService) GetProduct(ctx context.Context, id uint) (*dto.ProductResponse, error) {
+func (s *productService) GetProduct(ctx context.Context, id uint, currency string) (*dto.ProductResponse, error) {
 	product, err := s.productRepo.FindByID(ctx, id)
 	if err != nil {
 		logger.Error(ctx, "Failed to get product", zap.Error(err))
@@ -37,17 +45,20 @@ func (s *productService) GetProduct(ctx context.Context, id uint) (*dto.ProductR
 		return nil, nil
 	}
 
-	return &dto.ProductResponse{
-		ID:          product.ID,
-		Name:        product.Name,
-		Description: product.Description,
-		Price:       product.Price,
-		SKU:         product.SKU,
-		StockLevel:  product.Quantity,
-	}, nil
+	stockLevels, err := s.inventoryRepo.GetStockLevels(ctx, s.db, []uint{product.ID})
+	if err != nil {
+		logger.Error(ctx, "Failed to get stock level", zap.Error(err), zap.Uint("product_id", id))
+		return nil, err
+	}
+
+	resp := dto.ProductToResponse(product, stockLevels[product.ID])
+	if err := s.applyPrices(ctx, currency, []*models.Product{product}, []*dto.ProductResponse{resp}); err != nil {
+		return nil, err
+	}
+	return resp, nil
 }
 
-func (s *productService) ListProducts(ctx context.Context, page, limit int) (*dto.PaginatedProductsResponse, error) {
+func (s *productService) ListProducts(ctx context.Context, page, limit int, currency string) (*dto.PaginatedProductsResponse, error) {
 	offset := (page - 1) * limit
 
 	products, total, err := s.productRepo.List(ctx, offset, limit)
@@ -56,16 +67,26 @@ func (s *productService) ListProducts(ctx context.Context, page, limit int) (*dt
 		return nil, err
 	}
 
+	productIDs := make([]uint, len(products))
+	for i := range products {
+		productIDs[i] = products[i].ID
+	}
+	stockLevels, err := s.inventoryRepo.GetStockLevels(ctx, s.db, productIDs)
+	if err != nil {
+		logger.Error(ctx, "Failed to get stock levels", zap.Error(err))
+		return nil, err
+	}
+
 	responseProducts := make([]dto.ProductResponse, len(products))
-	for i, product := range products {
-		responseProducts[i] = dto.ProductResponse{
-			ID:          product.ID,
-			Name:        product.Name,
-			Description: product.Description,
-			Price:       product.Price,
-			SKU:         product.SKU,
-			StockLevel:  product.Quantity,
-		}
+	priced := make([]*models.Product, len(products))
+	targets := make([]*dto.ProductResponse, len(products))
+	for i := range products {
+		responseProducts[i] = *dto.ProductToResponse(&products[i], stockLevels[products[i].ID])
+		priced[i] = &products[i]
+		targets[i] = &responseProducts[i]
+	}
+	if err := s.applyPrices(ctx, currency, priced, targets); err != nil {
+		return nil, err
 	}
 
 	return &dto.PaginatedProductsResponse{
@@ -76,11 +97,15 @@ func (s *productService) ListProducts(ctx context.Context, page, limit int) (*dt
 	}, nil
 }
 
-func NewProductService(repo repository.ProductRepository, orderRepo repository.OrderRepository, inventoryRepo repository.InventoryRepository, db *gorm.DB) ProductService {
+func NewProductService(repo repository.ProductRepository, orderRepo repository.OrderRepository, inventoryRepo repository.InventoryRepository, supplierRepo repository.SupplierRepository, inventorySvc InventoryService, currencySvc CurrencyService, scheduleSvc PriceScheduleService, db *gorm.DB) ProductService {
 	return &productService{
 		productRepo:   repo,
 		orderRepo:     orderRepo,
 		inventoryRepo: inventoryRepo,
+		supplierRepo:  supplierRepo,
+		inventorySvc:  inventorySvc,
+		currencySvc:   currencySvc,
+		scheduleSvc:   scheduleSvc,
 		db:            db,
 	}
 }
@@ -96,60 +121,105 @@ func (s *productService) GetInventory(ctx context.Context, productID uint) (*dto
 		return nil, nil
 	}
 
-	// Get inventory
-	inventory, err := s.productRepo.GetInventory(ctx, productID)
+	// Get inventory in every warehouse
+	inventories, err := s.inventoryRepo.ListByProduct(ctx, s.db, productID)
 	if err != nil {
 		logger.Error(ctx, "Failed to get inventory", zap.Error(err))
 		return nil, err
 	}
-	if inventory == nil {
+	if len(inventories) == 0 {
 		return nil, nil
 	}
 
-	productInfo, err := s.productRepo.FindByID(ctx, inventory.ProductID)
-	if err != nil {
-		logger.Error(ctx, "Failed to get product for inventory", zap.Error(err))
-		return nil, err
-}
+	resp := &dto.InventoryResponse{
+		ProductID:  product.ID,
+		SKU:        product.SKU,
+		Warehouses: make([]dto.WarehouseInventoryResponse, len(inventories)),
+	}
+	for i, inventory := range inventories {
+		resp.StockLevel += inventory.Quantity
+		resp.Reserved += inventory.Reserved
+		resp.MinimumStock += inventory.MinimumStock
+		resp.Warehouses[i] = dto.WarehouseInventoryResponse{
+			WarehouseID:  inventory.WarehouseID,
+			StockLevel:   inventory.Quantity,
+			Reserved:     inventory.Reserved,
+			MinimumStock: inventory.MinimumStock,
+		}
+		if inventory.Warehouse != nil {
+			resp.Warehouses[i].WarehouseCode = inventory.Warehouse.Code
+			resp.Warehouses[i].WarehouseName = inventory.Warehouse.Name
+		}
+	}
 
-return &dto.InventoryResponse{
-		ProductID:    inventory.ProductID,
-		SKU:          productInfo.SKU,
-		StockLevel:   inventory.Quantity,
-		MinimumStock: inventory.MinimumStock,
-	}, nil
+	return resp, nil
 }
 
 func (s *productService) CreateProduct(ctx context.Context, req *dto.CreateProductRequest) (*dto.ProductResponse, error) {
 	// Create product model
 	product := &models.Product{
-		Name:        req.Name,
-		Description: req.Description,
-		Price:       req.Price,
+		Name:              req.Name,
+		Description:       req.Description,
+		Price:             req.Price,
+		StockPolicy:       models.StockPolicy(req.StockPolicy),
+		BackorderLimit:    req.BackorderLimit,
+		PreorderReleaseAt: req.PreorderReleaseAt,
+	}
+	if product.StockPolicy == "" {
+		product.StockPolicy = models.StockPolicyDeny
+	}
+	if err := validateStockPolicy(product); err != nil {
+		return nil, err
 	}
 
-	// Create inventory model
-	inventory := &models.Inventory{
-		Quantity: req.Quantity,
+	// Attach the preferred supplier used for restocking
+	if req.PreferredSupplierID != nil {
+		if err := s.validateSupplier(ctx, *req.PreferredSupplierID); err != nil {
+			return nil, err
+		}
+		product.PreferredSupplierID = req.PreferredSupplierID
 	}
 
-	// Create product with inventory
-	if err := s.productRepo.Create(ctx, product, inventory); err != nil {
+	// Create the product and record the opening stock as a receipt, which creates its
+	// inventory in the default warehouse
+	stockLevel := 0
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := s.productRepo.CreateInTx(ctx, tx, product); err != nil {
+			return err
+		}
+		if err := s.scheduleSvc.RecordPriceChange(ctx, tx, product, nil); err != nil {
+			return err
+		}
+
+		if req.Quantity == 0 {
+			return nil
+		}
+
+		updated, err := s.inventorySvc.ApplyMovement(ctx, tx, &models.StockMovement{
+			ProductID:     product.ID,
+			Type:          models.StockMovementReceipt,
+			QuantityDelta: req.Quantity,
+			ReferenceType: models.StockReferenceProduct,
+			ReferenceID:   &product.ID,
+			Note:          "Opening stock",
+		})
+		if err != nil {
+			return err
+		}
+		stockLevel = updated.Quantity
+		return nil
+	})
+	if err != nil {
 		logger.Error(ctx, "Failed to create product", zap.Error(err))
 		return nil, err
 	}
 
-	return &dto.ProductResponse{
-		ID:          product.ID,
-		Name:        product.Name,
-		Description: product.Description,
-		Price:       product.Price,
-		SKU:         product.SKU,
-		StockLevel:  inventory.Quantity,
-	}, nil
+	s.inventorySvc.StockChanged(ctx, product.ID)
+
+	return dto.ProductToResponse(product, stockLevel), nil
 }
 
-func (s *productService) UpdateProduct(ctx context.Context, id uint, req *dto.UpdateProductRequest) (*dto.ProductResponse, error) {
+func (s *productService) UpdateProduct(ctx context.Context, id uint, actorID uint, req *dto.UpdateProductRequest) (*dto.ProductResponse, error) {
 	// Get existing product
 	existingProduct, err := s.productRepo.FindByID(ctx, id)
 	if err != nil {
@@ -167,30 +237,51 @@ func (s *productService) UpdateProduct(ctx context.Context, id uint, req *dto.Up
 	if req.Description != nil {
 		existingProduct.Description = *req.Description
 	}
+	priceChanged := req.Price != nil && !req.Price.Equal(existingProduct.Price)
 	if req.Price != nil {
 		existingProduct.Price = *req.Price
 	}
+	if req.PreferredSupplierID != nil {
+		if err := s.validateSupplier(ctx, *req.PreferredSupplierID); err != nil {
+			return nil, err
+		}
+		existingProduct.PreferredSupplierID = req.PreferredSupplierID
+	}
+	if req.StockPolicy != nil {
+		existingProduct.StockPolicy = models.StockPolicy(*req.StockPolicy)
+	}
+	if req.BackorderLimit != nil {
+		existingProduct.BackorderLimit = *req.BackorderLimit
+	}
+	if req.PreorderReleaseAt != nil {
+		existingProduct.PreorderReleaseAt = req.PreorderReleaseAt
+	}
+	if req.Discontinued != nil {
+		existingProduct.Discontinued = *req.Discontinued
+	}
+	if err := validateStockPolicy(existingProduct); err != nil {
+		return nil, err
+	}
 
 	// Get and update inventory if quantity provided
 	if req.Quantity != nil {
 		// Start a transaction
-		err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-			// Get current inventory with lock
-			inventory, err := s.inventoryRepo.GetForUpdate(ctx, tx, id)
-			if err != nil {
-				return err
-			}
-
-			// Update inventory quantity
-			inventory.Quantity = *req.Quantity
+		var warehouseID uint
+		if req.WarehouseID != nil {
+			warehouseID = *req.WarehouseID
+		}
 
-			// Update with transaction-safe method
-			return s.inventoryRepo.Update(ctx, tx, inventory)
+		// Record the difference as an adjustment instead of overwriting the quantity
+		err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			_, err := s.inventorySvc.SetStockLevel(ctx, tx, id, warehouseID, *req.Quantity, actorID)
+			return err
 		})
 		if err != nil {
 			logger.Error(ctx, "Failed to update inventory", zap.Error(err))
 			return nil, err
 		}
+
+		s.inventorySvc.StockChanged(ctx, id)
 	}
 
 	// Update product
@@ -198,21 +289,71 @@ func (s *productService) UpdateProduct(ctx context.Context, id uint, req *dto.Up
 		logger.Error(ctx, "Failed to update product", zap.Error(err))
 		return nil, err
 	}
+	if priceChanged {
+		if err := s.scheduleSvc.RecordPriceChange(ctx, s.db, existingProduct, &actorID); err != nil {
+			logger.Error(ctx, "Failed to record price change", zap.Error(err), zap.Uint("product_id", id))
+		}
+	}
 
-	// Get updated inventory for response
-	updatedInventory, err := s.productRepo.GetInventory(ctx, id)
+	// Get updated stock level for response
+	stockLevels, err := s.inventoryRepo.GetStockLevels(ctx, s.db, []uint{id})
 	if err != nil {
 		logger.Error(ctx, "Failed to get updated inventory", zap.Error(err))
 		return nil, err
 	}
 
 	// Return response
-	return &dto.ProductResponse{
-		ID:          existingProduct.ID,
-		Name:        existingProduct.Name,
-		Description: existingProduct.Description,
-		Price:       existingProduct.Price,
-		SKU:         existingProduct.SKU,
-		StockLevel:  updatedInventory.Quantity,
-	}, nil
+	return dto.ProductToResponse(existingProduct, stockLevels[id]), nil
+}
+
+// applyPrices replaces the regular base prices of the responses with the current prices,
+// including sales, in the requested currency
+func (s *productService) applyPrices(ctx context.Context, currency string, products []*models.Product, responses []*dto.ProductResponse) error {
+	code := money.DefaultCurrency
+	if currency != "" {
+		var err error
+		if code, err = s.currencySvc.ValidateCurrency(currency); err != nil {
+			return err
+		}
+	}
+
+	quotes, _, err := s.currencySvc.PriceProducts(ctx, s.db, code, products)
+	if err != nil {
+		return err
+	}
+	for i, product := range products {
+		quote := quotes[product.ID]
+		responses[i].Price = quote.Price
+		responses[i].Currency = string(code)
+		if !quote.CompareAtPrice.IsZero() {
+			responses[i].CompareAtPrice = &quote.CompareAtPrice
+		}
+	}
+	return nil
+}
+
+// validateSupplier makes sure a supplier exists before it is set as a product's preferred supplier
+func (s *productService) validateSupplier(ctx context.Context, supplierID uint) error {
+	if _, err := s.supplierRepo.FindByID(ctx, s.db, supplierID); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return errors.NewValidationError("Supplier not found", map[string]string{"preferred_supplier_id": "supplier does not exist"}, http.StatusBadRequest)
+		}
+		return err
+	}
+	return nil
+}
+
+// validateStockPolicy checks that a product's backorder settings fit its stock policy
+func validateStockPolicy(product *models.Product) error {
+	switch product.StockPolicy {
+	case models.StockPolicyBackorder:
+		if product.BackorderLimit <= 0 {
+			return errors.NewValidationError("Invalid stock policy", map[string]string{"backorder_limit": "must be greater than 0 for backorder policy"}, http.StatusBadRequest)
+		}
+	case models.StockPolicyPreorder:
+		if product.PreorderReleaseAt == nil {
+			return errors.NewValidationError("Invalid stock policy", map[string]string{"preorder_release_at": "required for preorder policy"}, http.StatusBadRequest)
+		}
+	}
+	return nil
 }
@@ -0,0 +1,401 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/api/dto"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/repository"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/errors"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/logger"
+)
+
+type PurchaseOrderService interface {
+	CreatePurchaseOrder(ctx context.Context, actorID uint, req *dto.CreatePurchaseOrderRequest) (*dto.PurchaseOrderResponse, error)
+	// CreateFromStockAlert drafts a purchase order for the alerted product from its preferred
+	// supplier. If the alert already has an open purchase order, that order is returned
+	// and created is false.
+	CreateFromStockAlert(ctx context.Context, actorID, alertID uint) (resp *dto.PurchaseOrderResponse, created bool, err error)
+	GetPurchaseOrder(ctx context.Context, id uint) (*dto.PurchaseOrderResponse, error)
+	ListPurchaseOrders(ctx context.Context, status models.PurchaseOrderStatus, page, limit int) (*dto.PaginatedPurchaseOrdersResponse, error)
+	SendPurchaseOrder(ctx context.Context, id uint) (*dto.PurchaseOrderResponse, error)
+	ReceivePurchaseOrder(ctx context.Context, actorID, id uint, req *dto.ReceivePurchaseOrderRequest) (*dto.PurchaseOrderResponse, error)
+	CancelPurchaseOrder(ctx context.Context, id uint) (*dto.PurchaseOrderResponse, error)
+}
+
+type purchaseOrderService struct {
+	db            *gorm.DB
+	poRepo        repository.PurchaseOrderRepository
+	supplierRepo  repository.SupplierRepository
+	warehouseRepo repository.WarehouseRepository
+	productRepo   repository.ProductRepository
+	alertRepo     repository.StockAlertRepository
+	inventorySvc  InventoryService
+}
+
+func NewPurchaseOrderService(
+	db *gorm.DB,
+	poRepo repository.PurchaseOrderRepository,
+	supplierRepo repository.SupplierRepository,
+	warehouseRepo repository.WarehouseRepository,
+	productRepo repository.ProductRepository,
+	alertRepo repository.StockAlertRepository,
+	inventorySvc InventoryService,
+) PurchaseOrderService {
+	return &purchaseOrderService{
+		db:            db,
+		poRepo:        poRepo,
+		supplierRepo:  supplierRepo,
+		warehouseRepo: warehouseRepo,
+		productRepo:   productRepo,
+		alertRepo:     alertRepo,
+		inventorySvc:  inventorySvc,
+	}
+}
+
+func (s *purchaseOrderService) CreatePurchaseOrder(ctx context.Context, actorID uint, req *dto.CreatePurchaseOrderRequest) (*dto.PurchaseOrderResponse, error) {
+	order, err := s.create(ctx, actorID, req, nil)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetPurchaseOrder(ctx, order.ID)
+}
+
+func (s *purchaseOrderService) CreateFromStockAlert(ctx context.Context, actorID, alertID uint) (*dto.PurchaseOrderResponse, bool, error) {
+	alert, err := s.alertRepo.FindByID(ctx, s.db, alertID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, false, errors.NewBusinessError("Low stock alert not found", errors.ErrCodeResourceNotFound, http.StatusNotFound)
+		}
+		return nil, false, err
+	}
+
+	existing, err := s.poRepo.FindOpenByStockAlert(ctx, s.db, alert.ID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, false, err
+	}
+	if existing != nil {
+		resp := dto.PurchaseOrderToResponse(existing)
+		return &resp, false, nil
+	}
+
+	if alert.Status != models.StockAlertStatusOpen {
+		return nil, false, errors.NewBusinessError("Low stock alert is already resolved", "STOCK_ALERT_RESOLVED", http.StatusConflict)
+	}
+	if alert.Product == nil || alert.Product.PreferredSupplierID == nil {
+		return nil, false, errors.NewValidationError(
+			"Product has no preferred supplier",
+			map[string]string{"preferred_supplier_id": "set a preferred supplier on the product first"},
+			http.StatusBadRequest,
+		)
+	}
+
+	order, err := s.create(ctx, actorID, &dto.CreatePurchaseOrderRequest{
+		SupplierID:  *alert.Product.PreferredSupplierID,
+		WarehouseID: alert.WarehouseID,
+		Notes:       fmt.Sprintf("Drafted from low stock alert #%d", alert.ID),
+		Lines: []dto.PurchaseOrderLineRequest{{
+			ProductID: alert.ProductID,
+			Quantity:  alert.SuggestedReorderQuantity,
+		}},
+	}, &alert.ID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	resp, err := s.GetPurchaseOrder(ctx, order.ID)
+	return resp, true, err
+}
+
+// create validates the supplier, warehouse and products and stores a draft purchase order
+func (s *purchaseOrderService) create(ctx context.Context, actorID uint, req *dto.CreatePurchaseOrderRequest, alertID *uint) (*models.PurchaseOrder, error) {
+	var order *models.PurchaseOrder
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		supplier, err := s.supplierRepo.FindByID(ctx, tx, req.SupplierID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.NewValidationError("Supplier not found", map[string]string{"supplier_id": "supplier does not exist"}, http.StatusBadRequest)
+			}
+			return err
+		}
+		if !supplier.Active {
+			return errors.NewValidationError("Supplier is inactive", map[string]string{"supplier_id": "supplier is inactive"}, http.StatusBadRequest)
+		}
+
+		var warehouse *models.Warehouse
+		if req.WarehouseID == 0 {
+			warehouse, err = s.warehouseRepo.GetDefault(ctx, tx)
+		} else {
+			warehouse, err = s.warehouseRepo.FindByID(ctx, tx, req.WarehouseID)
+		}
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.NewValidationError("Warehouse not found", map[string]string{"warehouse_id": "warehouse does not exist"}, http.StatusBadRequest)
+			}
+			return err
+		}
+
+		expectedAt := req.ExpectedAt
+		if expectedAt == nil {
+			expected := time.Now().AddDate(0, 0, supplier.LeadTimeDays)
+			expectedAt = &expected
+		}
+
+		order = &models.PurchaseOrder{
+			Number:       "PO-" + time.Now().Format("20060102") + "-" + strings.ToUpper(uuid.New().String()[:6]),
+			SupplierID:   supplier.ID,
+			WarehouseID:  warehouse.ID,
+			Status:       models.PurchaseOrderStatusDraft,
+			ExpectedAt:   expectedAt,
+			Notes:        req.Notes,
+			StockAlertID: alertID,
+			CreatedByID:  &actorID,
+			Lines:        make([]models.PurchaseOrderLine, len(req.Lines)),
+		}
+
+		for i, line := range req.Lines {
+			product, err := s.productRepo.FindByID(ctx, line.ProductID)
+			if err != nil {
+				return err
+			}
+			if product == nil {
+				return errors.NewValidationError(
+					fmt.Sprintf("Product with ID %d not found", line.ProductID),
+					map[string]string{fmt.Sprintf("lines[%d].product_id", i): "product not found"},
+					http.StatusBadRequest,
+				)
+			}
+
+			lineExpectedAt := line.ExpectedAt
+			if lineExpectedAt == nil {
+				lineExpectedAt = expectedAt
+			}
+			order.Lines[i] = models.PurchaseOrderLine{
+				ProductID:       line.ProductID,
+				QuantityOrdered: line.Quantity,
+				UnitCost:        line.UnitCost,
+				ExpectedAt:      lineExpectedAt,
+			}
+		}
+
+		return s.poRepo.Create(ctx, tx, order)
+	})
+	if err != nil {
+		logger.Error(ctx, "Failed to create purchase order", zap.Error(err), zap.Uint("supplier_id", req.SupplierID))
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (s *purchaseOrderService) GetPurchaseOrder(ctx context.Context, id uint) (*dto.PurchaseOrderResponse, error) {
+	order, err := s.poRepo.FindByID(ctx, s.db, id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewBusinessError("Purchase order not found", errors.ErrCodeResourceNotFound, http.StatusNotFound)
+		}
+		return nil, err
+	}
+
+	resp := dto.PurchaseOrderToResponse(order)
+	return &resp, nil
+}
+
+func (s *purchaseOrderService) ListPurchaseOrders(ctx context.Context, status models.PurchaseOrderStatus, page, limit int) (*dto.PaginatedPurchaseOrdersResponse, error) {
+	offset := (page - 1) * limit
+
+	orders, total, err := s.poRepo.List(ctx, s.db, status, offset, limit)
+	if err != nil {
+		logger.Error(ctx, "Failed to list purchase orders", zap.Error(err))
+		return nil, err
+	}
+
+	resp := &dto.PaginatedPurchaseOrdersResponse{
+		PurchaseOrders: make([]dto.PurchaseOrderResponse, len(orders)),
+		Total:          total,
+		Page:           page,
+		Limit:          limit,
+	}
+	for i := range orders {
+		resp.PurchaseOrders[i] = dto.PurchaseOrderToResponse(&orders[i])
+	}
+	return resp, nil
+}
+
+func (s *purchaseOrderService) SendPurchaseOrder(ctx context.Context, id uint) (*dto.PurchaseOrderResponse, error) {
+	err := s.transition(ctx, id, func(order *models.PurchaseOrder) error {
+		if order.Status != models.PurchaseOrderStatusDraft {
+			return invalidPurchaseOrderStatus(order.Status, "sent")
+		}
+		now := time.Now()
+		order.Status = models.PurchaseOrderStatusSent
+		order.SentAt = &now
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetPurchaseOrder(ctx, id)
+}
+
+func (s *purchaseOrderService) CancelPurchaseOrder(ctx context.Context, id uint) (*dto.PurchaseOrderResponse, error) {
+	err := s.transition(ctx, id, func(order *models.PurchaseOrder) error {
+		// Once goods arrived the order can only be completed
+		if order.Status != models.PurchaseOrderStatusDraft && order.Status != models.PurchaseOrderStatusSent {
+			return invalidPurchaseOrderStatus(order.Status, "cancelled")
+		}
+		order.Status = models.PurchaseOrderStatusCancelled
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetPurchaseOrder(ctx, id)
+}
+
+// transition locks a purchase order, applies a status change and saves it
+func (s *purchaseOrderService) transition(ctx context.Context, id uint, apply func(order *models.PurchaseOrder) error) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		order, err := s.poRepo.FindByIDForUpdate(ctx, tx, id)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.NewBusinessError("Purchase order not found", errors.ErrCodeResourceNotFound, http.StatusNotFound)
+			}
+			return err
+		}
+		if err := apply(order); err != nil {
+			return err
+		}
+		return s.poRepo.Update(ctx, tx, order)
+	})
+}
+
+// ReceivePurchaseOrder books delivered quantities into the purchase order's warehouse as
+// receipt movements, going through the same locked inventory update as every other stock
+// change, and moves the order to partially received or received.
+func (s *purchaseOrderService) ReceivePurchaseOrder(ctx context.Context, actorID, id uint, req *dto.ReceivePurchaseOrderRequest) (*dto.PurchaseOrderResponse, error) {
+	var productIDs []uint
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		order, err := s.poRepo.FindByIDForUpdate(ctx, tx, id)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.NewBusinessError("Purchase order not found", errors.ErrCodeResourceNotFound, http.StatusNotFound)
+			}
+			return err
+		}
+		if order.Status != models.PurchaseOrderStatusSent && order.Status != models.PurchaseOrderStatusPartiallyReceived {
+			return invalidPurchaseOrderStatus(order.Status, "received")
+		}
+
+		received, err := receivedQuantities(order, req)
+		if err != nil {
+			return err
+		}
+
+		for i := range order.Lines {
+			line := &order.Lines[i]
+			quantity := received[line.ID]
+			if quantity == 0 {
+				continue
+			}
+
+			if _, err := s.inventorySvc.ApplyMovement(ctx, tx, &models.StockMovement{
+				ProductID:     line.ProductID,
+				WarehouseID:   order.WarehouseID,
+				Type:          models.StockMovementReceipt,
+				QuantityDelta: quantity,
+				ReferenceType: models.StockReferencePurchase,
+				ReferenceID:   &order.ID,
+				Reference:     order.Number,
+				Note:          req.Note,
+				ActorID:       &actorID,
+			}); err != nil {
+				return err
+			}
+
+			line.QuantityReceived += quantity
+			if err := s.poRepo.UpdateLine(ctx, tx, line); err != nil {
+				return err
+			}
+			productIDs = append(productIDs, line.ProductID)
+		}
+
+		order.Status = models.PurchaseOrderStatusReceived
+		for _, line := range order.Lines {
+			if line.Outstanding() > 0 {
+				order.Status = models.PurchaseOrderStatusPartiallyReceived
+				break
+			}
+		}
+		if order.Status == models.PurchaseOrderStatusReceived {
+			now := time.Now()
+			order.ReceivedAt = &now
+		}
+
+		return s.poRepo.Update(ctx, tx, order)
+	})
+	if err != nil {
+		logger.Error(ctx, "Failed to receive purchase order", zap.Error(err), zap.Uint("purchase_order_id", id))
+		return nil, err
+	}
+
+	// Received stock may resolve open low stock alerts
+	s.inventorySvc.StockChanged(ctx, productIDs...)
+
+	return s.GetPurchaseOrder(ctx, id)
+}
+
+// receivedQuantities maps line IDs to the quantity received in this delivery. Without
+// explicit lines the whole outstanding quantity of every line is received.
+func receivedQuantities(order *models.PurchaseOrder, req *dto.ReceivePurchaseOrderRequest) (map[uint]int, error) {
+	received := make(map[uint]int, len(order.Lines))
+	if len(req.Lines) == 0 {
+		for _, line := range order.Lines {
+			received[line.ID] = line.Outstanding()
+		}
+		return received, nil
+	}
+
+	lines := make(map[uint]*models.PurchaseOrderLine, len(order.Lines))
+	for i := range order.Lines {
+		lines[order.Lines[i].ID] = &order.Lines[i]
+	}
+
+	for i, item := range req.Lines {
+		line, ok := lines[item.LineID]
+		if !ok {
+			return nil, errors.NewValidationError(
+				"Line does not belong to the purchase order",
+				map[string]string{fmt.Sprintf("lines[%d].line_id", i): "unknown line"},
+				http.StatusBadRequest,
+			)
+		}
+		received[line.ID] += item.Quantity
+		if received[line.ID] > line.Outstanding() {
+			return nil, errors.NewValidationError(
+				"Received quantity exceeds the outstanding quantity",
+				map[string]string{fmt.Sprintf("lines[%d].quantity", i): fmt.Sprintf("at most %d outstanding", line.Outstanding())},
+				http.StatusBadRequest,
+			)
+		}
+	}
+	return received, nil
+}
+
+func invalidPurchaseOrderStatus(current models.PurchaseOrderStatus, target string) error {
+	return errors.NewBusinessError(
+		fmt.Sprintf("Purchase order in status %s cannot be %s", current, target),
+		"INVALID_STATUS_TRANSITION",
+		http.StatusBadRequest,
+	)
+}
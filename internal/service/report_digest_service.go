@@ -0,0 +1,335 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/api/dto"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/repository"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/errors"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/export"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/logger"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/mail"
+)
+
+// ReportDigestConfig controls the delivery of report digests
+type ReportDigestConfig struct {
+	MaxAttempts int // Failed sends after which a digest is given up
+}
+
+// ErrCodeReportDigestExists is returned when an admin already gets a digest at that frequency
+const ErrCodeReportDigestExists = "REPORT_DIGEST_EXISTS"
+
+// reportDigestHistory is how many deliveries of a subscription are listed
+const reportDigestHistory = 100
+
+type ReportDigestService interface {
+	CreateSubscription(ctx context.Context, actorID uint, req *dto.CreateReportDigestRequest) (*dto.ReportDigestSubscriptionResponse, error)
+	ListSubscriptions(ctx context.Context) ([]dto.ReportDigestSubscriptionResponse, error)
+	UpdateSubscription(ctx context.Context, id uint, req *dto.UpdateReportDigestRequest) (*dto.ReportDigestSubscriptionResponse, error)
+	DeleteSubscription(ctx context.Context, id uint) error
+	ListDeliveries(ctx context.Context, id uint) ([]dto.ReportDigestDeliveryResponse, error)
+
+	// SendDueDigests emails the digests of the last completed day and week to the
+	// subscribers who haven't got them yet, retrying failed sends up to MaxAttempts
+	SendDueDigests(ctx context.Context, now time.Time) (sent, failed int, err error)
+}
+
+type reportDigestService struct {
+	db         *gorm.DB
+	config     ReportDigestConfig
+	digestRepo repository.ReportDigestRepository
+	userRepo   repository.UserRepository
+	reportSvc  *ReportService
+	sender     mail.Sender
+}
+
+func NewReportDigestService(
+	db *gorm.DB,
+	config ReportDigestConfig,
+	digestRepo repository.ReportDigestRepository,
+	userRepo repository.UserRepository,
+	reportSvc *ReportService,
+	sender mail.Sender,
+) ReportDigestService {
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 3
+	}
+	return &reportDigestService{
+		db:         db,
+		config:     config,
+		digestRepo: digestRepo,
+		userRepo:   userRepo,
+		reportSvc:  reportSvc,
+		sender:     sender,
+	}
+}
+
+func (s *reportDigestService) CreateSubscription(ctx context.Context, actorID uint, req *dto.CreateReportDigestRequest) (*dto.ReportDigestSubscriptionResponse, error) {
+	userID := req.UserID
+	if userID == 0 {
+		userID = actorID
+	}
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil || user.Role != models.RoleAdmin || !user.Active {
+		return nil, errors.NewValidationError("Only active admins can get report digests", map[string]string{"user_id": "must be an active admin"}, http.StatusBadRequest)
+	}
+
+	frequency := models.ReportDigestFrequency(req.Frequency)
+	if _, err := s.digestRepo.FindByUserAndFrequency(ctx, s.db, userID, frequency); err == nil {
+		return nil, errors.NewBusinessError(
+			fmt.Sprintf("The user already gets the %s report digest", frequency),
+			ErrCodeReportDigestExists,
+			http.StatusConflict,
+		)
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("failed to get report digest: %w", err)
+	}
+
+	subscription := &models.ReportDigestSubscription{
+		UserID:    userID,
+		Frequency: frequency,
+		Active:    true,
+	}
+	if err := s.digestRepo.Create(ctx, s.db, subscription); err != nil {
+		return nil, fmt.Errorf("failed to create report digest: %w", err)
+	}
+	subscription.User = *user
+
+	resp := dto.ReportDigestSubscriptionToResponse(subscription, nil)
+	return &resp, nil
+}
+
+func (s *reportDigestService) ListSubscriptions(ctx context.Context) ([]dto.ReportDigestSubscriptionResponse, error) {
+	subscriptions, err := s.digestRepo.List(ctx, s.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list report digests: %w", err)
+	}
+	ids := make([]uint, len(subscriptions))
+	for i, subscription := range subscriptions {
+		ids[i] = subscription.ID
+	}
+	latest, err := s.digestRepo.LatestDeliveries(ctx, s.db, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get report digest deliveries: %w", err)
+	}
+
+	resp := make([]dto.ReportDigestSubscriptionResponse, len(subscriptions))
+	for i := range subscriptions {
+		resp[i] = dto.ReportDigestSubscriptionToResponse(&subscriptions[i], latest[subscriptions[i].ID])
+	}
+	return resp, nil
+}
+
+func (s *reportDigestService) UpdateSubscription(ctx context.Context, id uint, req *dto.UpdateReportDigestRequest) (*dto.ReportDigestSubscriptionResponse, error) {
+	subscription, err := s.digestRepo.FindByID(ctx, s.db, id)
+	if err != nil {
+		return nil, reportDigestLookupError(err)
+	}
+
+	subscription.Active = *req.Active
+	if err := s.digestRepo.Update(ctx, s.db, subscription); err != nil {
+		return nil, fmt.Errorf("failed to update report digest: %w", err)
+	}
+
+	latest, err := s.digestRepo.LatestDeliveries(ctx, s.db, []uint{subscription.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get report digest deliveries: %w", err)
+	}
+	resp := dto.ReportDigestSubscriptionToResponse(subscription, latest[subscription.ID])
+	return &resp, nil
+}
+
+func (s *reportDigestService) DeleteSubscription(ctx context.Context, id uint) error {
+	if _, err := s.digestRepo.FindByID(ctx, s.db, id); err != nil {
+		return reportDigestLookupError(err)
+	}
+	if err := s.digestRepo.Delete(ctx, s.db, id); err != nil {
+		return fmt.Errorf("failed to delete report digest: %w", err)
+	}
+	return nil
+}
+
+func (s *reportDigestService) ListDeliveries(ctx context.Context, id uint) ([]dto.ReportDigestDeliveryResponse, error) {
+	if _, err := s.digestRepo.FindByID(ctx, s.db, id); err != nil {
+		return nil, reportDigestLookupError(err)
+	}
+	deliveries, err := s.digestRepo.ListDeliveries(ctx, s.db, id, reportDigestHistory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list report digest deliveries: %w", err)
+	}
+
+	resp := make([]dto.ReportDigestDeliveryResponse, len(deliveries))
+	for i := range deliveries {
+		resp[i] = dto.ReportDigestDeliveryToResponse(&deliveries[i])
+	}
+	return resp, nil
+}
+
+// reportDigest is the email of a digest period, shared by all its subscribers
+type reportDigest struct {
+	from, to time.Time
+	message  *mail.Message
+	err      error // Why the digest couldn't be built
+}
+
+func (s *reportDigestService) SendDueDigests(ctx context.Context, now time.Time) (sent, failed int, err error) {
+	subscriptions, err := s.digestRepo.ListActive(ctx, s.db)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to list report digests: %w", err)
+	}
+
+	digests := make(map[models.ReportDigestFrequency]*reportDigest)
+	for i := range subscriptions {
+		subscription := &subscriptions[i]
+		if subscription.User.Role != models.RoleAdmin || !subscription.User.Active {
+			continue
+		}
+
+		digest, ok := digests[subscription.Frequency]
+		if !ok {
+			digest = s.buildDigest(ctx, subscription.Frequency, now)
+			digests[subscription.Frequency] = digest
+		}
+
+		delivered, attempted, err := s.deliver(ctx, subscription, digest)
+		if err != nil {
+			return sent, failed, err
+		}
+		if delivered {
+			sent++
+		} else if attempted {
+			failed++
+		}
+	}
+	return sent, failed, nil
+}
+
+// deliver sends a subscriber their digest unless it was sent already, recording the outcome
+func (s *reportDigestService) deliver(ctx context.Context, subscription *models.ReportDigestSubscription, digest *reportDigest) (delivered, attempted bool, err error) {
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		delivery, err := s.digestRepo.ClaimDelivery(ctx, tx, &models.ReportDigestDelivery{
+			SubscriptionID: subscription.ID,
+			UserID:         subscription.UserID,
+			PeriodStart:    digest.from,
+			PeriodEnd:      digest.to,
+			Email:          subscription.User.Email,
+			Status:         models.ReportDigestDeliveryPending,
+		}, s.config.MaxAttempts)
+		if err == gorm.ErrRecordNotFound {
+			return nil // Sent, given up or being sent by another instance
+		}
+		if err != nil {
+			return err
+		}
+
+		attempted = true
+		delivery.Attempts++
+		delivery.Email = subscription.User.Email
+		sendErr := digest.err
+		if sendErr == nil {
+			msg := *digest.message
+			msg.To = []string{subscription.User.Email}
+			sendErr = s.sender.Send(ctx, &msg)
+		}
+
+		if sendErr != nil {
+			logger.Error(ctx, "Failed to send report digest",
+				zap.Uint("subscription_id", subscription.ID),
+				zap.Int("attempt", delivery.Attempts),
+				zap.Error(sendErr))
+			delivery.Status = models.ReportDigestDeliveryFailed
+			delivery.LastError = sendErr.Error()
+		} else {
+			now := time.Now()
+			delivered = true
+			delivery.Status = models.ReportDigestDeliverySent
+			delivery.LastError = ""
+			delivery.SentAt = &now
+		}
+		return s.digestRepo.UpdateDelivery(ctx, tx, delivery)
+	})
+	if err != nil {
+		return false, false, fmt.Errorf("failed to record report digest delivery: %w", err)
+	}
+	return delivered, attempted, nil
+}
+
+// buildDigest builds the email of the last completed day or ISO week before now: an HTML
+// summary with the full report attached as CSV
+func (s *reportDigestService) buildDigest(ctx context.Context, frequency models.ReportDigestFrequency, now time.Time) *reportDigest {
+	today := reportDay(now)
+	digest := &reportDigest{from: today.AddDate(0, 0, -1), to: today.AddDate(0, 0, -1)}
+
+	var doc *export.Document
+	var fileName string
+	if frequency == models.ReportDigestWeekly {
+		digest.from = reportPeriodStart(today, ReportGranularityWeek).AddDate(0, 0, -7)
+		digest.to = digest.from.AddDate(0, 0, 6)
+
+		report, err := s.reportSvc.GetRangeReport(ctx, &dto.SalesReportRangeQuery{
+			From:        digest.from.Format(time.DateOnly),
+			To:          digest.to.Format(time.DateOnly),
+			Granularity: ReportGranularityDay,
+		})
+		if err != nil {
+			digest.err = err
+			return digest
+		}
+		doc = rangeReportDocument(report)
+		fileName = fmt.Sprintf("sales-report-%s-to-%s.csv", digest.from.Format(time.DateOnly), digest.to.Format(time.DateOnly))
+	} else {
+		report, err := s.reportSvc.GetDailyReport(ctx, digest.from)
+		if err != nil {
+			digest.err = err
+			return digest
+		}
+		doc = dailyReportDocument(report)
+		fileName = fmt.Sprintf("sales-report-%s.csv", digest.from.Format(time.DateOnly))
+	}
+
+	attachment, err := export.Render(doc, export.FormatCSV)
+	if err != nil {
+		digest.err = err
+		return digest
+	}
+
+	// The weekly summary leaves the day by day breakdown to the attachment
+	summary := *doc
+	if frequency == models.ReportDigestWeekly {
+		summary.Sections = summary.Sections[:1]
+	}
+	html, err := export.RenderHTML(&summary)
+	if err != nil {
+		digest.err = err
+		return digest
+	}
+
+	digest.message = &mail.Message{
+		Subject: doc.Title,
+		HTML:    string(html),
+		Text:    fmt.Sprintf("%s\n%s\n\nThe full report is attached as %s.\n", doc.Title, doc.Subtitle, fileName),
+		Attachments: []mail.Attachment{{
+			Name:        fileName,
+			ContentType: export.FormatCSV.ContentType(),
+			Content:     attachment,
+		}},
+	}
+	return digest
+}
+
+func reportDigestLookupError(err error) error {
+	if err == gorm.ErrRecordNotFound {
+		return errors.NewBusinessError("Report digest not found", errors.ErrCodeResourceNotFound, http.StatusNotFound)
+	}
+	return fmt.Errorf("failed to get report digest: %w", err)
+}
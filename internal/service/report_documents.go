@@ -0,0 +1,146 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/api/dto"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/export"
+)
+
+// dailyReportDocument lays out a daily sales report for export: the figures of the day,
+// then its top products and low stock products
+func dailyReportDocument(report *models.DailySalesReport) *export.Document {
+	day := reportDay(report.Date).Format("2006-01-02")
+	doc := &export.Document{
+		Title:    "Daily sales report " + day,
+		Subtitle: fmt.Sprintf("UTC day, amounts in %s", report.Currency),
+	}
+
+	doc.Sections = append(doc.Sections, export.Section{
+		Name:    "Summary",
+		Columns: []string{"Metric", "Value"},
+		Rows: [][]any{
+			{"Total orders", report.TotalOrders},
+			{"Pending orders", report.PendingOrders},
+			{"Processing orders", report.ProcessingOrders},
+			{"Shipped orders", report.ShippedOrders},
+			{"Delivered orders", report.DeliveredOrders},
+			{"Cancelled orders", report.CancelledOrders},
+			{"Gross bookings", report.GrossBookings},
+			{"Cancellations", report.Cancellations},
+			{"Net revenue", report.NetRevenue},
+			{"Recognised revenue", report.RecognisedRevenue},
+			{"Average order value", report.AverageOrderValue},
+			{"Unique customers", report.UniqueCustomers},
+			{"New customers", report.NewCustomers},
+			{"Fulfilment rate (%)", report.OrderFulfillmentRate},
+			{"Cancellation rate (%)", report.CancellationRate},
+		},
+	})
+
+	topProducts := export.Section{
+		Name:    "Top products",
+		Columns: []string{"Product ID", "Product", "Quantity sold", "Revenue", "Stock turnover"},
+	}
+	for _, product := range report.TopProducts {
+		topProducts.Rows = append(topProducts.Rows, []any{
+			product.ProductID, product.ProductName, product.QuantitySold, product.Revenue, product.StockTurnover,
+		})
+	}
+
+	lowStock := export.Section{
+		Name:    "Low stock",
+		Columns: []string{"Product ID", "Product", "Current stock", "Reserved stock", "Reorder point"},
+	}
+	for _, alert := range report.LowStockProducts {
+		lowStock.Rows = append(lowStock.Rows, []any{
+			alert.ProductID, alert.ProductName, alert.CurrentStock, alert.ReservedStock, alert.ReorderPoint,
+		})
+	}
+
+	doc.Sections = append(doc.Sections, topProducts, lowStock)
+	return doc
+}
+
+// rangeReportDocument lays out a date range sales report for export: the range against
+// the previous one, then the figures of every period
+func rangeReportDocument(report *dto.SalesReportRangeResponse) *export.Document {
+	doc := &export.Document{
+		Title: fmt.Sprintf("Sales report %s to %s",
+			report.From.Format("2006-01-02"), report.To.Format("2006-01-02")),
+		Subtitle: fmt.Sprintf("By %s (UTC), amounts in %s, compared with %s to %s", report.Granularity, report.Currency,
+			report.PreviousTotals.From.Format("2006-01-02"), report.PreviousTotals.To.Format("2006-01-02")),
+	}
+
+	current, previous := report.Totals, report.PreviousTotals
+	summary := export.Section{
+		Name:    "Summary",
+		Columns: []string{"Metric", "Range", "Previous range", "Change", "Change (%)"},
+		Rows: [][]any{
+			{"Total orders", current.TotalOrders, previous.TotalOrders},
+			{"Delivered orders", current.DeliveredOrders, previous.DeliveredOrders},
+			{"Cancelled orders", current.CancelledOrders, previous.CancelledOrders},
+			{"Gross bookings", current.GrossBookings, previous.GrossBookings},
+			{"Cancellations", current.Cancellations, previous.Cancellations},
+			{"Net revenue", current.NetRevenue, previous.NetRevenue},
+			{"Recognised revenue", current.RecognisedRevenue, previous.RecognisedRevenue},
+			{"Average order value", current.AverageOrderValue, previous.AverageOrderValue},
+			{"Unique customers", current.UniqueCustomers, previous.UniqueCustomers},
+			{"New customers", current.NewCustomers, previous.NewCustomers},
+			{"Fulfilment rate (%)", current.OrderFulfillmentRate, previous.OrderFulfillmentRate},
+			{"Cancellation rate (%)", current.CancellationRate, previous.CancellationRate},
+		},
+	}
+	if change := current.Change; change != nil {
+		for _, delta := range []struct {
+			row     int
+			change  any
+			percent *float64
+		}{
+			{0, change.TotalOrders, change.TotalOrdersPercent},
+			{5, change.TotalRevenue, change.TotalRevenuePercent},
+			{7, change.AverageOrderValue, change.AverageOrderValuePercent},
+			{8, change.UniqueCustomers, change.UniqueCustomersPercent},
+			{10, change.OrderFulfillmentRate, nil},
+			{11, change.CancellationRate, nil},
+		} {
+			summary.Rows[delta.row] = append(summary.Rows[delta.row], delta.change, percentCell(delta.percent))
+		}
+	}
+	for i, row := range summary.Rows {
+		for len(row) < len(summary.Columns) {
+			row = append(row, nil) // Figures that aren't compared
+		}
+		summary.Rows[i] = row
+	}
+
+	periods := export.Section{
+		Name: "Periods",
+		Columns: []string{
+			"From", "To", "Orders", "Delivered", "Cancelled", "Gross bookings", "Cancellations",
+			"Net revenue", "Recognised revenue", "Average order value", "Customers", "New customers",
+			"Fulfilment rate (%)", "Cancellation rate (%)",
+		},
+	}
+	for _, period := range report.Periods {
+		periods.Rows = append(periods.Rows, []any{
+			period.From.Format("2006-01-02"), period.To.Format("2006-01-02"),
+			period.TotalOrders, period.DeliveredOrders, period.CancelledOrders,
+			period.GrossBookings, period.Cancellations, period.NetRevenue, period.RecognisedRevenue,
+			period.AverageOrderValue, period.UniqueCustomers, period.NewCustomers,
+			period.OrderFulfillmentRate, period.CancellationRate,
+		})
+	}
+
+	doc.Sections = append(doc.Sections, summary, periods)
+	return doc
+}
+
+// percentCell shows a percentage change, which is undefined when the previous figure was zero
+func percentCell(percent *float64) any {
+	if percent == nil {
+		return "n/a"
+	}
+	return *percent
+}
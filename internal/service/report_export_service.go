@@ -0,0 +1,297 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/api/dto"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/repository"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/errors"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/export"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/logger"
+)
+
+// ReportExportConfig controls when report exports run in the background and how long
+// their files can be downloaded
+type ReportExportConfig struct {
+	SyncMaxDays int           // Longest range exported within the request; longer ones become export jobs
+	LinkTTL     time.Duration // How long a download link is valid
+	Retention   time.Duration // How long a finished export's file is kept
+	StaleAfter  time.Duration // How long a running export may take before another instance takes over
+	SigningKey  string        // Key download links are signed with
+	BaseURL     string        // Prepended to download links, e.g. https://api.example.com
+}
+
+// ErrCodeInvalidDownloadLink is returned for download links that are forged or expired
+const ErrCodeInvalidDownloadLink = "INVALID_DOWNLOAD_LINK"
+
+// ReportFile is a report rendered to a file
+type ReportFile struct {
+	Name        string
+	ContentType string
+	Content     []byte
+}
+
+type ReportExportService interface {
+	// ExportDailyReport renders the sales report of a day
+	ExportDailyReport(ctx context.Context, date time.Time, format export.Format) (*ReportFile, error)
+	// ExportRangeReport renders the sales report of a date range, or queues an export job
+	// and returns it instead if the range is longer than SyncMaxDays
+	ExportRangeReport(ctx context.Context, userID uint, query *dto.SalesReportRangeQuery, format export.Format) (*ReportFile, *dto.ReportExportResponse, error)
+	// GetExport returns an export job, with a fresh download link once it completed
+	GetExport(ctx context.Context, id uint) (*dto.ReportExportResponse, error)
+	// DownloadExport returns the file of an export if the link's signature is valid
+	DownloadExport(ctx context.Context, id uint, expires int64, signature string) (*ReportFile, error)
+
+	// RunPendingExports renders queued exports until none are left, returning how many
+	// were rendered and how many failed
+	RunPendingExports(ctx context.Context) (completed, failed int, err error)
+	// DeleteExpiredExports deletes the exports whose files expired
+	DeleteExpiredExports(ctx context.Context) (int64, error)
+}
+
+type reportExportService struct {
+	db         *gorm.DB
+	config     ReportExportConfig
+	exportRepo repository.ReportExportRepository
+	reportSvc  *ReportService
+}
+
+func NewReportExportService(
+	db *gorm.DB,
+	config ReportExportConfig,
+	exportRepo repository.ReportExportRepository,
+	reportSvc *ReportService,
+) ReportExportService {
+	if config.SyncMaxDays <= 0 {
+		config.SyncMaxDays = 31
+	}
+	if config.LinkTTL <= 0 {
+		config.LinkTTL = 24 * time.Hour
+	}
+	if config.Retention <= 0 {
+		config.Retention = 7 * 24 * time.Hour
+	}
+	if config.StaleAfter <= 0 {
+		config.StaleAfter = 30 * time.Minute
+	}
+	config.BaseURL = strings.TrimSuffix(config.BaseURL, "/")
+	return &reportExportService{
+		db:         db,
+		config:     config,
+		exportRepo: exportRepo,
+		reportSvc:  reportSvc,
+	}
+}
+
+func (s *reportExportService) ExportDailyReport(ctx context.Context, date time.Time, format export.Format) (*ReportFile, error) {
+	report, err := s.reportSvc.GetDailyReport(ctx, date)
+	if err != nil {
+		return nil, err
+	}
+	name := "sales-report-" + reportDay(report.Date).Format(time.DateOnly)
+	return renderReport(dailyReportDocument(report), name, format)
+}
+
+func (s *reportExportService) ExportRangeReport(ctx context.Context, userID uint, query *dto.SalesReportRangeQuery, format export.Format) (*ReportFile, *dto.ReportExportResponse, error) {
+	from, to, err := reportRange(query.From, query.To)
+	if err != nil {
+		return nil, nil, err
+	}
+	granularity := query.Granularity
+	if granularity == "" {
+		granularity = ReportGranularityDay
+	}
+
+	if days := int(to.Sub(from).Hours()/24) + 1; days > s.config.SyncMaxDays {
+		job := &models.ReportExport{
+			RequestedBy: userID,
+			StartDate:   from,
+			EndDate:     to,
+			Granularity: granularity,
+			Format:      string(format),
+			Status:      models.ReportExportStatusPending,
+		}
+		if err := s.exportRepo.Create(ctx, s.db, job); err != nil {
+			return nil, nil, fmt.Errorf("failed to queue report export: %w", err)
+		}
+		return nil, s.toResponse(job), nil
+	}
+
+	file, err := s.renderRange(ctx, from, to, granularity, format)
+	if err != nil {
+		return nil, nil, err
+	}
+	return file, nil, nil
+}
+
+func (s *reportExportService) GetExport(ctx context.Context, id uint) (*dto.ReportExportResponse, error) {
+	job, err := s.exportRepo.FindByID(ctx, s.db, id)
+	if err != nil {
+		return nil, reportExportLookupError(err)
+	}
+	return s.toResponse(job), nil
+}
+
+func (s *reportExportService) DownloadExport(ctx context.Context, id uint, expires int64, signature string) (*ReportFile, error) {
+	if !hmac.Equal([]byte(signature), []byte(s.sign(id, expires))) || time.Now().Unix() > expires {
+		return nil, errors.NewBusinessError("Download link is invalid or expired", ErrCodeInvalidDownloadLink, http.StatusForbidden)
+	}
+
+	job, err := s.exportRepo.FindWithContent(ctx, s.db, id)
+	if err != nil {
+		return nil, reportExportLookupError(err)
+	}
+	if job.Status != models.ReportExportStatusCompleted || (job.ExpiresAt != nil && time.Now().After(*job.ExpiresAt)) {
+		return nil, errors.NewBusinessError("Report export not found", errors.ErrCodeResourceNotFound, http.StatusNotFound)
+	}
+	return &ReportFile{Name: job.FileName, ContentType: job.ContentType, Content: job.Content}, nil
+}
+
+func (s *reportExportService) RunPendingExports(ctx context.Context) (completed, failed int, err error) {
+	for {
+		job, err := s.claimExport(ctx)
+		if err != nil {
+			return completed, failed, err
+		}
+		if job == nil {
+			return completed, failed, nil
+		}
+
+		file, renderErr := s.renderRange(ctx, job.StartDate, job.EndDate, job.Granularity, export.Format(job.Format))
+		now := time.Now()
+		job.CompletedAt = &now
+		if renderErr != nil {
+			logger.Error(ctx, "Failed to render report export", zap.Uint("export_id", job.ID), zap.Error(renderErr))
+			job.Status = models.ReportExportStatusFailed
+			job.Error = renderErr.Error()
+			failed++
+		} else {
+			expiresAt := now.Add(s.config.Retention)
+			job.Status = models.ReportExportStatusCompleted
+			job.FileName = file.Name
+			job.ContentType = file.ContentType
+			job.Content = file.Content
+			job.ExpiresAt = &expiresAt
+			completed++
+		}
+		if err := s.exportRepo.Update(ctx, s.db, job); err != nil {
+			return completed, failed, fmt.Errorf("failed to save report export: %w", err)
+		}
+	}
+}
+
+// claimExport marks the next queued export as running, or returns nil if there is none
+func (s *reportExportService) claimExport(ctx context.Context) (*models.ReportExport, error) {
+	var job *models.ReportExport
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		job, err = s.exportRepo.ClaimNext(ctx, tx, time.Now().Add(-s.config.StaleAfter))
+		if err == gorm.ErrRecordNotFound {
+			job = nil
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		job.Status = models.ReportExportStatusRunning
+		job.StartedAt = &now
+		return s.exportRepo.Update(ctx, tx, job)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim report export: %w", err)
+	}
+	return job, nil
+}
+
+func (s *reportExportService) DeleteExpiredExports(ctx context.Context) (int64, error) {
+	return s.exportRepo.DeleteExpired(ctx, s.db, time.Now())
+}
+
+// renderRange renders the sales report of the days from the first date through the second
+func (s *reportExportService) renderRange(ctx context.Context, from, to time.Time, granularity string, format export.Format) (*ReportFile, error) {
+	report, err := s.reportSvc.GetRangeReport(ctx, &dto.SalesReportRangeQuery{
+		From:        from.Format(time.DateOnly),
+		To:          to.Format(time.DateOnly),
+		Granularity: granularity,
+	})
+	if err != nil {
+		return nil, err
+	}
+	name := fmt.Sprintf("sales-report-%s-to-%s-by-%s", from.Format(time.DateOnly), to.Format(time.DateOnly), granularity)
+	return renderReport(rangeReportDocument(report), name, format)
+}
+
+// toResponse converts an export job to its response, signing a download link if the file is ready
+func (s *reportExportService) toResponse(job *models.ReportExport) *dto.ReportExportResponse {
+	resp := &dto.ReportExportResponse{
+		ID:          job.ID,
+		Status:      string(job.Status),
+		From:        job.StartDate,
+		To:          job.EndDate,
+		Granularity: job.Granularity,
+		Format:      job.Format,
+		FileName:    job.FileName,
+		Error:       job.Error,
+		CreatedAt:   job.CreatedAt,
+		CompletedAt: job.CompletedAt,
+		ExpiresAt:   job.ExpiresAt,
+	}
+
+	if job.Status == models.ReportExportStatusCompleted && job.ExpiresAt != nil {
+		// A link never outlives the file it points to
+		linkExpiresAt := time.Now().Add(s.config.LinkTTL).Truncate(time.Second)
+		if linkExpiresAt.After(*job.ExpiresAt) {
+			linkExpiresAt = job.ExpiresAt.Truncate(time.Second)
+		}
+		expires := linkExpiresAt.Unix()
+		query := url.Values{
+			"expires":   {strconv.FormatInt(expires, 10)},
+			"signature": {s.sign(job.ID, expires)},
+		}
+		resp.DownloadURL = fmt.Sprintf("%s/api/v1/reports/exports/%d/download?%s", s.config.BaseURL, job.ID, query.Encode())
+		resp.DownloadExpiresAt = &linkExpiresAt
+	}
+	return resp
+}
+
+// sign returns the signature of a download link to an export that is valid until expires
+func (s *reportExportService) sign(id uint, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(s.config.SigningKey))
+	fmt.Fprintf(mac, "report-export:%d:%d", id, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// renderReport renders a report document to a file named after the report
+func renderReport(doc *export.Document, name string, format export.Format) (*ReportFile, error) {
+	content, err := export.Render(doc, format)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render report: %w", err)
+	}
+	return &ReportFile{
+		Name:        name + "." + string(format),
+		ContentType: format.ContentType(),
+		Content:     content,
+	}, nil
+}
+
+func reportExportLookupError(err error) error {
+	if err == gorm.ErrRecordNotFound {
+		return errors.NewBusinessError("Report export not found", errors.ErrCodeResourceNotFound, http.StatusNotFound)
+	}
+	return fmt.Errorf("failed to get report export: %w", err)
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"math"
+	"time"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/repository"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
+)
+
+// Sales report figures are defined as follows, for a UTC day:
+//
+//   - Gross bookings: orders placed on the day, valued as booked at checkout, whatever
+//     happened to them since.
+//   - Cancellations: the part of the gross bookings cancelled since, whole orders or items
+//     removed from orders. Paid orders are refunded exactly these amounts.
+//   - Net revenue: gross bookings less cancellations. TotalRevenue mirrors it.
+//   - Recognised revenue: the net value of the orders delivered on the day, whenever they
+//     were placed.
+//   - Average order value: net revenue over the orders placed on the day that weren't
+//     cancelled.
+//   - Fulfilment and cancellation rates: delivered and cancelled orders as a percentage of
+//     the orders placed on the day.
+//   - Stock turnover: units sold over the average stock on hand during the day.
+//
+// Ratios with nothing to divide by are zero.
+
+// salesFigures are the raw figures of a day that its sales report is computed from.
+// Amounts are in the base currency.
+type salesFigures struct {
+	orderCounts  map[models.OrderStatus]int
+	revenue      repository.DailyRevenue
+	customers    int
+	newCustomers int
+	topProducts  []models.TopProduct
+	averageStock map[uint]float64 // By product ID
+}
+
+// computeDailyReport works out the sales report of a day from its figures, converting the
+// amounts to the reporting currency at the given rate
+func computeDailyReport(day time.Time, figures salesFigures, currency money.Currency, rate money.Rate) (*models.DailySalesReport, error) {
+	convert := func(amount money.Money) (money.Money, error) {
+		return amount.Convert(rate, currency, money.RoundHalfEven)
+	}
+
+	report := &models.DailySalesReport{
+		Date:             day,
+		Currency:         currency,
+		PendingOrders:    figures.orderCounts[models.OrderStatusPending],
+		ProcessingOrders: figures.orderCounts[models.OrderStatusProcessing],
+		ShippedOrders:    figures.orderCounts[models.OrderStatusShipped],
+		DeliveredOrders:  figures.orderCounts[models.OrderStatusDelivered],
+		CancelledOrders:  figures.orderCounts[models.OrderStatusCancelled],
+		UniqueCustomers:  figures.customers,
+		NewCustomers:     figures.newCustomers,
+		TopProducts:      figures.topProducts,
+	}
+	for _, count := range figures.orderCounts {
+		report.TotalOrders += count
+	}
+
+	var err error
+	if report.GrossBookings, err = convert(figures.revenue.GrossBookings); err != nil {
+		return nil, err
+	}
+	if report.Cancellations, err = convert(figures.revenue.Cancellations); err != nil {
+		return nil, err
+	}
+	if report.RecognisedRevenue, err = convert(figures.revenue.RecognisedRevenue); err != nil {
+		return nil, err
+	}
+	// Net revenue is taken from the converted figures so the three always add up
+	if report.NetRevenue, err = report.GrossBookings.Sub(report.Cancellations); err != nil {
+		return nil, err
+	}
+	report.TotalRevenue = report.NetRevenue
+
+	if report.AverageOrderValue, err = averageOrderValue(report.NetRevenue, report.TotalOrders-report.CancelledOrders); err != nil {
+		return nil, err
+	}
+	report.OrderFulfillmentRate = percentage(report.DeliveredOrders, report.TotalOrders)
+	report.CancellationRate = percentage(report.CancelledOrders, report.TotalOrders)
+
+	for i := range report.TopProducts {
+		product := &report.TopProducts[i]
+		if product.Revenue, err = convert(product.Revenue); err != nil {
+			return nil, err
+		}
+		product.StockTurnover = stockTurnover(product.QuantitySold, figures.averageStock[product.ProductID])
+	}
+	return report, nil
+}
+
+// averageOrderValue divides revenue over a number of orders, or returns zero if there are none
+func averageOrderValue(revenue money.Money, orders int) (money.Money, error) {
+	if orders <= 0 {
+		return money.Zero(revenue.Currency()), nil
+	}
+	return revenue.Div(int64(orders), money.RoundHalfEven)
+}
+
+// percentage returns part as a percentage of whole, or zero if whole is zero
+func percentage(part, whole int) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return roundPercent(float64(part) / float64(whole) * 100)
+}
+
+// stockTurnover returns how many times the average stock was sold, or zero if no stock
+// was held
+func stockTurnover(sold int, averageStock float64) float64 {
+	if averageStock <= 0 {
+		return 0
+	}
+	return roundPercent(float64(sold) / averageStock)
+}
+
+// percentChange returns the change from previous to current as a percentage of previous,
+// or nil when previous is zero
+func percentChange(previous, current int64) *float64 {
+	if previous == 0 {
+		return nil
+	}
+	change := roundPercent(float64(current-previous) / math.Abs(float64(previous)) * 100)
+	return &change
+}
+
+// roundPercent rounds a percentage or ratio to two decimal places
+func roundPercent(p float64) float64 {
+	return math.Round(p*100) / 100
+}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/repository"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
+)
+
+func mustRate(t *testing.T, s string) money.Rate {
+	t.Helper()
+	rate, err := money.ParseRate(s)
+	if err != nil {
+		t.Fatalf("ParseRate(%q) unexpected error: %v", s, err)
+	}
+	return rate
+}
+
+func TestComputeDailyReport(t *testing.T) {
+	day := time.Date(2001, 2, 3, 0, 0, 0, 0, time.UTC)
+
+	// A processing order with a unit removed, a cancelled order and a delivered order
+	// placed on the day, plus an earlier order delivered on it
+	busyDay := salesFigures{
+		orderCounts: map[models.OrderStatus]int{
+			models.OrderStatusProcessing: 1,
+			models.OrderStatusCancelled:  1,
+			models.OrderStatusDelivered:  1,
+		},
+		revenue: repository.DailyRevenue{
+			GrossBookings:     money.New(6000, money.USD),
+			Cancellations:     money.New(3500, money.USD),
+			RecognisedRevenue: money.New(4000, money.USD),
+		},
+		customers:    3,
+		newCustomers: 2,
+		averageStock: map[uint]float64{1: 6},
+	}
+	topProducts := func() []models.TopProduct {
+		return []models.TopProduct{
+			{ProductID: 1, ProductName: "pot", QuantitySold: 3, Revenue: money.New(1500, money.USD)},
+			{ProductID: 2, ProductName: "mug", QuantitySold: 2, Revenue: money.New(2000, money.USD)},
+		}
+	}
+
+	type product struct {
+		revenue  money.Money
+		turnover float64
+	}
+	tests := []struct {
+		name                 string
+		figures              salesFigures
+		currency             money.Currency
+		rate                 string
+		wantTotalOrders      int
+		wantGross            money.Money
+		wantCancellations    money.Money
+		wantNet              money.Money
+		wantRecognised       money.Money
+		wantAverageOrder     money.Money
+		wantFulfillmentRate  float64
+		wantCancellationRate float64
+		wantProducts         []product
+	}{
+		{
+			name:              "zero-order day",
+			figures:           salesFigures{orderCounts: map[models.OrderStatus]int{}},
+			currency:          money.USD,
+			rate:              "1",
+			wantGross:         money.Zero(money.USD),
+			wantCancellations: money.Zero(money.USD),
+			wantNet:           money.Zero(money.USD),
+			wantRecognised:    money.Zero(money.USD),
+			wantAverageOrder:  money.Zero(money.USD),
+		},
+		{
+			name:                 "base currency",
+			figures:              busyDay,
+			currency:             money.USD,
+			rate:                 "1",
+			wantTotalOrders:      3,
+			wantGross:            money.New(6000, money.USD),
+			wantCancellations:    money.New(3500, money.USD),
+			wantNet:              money.New(2500, money.USD),
+			wantRecognised:       money.New(4000, money.USD),
+			wantAverageOrder:     money.New(1250, money.USD), // Over the two orders not cancelled
+			wantFulfillmentRate:  33.33,
+			wantCancellationRate: 33.33,
+			wantProducts: []product{
+				{revenue: money.New(1500, money.USD), turnover: 0.5},
+				{revenue: money.New(2000, money.USD), turnover: 0}, // No stock held
+			},
+		},
+		{
+			name:                 "reporting currency",
+			figures:              busyDay,
+			currency:             money.EUR,
+			rate:                 "0.9",
+			wantTotalOrders:      3,
+			wantGross:            money.New(5400, money.EUR),
+			wantCancellations:    money.New(3150, money.EUR),
+			wantNet:              money.New(2250, money.EUR),
+			wantRecognised:       money.New(3600, money.EUR),
+			wantAverageOrder:     money.New(1125, money.EUR),
+			wantFulfillmentRate:  33.33,
+			wantCancellationRate: 33.33,
+			wantProducts: []product{
+				{revenue: money.New(1350, money.EUR), turnover: 0.5},
+				{revenue: money.New(1800, money.EUR), turnover: 0},
+			},
+		},
+		{
+			name: "net revenue adds up after rounding",
+			figures: salesFigures{
+				orderCounts: map[models.OrderStatus]int{models.OrderStatusPending: 3},
+				revenue: repository.DailyRevenue{
+					GrossBookings: money.New(1001, money.USD),
+					Cancellations: money.New(1, money.USD),
+				},
+			},
+			currency:          money.EUR,
+			rate:              "0.5",
+			wantTotalOrders:   3,
+			wantGross:         money.New(500, money.EUR), // 5.005 rounds half to even
+			wantCancellations: money.New(0, money.EUR),
+			wantNet:           money.New(500, money.EUR),
+			wantRecognised:    money.Zero(money.EUR),
+			wantAverageOrder:  money.New(167, money.EUR),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.wantProducts != nil {
+				tt.figures.topProducts = topProducts()
+			}
+			report, err := computeDailyReport(day, tt.figures, tt.currency, mustRate(t, tt.rate))
+			if err != nil {
+				t.Fatalf("computeDailyReport unexpected error: %v", err)
+			}
+
+			if !report.Date.Equal(day) || report.Currency != tt.currency {
+				t.Errorf("report is for %s in %s, want %s in %s", report.Date, report.Currency, day, tt.currency)
+			}
+			if report.TotalOrders != tt.wantTotalOrders {
+				t.Errorf("TotalOrders = %d, want %d", report.TotalOrders, tt.wantTotalOrders)
+			}
+			if report.UniqueCustomers != tt.figures.customers || report.NewCustomers != tt.figures.newCustomers {
+				t.Errorf("customers = %d, %d new; want %d, %d new",
+					report.UniqueCustomers, report.NewCustomers, tt.figures.customers, tt.figures.newCustomers)
+			}
+
+			amounts := []struct {
+				field     string
+				got, want money.Money
+			}{
+				{"GrossBookings", report.GrossBookings, tt.wantGross},
+				{"Cancellations", report.Cancellations, tt.wantCancellations},
+				{"NetRevenue", report.NetRevenue, tt.wantNet},
+				{"TotalRevenue", report.TotalRevenue, tt.wantNet},
+				{"RecognisedRevenue", report.RecognisedRevenue, tt.wantRecognised},
+				{"AverageOrderValue", report.AverageOrderValue, tt.wantAverageOrder},
+			}
+			for _, amount := range amounts {
+				if !amount.got.Equal(amount.want) {
+					t.Errorf("%s = %s %s, want %s %s",
+						amount.field, amount.got, amount.got.Currency(), amount.want, amount.want.Currency())
+				}
+			}
+
+			if report.OrderFulfillmentRate != tt.wantFulfillmentRate {
+				t.Errorf("OrderFulfillmentRate = %v, want %v", report.OrderFulfillmentRate, tt.wantFulfillmentRate)
+			}
+			if report.CancellationRate != tt.wantCancellationRate {
+				t.Errorf("CancellationRate = %v, want %v", report.CancellationRate, tt.wantCancellationRate)
+			}
+
+			if len(report.TopProducts) != len(tt.wantProducts) {
+				t.Fatalf("report has %d top products, want %d", len(report.TopProducts), len(tt.wantProducts))
+			}
+			for i, want := range tt.wantProducts {
+				got := report.TopProducts[i]
+				if !got.Revenue.Equal(want.revenue) {
+					t.Errorf("%s revenue = %s %s, want %s %s",
+						got.ProductName, got.Revenue, got.Revenue.Currency(), want.revenue, want.revenue.Currency())
+				}
+				if got.StockTurnover != want.turnover {
+					t.Errorf("%s turnover = %v, want %v", got.ProductName, got.StockTurnover, want.turnover)
+				}
+			}
+		})
+	}
+}
+
+func TestStockTurnover(t *testing.T) {
+	tests := []struct {
+		sold    int
+		average float64
+		want    float64
+	}{
+		{sold: 12, average: 4, want: 3},
+		{sold: 1, average: 3, want: 0.33},
+		{sold: 2, average: 3, want: 0.67},
+		{sold: 0, average: 5, want: 0},
+		{sold: 5, average: 0, want: 0},
+		{sold: 5, average: -2, want: 0}, // Oversold stock
+	}
+
+	for _, tt := range tests {
+		if got := stockTurnover(tt.sold, tt.average); got != tt.want {
+			t.Errorf("stockTurnover(%d, %v) = %v, want %v", tt.sold, tt.average, got, tt.want)
+		}
+	}
+}
+
+func TestPercentage(t *testing.T) {
+	tests := []struct {
+		part, whole int
+		want        float64
+	}{
+		{part: 1, whole: 4, want: 25},
+		{part: 2, whole: 3, want: 66.67},
+		{part: 3, whole: 3, want: 100},
+		{part: 0, whole: 3, want: 0},
+		{part: 0, whole: 0, want: 0},
+	}
+
+	for _, tt := range tests {
+		if got := percentage(tt.part, tt.whole); got != tt.want {
+			t.Errorf("percentage(%d, %d) = %v, want %v", tt.part, tt.whole, got, tt.want)
+		}
+	}
+}
+
+func TestPercentChange(t *testing.T) {
+	change := func(p float64) *float64 { return &p }
+	tests := []struct {
+		previous, current int64
+		want              *float64
+	}{
+		{previous: 100, current: 150, want: change(50)},
+		{previous: 150, current: 100, want: change(-33.33)},
+		{previous: 100, current: 0, want: change(-100)},
+		{previous: -200, current: -100, want: change(50)},
+		{previous: 5, current: 5, want: change(0)},
+		{previous: 0, current: 100, want: nil},
+	}
+
+	for _, tt := range tests {
+		got := percentChange(tt.previous, tt.current)
+		switch {
+		case got == nil && tt.want == nil:
+		case got == nil || tt.want == nil:
+			t.Errorf("percentChange(%d, %d) = %v, want %v", tt.previous, tt.current, got, tt.want)
+		case *got != *tt.want:
+			t.Errorf("percentChange(%d, %d) = %v, want %v", tt.previous, tt.current, *got, *tt.want)
+		}
+	}
+}
+
+func TestAverageOrderValue(t *testing.T) {
+	tests := []struct {
+		revenue money.Money
+		orders  int
+		want    money.Money
+	}{
+		{revenue: money.New(1000, money.USD), orders: 4, want: money.New(250, money.USD)},
+		{revenue: money.New(1000, money.USD), orders: 3, want: money.New(333, money.USD)},
+		{revenue: money.New(1000, money.JPY), orders: 0, want: money.Zero(money.JPY)},
+		{revenue: money.New(1000, money.USD), orders: -1, want: money.Zero(money.USD)},
+	}
+
+	for _, tt := range tests {
+		got, err := averageOrderValue(tt.revenue, tt.orders)
+		if err != nil {
+			t.Fatalf("averageOrderValue(%s, %d) unexpected error: %v", tt.revenue, tt.orders, err)
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("averageOrderValue(%s, %d) = %s %s, want %s %s",
+				tt.revenue, tt.orders, got, got.Currency(), tt.want, tt.want.Currency())
+		}
+	}
+}
@@ -0,0 +1,274 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/api/dto"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/repository"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/errors"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
+)
+
+// Report periods a date range can be broken down into
+const (
+	ReportGranularityDay   = "day"
+	ReportGranularityWeek  = "week"
+	ReportGranularityMonth = "month"
+)
+
+// maxReportRangeDays caps how many days a range report covers
+const maxReportRangeDays = 366
+
+// GetRangeReport aggregates the daily reports of a date range into day, week or month
+// periods, with deltas against the previous period. Missing reports of past days are
+// generated on demand, and today's figures are computed live.
+func (s *ReportService) GetRangeReport(ctx context.Context, query *dto.SalesReportRangeQuery) (*dto.SalesReportRangeResponse, error) {
+	from, to, err := reportRange(query.From, query.To)
+	if err != nil {
+		return nil, err
+	}
+	granularity := query.Granularity
+	if granularity == "" {
+		granularity = ReportGranularityDay
+	}
+
+	// The previous period is the same number of days right before the range
+	days := int(to.Sub(from).Hours()/24) + 1
+	previousFrom := from.AddDate(0, 0, -days)
+	previousTo := from.AddDate(0, 0, -1)
+
+	reports, err := s.dailyReports(ctx, previousFrom, to)
+	if err != nil {
+		return nil, err
+	}
+	currency := reports[0].Currency
+	for _, report := range reports {
+		if report.Currency != currency {
+			return nil, errors.NewBusinessError(
+				"Reports in the range use different reporting currencies",
+				"REPORT_CURRENCY_MISMATCH",
+				http.StatusConflict,
+			)
+		}
+	}
+
+	resp := &dto.SalesReportRangeResponse{
+		From:        from,
+		To:          to,
+		Granularity: granularity,
+		Currency:    string(currency),
+	}
+
+	if resp.PreviousTotals, err = summarizeReports(reports, previousFrom, previousTo, currency); err != nil {
+		return nil, err
+	}
+	if resp.Totals, err = summarizeReports(reports, from, to, currency); err != nil {
+		return nil, err
+	}
+	if err := s.countCustomers(ctx, &resp.PreviousTotals); err != nil {
+		return nil, err
+	}
+	if err := s.countCustomers(ctx, &resp.Totals); err != nil {
+		return nil, err
+	}
+	if resp.Totals.Change, err = compareSalesPeriods(resp.PreviousTotals, resp.Totals); err != nil {
+		return nil, err
+	}
+
+	customers, err := s.userRepo.GetCustomerStatsByPeriod(ctx, s.db, from, to.AddDate(0, 0, 1), granularity)
+	if err != nil {
+		return nil, err
+	}
+	customersByPeriod := make(map[time.Time]repository.CustomerPeriodStats, len(customers))
+	for _, stats := range customers {
+		customersByPeriod[stats.Period] = stats
+	}
+
+	for start := from; !start.After(to); {
+		periodStart := reportPeriodStart(start, granularity)
+		end := reportPeriodEnd(periodStart, granularity)
+		if end.After(to) {
+			end = to
+		}
+
+		period, err := summarizeReports(reports, start, end, currency)
+		if err != nil {
+			return nil, err
+		}
+		period.UniqueCustomers = customersByPeriod[periodStart].Customers
+		period.NewCustomers = customersByPeriod[periodStart].New
+		if n := len(resp.Periods); n > 0 {
+			if period.Change, err = compareSalesPeriods(resp.Periods[n-1], period); err != nil {
+				return nil, err
+			}
+		}
+		resp.Periods = append(resp.Periods, period)
+
+		start = end.AddDate(0, 0, 1)
+	}
+
+	return resp, nil
+}
+
+// dailyReports returns the report of every day from the first date through the second,
+// generating the missing ones
+func (s *ReportService) dailyReports(ctx context.Context, from, to time.Time) ([]*models.DailySalesReport, error) {
+	stored, err := s.reportRepo.ListReportsBetween(ctx, s.db, from, to)
+	if err != nil {
+		return nil, err
+	}
+	byDay := make(map[time.Time]*models.DailySalesReport, len(stored))
+	for i := range stored {
+		byDay[reportDay(stored[i].Date)] = &stored[i]
+	}
+
+	today := reportDay(time.Now())
+	var reports []*models.DailySalesReport
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		report, ok := byDay[day]
+		if !ok {
+			if day.Equal(today) {
+				report, err = s.buildDailyReport(ctx, s.db, day)
+			} else {
+				report, err = s.generateMissingReport(ctx, day)
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// countCustomers fills in the customers who ordered in a summary's days
+func (s *ReportService) countCustomers(ctx context.Context, summary *dto.SalesPeriodSummary) error {
+	var err error
+	summary.UniqueCustomers, summary.NewCustomers, err = s.userRepo.GetCustomerStatsBetween(ctx, s.db, summary.From, summary.To.AddDate(0, 0, 1))
+	return err
+}
+
+// reportRange parses and checks the days of a range report
+func reportRange(fromParam, toParam string) (time.Time, time.Time, error) {
+	from, err := time.Parse(time.DateOnly, fromParam)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.NewValidationError("Invalid date range", map[string]string{"from": "must be a YYYY-MM-DD date"}, http.StatusBadRequest)
+	}
+	to, err := time.Parse(time.DateOnly, toParam)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.NewValidationError("Invalid date range", map[string]string{"to": "must be a YYYY-MM-DD date"}, http.StatusBadRequest)
+	}
+
+	switch {
+	case to.Before(from):
+		return time.Time{}, time.Time{}, errors.NewValidationError("Invalid date range", map[string]string{"to": "must not be before from"}, http.StatusBadRequest)
+	case to.After(reportDay(time.Now())):
+		return time.Time{}, time.Time{}, errors.NewValidationError("Invalid date range", map[string]string{"to": "must not be in the future"}, http.StatusBadRequest)
+	case to.Sub(from) >= maxReportRangeDays*24*time.Hour:
+		return time.Time{}, time.Time{}, errors.NewValidationError("Invalid date range", map[string]string{"to": "range must not be longer than 366 days"}, http.StatusBadRequest)
+	}
+	return from, to, nil
+}
+
+// reportPeriodStart returns the first day of the UTC day, ISO week or month containing day
+func reportPeriodStart(day time.Time, granularity string) time.Time {
+	switch granularity {
+	case ReportGranularityWeek:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case ReportGranularityMonth:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+// reportPeriodEnd returns the last day of the period starting on start
+func reportPeriodEnd(start time.Time, granularity string) time.Time {
+	switch granularity {
+	case ReportGranularityWeek:
+		return start.AddDate(0, 0, 6)
+	case ReportGranularityMonth:
+		return start.AddDate(0, 1, -1)
+	default:
+		return start
+	}
+}
+
+// summarizeReports adds up the daily reports of the days from the first date through the
+// second. Customers can't be added up across days, so they are left for the caller.
+func summarizeReports(reports []*models.DailySalesReport, from, to time.Time, currency money.Currency) (dto.SalesPeriodSummary, error) {
+	summary := dto.SalesPeriodSummary{
+		From:              from,
+		To:                to,
+		GrossBookings:     money.Zero(currency),
+		Cancellations:     money.Zero(currency),
+		NetRevenue:        money.Zero(currency),
+		RecognisedRevenue: money.Zero(currency),
+		TotalRevenue:      money.Zero(currency),
+	}
+
+	for _, report := range reports {
+		day := reportDay(report.Date)
+		if day.Before(from) || day.After(to) {
+			continue
+		}
+		summary.TotalOrders += report.TotalOrders
+		summary.PendingOrders += report.PendingOrders
+		summary.ProcessingOrders += report.ProcessingOrders
+		summary.ShippedOrders += report.ShippedOrders
+		summary.DeliveredOrders += report.DeliveredOrders
+		summary.CancelledOrders += report.CancelledOrders
+
+		for _, sum := range []struct {
+			total  *money.Money
+			amount money.Money
+		}{
+			{&summary.GrossBookings, report.GrossBookings},
+			{&summary.Cancellations, report.Cancellations},
+			{&summary.NetRevenue, report.NetRevenue},
+			{&summary.RecognisedRevenue, report.RecognisedRevenue},
+			{&summary.TotalRevenue, report.TotalRevenue},
+		} {
+			var err error
+			if *sum.total, err = sum.total.Add(sum.amount); err != nil {
+				return dto.SalesPeriodSummary{}, err
+			}
+		}
+	}
+
+	var err error
+	if summary.AverageOrderValue, err = averageOrderValue(summary.TotalRevenue, summary.TotalOrders-summary.CancelledOrders); err != nil {
+		return dto.SalesPeriodSummary{}, err
+	}
+	summary.OrderFulfillmentRate = percentage(summary.DeliveredOrders, summary.TotalOrders)
+	summary.CancellationRate = percentage(summary.CancelledOrders, summary.TotalOrders)
+	return summary, nil
+}
+
+// compareSalesPeriods returns how the current period changed since the previous one
+func compareSalesPeriods(previous, current dto.SalesPeriodSummary) (*dto.SalesPeriodChange, error) {
+	revenue, err := current.TotalRevenue.Sub(previous.TotalRevenue)
+	if err != nil {
+		return nil, err
+	}
+	averageOrderValue, err := current.AverageOrderValue.Sub(previous.AverageOrderValue)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.SalesPeriodChange{
+		TotalOrders:              current.TotalOrders - previous.TotalOrders,
+		TotalOrdersPercent:       percentChange(int64(previous.TotalOrders), int64(current.TotalOrders)),
+		TotalRevenue:             revenue,
+		TotalRevenuePercent:      percentChange(previous.TotalRevenue.Amount(), current.TotalRevenue.Amount()),
+		AverageOrderValue:        averageOrderValue,
+		AverageOrderValuePercent: percentChange(previous.AverageOrderValue.Amount(), current.AverageOrderValue.Amount()),
+		UniqueCustomers:          current.UniqueCustomers - previous.UniqueCustomers,
+		UniqueCustomersPercent:   percentChange(int64(previous.UniqueCustomers), int64(current.UniqueCustomers)),
+		OrderFulfillmentRate:     roundPercent(current.OrderFulfillmentRate - previous.OrderFulfillmentRate),
+		CancellationRate:         roundPercent(current.CancellationRate - previous.CancellationRate),
+	}, nil
+}
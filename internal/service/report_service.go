@@ -2,51 +2,235 @@ package service
 
 import (
 	"context"
+	"net/http"
 	"time"
 
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/api/dto"
 	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
 	"github.com/Ahmed1monm/backend-golang-task-2025/internal/repository"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/errors"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/logger"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/redis"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
+// reportLockTTL bounds how long an instance that died while generating a report keeps
+// the others from generating it
+const reportLockTTL = 5 * time.Minute
+
 type ReportService struct {
-	reportRepo repository.ReportRepository
+	db           *gorm.DB
+	reportRepo   repository.ReportRepository
+	orderRepo    repository.OrderRepository
+	userRepo     repository.UserRepository
+	productRepo  repository.ProductRepository
+	currencySvc  CurrencyService
+	redisService redis.Service
 }
 
-func NewReportService(reportRepo repository.ReportRepository) *ReportService {
+func NewReportService(
+	db *gorm.DB,
+	reportRepo repository.ReportRepository,
+	orderRepo repository.OrderRepository,
+	userRepo repository.UserRepository,
+	productRepo repository.ProductRepository,
+	currencySvc CurrencyService,
+	redisService redis.Service,
+) *ReportService {
 	return &ReportService{
-		reportRepo: reportRepo,
+		db:           db,
+		reportRepo:   reportRepo,
+		orderRepo:    orderRepo,
+		userRepo:     userRepo,
+		productRepo:  productRepo,
+		currencySvc:  currencySvc,
+		redisService: redisService,
 	}
 }
 
-// GetDailyReport returns the sales report for the specified date.
-// If no report exists, returns an empty report structure.
+// GetDailyReport returns the sales report for the specified day. A missing report for a
+// past day is generated and stored; today's report is computed live and not stored
+// until the day is over.
 func (s *ReportService) GetDailyReport(ctx context.Context, date time.Time) (*models.DailySalesReport, error) {
-	// Get report from repository
-	report, err := s.reportRepo.GetReportByDate(ctx, nil, date)
+	day := reportDay(date)
+	today := reportDay(time.Now())
+	if day.After(today) {
+		return nil, errors.NewValidationError("Invalid report date", map[string]string{"date": "must not be in the future"}, http.StatusBadRequest)
+	}
+
+	report, err := s.reportRepo.GetReportByDate(ctx, s.db, day)
+	if err == nil {
+		return report, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	if day.Equal(today) {
+		return s.buildDailyReport(ctx, s.db, day)
+	}
+	return s.generateMissingReport(ctx, day)
+}
+
+// GenerateReports generates and stores the reports of the completed days from the first
+// date through the second, replacing stored ones unless onlyMissing is set. Days another
+// instance is generating at the same time are skipped.
+func (s *ReportService) GenerateReports(ctx context.Context, from, to time.Time, onlyMissing bool) (*dto.ReportGenerationResponse, error) {
+	from, to = reportDay(from), reportDay(to)
+	switch {
+	case to.Before(from):
+		return nil, errors.NewValidationError("Invalid date range", map[string]string{"to": "must not be before from"}, http.StatusBadRequest)
+	case !to.Before(reportDay(time.Now())):
+		return nil, errors.NewValidationError("Invalid date range", map[string]string{"to": "must be a completed day, before today"}, http.StatusBadRequest)
+	case to.Sub(from) >= maxReportRangeDays*24*time.Hour:
+		return nil, errors.NewValidationError("Invalid date range", map[string]string{"to": "range must not be longer than 366 days"}, http.StatusBadRequest)
+	}
+
+	resp := &dto.ReportGenerationResponse{
+		Generated: []time.Time{},
+		Existing:  []time.Time{},
+		Skipped:   []time.Time{},
+	}
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		_, outcome, err := s.storeDailyReport(ctx, day, onlyMissing)
+		if err != nil {
+			return nil, err
+		}
+		switch outcome {
+		case reportGenerated:
+			resp.Generated = append(resp.Generated, day)
+		case reportExisting:
+			resp.Existing = append(resp.Existing, day)
+		case reportLocked:
+			resp.Skipped = append(resp.Skipped, day)
+		}
+	}
+	return resp, nil
+}
+
+// reportOutcome tells what storeDailyReport did with a day
+type reportOutcome int
+
+const (
+	reportGenerated reportOutcome = iota
+	reportExisting
+	reportLocked
+)
+
+// storeDailyReport generates and upserts the report of a day while holding the day's lock,
+// so only one instance computes it at a time. With onlyMissing, a report already stored
+// is returned instead. No report is returned if another instance holds the lock. If
+// Redis is unavailable the report is generated anyway; the upsert keeps that safe.
+func (s *ReportService) storeDailyReport(ctx context.Context, day time.Time, onlyMissing bool) (*models.DailySalesReport, reportOutcome, error) {
+	release, acquired, err := s.redisService.Lock(ctx, "lock:reports:daily:"+day.Format(time.DateOnly), reportLockTTL)
+	switch {
+	case err != nil:
+		logger.Error(ctx, "Generating daily report without a lock", zap.String("date", day.Format(time.DateOnly)), zap.Error(err))
+	case !acquired:
+		return nil, reportLocked, nil
+	default:
+		defer release()
+	}
+
+	if onlyMissing {
+		report, err := s.reportRepo.GetReportByDate(ctx, s.db, day)
+		if err == nil {
+			return report, reportExisting, nil
+		}
+		if err != gorm.ErrRecordNotFound {
+			return nil, 0, err
+		}
+	}
+
+	var report *models.DailySalesReport
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if report, err = s.buildDailyReport(ctx, tx, day); err != nil {
+			return err
+		}
+		return s.reportRepo.SaveReport(ctx, tx, report)
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	logger.Info(ctx, "Generated daily report", zap.String("date", day.Format(time.DateOnly)))
+	return report, reportGenerated, nil
+}
+
+// generateMissingReport generates the report of a past day on demand. If another instance
+// is generating it at the same time, the report is computed without storing it.
+func (s *ReportService) generateMissingReport(ctx context.Context, day time.Time) (*models.DailySalesReport, error) {
+	report, outcome, err := s.storeDailyReport(ctx, day, true)
+	if err != nil {
+		return nil, err
+	}
+	if outcome == reportLocked {
+		return s.buildDailyReport(ctx, s.db, day)
+	}
+	return report, nil
+}
+
+// buildDailyReport computes the sales report for a day without storing it. Stock levels
+// aren't kept historically, so the low stock snapshot is only taken for the current day
+// and the one that just ended.
+func (s *ReportService) buildDailyReport(ctx context.Context, tx *gorm.DB, day time.Time) (*models.DailySalesReport, error) {
+	var figures salesFigures
+	var err error
+
+	// Get order statistics
+	if figures.orderCounts, figures.revenue, err = s.orderRepo.GetOrderStatsByDate(ctx, tx, day); err != nil {
+		return nil, err
+	}
+
+	// Get customer statistics
+	if figures.customers, figures.newCustomers, err = s.userRepo.GetCustomerStatsBetween(ctx, tx, day, day.AddDate(0, 0, 1)); err != nil {
+		return nil, err
+	}
+
+	// Get top products and the stock they turned over
+	if figures.topProducts, err = s.productRepo.GetTopProducts(ctx, tx, day, 10); err != nil {
+		return nil, err
+	}
+	productIDs := make([]uint, len(figures.topProducts))
+	for i, product := range figures.topProducts {
+		productIDs[i] = product.ProductID
+	}
+	if figures.averageStock, err = s.productRepo.GetAverageStock(ctx, tx, productIDs, day); err != nil {
+		return nil, err
+	}
+
+	// Revenue is summed in the base currency; normalise it to the reporting currency
+	currency := s.currencySvc.ReportingCurrency()
+	rate, err := s.currencySvc.Rate(ctx, tx, currency, day)
 	if err != nil {
 		return nil, err
 	}
+	if rate.IsZero() {
+		logger.Error(ctx, "No exchange rate for reporting currency, reporting in base currency",
+			zap.String("currency", string(currency)))
+		currency, rate = money.DefaultCurrency, money.IdentityRate()
+	}
 
-	// If no report exists yet [this will mean that the CRON job not finished yet], return empty report structure
-	if report == nil {
-		report = &models.DailySalesReport{
-			Date:                 date,
-			TotalOrders:          0,
-			PendingOrders:        0,
-			ProcessingOrders:     0,
-			ShippedOrders:        0,
-			DeliveredOrders:      0,
-			CancelledOrders:      0,
-			TotalRevenue:         0,
-			AverageOrderValue:    0,
-			UniqueCustomers:      0,
-			NewCustomers:         0,
-			TopProducts:          []models.TopProduct{},
-			LowStockProducts:     []models.LowStockAlert{},
-			OrderFulfillmentRate: 0,
-			CancellationRate:     0,
+	report, err := computeDailyReport(day, figures, currency, rate)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get low stock alerts
+	report.LowStockProducts = []models.LowStockAlert{}
+	if !day.Before(reportDay(time.Now()).AddDate(0, 0, -1)) {
+		if report.LowStockProducts, err = s.productRepo.GetLowStockProducts(ctx, tx); err != nil {
+			return nil, err
 		}
 	}
 
 	return report, nil
 }
+
+// reportDay returns the UTC day a report covers
+func reportDay(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
@@ -0,0 +1,540 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/api/dto"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/repository"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/errors"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/logger"
+)
+
+// SubscriptionConfig controls renewal reminders and retries
+type SubscriptionConfig struct {
+	ReminderLead       time.Duration // How long before a renewal the customer is reminded
+	RetryDelay         time.Duration // Wait before a failed renewal is tried again
+	MaxRenewalAttempts int           // Failed attempts after which the subscription is paused
+	RenewalBatchSize   int           // Subscriptions renewed per scheduler run
+}
+
+// ErrCodeInvalidSubscriptionStatus is returned for actions the subscription's status doesn't allow
+const ErrCodeInvalidSubscriptionStatus = "INVALID_SUBSCRIPTION_STATUS"
+
+type SubscriptionService interface {
+	CreateSubscription(ctx context.Context, userID uint, currency string, req *dto.CreateSubscriptionRequest) (*dto.SubscriptionResponse, error)
+	ListSubscriptions(ctx context.Context, userID uint) ([]dto.SubscriptionResponse, error)
+	GetSubscription(ctx context.Context, userID, id uint) (*dto.SubscriptionResponse, error)
+	PauseSubscription(ctx context.Context, userID, id uint) (*dto.SubscriptionResponse, error)
+	ResumeSubscription(ctx context.Context, userID, id uint) (*dto.SubscriptionResponse, error)
+	// SkipNextRenewal moves the next renewal, or a pending retry, to the following period
+	SkipNextRenewal(ctx context.Context, userID, id uint) (*dto.SubscriptionResponse, error)
+	CancelSubscription(ctx context.Context, userID, id uint) (*dto.SubscriptionResponse, error)
+
+	// RenewDue places and charges the orders of subscriptions due for renewal. Failed
+	// renewals are retried later and pause the subscription after too many attempts.
+	RenewDue(ctx context.Context, now time.Time) (renewed, failed int, err error)
+	// SendReminders notifies customers of renewals coming up within the reminder lead
+	SendReminders(ctx context.Context, now time.Time) (int, error)
+}
+
+type subscriptionService struct {
+	db               *gorm.DB
+	config           SubscriptionConfig
+	subscriptionRepo repository.SubscriptionRepository
+	productRepo      repository.ProductRepository
+	currencySvc      CurrencyService
+	orderSvc         *OrderService
+	notificationSvc  NotificationService
+}
+
+func NewSubscriptionService(
+	db *gorm.DB,
+	config SubscriptionConfig,
+	subscriptionRepo repository.SubscriptionRepository,
+	productRepo repository.ProductRepository,
+	currencySvc CurrencyService,
+	orderSvc *OrderService,
+	notificationSvc NotificationService,
+) SubscriptionService {
+	if config.ReminderLead <= 0 {
+		config.ReminderLead = 24 * time.Hour
+	}
+	if config.RetryDelay <= 0 {
+		config.RetryDelay = 24 * time.Hour
+	}
+	if config.MaxRenewalAttempts <= 0 {
+		config.MaxRenewalAttempts = 3
+	}
+	if config.RenewalBatchSize <= 0 {
+		config.RenewalBatchSize = 100
+	}
+	return &subscriptionService{
+		db:               db,
+		config:           config,
+		subscriptionRepo: subscriptionRepo,
+		productRepo:      productRepo,
+		currencySvc:      currencySvc,
+		orderSvc:         orderSvc,
+		notificationSvc:  notificationSvc,
+	}
+}
+
+func (s *subscriptionService) CreateSubscription(ctx context.Context, userID uint, currency string, req *dto.CreateSubscriptionRequest) (*dto.SubscriptionResponse, error) {
+	code, err := s.currencySvc.ResolveCurrency(ctx, userID, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription := &models.Subscription{
+		UserID:             userID,
+		Cadence:            models.SubscriptionCadence(req.Cadence),
+		Status:             models.SubscriptionStatusActive,
+		Currency:           code,
+		PaymentMethodToken: req.PaymentMethodToken,
+		NextRenewalAt:      time.Now(),
+		Items:              make([]models.SubscriptionItem, len(req.Items)),
+	}
+	if req.StartsAt != nil && req.StartsAt.After(subscription.NextRenewalAt) {
+		subscription.NextRenewalAt = *req.StartsAt
+	}
+	if req.ShippingAddress != nil {
+		subscription.ShippingAddress = req.ShippingAddress.ToModel()
+	}
+
+	products := make([]*models.Product, len(req.Items))
+	seen := make(map[uint]bool, len(req.Items))
+	for i, item := range req.Items {
+		if seen[item.ProductID] {
+			return nil, errors.NewValidationError(
+				fmt.Sprintf("Product with ID %d is listed more than once", item.ProductID),
+				map[string]string{"product_id": "must be unique"},
+				http.StatusBadRequest,
+			)
+		}
+		seen[item.ProductID] = true
+
+		product, err := s.productRepo.FindByID(ctx, item.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		if product == nil || product.Discontinued {
+			return nil, errors.NewValidationError(
+				fmt.Sprintf("Product with ID %d is not available", item.ProductID),
+				map[string]string{"product_id": "product not found or discontinued"},
+				http.StatusBadRequest,
+			)
+		}
+		products[i] = product
+		subscription.Items[i] = models.SubscriptionItem{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+		}
+	}
+
+	if err := s.subscriptionRepo.Create(ctx, s.db, subscription); err != nil {
+		return nil, fmt.Errorf("failed to create subscription: %w", err)
+	}
+	for i := range subscription.Items {
+		subscription.Items[i].Product = *products[i]
+	}
+
+	resp := dto.SubscriptionToResponse(subscription)
+	return &resp, nil
+}
+
+func (s *subscriptionService) ListSubscriptions(ctx context.Context, userID uint) ([]dto.SubscriptionResponse, error) {
+	subscriptions, err := s.subscriptionRepo.ListByUser(ctx, s.db, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list subscriptions: %w", err)
+	}
+
+	resp := make([]dto.SubscriptionResponse, len(subscriptions))
+	for i := range subscriptions {
+		resp[i] = dto.SubscriptionToResponse(&subscriptions[i])
+	}
+	return resp, nil
+}
+
+func (s *subscriptionService) GetSubscription(ctx context.Context, userID, id uint) (*dto.SubscriptionResponse, error) {
+	subscription, err := s.subscriptionRepo.FindByID(ctx, s.db, id)
+	if err != nil {
+		return nil, subscriptionLookupError(err)
+	}
+	if subscription.UserID != userID {
+		return nil, errors.NewBusinessError("Subscription does not belong to user", "UNAUTHORIZED_ACCESS", http.StatusForbidden)
+	}
+
+	resp := dto.SubscriptionToResponse(subscription)
+	return &resp, nil
+}
+
+func (s *subscriptionService) PauseSubscription(ctx context.Context, userID, id uint) (*dto.SubscriptionResponse, error) {
+	return s.updateSubscription(ctx, userID, id, func(subscription *models.Subscription, now time.Time) error {
+		if subscription.Status != models.SubscriptionStatusActive {
+			return invalidSubscriptionStatus("paused", subscription.Status)
+		}
+		subscription.Status = models.SubscriptionStatusPaused
+		subscription.PausedAt = &now
+		subscription.RetryAt = nil
+		return nil
+	})
+}
+
+func (s *subscriptionService) ResumeSubscription(ctx context.Context, userID, id uint) (*dto.SubscriptionResponse, error) {
+	return s.updateSubscription(ctx, userID, id, func(subscription *models.Subscription, now time.Time) error {
+		if subscription.Status != models.SubscriptionStatusPaused {
+			return invalidSubscriptionStatus("resumed", subscription.Status)
+		}
+		subscription.Status = models.SubscriptionStatusActive
+		subscription.PausedAt = nil
+		subscription.FailureCount = 0
+
+		// Renewals missed while paused are not made up
+		if next := subscription.Cadence.NextAfter(subscription.NextRenewalAt, now); !next.Equal(subscription.NextRenewalAt) {
+			subscription.NextRenewalAt = next
+			subscription.ReminderSentAt = nil
+		}
+		return nil
+	})
+}
+
+func (s *subscriptionService) SkipNextRenewal(ctx context.Context, userID, id uint) (*dto.SubscriptionResponse, error) {
+	return s.updateSubscription(ctx, userID, id, func(subscription *models.Subscription, now time.Time) error {
+		if subscription.Status != models.SubscriptionStatusActive {
+			return invalidSubscriptionStatus("skipped", subscription.Status)
+		}
+		subscription.NextRenewalAt = subscription.Cadence.NextAfter(subscription.Cadence.Next(subscription.NextRenewalAt), now)
+		subscription.RetryAt = nil
+		subscription.FailureCount = 0
+		subscription.ReminderSentAt = nil
+		return nil
+	})
+}
+
+func (s *subscriptionService) CancelSubscription(ctx context.Context, userID, id uint) (*dto.SubscriptionResponse, error) {
+	return s.updateSubscription(ctx, userID, id, func(subscription *models.Subscription, now time.Time) error {
+		if subscription.Status == models.SubscriptionStatusCancelled {
+			return invalidSubscriptionStatus("cancelled", subscription.Status)
+		}
+		subscription.Status = models.SubscriptionStatusCancelled
+		subscription.CancelledAt = &now
+		subscription.RetryAt = nil
+		return nil
+	})
+}
+
+// updateSubscription locks one of the user's subscriptions, applies the change and saves it
+func (s *subscriptionService) updateSubscription(ctx context.Context, userID, id uint, change func(*models.Subscription, time.Time) error) (*dto.SubscriptionResponse, error) {
+	var subscription *models.Subscription
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		subscription, err = s.subscriptionRepo.FindByIDForUpdate(ctx, tx, id)
+		if err != nil {
+			return subscriptionLookupError(err)
+		}
+		if subscription.UserID != userID {
+			return errors.NewBusinessError("Subscription does not belong to user", "UNAUTHORIZED_ACCESS", http.StatusForbidden)
+		}
+		if err := change(subscription, time.Now()); err != nil {
+			return err
+		}
+		return s.subscriptionRepo.Update(ctx, tx, subscription)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := dto.SubscriptionToResponse(subscription)
+	return &resp, nil
+}
+
+func (s *subscriptionService) RenewDue(ctx context.Context, now time.Time) (int, int, error) {
+	ids, err := s.subscriptionRepo.ListDueIDs(ctx, s.db, now, s.config.RenewalBatchSize)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to list due subscriptions: %w", err)
+	}
+
+	renewed, failed := 0, 0
+	for _, id := range ids {
+		ok, err := s.renew(ctx, id, now)
+		if err != nil {
+			logger.Error(ctx, "Failed to renew subscription", zap.Error(err), zap.Uint("subscription_id", id))
+			continue
+		}
+		if ok {
+			renewed++
+		} else {
+			failed++
+		}
+	}
+	return renewed, failed, nil
+}
+
+// renewalClaim is how long an instance has a due subscription to itself while renewing
+// it. It outlasts placing and charging an order; the claim of an instance that stopped
+// midway expires after it and the renewal is taken up again.
+const renewalClaim = 15 * time.Minute
+
+// renew places and charges the order of one due subscription. The subscription is claimed
+// first, so no other instance renews it meanwhile without its row staying locked while the
+// payment is processed. It reports whether the renewal succeeded; an error means the
+// subscription could not be processed at all.
+func (s *subscriptionService) renew(ctx context.Context, id uint, now time.Time) (bool, error) {
+	var subscription *models.Subscription
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		subscription, err = s.subscriptionRepo.ClaimDue(ctx, tx, id, now)
+		if err == gorm.ErrRecordNotFound {
+			subscription = nil
+			return nil // Renewed, changed or claimed by someone else meanwhile
+		}
+		if err != nil {
+			return err
+		}
+		claimedUntil := now.Add(renewalClaim)
+		subscription.ClaimedUntil = &claimedUntil
+		return s.subscriptionRepo.Update(ctx, tx, subscription)
+	})
+	if err != nil || subscription == nil {
+		return false, err
+	}
+
+	order, renewErr := s.placeRenewalOrder(ctx, subscription)
+
+	// Record the outcome on the subscription as it is now, since the customer may have
+	// changed it while the order was placed
+	var abandoned *uint
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		subscription, err = s.subscriptionRepo.FindByIDForUpdate(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		subscription.ClaimedUntil = nil
+
+		if renewErr == nil {
+			subscription.FailureCount = 0
+			subscription.LastFailureReason = ""
+			subscription.RetryAt = nil
+			subscription.ReminderSentAt = nil
+			subscription.RenewalOrderID = nil
+			subscription.LastOrderID = &order.ID
+			subscription.LastRenewedAt = &now
+			subscription.NextRenewalAt = subscription.Cadence.NextAfter(subscription.NextRenewalAt, now)
+			return s.subscriptionRepo.Update(ctx, tx, subscription)
+		}
+
+		// An order kept for the retry is charged again with the same idempotency key
+		if order == nil {
+			subscription.RenewalOrderID = nil
+		}
+		if subscription.Status == models.SubscriptionStatusActive {
+			s.recordRenewalFailure(subscription, renewErr, now)
+		}
+		if subscription.Status != models.SubscriptionStatusActive && subscription.RenewalOrderID != nil {
+			// No retry is coming for the order kept for it
+			abandoned = subscription.RenewalOrderID
+			subscription.RenewalOrderID = nil
+		}
+		return s.subscriptionRepo.Update(ctx, tx, subscription)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if abandoned != nil {
+		s.cancelRenewalOrder(ctx, subscription, *abandoned, "Subscription renewal abandoned")
+	}
+	if renewErr != nil {
+		go s.notifyRenewalFailed(context.Background(), subscription)
+		return false, nil
+	}
+	return true, nil
+}
+
+// placeRenewalOrder creates the subscription's order, or takes up the one placed by an
+// earlier attempt, and charges it to the stored payment method. A declined order is
+// cancelled and returned as nil with the error; after any other error the order is
+// returned too, and kept for the next attempt.
+func (s *subscriptionService) placeRenewalOrder(ctx context.Context, subscription *models.Subscription) (*models.Order, error) {
+	var order *models.Order
+	if subscription.RenewalOrderID != nil {
+		previous, err := s.orderSvc.GetOrderByID(ctx, *subscription.RenewalOrderID)
+		if _, missing := err.(*errors.BusinessError); err != nil && !missing {
+			return nil, fmt.Errorf("failed to get renewal order: %w", err)
+		}
+		if err == nil && previous.Status != models.OrderStatusCancelled {
+			order = previous
+		}
+	}
+
+	if order == nil {
+		input := CreateOrderInput{
+			Items:           make([]OrderItemInput, len(subscription.Items)),
+			ShippingAddress: subscription.ShippingAddress,
+			Currency:        string(subscription.Currency),
+		}
+		for i, item := range subscription.Items {
+			input.Items[i] = OrderItemInput{ProductID: item.ProductID, Quantity: item.Quantity}
+		}
+
+		var err error
+		order, err = s.orderSvc.CreateOrder(ctx, subscription.UserID, input)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.subscriptionRepo.SetRenewalOrder(ctx, s.db, subscription.ID, order.ID); err != nil {
+			s.cancelRenewalOrder(ctx, subscription, order.ID, "Subscription renewal could not be recorded")
+			return nil, fmt.Errorf("failed to record renewal order: %w", err)
+		}
+	}
+
+	_, err := s.orderSvc.ChargeOrder(ctx, order, subscription.PaymentMethodToken, renewalIdempotencyKey(subscription, order.ID))
+	if err == nil {
+		return order, nil
+	}
+	if _, declined := err.(*errors.BusinessError); !declined {
+		// The charge may have gone through; the retry finds out with the same key
+		return order, err
+	}
+	s.cancelRenewalOrder(ctx, subscription, order.ID, "Subscription renewal payment failed")
+	return nil, err
+}
+
+// cancelRenewalOrder cancels an order placed for a renewal that won't go through, on the
+// customer's behalf since the renewal was made on it
+func (s *subscriptionService) cancelRenewalOrder(ctx context.Context, subscription *models.Subscription, orderID uint, reason string) {
+	if _, err := s.orderSvc.CancelOrder(ctx, orderID, CancelOrderInput{
+		ActorID: subscription.UserID,
+		OwnerID: subscription.UserID,
+		Reason:  reason,
+	}); err != nil {
+		logger.Error(ctx, "Failed to cancel subscription renewal order",
+			zap.Error(err),
+			zap.Uint("subscription_id", subscription.ID),
+			zap.Uint("order_id", orderID))
+	}
+}
+
+// renewalIdempotencyKey identifies the charge of a renewal period's order, so retrying it
+// never charges the customer twice while a new order after a decline is charged afresh
+func renewalIdempotencyKey(subscription *models.Subscription, orderID uint) string {
+	return fmt.Sprintf("subscription-%d-%d-order-%d", subscription.ID, subscription.NextRenewalAt.Unix(), orderID)
+}
+
+// recordRenewalFailure schedules a retry of the failed renewal, or pauses the subscription
+// and gives up on this renewal once the attempts run out
+func (s *subscriptionService) recordRenewalFailure(subscription *models.Subscription, err error, now time.Time) {
+	subscription.FailureCount++
+	subscription.LastFailureReason = renewalFailureReason(err)
+	if subscription.FailureCount < s.config.MaxRenewalAttempts {
+		retryAt := now.Add(s.config.RetryDelay)
+		subscription.RetryAt = &retryAt
+		return
+	}
+
+	subscription.Status = models.SubscriptionStatusPaused
+	subscription.PausedAt = &now
+	subscription.RetryAt = nil
+	subscription.ReminderSentAt = nil
+	subscription.NextRenewalAt = subscription.Cadence.NextAfter(subscription.NextRenewalAt, now)
+}
+
+func (s *subscriptionService) SendReminders(ctx context.Context, now time.Time) (int, error) {
+	subscriptions, err := s.subscriptionRepo.ListReminderDue(ctx, s.db, now.Add(s.config.ReminderLead))
+	if err != nil {
+		return 0, fmt.Errorf("failed to list subscriptions to remind: %w", err)
+	}
+
+	sent := 0
+	for i := range subscriptions {
+		subscription := &subscriptions[i]
+
+		// Record the reminder first, so only one instance sends it and a subscription
+		// paused or cancelled meanwhile isn't reminded
+		marked, err := s.subscriptionRepo.MarkReminderSent(ctx, s.db, subscription.ID, now)
+		if err != nil {
+			logger.Error(ctx, "Failed to record subscription reminder",
+				zap.Error(err),
+				zap.Uint("subscription_id", subscription.ID))
+			continue
+		}
+		if !marked {
+			continue
+		}
+
+		if err := s.notificationSvc.CreateNotification(
+			ctx,
+			subscription.UserID,
+			models.NotificationTypeSubscription,
+			"Upcoming Subscription Renewal",
+			fmt.Sprintf("Your subscription #%d renews on %s. Skip or pause it before then if you don't need this delivery.",
+				subscription.ID, subscription.NextRenewalAt.UTC().Format("2006-01-02 15:04 MST")),
+			nil,
+		); err != nil {
+			logger.Error(ctx, "Failed to send subscription reminder",
+				zap.Error(err),
+				zap.Uint("subscription_id", subscription.ID))
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// notifyRenewalFailed tells the customer a renewal failed and what happens next
+func (s *subscriptionService) notifyRenewalFailed(ctx context.Context, subscription *models.Subscription) {
+	message := fmt.Sprintf("We couldn't renew your subscription #%d: %s.", subscription.ID, subscription.LastFailureReason)
+	if subscription.Status == models.SubscriptionStatusPaused {
+		message += " The subscription has been paused; resume it once the issue is resolved."
+	} else if subscription.RetryAt != nil {
+		message += fmt.Sprintf(" We'll try again on %s.", subscription.RetryAt.UTC().Format("2006-01-02 15:04 MST"))
+	}
+
+	if err := s.notificationSvc.CreateNotification(
+		ctx,
+		subscription.UserID,
+		models.NotificationTypeSubscription,
+		"Subscription Renewal Failed",
+		message,
+		nil,
+	); err != nil {
+		logger.Error(ctx, "Failed to send subscription renewal failure notification",
+			zap.Error(err),
+			zap.Uint("subscription_id", subscription.ID))
+	}
+}
+
+// renewalFailureReason describes why a renewal failed in terms the customer can act on
+func renewalFailureReason(err error) string {
+	switch e := err.(type) {
+	case *errors.ValidationError:
+		return e.Message
+	case *errors.BusinessError:
+		return e.Message
+	default:
+		return "the order could not be placed"
+	}
+}
+
+func subscriptionLookupError(err error) error {
+	if err == gorm.ErrRecordNotFound {
+		return errors.NewBusinessError("Subscription not found", errors.ErrCodeResourceNotFound, http.StatusNotFound)
+	}
+	return fmt.Errorf("failed to get subscription: %w", err)
+}
+
+func invalidSubscriptionStatus(action string, status models.SubscriptionStatus) error {
+	return errors.NewBusinessError(
+		fmt.Sprintf("A %s subscription cannot be %s", status, action),
+		ErrCodeInvalidSubscriptionStatus,
+		http.StatusBadRequest,
+	)
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"net/http"
+
+	"gorm.io/gorm"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/api/dto"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/repository"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/errors"
+)
+
+type SupplierService interface {
+	CreateSupplier(ctx context.Context, req *dto.CreateSupplierRequest) (*dto.SupplierResponse, error)
+	UpdateSupplier(ctx context.Context, id uint, req *dto.UpdateSupplierRequest) (*dto.SupplierResponse, error)
+	ListSuppliers(ctx context.Context) ([]dto.SupplierResponse, error)
+}
+
+type supplierService struct {
+	db           *gorm.DB
+	supplierRepo repository.SupplierRepository
+}
+
+func NewSupplierService(db *gorm.DB, supplierRepo repository.SupplierRepository) SupplierService {
+	return &supplierService{
+		db:           db,
+		supplierRepo: supplierRepo,
+	}
+}
+
+func (s *supplierService) CreateSupplier(ctx context.Context, req *dto.CreateSupplierRequest) (*dto.SupplierResponse, error) {
+	supplier := &models.Supplier{
+		Name:         req.Name,
+		Email:        req.Email,
+		Phone:        req.Phone,
+		LeadTimeDays: req.LeadTimeDays,
+		Active:       true,
+	}
+
+	if err := s.supplierRepo.Create(ctx, s.db, supplier); err != nil {
+		return nil, err
+	}
+
+	resp := dto.SupplierToResponse(supplier)
+	return &resp, nil
+}
+
+func (s *supplierService) UpdateSupplier(ctx context.Context, id uint, req *dto.UpdateSupplierRequest) (*dto.SupplierResponse, error) {
+	supplier, err := s.supplierRepo.FindByID(ctx, s.db, id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, errors.NewBusinessError("Supplier not found", errors.ErrCodeResourceNotFound, http.StatusNotFound)
+		}
+		return nil, err
+	}
+
+	if req.Name != nil {
+		supplier.Name = *req.Name
+	}
+	if req.Email != nil {
+		supplier.Email = *req.Email
+	}
+	if req.Phone != nil {
+		supplier.Phone = *req.Phone
+	}
+	if req.LeadTimeDays != nil {
+		supplier.LeadTimeDays = *req.LeadTimeDays
+	}
+	if req.Active != nil {
+		supplier.Active = *req.Active
+	}
+
+	if err := s.supplierRepo.Update(ctx, s.db, supplier); err != nil {
+		return nil, err
+	}
+
+	resp := dto.SupplierToResponse(supplier)
+	return &resp, nil
+}
+
+func (s *supplierService) ListSuppliers(ctx context.Context) ([]dto.SupplierResponse, error) {
+	suppliers, err := s.supplierRepo.List(ctx, s.db)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]dto.SupplierResponse, len(suppliers))
+	for i := range suppliers {
+		resp[i] = dto.SupplierToResponse(&suppliers[i])
+	}
+	return resp, nil
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/repository"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/logger"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/websocket"
+)
+
+// TopicService decides which WebSocket topics a client may subscribe to, from the role
+// and user of its JWT claims, and publishes product stock to the stock topics
+type TopicService interface {
+	websocket.TopicAuthorizer
+
+	// PublishStock sends the current stock of the given products to their stock topics. It
+	// is registered as a stock change listener.
+	PublishStock(ctx context.Context, productIDs ...uint)
+}
+
+type topicService struct {
+	db            *gorm.DB
+	orderRepo     repository.OrderRepository
+	productRepo   repository.ProductRepository
+	inventoryRepo repository.InventoryRepository
+	dashboardSvc  DashboardService
+	wsManager     *websocket.Manager
+}
+
+func NewTopicService(
+	db *gorm.DB,
+	orderRepo repository.OrderRepository,
+	productRepo repository.ProductRepository,
+	inventoryRepo repository.InventoryRepository,
+	dashboardSvc DashboardService,
+	wsManager *websocket.Manager,
+) TopicService {
+	return &topicService{
+		db:            db,
+		orderRepo:     orderRepo,
+		productRepo:   productRepo,
+		inventoryRepo: inventoryRepo,
+		dashboardSvc:  dashboardSvc,
+		wsManager:     wsManager,
+	}
+}
+
+// AuthorizeTopic lets admins subscribe to any topic, customers to their own orders, and
+// everyone to product stock. Orders and products must exist; an order of someone else is
+// reported as unknown so its existence isn't given away.
+func (s *topicService) AuthorizeTopic(ctx context.Context, client *websocket.Client, topic string) (websocket.SnapshotFunc, error) {
+	isAdmin := client.Role == string(models.RoleAdmin)
+	parts := strings.Split(topic, ":")
+
+	switch {
+	case topic == websocket.TopicAdminDashboard:
+		if !isAdmin {
+			return nil, websocket.ErrTopicNotAllowed
+		}
+		return func() (websocket.Event, error) {
+			snapshot, err := s.dashboardSvc.Snapshot(ctx)
+			if err != nil {
+				return websocket.Event{}, err
+			}
+			return websocket.Event{Type: websocket.EventDashboardSnapshot, Payload: snapshot}, nil
+		}, nil
+
+	case topic == websocket.TopicAdminOrders:
+		if !isAdmin {
+			return nil, websocket.ErrTopicNotAllowed
+		}
+		return nil, nil
+
+	case len(parts) == 2 && parts[0] == "order":
+		orderID, ok := parseTopicID(parts[1])
+		if !ok {
+			return nil, websocket.ErrUnknownTopic
+		}
+		order, err := s.orderRepo.GetOrderByID(ctx, s.db, orderID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return nil, websocket.ErrUnknownTopic
+			}
+			return nil, fmt.Errorf("failed to get order: %w", err)
+		}
+		if !isAdmin && order.UserID != client.UserID {
+			return nil, websocket.ErrUnknownTopic
+		}
+		return nil, nil
+
+	case len(parts) == 3 && parts[0] == "product" && parts[2] == "stock":
+		productID, ok := parseTopicID(parts[1])
+		if !ok {
+			return nil, websocket.ErrUnknownTopic
+		}
+		product, err := s.productRepo.FindByID(ctx, productID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get product: %w", err)
+		}
+		if product == nil {
+			return nil, websocket.ErrUnknownTopic
+		}
+		return func() (websocket.Event, error) {
+			return s.stockEvent(ctx, productID)
+		}, nil
+	}
+	return nil, websocket.ErrUnknownTopic
+}
+
+func (s *topicService) PublishStock(ctx context.Context, productIDs ...uint) {
+	for _, productID := range productIDs {
+		topic := websocket.ProductStockTopic(productID)
+		if !s.wsManager.HasSubscribers(topic) {
+			continue
+		}
+
+		event, err := s.stockEvent(ctx, productID)
+		if err != nil {
+			logger.Error(ctx, "Failed to publish product stock",
+				zap.Error(err),
+				zap.Uint("product_id", productID))
+			continue
+		}
+		s.wsManager.Publish(topic, event)
+	}
+}
+
+// stockEvent sums up a product's available stock across warehouses
+func (s *topicService) stockEvent(ctx context.Context, productID uint) (websocket.Event, error) {
+	inventories, err := s.inventoryRepo.ListByProduct(ctx, s.db, productID)
+	if err != nil {
+		return websocket.Event{}, fmt.Errorf("failed to list inventory: %w", err)
+	}
+
+	payload := websocket.ProductStockEventPayload{ProductID: productID}
+	for _, inventory := range inventories {
+		payload.Available += inventory.Quantity
+	}
+	payload.InStock = payload.Available > 0
+	return websocket.Event{Type: websocket.EventProductStock, Payload: payload}, nil
+}
+
+// parseTopicID parses the ID in a topic such as order:42, rejecting zero
+func parseTopicID(value string) (uint, bool) {
+	id, err := strconv.ParseUint(value, 10, 32)
+	if err != nil || id == 0 {
+		return 0, false
+	}
+	return uint(id), true
+}
@@ -23,12 +23,14 @@ type UserService interface {
 type userService struct {
 	userRepo     repository.UserRepository
 	hashService  hashing.Service
+	currencySvc  CurrencyService
 }
 
-func NewUserService(repo repository.UserRepository) UserService {
+func NewUserService(repo repository.UserRepository, currencySvc CurrencyService) UserService {
 	return &userService{
 		userRepo:    repo,
 		hashService: hashing.NewService(),
+		currencySvc: currencySvc,
 	}
 }
 
@@ -50,6 +52,7 @@ func (s *userService) GetUserProfile(ctx context.Context, id uint) (*dto.UserPro
 		LastName:  user.LastName,
 		Role:      user.Role,
 		Active:    user.Active,
+		PreferredCurrency: string(user.PreferredCurrency),
 	}, nil
 }
 
@@ -142,6 +145,13 @@ func (s *userService) UpdateUserProfile(ctx context.Context, userID uint, req *d
 	if req.LastName != nil {
 		user.LastName = *req.LastName
 	}
+	if req.PreferredCurrency != nil {
+		currency, err := s.currencySvc.ValidateCurrency(*req.PreferredCurrency)
+		if err != nil {
+			return nil, err
+		}
+		user.PreferredCurrency = currency
+	}
 
 	// Save updates
 	if err := s.userRepo.Update(ctx, user); err != nil {
@@ -156,5 +166,6 @@ func (s *userService) UpdateUserProfile(ctx context.Context, userID uint, req *d
 		LastName:  user.LastName,
 		Role:      user.Role,
 		Active:    user.Active,
+		PreferredCurrency: string(user.PreferredCurrency),
 	}, nil
 }
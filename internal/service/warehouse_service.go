@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/api/dto"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/repository"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/errors"
+)
+
+type WarehouseService interface {
+	CreateWarehouse(ctx context.Context, req *dto.CreateWarehouseRequest) (*dto.WarehouseResponse, error)
+	UpdateWarehouse(ctx context.Context, id uint, req *dto.UpdateWarehouseRequest) (*dto.WarehouseResponse, error)
+	ListWarehouses(ctx context.Context) ([]dto.WarehouseResponse, error)
+}
+
+type warehouseService struct {
+	db            *gorm.DB
+	warehouseRepo repository.WarehouseRepository
+}
+
+func NewWarehouseService(db *gorm.DB, warehouseRepo repository.WarehouseRepository) WarehouseService {
+	return &warehouseService{
+		db:            db,
+		warehouseRepo: warehouseRepo,
+	}
+}
+
+func (s *warehouseService) CreateWarehouse(ctx context.Context, req *dto.CreateWarehouseRequest) (*dto.WarehouseResponse, error) {
+	warehouse := &models.Warehouse{
+		Code:      strings.ToUpper(req.Code),
+		Name:      req.Name,
+		Address:   req.Address.ToModel(),
+		IsDefault: req.IsDefault,
+		Active:    true,
+	}
+	if req.Active != nil {
+		warehouse.Active = *req.Active
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		existing, err := s.warehouseRepo.FindByCode(ctx, tx, warehouse.Code)
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return err
+		}
+		if existing != nil {
+			return errors.NewValidationError("Warehouse code already exists", map[string]string{"code": "must be unique"}, http.StatusBadRequest)
+		}
+
+		if err := s.warehouseRepo.Create(ctx, tx, warehouse); err != nil {
+			return err
+		}
+		if warehouse.IsDefault {
+			return s.warehouseRepo.ClearDefault(ctx, tx, warehouse.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := dto.WarehouseToResponse(warehouse)
+	return &resp, nil
+}
+
+func (s *warehouseService) UpdateWarehouse(ctx context.Context, id uint, req *dto.UpdateWarehouseRequest) (*dto.WarehouseResponse, error) {
+	var warehouse *models.Warehouse
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		warehouse, err = s.warehouseRepo.FindByID(ctx, tx, id)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return errors.NewBusinessError("Warehouse not found", errors.ErrCodeResourceNotFound, http.StatusNotFound)
+			}
+			return err
+		}
+
+		if req.Name != nil {
+			warehouse.Name = *req.Name
+		}
+		if req.Address != nil {
+			warehouse.Address = req.Address.ToModel()
+		}
+		if req.Active != nil {
+			warehouse.Active = *req.Active
+		}
+		if req.IsDefault != nil {
+			// The default warehouse can only change by promoting another one
+			if warehouse.IsDefault && !*req.IsDefault {
+				return errors.NewValidationError("Cannot unset the default warehouse", map[string]string{"is_default": "promote another warehouse instead"}, http.StatusBadRequest)
+			}
+			warehouse.IsDefault = *req.IsDefault
+		}
+		if warehouse.IsDefault && !warehouse.Active {
+			return errors.NewValidationError("The default warehouse must be active", map[string]string{"active": "default warehouse cannot be deactivated"}, http.StatusBadRequest)
+		}
+
+		if err := s.warehouseRepo.Update(ctx, tx, warehouse); err != nil {
+			return err
+		}
+		if warehouse.IsDefault {
+			return s.warehouseRepo.ClearDefault(ctx, tx, warehouse.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := dto.WarehouseToResponse(warehouse)
+	return &resp, nil
+}
+
+func (s *warehouseService) ListWarehouses(ctx context.Context) ([]dto.WarehouseResponse, error) {
+	warehouses, err := s.warehouseRepo.List(ctx, s.db)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]dto.WarehouseResponse, len(warehouses))
+	for i := range warehouses {
+		resp[i] = dto.WarehouseToResponse(&warehouses[i])
+	}
+	return resp, nil
+}
@@ -0,0 +1,56 @@
+package workers
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/service"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/logger"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+// AnalyticsWorker recomputes the analytics summary tables every night
+type AnalyticsWorker struct {
+	customerAnalyticsSvc service.CustomerAnalyticsService
+	cron                 *cron.Cron
+}
+
+func NewAnalyticsWorker(customerAnalyticsSvc service.CustomerAnalyticsService) *AnalyticsWorker {
+	return &AnalyticsWorker{
+		customerAnalyticsSvc: customerAnalyticsSvc,
+		cron: cron.New(cron.WithSeconds(), cron.WithLocation(time.UTC),
+			cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger))),
+	}
+}
+
+func (w *AnalyticsWorker) Start() error {
+	// Recompute analytics at 01:00 UTC, after the daily reports are generated
+	_, err := w.cron.AddFunc("0 0 1 * * *", w.refreshAnalytics)
+	if err != nil {
+		return err
+	}
+
+	// Compute them right away so they are available after a deploy
+	go w.refreshAnalytics()
+
+	w.cron.Start()
+	return nil
+}
+
+func (w *AnalyticsWorker) Stop() {
+	w.cron.Stop()
+}
+
+func (w *AnalyticsWorker) refreshAnalytics() {
+	ctx := context.Background()
+
+	refreshed, err := w.customerAnalyticsSvc.RefreshCustomerAnalytics(ctx, time.Now())
+	if err != nil {
+		logger.Error(ctx, "Failed to compute customer analytics", zap.Error(err))
+		return
+	}
+	if refreshed {
+		logger.Info(ctx, "Computed customer analytics")
+	}
+}
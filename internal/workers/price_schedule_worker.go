@@ -0,0 +1,61 @@
+package workers
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/service"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/logger"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/redis"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+// PriceScheduleWorker starts and ends price schedules on time and clears the cached
+// product responses whose prices changed
+type PriceScheduleWorker struct {
+	scheduleSvc  service.PriceScheduleService
+	redisService redis.Service
+	cron         *cron.Cron
+}
+
+func NewPriceScheduleWorker(scheduleSvc service.PriceScheduleService, redisService redis.Service) *PriceScheduleWorker {
+	return &PriceScheduleWorker{
+		scheduleSvc:  scheduleSvc,
+		redisService: redisService,
+		cron:         cron.New(cron.WithSeconds()),
+	}
+}
+
+func (w *PriceScheduleWorker) Start() error {
+	// Check for due schedules at the start of every minute
+	_, err := w.cron.AddFunc("0 * * * * *", w.applyDueSchedules)
+	if err != nil {
+		return err
+	}
+
+	w.cron.Start()
+	return nil
+}
+
+func (w *PriceScheduleWorker) Stop() {
+	w.cron.Stop()
+}
+
+func (w *PriceScheduleWorker) applyDueSchedules() {
+	ctx := context.Background()
+
+	productIDs, err := w.scheduleSvc.ApplyDueSchedules(ctx, time.Now())
+	if err != nil {
+		logger.Error(ctx, "Failed to apply price schedules", zap.Error(err))
+		return
+	}
+	if len(productIDs) == 0 {
+		return
+	}
+
+	if err := w.redisService.InvalidatePattern(ctx, "/api/v1/products*"); err != nil {
+		logger.Error(ctx, "Failed to invalidate products cache", zap.Error(err))
+	}
+	logger.Info(ctx, "Applied price schedules", zap.Uints("product_ids", productIDs))
+}
@@ -0,0 +1,56 @@
+package workers
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/service"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/logger"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+// RefundWorker retries the refunds of cancelled and adjusted orders that the payment
+// provider hasn't confirmed yet
+type RefundWorker struct {
+	orderSvc *service.OrderService
+	cron     *cron.Cron
+}
+
+func NewRefundWorker(orderSvc *service.OrderService) *RefundWorker {
+	return &RefundWorker{
+		orderSvc: orderSvc,
+		// Refunds wait on the provider, so a slow run must not overlap the next one
+		cron: cron.New(cron.WithSeconds(), cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger))),
+	}
+}
+
+func (w *RefundWorker) Start() error {
+	// Retry pending refunds every minute
+	_, err := w.cron.AddFunc("30 * * * * *", w.retryRefunds)
+	if err != nil {
+		return err
+	}
+
+	w.cron.Start()
+	return nil
+}
+
+func (w *RefundWorker) Stop() {
+	w.cron.Stop()
+}
+
+func (w *RefundWorker) retryRefunds() {
+	ctx := context.Background()
+
+	refunded, failed, err := w.orderSvc.RetryPendingRefunds(ctx, time.Now())
+	if err != nil {
+		logger.Error(ctx, "Failed to retry refunds", zap.Error(err))
+		return
+	}
+	if refunded+failed > 0 {
+		logger.Info(ctx, "Retried refunds",
+			zap.Int("refunded", refunded),
+			zap.Int("failed", failed))
+	}
+}
@@ -0,0 +1,55 @@
+package workers
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/service"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/logger"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+// ReportDigestWorker emails admins their report digests once the day or week is over,
+// retrying failed sends on later runs
+type ReportDigestWorker struct {
+	digestSvc service.ReportDigestService
+	cron      *cron.Cron
+}
+
+func NewReportDigestWorker(digestSvc service.ReportDigestService) *ReportDigestWorker {
+	return &ReportDigestWorker{
+		digestSvc: digestSvc,
+		cron: cron.New(cron.WithSeconds(), cron.WithLocation(time.UTC),
+			cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger))),
+	}
+}
+
+func (w *ReportDigestWorker) Start() error {
+	// Send digests at half past every hour, the first run of the day coming after the
+	// daily reports are generated
+	_, err := w.cron.AddFunc("0 30 * * * *", w.sendDigests)
+	if err != nil {
+		return err
+	}
+
+	w.cron.Start()
+	return nil
+}
+
+func (w *ReportDigestWorker) Stop() {
+	w.cron.Stop()
+}
+
+func (w *ReportDigestWorker) sendDigests() {
+	ctx := context.Background()
+
+	sent, failed, err := w.digestSvc.SendDueDigests(ctx, time.Now())
+	if err != nil {
+		logger.Error(ctx, "Failed to send report digests", zap.Error(err))
+		return
+	}
+	if sent+failed > 0 {
+		logger.Info(ctx, "Sent report digests", zap.Int("sent", sent), zap.Int("failed", failed))
+	}
+}
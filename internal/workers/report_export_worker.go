@@ -0,0 +1,68 @@
+package workers
+
+import (
+	"context"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/service"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/logger"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+// ReportExportWorker renders queued report exports and deletes the expired ones
+type ReportExportWorker struct {
+	exportSvc service.ReportExportService
+	cron      *cron.Cron
+}
+
+func NewReportExportWorker(exportSvc service.ReportExportService) *ReportExportWorker {
+	return &ReportExportWorker{
+		exportSvc: exportSvc,
+		// Rendering a long range can take a while, so a slow run must not overlap the next one
+		cron: cron.New(cron.WithSeconds(), cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger))),
+	}
+}
+
+func (w *ReportExportWorker) Start() error {
+	// Pick up queued exports every ten seconds
+	if _, err := w.cron.AddFunc("*/10 * * * * *", w.runExports); err != nil {
+		return err
+	}
+
+	// Delete expired exports every hour
+	if _, err := w.cron.AddFunc("0 0 * * * *", w.deleteExpiredExports); err != nil {
+		return err
+	}
+
+	w.cron.Start()
+	return nil
+}
+
+func (w *ReportExportWorker) Stop() {
+	w.cron.Stop()
+}
+
+func (w *ReportExportWorker) runExports() {
+	ctx := context.Background()
+
+	completed, failed, err := w.exportSvc.RunPendingExports(ctx)
+	if err != nil {
+		logger.Error(ctx, "Failed to run report exports", zap.Error(err))
+	}
+	if completed+failed > 0 {
+		logger.Info(ctx, "Ran report exports", zap.Int("completed", completed), zap.Int("failed", failed))
+	}
+}
+
+func (w *ReportExportWorker) deleteExpiredExports() {
+	ctx := context.Background()
+
+	deleted, err := w.exportSvc.DeleteExpiredExports(ctx)
+	if err != nil {
+		logger.Error(ctx, "Failed to delete expired report exports", zap.Error(err))
+		return
+	}
+	if deleted > 0 {
+		logger.Info(ctx, "Deleted expired report exports", zap.Int64("exports", deleted))
+	}
+}
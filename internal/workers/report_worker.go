@@ -4,49 +4,47 @@ import (
 	"context"
 	"time"
 
-	"github.com/Ahmed1monm/backend-golang-task-2025/internal/models"
-	"github.com/Ahmed1monm/backend-golang-task-2025/internal/repository"
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/service"
 	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/logger"
 	"github.com/robfig/cron/v3"
 	"go.uber.org/zap"
-	"gorm.io/gorm"
 )
 
+// ReportWorker generates the daily sales report once the day is over, catching up on
+// days missed while no instance was running, and then brings the product sales rollup
+// up to date
 type ReportWorker struct {
-	db            *gorm.DB
-	reportRepo    repository.ReportRepository
-	orderRepo     repository.OrderRepository
-	userRepo      repository.UserRepository
-	productRepo   repository.ProductRepository
-	cron         *cron.Cron
+	reportSvc           *service.ReportService
+	productAnalyticsSvc service.ProductAnalyticsService
+	catchUpDays         int
+	cron                *cron.Cron
 }
 
-func NewReportWorker(
-	db *gorm.DB,
-	reportRepo repository.ReportRepository,
-	orderRepo repository.OrderRepository,
-	userRepo repository.UserRepository,
-	productRepo repository.ProductRepository,
-) *ReportWorker {
+func NewReportWorker(reportSvc *service.ReportService, productAnalyticsSvc service.ProductAnalyticsService, catchUpDays int) *ReportWorker {
+	if catchUpDays < 1 {
+		catchUpDays = 1
+	}
 	worker := &ReportWorker{
-		db:         db,
-		reportRepo: reportRepo,
-		orderRepo: orderRepo,
-		userRepo:  userRepo,
-		productRepo: productRepo,
-		cron:      cron.New(cron.WithSeconds()),
+		reportSvc:           reportSvc,
+		productAnalyticsSvc: productAnalyticsSvc,
+		catchUpDays:         catchUpDays,
+		cron:                cron.New(cron.WithSeconds(), cron.WithLocation(time.UTC)),
 	}
 
 	return worker
 }
 
 func (w *ReportWorker) Start() error {
-	// Schedule report generation for 23:59:59 every day
-	_, err := w.cron.AddFunc("59 59 23 * * *", w.generateDailyReport)
+	// Schedule report generation for 00:05 UTC every day, once late orders of the previous
+	// day are in
+	_, err := w.cron.AddFunc("0 5 0 * * *", w.run)
 	if err != nil {
 		return err
 	}
 
+	// Catch up on days missed while the service was down
+	go w.run()
+
 	w.cron.Start()
 	return nil
 }
@@ -55,88 +53,38 @@ func (w *ReportWorker) Stop() {
 	w.cron.Stop()
 }
 
-func (w *ReportWorker) generateDailyReport() {
-	ctx := context.Background()
-	today := time.Now().UTC().Truncate(24 * time.Hour)
+func (w *ReportWorker) run() {
+	w.generateDailyReports()
+	w.rollupProductSales()
+}
 
-	// Start transaction
-	tx := w.db.Begin()
-	if tx.Error != nil {
-		logger.Error(ctx, "Failed to start transaction", zap.Error(tx.Error))
-		return
-	}
-	defer tx.Rollback()
+// generateDailyReports generates the missing reports of the last catchUpDays completed days
+func (w *ReportWorker) generateDailyReports() {
+	ctx := context.Background()
+	yesterday := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
 
-	// Get order statistics
-	orderStats, totalRevenue, err := w.orderRepo.GetOrderStatsByDate(ctx, tx, today)
+	resp, err := w.reportSvc.GenerateReports(ctx, yesterday.AddDate(0, 0, 1-w.catchUpDays), yesterday, true)
 	if err != nil {
-		logger.Error(ctx, "Failed to get order stats", zap.Error(err))
+		logger.Error(ctx, "Failed to generate daily reports", zap.Error(err))
 		return
 	}
 
-	// Get customer statistics
-	totalCustomers, newCustomers, err := w.userRepo.GetUniqueCustomerStats(ctx, tx, today)
-	if err != nil {
-		logger.Error(ctx, "Failed to get customer stats", zap.Error(err))
-		return
+	if len(resp.Generated) > 0 {
+		logger.Info(ctx, "Successfully generated daily reports", zap.Int("days", len(resp.Generated)))
 	}
+}
 
-	// Get top products
-	topProducts, err := w.productRepo.GetTopProducts(ctx, tx, today, 10)
-	if err != nil {
-		logger.Error(ctx, "Failed to get top products", zap.Error(err))
-		return
-	}
+// rollupProductSales rolls up the product sales of the days with new or changed orders
+func (w *ReportWorker) rollupProductSales() {
+	ctx := context.Background()
 
-	// Get low stock alerts
-	lowStockAlerts, err := w.productRepo.GetLowStockProducts(ctx, tx)
+	days, _, err := w.productAnalyticsSvc.RollupProductSales(ctx, time.Now())
 	if err != nil {
-		logger.Error(ctx, "Failed to get low stock alerts", zap.Error(err))
-		return
-	}
-
-	// Calculate rates
-	totalOrders := 0
-	for _, count := range orderStats {
-		totalOrders += count
-	}
-
-	var fulfillmentRate, cancellationRate float64
-	if totalOrders > 0 {
-		fulfillmentRate = float64(orderStats[models.OrderStatusDelivered]) / float64(totalOrders) * 100
-		cancellationRate = float64(orderStats[models.OrderStatusCancelled]) / float64(totalOrders) * 100
-	}
-
-	// Create report
-	report := &models.DailySalesReport{
-		Date:                today,
-		TotalOrders:        totalOrders,
-		PendingOrders:      orderStats[models.OrderStatusPending],
-		ProcessingOrders:   orderStats[models.OrderStatusProcessing],
-		ShippedOrders:      orderStats[models.OrderStatusShipped],
-		DeliveredOrders:    orderStats[models.OrderStatusDelivered],
-		CancelledOrders:    orderStats[models.OrderStatusCancelled],
-		TotalRevenue:       totalRevenue,
-		AverageOrderValue:  totalRevenue / float64(totalOrders),
-		UniqueCustomers:    totalCustomers,
-		NewCustomers:       newCustomers,
-		TopProducts:        topProducts,
-		LowStockProducts:   lowStockAlerts,
-		OrderFulfillmentRate: fulfillmentRate,
-		CancellationRate:   cancellationRate,
-	}
-
-	// Save report
-	if err := w.reportRepo.CreateReport(ctx, tx, report); err != nil {
-		logger.Error(ctx, "Failed to create report", zap.Error(err))
+		logger.Error(ctx, "Failed to roll up product sales", zap.Error(err))
 		return
 	}
 
-	// Commit transaction
-	if err := tx.Commit().Error; err != nil {
-		logger.Error(ctx, "Failed to commit transaction", zap.Error(err))
-		return
+	if days > 0 {
+		logger.Info(ctx, "Rolled up product sales", zap.Int("days", days))
 	}
-
-	logger.Info(ctx, "Successfully generated daily report", zap.String("date", today.Format("2006-01-02")))
 }
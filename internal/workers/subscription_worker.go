@@ -0,0 +1,63 @@
+package workers
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/internal/service"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/logger"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+// SubscriptionWorker reminds customers of upcoming renewals and places the orders of
+// subscriptions that are due
+type SubscriptionWorker struct {
+	subscriptionSvc service.SubscriptionService
+	cron            *cron.Cron
+}
+
+func NewSubscriptionWorker(subscriptionSvc service.SubscriptionService) *SubscriptionWorker {
+	return &SubscriptionWorker{
+		subscriptionSvc: subscriptionSvc,
+		// Renewals wait on payments, so a slow run must not overlap the next one
+		cron: cron.New(cron.WithSeconds(), cron.WithChain(cron.SkipIfStillRunning(cron.DefaultLogger))),
+	}
+}
+
+func (w *SubscriptionWorker) Start() error {
+	// Process subscriptions every five minutes
+	_, err := w.cron.AddFunc("0 */5 * * * *", w.processSubscriptions)
+	if err != nil {
+		return err
+	}
+
+	w.cron.Start()
+	return nil
+}
+
+func (w *SubscriptionWorker) Stop() {
+	w.cron.Stop()
+}
+
+func (w *SubscriptionWorker) processSubscriptions() {
+	ctx := context.Background()
+	now := time.Now()
+
+	reminded, err := w.subscriptionSvc.SendReminders(ctx, now)
+	if err != nil {
+		logger.Error(ctx, "Failed to send subscription reminders", zap.Error(err))
+	}
+
+	renewed, failed, err := w.subscriptionSvc.RenewDue(ctx, now)
+	if err != nil {
+		logger.Error(ctx, "Failed to renew subscriptions", zap.Error(err))
+		return
+	}
+	if reminded+renewed+failed > 0 {
+		logger.Info(ctx, "Processed subscriptions",
+			zap.Int("reminded", reminded),
+			zap.Int("renewed", renewed),
+			zap.Int("failed", failed))
+	}
+}
@@ -0,0 +1,60 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+)
+
+// renderCSV writes the sections one after another, each headed by its name and
+// separated by an empty line
+func renderCSV(doc *Document) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+
+	for i, section := range doc.Sections {
+		if i > 0 {
+			if err := w.Write([]string{}); err != nil {
+				return nil, err
+			}
+		}
+		if err := w.Write([]string{section.Name}); err != nil {
+			return nil, err
+		}
+		if err := w.Write(section.Columns); err != nil {
+			return nil, err
+		}
+		for _, row := range section.Rows {
+			record := make([]string, len(row))
+			for j, value := range row {
+				record[j] = cellText(value)
+			}
+			if err := w.Write(record); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// formatNumber formats numeric cells without exponents
+func formatNumber(value any) string {
+	switch v := value.(type) {
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint:
+		return strconv.FormatUint(uint64(v), 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
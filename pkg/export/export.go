@@ -0,0 +1,107 @@
+// Package export renders tabular documents as CSV, XLSX or PDF files
+package export
+
+import (
+	"errors"
+	"mime"
+	"strings"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
+)
+
+// Format is a file format a document can be exported to
+type Format string
+
+const (
+	FormatJSON Format = "json" // Not rendered by this package; the caller responds with JSON
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+	FormatPDF  Format = "pdf"
+)
+
+var ErrUnsupportedFormat = errors.New("export: unsupported format")
+
+var mediaTypes = map[Format]string{
+	FormatJSON: "application/json",
+	FormatCSV:  "text/csv",
+	FormatXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	FormatPDF:  "application/pdf",
+}
+
+// ParseFormat parses a format name such as "xlsx", case-insensitively
+func ParseFormat(name string) (Format, bool) {
+	format := Format(strings.ToLower(strings.TrimSpace(name)))
+	_, ok := mediaTypes[format]
+	return format, ok
+}
+
+// NegotiateFormat picks the first format of an Accept header that can be produced. A
+// header without any, or asking for anything, gets JSON.
+func NegotiateFormat(accept string) (Format, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return FormatJSON, true
+	}
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(part)
+		if err != nil {
+			continue
+		}
+		if mediaType == "*/*" || mediaType == "application/*" {
+			return FormatJSON, true
+		}
+		for format, candidate := range mediaTypes {
+			if mediaType == candidate {
+				return format, true
+			}
+		}
+	}
+	return "", false
+}
+
+// ContentType returns the media type of files in the format
+func (f Format) ContentType() string {
+	return mediaTypes[f]
+}
+
+// Document is a titled set of tables, one per section
+type Document struct {
+	Title    string
+	Subtitle string
+	Sections []Section
+}
+
+// Section is a table of a document. Cells are strings, integers, floats or money.Money
+// amounts, which spreadsheets keep as numbers.
+type Section struct {
+	Name    string
+	Columns []string
+	Rows    [][]any
+}
+
+// Render renders a document in a file format
+func Render(doc *Document, format Format) ([]byte, error) {
+	switch format {
+	case FormatCSV:
+		return renderCSV(doc)
+	case FormatXLSX:
+		return renderXLSX(doc)
+	case FormatPDF:
+		return renderPDF(doc)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// cellText formats a cell for text formats
+func cellText(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case money.Money:
+		return v.Decimal()
+	default:
+		return formatNumber(v)
+	}
+}
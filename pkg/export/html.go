@@ -0,0 +1,35 @@
+package export
+
+import (
+	"bytes"
+	"html/template"
+)
+
+// htmlTemplate lays a document out with inline styles, as email clients ignore style sheets
+var htmlTemplate = template.Must(template.New("document").Funcs(template.FuncMap{
+	"cell":    cellText,
+	"numeric": func(value any) bool { _, ok := value.(string); return !ok },
+}).Parse(`<!DOCTYPE html>
+<html>
+<body style="font-family: Helvetica, Arial, sans-serif; color: #222;">
+<h2 style="margin-bottom: 4px;">{{.Title}}</h2>
+{{if .Subtitle}}<p style="margin-top: 0; color: #666;">{{.Subtitle}}</p>{{end}}
+{{range .Sections}}
+<h3 style="margin-bottom: 6px;">{{.Name}}</h3>
+{{if .Rows}}<table style="border-collapse: collapse; font-size: 13px;">
+<tr>{{range .Columns}}<th style="border: 1px solid #ccc; background: #eee; padding: 4px 8px; text-align: left;">{{.}}</th>{{end}}</tr>
+{{range .Rows}}<tr>{{range .}}<td style="border: 1px solid #ccc; padding: 4px 8px;{{if numeric .}} text-align: right;{{end}}">{{cell .}}</td>{{end}}</tr>
+{{end}}</table>{{else}}<p style="color: #666;">None</p>{{end}}
+{{end}}
+</body>
+</html>
+`))
+
+// RenderHTML renders a document as a standalone HTML page, suitable for email bodies
+func RenderHTML(doc *Document) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
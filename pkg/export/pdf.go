@@ -0,0 +1,109 @@
+package export
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// renderPDF lays a document out for printing: the title, then each section as a heading
+// and a table. Columns share the page width, and cells too long for their column are cut.
+func renderPDF(doc *Document) ([]byte, error) {
+	orientation := "P"
+	for _, section := range doc.Sections {
+		if len(section.Columns) > 6 {
+			orientation = "L"
+		}
+	}
+
+	pdf := fpdf.New(orientation, "mm", "A4", "")
+	pdf.SetTitle(doc.Title, true)
+	pdf.SetMargins(15, 15, 15)
+	pdf.SetAutoPageBreak(true, 15)
+	pdf.AliasNbPages("")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-12)
+		pdf.SetFont("Helvetica", "I", 8)
+		pdf.CellFormat(0, 5, fmt.Sprintf("Page %d of {nb}", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+	tr := pdf.UnicodeTranslatorFromDescriptor("") // The core fonts are cp1252 encoded
+
+	pdf.AddPage()
+	pageWidth, _ := pdf.GetPageSize()
+	left, _, right, _ := pdf.GetMargins()
+	width := pageWidth - left - right
+
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(0, 9, tr(doc.Title), "", 1, "L", false, 0, "")
+	if doc.Subtitle != "" {
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(0, 6, tr(doc.Subtitle), "", 1, "L", false, 0, "")
+	}
+
+	for _, section := range doc.Sections {
+		pdf.Ln(6)
+		pdf.SetFont("Helvetica", "B", 12)
+		pdf.CellFormat(0, 7, tr(section.Name), "", 1, "L", false, 0, "")
+		if len(section.Columns) == 0 {
+			continue
+		}
+		columnWidth := width / float64(len(section.Columns))
+
+		header := func() {
+			pdf.SetFont("Helvetica", "B", 9)
+			pdf.SetFillColor(230, 230, 230)
+			for _, name := range section.Columns {
+				pdf.CellFormat(columnWidth, 6, fitText(pdf, tr(name), columnWidth), "1", 0, "L", true, 0, "")
+			}
+			pdf.Ln(-1)
+			pdf.SetFont("Helvetica", "", 9)
+		}
+		header()
+
+		if len(section.Rows) == 0 {
+			pdf.CellFormat(width, 6, "None", "1", 1, "L", false, 0, "")
+			continue
+		}
+		for _, row := range section.Rows {
+			// Repeat the header on top of every page the table spans
+			if _, pageHeight := pdf.GetPageSize(); pdf.GetY()+6 > pageHeight-15 {
+				pdf.AddPage()
+				header()
+			}
+			for col := range section.Columns {
+				var value any
+				if col < len(row) {
+					value = row[col]
+				}
+				align := "R"
+				if _, ok := value.(string); ok {
+					align = "L"
+				}
+				pdf.CellFormat(columnWidth, 6, fitText(pdf, tr(cellText(value)), columnWidth), "1", 0, align, false, 0, "")
+			}
+			pdf.Ln(-1)
+		}
+	}
+
+	if err := pdf.Error(); err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// fitText cuts text that is wider than a cell, marking the cut with an ellipsis
+func fitText(pdf *fpdf.Fpdf, text string, width float64) string {
+	width -= 2 * pdf.GetCellMargin()
+	if pdf.GetStringWidth(text) <= width {
+		return text
+	}
+	for len(text) > 0 && pdf.GetStringWidth(text+"...") > width {
+		text = text[:len(text)-1]
+	}
+	return text + "..."
+}
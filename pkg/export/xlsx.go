@@ -0,0 +1,115 @@
+package export
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
+	"github.com/xuri/excelize/v2"
+)
+
+// maxSheetName is the longest sheet name spreadsheet applications accept
+const maxSheetName = 31
+
+// renderXLSX writes a workbook with a sheet per section. Amounts are stored as numbers
+// formatted to their currency's minor unit, so they can be summed in the spreadsheet.
+func renderXLSX(doc *Document) ([]byte, error) {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	if err := f.SetDocProps(&excelize.DocProperties{Title: doc.Title, Description: doc.Subtitle}); err != nil {
+		return nil, err
+	}
+	header, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return nil, err
+	}
+	amountStyles := make(map[int]int) // By number of decimals
+
+	for i, section := range doc.Sections {
+		sheet := sheetName(section.Name)
+		if i == 0 {
+			err = f.SetSheetName(f.GetSheetName(0), sheet)
+		} else {
+			_, err = f.NewSheet(sheet)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for col, name := range section.Columns {
+			cell, _ := excelize.CoordinatesToCellName(col+1, 1)
+			if err := f.SetCellValue(sheet, cell, name); err != nil {
+				return nil, err
+			}
+		}
+		if err := f.SetRowStyle(sheet, 1, 1, header); err != nil {
+			return nil, err
+		}
+		if len(section.Columns) > 0 {
+			last, _ := excelize.ColumnNumberToName(len(section.Columns))
+			if err := f.SetColWidth(sheet, "A", last, 20); err != nil {
+				return nil, err
+			}
+		}
+
+		for r, row := range section.Rows {
+			for col, value := range row {
+				cell, _ := excelize.CoordinatesToCellName(col+1, r+2)
+				amount, ok := value.(money.Money)
+				if !ok {
+					if err := f.SetCellValue(sheet, cell, value); err != nil {
+						return nil, err
+					}
+					continue
+				}
+
+				number, err := strconv.ParseFloat(amount.Decimal(), 64)
+				if err != nil {
+					return nil, err
+				}
+				if err := f.SetCellValue(sheet, cell, number); err != nil {
+					return nil, err
+				}
+				decimals := amount.Currency().Exponent()
+				style, ok := amountStyles[decimals]
+				if !ok {
+					format := "#,##0"
+					if decimals > 0 {
+						format += "." + strings.Repeat("0", decimals)
+					}
+					if style, err = f.NewStyle(&excelize.Style{CustomNumFmt: &format}); err != nil {
+						return nil, err
+					}
+					amountStyles[decimals] = style
+				}
+				if err := f.SetCellStyle(sheet, cell, cell, style); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// sheetName makes a section name a valid sheet name
+func sheetName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`:\/?*[]`, r) {
+			return '-'
+		}
+		return r
+	}, name)
+	if name == "" {
+		name = "Sheet"
+	}
+	if len([]rune(name)) > maxSheetName {
+		name = string([]rune(name)[:maxSheetName])
+	}
+	return name
+}
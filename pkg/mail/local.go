@@ -0,0 +1,68 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/logger"
+	"go.uber.org/zap"
+)
+
+type fileSender struct {
+	dir  string
+	from string
+}
+
+// NewFileSender creates a sender writing every message to an .eml file in a directory,
+// for development and tests
+func NewFileSender(dir, from string) Sender {
+	return &fileSender{dir: dir, from: from}
+}
+
+func (s *fileSender) Send(ctx context.Context, msg *Message) error {
+	msg = msg.withFrom(s.from)
+	body, err := msg.Bytes()
+	if err != nil {
+		return fmt.Errorf("mail: failed to build message: %w", err)
+	}
+
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("mail: failed to create mail directory: %w", err)
+	}
+	name := filepath.Join(s.dir, fmt.Sprintf("%s-%s.eml", time.Now().UTC().Format("20060102T150405"), randomID()[:8]))
+	if err := os.WriteFile(name, body, 0o644); err != nil {
+		return fmt.Errorf("mail: failed to write message: %w", err)
+	}
+
+	logger.Info(ctx, "Wrote email to file", zap.String("file", name), zap.Strings("to", msg.To), zap.String("subject", msg.Subject))
+	return nil
+}
+
+type consoleSender struct {
+	from string
+}
+
+// NewConsoleSender creates a sender logging messages instead of delivering them
+func NewConsoleSender(from string) Sender {
+	return &consoleSender{from: from}
+}
+
+func (s *consoleSender) Send(ctx context.Context, msg *Message) error {
+	msg = msg.withFrom(s.from)
+	attachments := make([]string, len(msg.Attachments))
+	for i, attachment := range msg.Attachments {
+		attachments[i] = fmt.Sprintf("%s (%d bytes)", attachment.Name, len(attachment.Content))
+	}
+
+	logger.Info(ctx, "Email not delivered by the console sender",
+		zap.String("from", msg.From),
+		zap.Strings("to", msg.To),
+		zap.String("subject", msg.Subject),
+		zap.String("attachments", strings.Join(attachments, ", ")),
+	)
+	return nil
+}
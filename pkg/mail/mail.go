@@ -0,0 +1,206 @@
+// Package mail builds email messages and sends them through a pluggable Sender: SMTP in
+// production, or a file or console sender for development and tests
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	netmail "net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// Sender delivers email messages
+type Sender interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// Message is an email with an HTML body, an optional plain text alternative and attachments
+type Message struct {
+	From        string // Defaults to the sender's configured address
+	To          []string
+	Subject     string
+	HTML        string
+	Text        string
+	Attachments []Attachment
+}
+
+// Attachment is a file attached to a message
+type Attachment struct {
+	Name        string
+	ContentType string
+	Content     []byte
+}
+
+// Config picks and configures the sender of outgoing mail
+type Config struct {
+	Driver string // smtp, file or console
+	From   string // Address messages are sent from unless they set their own
+	Dir    string // Directory of the file sender
+	SMTP   SMTPConfig
+}
+
+// NewSender creates the sender a configuration asks for
+func NewSender(config Config) (Sender, error) {
+	switch strings.ToLower(config.Driver) {
+	case "smtp":
+		if config.SMTP.Host == "" {
+			return nil, fmt.Errorf("mail: the smtp sender needs a host")
+		}
+		if config.SMTP.From == "" {
+			config.SMTP.From = config.From
+		}
+		return NewSMTPSender(config.SMTP), nil
+	case "file":
+		if config.Dir == "" {
+			return nil, fmt.Errorf("mail: the file sender needs a directory")
+		}
+		return NewFileSender(config.Dir, config.From), nil
+	case "", "console":
+		return NewConsoleSender(config.From), nil
+	default:
+		return nil, fmt.Errorf("mail: unknown driver %q", config.Driver)
+	}
+}
+
+// withFrom returns the message sent from the given address unless it sets its own
+func (m *Message) withFrom(from string) *Message {
+	if m.From != "" {
+		return m
+	}
+	copied := *m
+	copied.From = from
+	return &copied
+}
+
+// Bytes renders the message in MIME format
+func (m *Message) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	header := func(key, value string) {
+		fmt.Fprintf(&buf, "%s: %s\r\n", key, value)
+	}
+
+	header("From", m.From)
+	header("To", strings.Join(m.To, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	header("Date", time.Now().Format(time.RFC1123Z))
+	header("Message-ID", "<"+randomID()+"@"+domain(m.From)+">")
+	header("MIME-Version", "1.0")
+
+	mixed := multipart.NewWriter(&buf)
+	header("Content-Type", "multipart/mixed; boundary="+mixed.Boundary())
+	buf.WriteString("\r\n")
+
+	// The bodies go first, as alternatives of each other
+	var body bytes.Buffer
+	alternative := multipart.NewWriter(&body)
+	if m.Text != "" {
+		if err := writeText(alternative, "text/plain", m.Text); err != nil {
+			return nil, err
+		}
+	}
+	if err := writeText(alternative, "text/html", m.HTML); err != nil {
+		return nil, err
+	}
+	if err := alternative.Close(); err != nil {
+		return nil, err
+	}
+	part, err := mixed.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {"multipart/alternative; boundary=" + alternative.Boundary()},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := part.Write(body.Bytes()); err != nil {
+		return nil, err
+	}
+
+	for _, attachment := range m.Attachments {
+		part, err := mixed.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {mime.FormatMediaType(attachment.ContentType, map[string]string{"name": attachment.Name})},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name})},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeBase64(part, attachment.Content); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := mixed.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeText writes a quoted-printable UTF-8 text part
+func writeText(w *multipart.Writer, contentType, text string) error {
+	part, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType + "; charset=utf-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+	qp := quotedprintable.NewWriter(part)
+	if _, err := qp.Write([]byte(text)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+// writeBase64 writes base64 content in lines of 76 characters, as MIME requires
+func writeBase64(w interface{ Write([]byte) (int, error) }, content []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(content)
+	for len(encoded) > 0 {
+		n := min(76, len(encoded))
+		if _, err := fmt.Fprintf(w, "%s\r\n", encoded[:n]); err != nil {
+			return err
+		}
+		encoded = encoded[n:]
+	}
+	return nil
+}
+
+// randomID returns a random hex identifier
+func randomID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// envelopeAddress returns the bare address of a header address such as "Shop <shop@example.com>"
+func envelopeAddress(address string) string {
+	parsed, err := netmail.ParseAddress(address)
+	if err != nil {
+		return address
+	}
+	return parsed.Address
+}
+
+func envelopeAddresses(addresses []string) []string {
+	bare := make([]string, len(addresses))
+	for i, address := range addresses {
+		bare[i] = envelopeAddress(address)
+	}
+	return bare
+}
+
+// domain returns the domain of an address, for message IDs
+func domain(address string) string {
+	address = envelopeAddress(address)
+	if i := strings.LastIndex(address, "@"); i >= 0 && i < len(address)-1 {
+		return address[i+1:]
+	}
+	return "localhost"
+}
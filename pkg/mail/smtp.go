@@ -0,0 +1,52 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+)
+
+// SMTPConfig holds the SMTP server messages are relayed through
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string // Authentication is skipped without one
+	Password string
+	From     string // Address messages are sent from unless they set their own
+}
+
+type smtpSender struct {
+	config SMTPConfig
+}
+
+// NewSMTPSender creates a sender relaying messages through an SMTP server, upgrading the
+// connection with STARTTLS when the server supports it
+func NewSMTPSender(config SMTPConfig) Sender {
+	if config.Port == 0 {
+		config.Port = 587
+	}
+	return &smtpSender{config: config}
+}
+
+func (s *smtpSender) Send(ctx context.Context, msg *Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	msg = msg.withFrom(s.config.From)
+	body, err := msg.Bytes()
+	if err != nil {
+		return fmt.Errorf("mail: failed to build message: %w", err)
+	}
+
+	var auth smtp.Auth
+	if s.config.Username != "" {
+		auth = smtp.PlainAuth("", s.config.Username, s.config.Password, s.config.Host)
+	}
+	addr := net.JoinHostPort(s.config.Host, strconv.Itoa(s.config.Port))
+	if err := smtp.SendMail(addr, auth, envelopeAddress(msg.From), envelopeAddresses(msg.To), body); err != nil {
+		return fmt.Errorf("mail: failed to send message: %w", err)
+	}
+	return nil
+}
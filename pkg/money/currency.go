@@ -0,0 +1,67 @@
+package money
+
+import "strings"
+
+// Currency is an ISO 4217 currency code
+type Currency string
+
+const (
+	USD Currency = "USD"
+	EUR Currency = "EUR"
+	GBP Currency = "GBP"
+	EGP Currency = "EGP"
+	SAR Currency = "SAR"
+	AED Currency = "AED"
+	JPY Currency = "JPY"
+)
+
+// DefaultCurrency is used for amounts that don't carry a currency, such as
+// values read from decimal database columns
+const DefaultCurrency = USD
+
+// exponents lists the currencies whose minor unit isn't a hundredth
+var exponents = map[Currency]int{
+	JPY: 0,
+}
+
+// unsupported lists the currencies whose minor unit is a thousandth. Amounts are stored in
+// decimal columns with two places, which can't hold them.
+var unsupported = map[Currency]bool{
+	"BHD": true,
+	"IQD": true,
+	"JOD": true,
+	"KWD": true,
+	"LYD": true,
+	"OMR": true,
+	"TND": true,
+}
+
+// Exponent returns the number of decimal places of the currency's minor unit
+func (c Currency) Exponent() int {
+	if exp, ok := exponents[c]; ok {
+		return exp
+	}
+	return 2
+}
+
+// Valid reports whether the code looks like an ISO 4217 code of a supported currency
+func (c Currency) Valid() bool {
+	if len(c) != 3 || unsupported[c] {
+		return false
+	}
+	for _, r := range c {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseCurrency normalises a currency code and checks that it is well formed
+func ParseCurrency(code string) (Currency, error) {
+	c := Currency(strings.ToUpper(strings.TrimSpace(code)))
+	if !c.Valid() {
+		return "", ErrInvalidCurrency
+	}
+	return c, nil
+}
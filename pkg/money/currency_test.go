@@ -0,0 +1,57 @@
+package money
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseCurrency(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Currency
+		wantErr bool
+	}{
+		{input: "USD", want: USD},
+		{input: " eur ", want: EUR},
+		{input: "aed", want: AED},
+		{input: "KWD", wantErr: true}, // Three decimals
+		{input: "bhd", wantErr: true},
+		{input: "US", wantErr: true},
+		{input: "USDT", wantErr: true},
+		{input: "U5D", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseCurrency(tt.input)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidCurrency) {
+				t.Errorf("ParseCurrency(%q) error = %v, want %v", tt.input, err, ErrInvalidCurrency)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("ParseCurrency(%q) unexpected error: %v", tt.input, err)
+		}
+		if got != tt.want {
+			t.Errorf("ParseCurrency(%q) = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestCurrencyExponent(t *testing.T) {
+	tests := []struct {
+		currency Currency
+		want     int
+	}{
+		{currency: USD, want: 2},
+		{currency: EGP, want: 2},
+		{currency: JPY, want: 0},
+	}
+
+	for _, tt := range tests {
+		if got := tt.currency.Exponent(); got != tt.want {
+			t.Errorf("%s.Exponent() = %d, want %d", tt.currency, got, tt.want)
+		}
+	}
+}
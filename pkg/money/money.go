@@ -0,0 +1,419 @@
+// Package money represents monetary amounts as integer minor units (cents) with a
+// currency, so sums and splits never drift the way float64 arithmetic does.
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrCurrencyMismatch = errors.New("money: currency mismatch")
+	ErrOverflow         = errors.New("money: amount overflows int64 minor units")
+	ErrInvalidAmount    = errors.New("money: invalid amount")
+	ErrInvalidCurrency  = errors.New("money: invalid currency code")
+	ErrTooPrecise       = errors.New("money: amount has more decimals than the currency allows")
+	ErrDivisionByZero   = errors.New("money: division by zero")
+	ErrInvalidRatios    = errors.New("money: ratios must be non-negative and not all zero")
+	ErrInvalidRate      = errors.New("money: exchange rate must be positive")
+)
+
+// RoundingMode decides how amounts that fall between two minor units are rounded
+type RoundingMode int
+
+const (
+	RoundHalfEven RoundingMode = iota // Banker's rounding, the default for calculations
+	RoundHalfUp                       // Halves are rounded away from zero
+	RoundDown                         // Towards zero (truncate)
+	RoundUp                           // Away from zero
+)
+
+// Money is an amount in minor units of a currency. The zero value is zero in the
+// default currency.
+type Money struct {
+	amount   int64
+	currency Currency
+}
+
+// New returns an amount given in minor units, e.g. New(1999, USD) is $19.99
+func New(amount int64, currency Currency) Money {
+	return Money{amount: amount, currency: currency}
+}
+
+// Zero returns a zero amount in the given currency
+func Zero(currency Currency) Money {
+	return Money{currency: currency}
+}
+
+// Parse reads a decimal string such as "19.99". Digits beyond the currency's
+// minor unit are rejected unless they are zeros.
+func Parse(s string, currency Currency) (Money, error) {
+	r, err := parseRat(s)
+	if err != nil {
+		return Money{}, err
+	}
+	scaled := r.Mul(r, pow10(currency.Exponent()))
+	if !scaled.IsInt() {
+		return Money{}, ErrTooPrecise
+	}
+	return fromInt(scaled.Num(), currency)
+}
+
+// ParseRounded reads a decimal string and rounds it to the currency's minor unit
+func ParseRounded(s string, currency Currency, mode RoundingMode) (Money, error) {
+	r, err := parseRat(s)
+	if err != nil {
+		return Money{}, err
+	}
+	return fromRat(r.Mul(r, pow10(currency.Exponent())), currency, mode)
+}
+
+// FromFloat converts a float amount, rounding to the currency's minor unit. It is
+// meant for legacy inputs only; new code should work in minor units.
+func FromFloat(f float64, currency Currency, mode RoundingMode) (Money, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return Money{}, ErrInvalidAmount
+	}
+	return ParseRounded(strconv.FormatFloat(f, 'f', -1, 64), currency, mode)
+}
+
+// Sum adds up amounts that must all be in the given currency
+func Sum(currency Currency, values ...Money) (Money, error) {
+	total := Zero(currency)
+	for _, v := range values {
+		var err error
+		if total, err = total.Add(v); err != nil {
+			return Money{}, err
+		}
+	}
+	return total, nil
+}
+
+// Amount returns the amount in minor units
+func (m Money) Amount() int64 {
+	return m.amount
+}
+
+// Currency returns the currency of the amount
+func (m Money) Currency() Currency {
+	if m.currency == "" {
+		return DefaultCurrency
+	}
+	return m.currency
+}
+
+func (m Money) IsZero() bool     { return m.amount == 0 }
+func (m Money) IsPositive() bool { return m.amount > 0 }
+func (m Money) IsNegative() bool { return m.amount < 0 }
+
+// Equal reports whether both amounts and currencies match
+func (m Money) Equal(other Money) bool {
+	return m.amount == other.amount && m.Currency() == other.Currency()
+}
+
+// Cmp compares two amounts of the same currency, returning -1, 0 or +1
+func (m Money) Cmp(other Money) (int, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.amount < other.amount:
+		return -1, nil
+	case m.amount > other.amount:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+// Add returns m + other
+func (m Money) Add(other Money) (Money, error) {
+	if err := m.sameCurrency(other); err != nil {
+		return Money{}, err
+	}
+	if (other.amount > 0 && m.amount > math.MaxInt64-other.amount) ||
+		(other.amount < 0 && m.amount < math.MinInt64-other.amount) {
+		return Money{}, ErrOverflow
+	}
+	return Money{amount: m.amount + other.amount, currency: m.Currency()}, nil
+}
+
+// Sub returns m - other
+func (m Money) Sub(other Money) (Money, error) {
+	if other.amount == math.MinInt64 {
+		return Money{}, ErrOverflow
+	}
+	return m.Add(Money{amount: -other.amount, currency: other.currency})
+}
+
+// Mul multiplies the amount by an integer, e.g. a unit price by a quantity
+func (m Money) Mul(n int64) (Money, error) {
+	product := new(big.Int).Mul(big.NewInt(m.amount), big.NewInt(n))
+	return fromInt(product, m.Currency())
+}
+
+// MulRat multiplies the amount by an exact ratio, such as an exchange rate or a
+// percentage, and rounds the result to the minor unit
+func (m Money) MulRat(r *big.Rat, mode RoundingMode) (Money, error) {
+	product := new(big.Rat).Mul(new(big.Rat).SetInt64(m.amount), r)
+	return fromRat(product, m.Currency(), mode)
+}
+
+// Div divides the amount by an integer and rounds the result to the minor unit.
+// Use Allocate or Split when the parts must add back up to the original amount.
+func (m Money) Div(n int64, mode RoundingMode) (Money, error) {
+	if n == 0 {
+		return Money{}, ErrDivisionByZero
+	}
+	return fromRat(big.NewRat(m.amount, n), m.Currency(), mode)
+}
+
+// As returns the same decimal value expressed in another currency's minor units. It
+// is used when a decimal column's currency is stored separately and only known after
+// the row is read. It fails if the value doesn't fit the currency's minor unit.
+func (m Money) As(currency Currency) (Money, error) {
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	from, to := m.Currency().Exponent(), currency.Exponent()
+	if from == to {
+		return Money{amount: m.amount, currency: currency}, nil
+	}
+	scaled := new(big.Rat).Mul(new(big.Rat).SetInt64(m.amount), new(big.Rat).Quo(pow10(to), pow10(from)))
+	if !scaled.IsInt() {
+		return Money{}, ErrTooPrecise
+	}
+	return fromInt(scaled.Num(), currency)
+}
+
+// Convert exchanges the amount into another currency at the given rate, i.e. one
+// unit of m's currency buys rate units of the target currency
+func (m Money) Convert(rate Rate, to Currency, mode RoundingMode) (Money, error) {
+	if rate.IsZero() {
+		return Money{}, ErrInvalidRate
+	}
+	r := new(big.Rat).Mul(new(big.Rat).SetInt64(m.amount), rate.Rat())
+	r.Mul(r, new(big.Rat).Quo(pow10(to.Exponent()), pow10(m.Currency().Exponent())))
+	return fromRat(r, to, mode)
+}
+
+// Neg returns -m
+func (m Money) Neg() Money {
+	return Money{amount: -m.amount, currency: m.currency}
+}
+
+// Abs returns |m|
+func (m Money) Abs() Money {
+	if m.amount < 0 {
+		return m.Neg()
+	}
+	return m
+}
+
+// Allocate splits the amount proportionally to the given ratios. Cents that don't
+// divide evenly go to the parts with the largest remainders, earlier parts winning
+// ties, so the parts always add up to the original amount.
+func (m Money) Allocate(ratios ...int64) ([]Money, error) {
+	if len(ratios) == 0 {
+		return nil, ErrInvalidRatios
+	}
+	total := big.NewInt(0)
+	for _, ratio := range ratios {
+		if ratio < 0 {
+			return nil, ErrInvalidRatios
+		}
+		total.Add(total, big.NewInt(ratio))
+	}
+	if total.Sign() == 0 {
+		return nil, ErrInvalidRatios
+	}
+
+	// Work on the absolute amount so remainders are never negative
+	negative := m.amount < 0
+	amount := new(big.Int).Abs(big.NewInt(m.amount))
+
+	parts := make([]Money, len(ratios))
+	remainders := make([]*big.Int, len(ratios))
+	left := new(big.Int).Set(amount)
+	for i, ratio := range ratios {
+		share, rem := new(big.Int).QuoRem(new(big.Int).Mul(amount, big.NewInt(ratio)), total, new(big.Int))
+		parts[i] = Money{amount: share.Int64(), currency: m.Currency()}
+		remainders[i] = rem
+		left.Sub(left, share)
+	}
+
+	// Hand out the leftover minor units, largest remainder first
+	for left.Sign() > 0 {
+		best := -1
+		for i, rem := range remainders {
+			if rem.Sign() > 0 && (best < 0 || rem.Cmp(remainders[best]) > 0) {
+				best = i
+			}
+		}
+		parts[best].amount++
+		remainders[best].SetInt64(0)
+		left.Sub(left, big.NewInt(1))
+	}
+
+	if negative {
+		for i := range parts {
+			parts[i] = parts[i].Neg()
+		}
+	}
+	return parts, nil
+}
+
+// Split divides the amount into n parts that differ by at most one minor unit
+func (m Money) Split(n int) ([]Money, error) {
+	if n <= 0 {
+		return nil, ErrInvalidRatios
+	}
+	ratios := make([]int64, n)
+	for i := range ratios {
+		ratios[i] = 1
+	}
+	return m.Allocate(ratios...)
+}
+
+// Decimal formats the amount as a plain decimal string, e.g. "19.99"
+func (m Money) Decimal() string {
+	exp := m.Currency().Exponent()
+	sign := ""
+	amount := new(big.Int).SetInt64(m.amount)
+	if amount.Sign() < 0 {
+		sign = "-"
+		amount.Abs(amount)
+	}
+	digits := amount.String()
+	if exp == 0 {
+		return sign + digits
+	}
+	if len(digits) <= exp {
+		digits = strings.Repeat("0", exp-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-exp] + "." + digits[len(digits)-exp:]
+}
+
+// String formats the amount with its currency, e.g. "19.99 USD"
+func (m Money) String() string {
+	return m.Decimal() + " " + string(m.Currency())
+}
+
+// MarshalJSON encodes the amount as a JSON number with exactly the currency's
+// decimal places, keeping the wire format of the former float fields
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.Decimal()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a numeric string. The amount must fit the
+// currency's minor unit; the currency is kept if already set, otherwise the default.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	s = strings.Trim(s, `"`)
+	parsed, err := Parse(s, m.Currency())
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// Value stores the amount in a decimal column
+func (m Money) Value() (driver.Value, error) {
+	return m.Decimal(), nil
+}
+
+// Scan reads a decimal column, rounding half to even if the column has more
+// decimals than the currency. The currency is kept if already set, otherwise the default.
+func (m *Money) Scan(src interface{}) error {
+	currency := m.Currency()
+	var (
+		parsed Money
+		err    error
+	)
+	switch v := src.(type) {
+	case nil:
+		parsed = Zero(currency)
+	case []byte:
+		parsed, err = ParseRounded(string(v), currency, RoundHalfEven)
+	case string:
+		parsed, err = ParseRounded(v, currency, RoundHalfEven)
+	case float64:
+		parsed, err = FromFloat(v, currency, RoundHalfEven)
+	case int64:
+		parsed, err = New(v, currency).Mul(pow10Int(currency.Exponent()))
+	default:
+		return fmt.Errorf("money: cannot scan %T", src)
+	}
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+func (m Money) sameCurrency(other Money) error {
+	if m.Currency() != other.Currency() {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency(), other.Currency())
+	}
+	return nil
+}
+
+func parseRat(s string) (*big.Rat, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidAmount, s)
+	}
+	return r, nil
+}
+
+func fromInt(i *big.Int, currency Currency) (Money, error) {
+	if !i.IsInt64() {
+		return Money{}, ErrOverflow
+	}
+	return Money{amount: i.Int64(), currency: currency}, nil
+}
+
+// fromRat rounds a value already expressed in minor units to an integer
+func fromRat(r *big.Rat, currency Currency, mode RoundingMode) (Money, error) {
+	quo, rem := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if rem.Sign() != 0 {
+		// Compare twice the remainder with the denominator to find halves
+		half := new(big.Int).Abs(rem)
+		half.Lsh(half, 1)
+		cmp := half.Cmp(r.Denom())
+
+		awayFromZero := false
+		switch mode {
+		case RoundUp:
+			awayFromZero = true
+		case RoundHalfUp:
+			awayFromZero = cmp >= 0
+		case RoundHalfEven:
+			awayFromZero = cmp > 0 || (cmp == 0 && quo.Bit(0) == 1)
+		}
+		if awayFromZero {
+			quo.Add(quo, big.NewInt(int64(r.Sign())))
+		}
+	}
+	return fromInt(quo, currency)
+}
+
+func pow10(exp int) *big.Rat {
+	return new(big.Rat).SetInt64(pow10Int(exp))
+}
+
+func pow10Int(exp int) int64 {
+	p := int64(1)
+	for i := 0; i < exp; i++ {
+		p *= 10
+	}
+	return p
+}
@@ -0,0 +1,361 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		currency Currency
+		want     int64
+		wantErr  error
+	}{
+		{name: "two decimals", input: "19.99", currency: USD, want: 1999},
+		{name: "whole amount", input: "20", currency: USD, want: 2000},
+		{name: "one decimal", input: "0.5", currency: USD, want: 50},
+		{name: "negative", input: "-5.25", currency: EUR, want: -525},
+		{name: "surrounding spaces", input: " 2.50 ", currency: USD, want: 250},
+		{name: "trailing zeros beyond minor unit", input: "19.9900", currency: USD, want: 1999},
+		{name: "zero decimal currency", input: "1500", currency: JPY, want: 1500},
+		{name: "too precise", input: "19.999", currency: USD, wantErr: ErrTooPrecise},
+		{name: "too precise for zero decimal currency", input: "1.5", currency: JPY, wantErr: ErrTooPrecise},
+		{name: "not a number", input: "abc", currency: USD, wantErr: ErrInvalidAmount},
+		{name: "empty", input: "", currency: USD, wantErr: ErrInvalidAmount},
+		{name: "overflow", input: "100000000000000000000", currency: USD, wantErr: ErrOverflow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.input, tt.currency)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Parse(%q) error = %v, want %v", tt.input, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) unexpected error: %v", tt.input, err)
+			}
+			if want := New(tt.want, tt.currency); !got.Equal(want) {
+				t.Errorf("Parse(%q) = %s, want %s", tt.input, got, want)
+			}
+		})
+	}
+}
+
+func TestParseRounded(t *testing.T) {
+	tests := []struct {
+		input string
+		mode  RoundingMode
+		want  int64
+	}{
+		// Halves between an even and an odd cent
+		{input: "1.005", mode: RoundHalfEven, want: 100},
+		{input: "1.005", mode: RoundHalfUp, want: 101},
+		{input: "1.005", mode: RoundDown, want: 100},
+		{input: "1.005", mode: RoundUp, want: 101},
+		{input: "1.015", mode: RoundHalfEven, want: 102},
+		{input: "1.015", mode: RoundHalfUp, want: 102},
+		{input: "1.015", mode: RoundDown, want: 101},
+		{input: "1.015", mode: RoundUp, want: 102},
+
+		// Below and above the half
+		{input: "1.004", mode: RoundHalfEven, want: 100},
+		{input: "1.004", mode: RoundHalfUp, want: 100},
+		{input: "1.004", mode: RoundDown, want: 100},
+		{input: "1.004", mode: RoundUp, want: 101},
+		{input: "1.006", mode: RoundHalfEven, want: 101},
+		{input: "1.006", mode: RoundHalfUp, want: 101},
+		{input: "1.006", mode: RoundDown, want: 100},
+		{input: "1.006", mode: RoundUp, want: 101},
+
+		// Negative amounts round symmetrically around zero
+		{input: "-1.005", mode: RoundHalfEven, want: -100},
+		{input: "-1.005", mode: RoundHalfUp, want: -101},
+		{input: "-1.005", mode: RoundDown, want: -100},
+		{input: "-1.005", mode: RoundUp, want: -101},
+		{input: "-1.015", mode: RoundHalfEven, want: -102},
+
+		// Exact amounts are never rounded
+		{input: "1.01", mode: RoundUp, want: 101},
+		{input: "1.01", mode: RoundDown, want: 101},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseRounded(tt.input, USD, tt.mode)
+		if err != nil {
+			t.Fatalf("ParseRounded(%q, %d) unexpected error: %v", tt.input, tt.mode, err)
+		}
+		if got.Amount() != tt.want {
+			t.Errorf("ParseRounded(%q, %d) = %d, want %d", tt.input, tt.mode, got.Amount(), tt.want)
+		}
+	}
+}
+
+func TestDiv(t *testing.T) {
+	tests := []struct {
+		amount int64
+		n      int64
+		mode   RoundingMode
+		want   int64
+	}{
+		{amount: 100, n: 3, mode: RoundHalfEven, want: 33},
+		{amount: 100, n: 3, mode: RoundUp, want: 34},
+		{amount: 5, n: 2, mode: RoundHalfEven, want: 2},
+		{amount: 5, n: 2, mode: RoundHalfUp, want: 3},
+		{amount: -5, n: 2, mode: RoundDown, want: -2},
+		{amount: 5, n: -2, mode: RoundUp, want: -3},
+	}
+
+	for _, tt := range tests {
+		got, err := New(tt.amount, USD).Div(tt.n, tt.mode)
+		if err != nil {
+			t.Fatalf("Div(%d, %d) unexpected error: %v", tt.amount, tt.n, err)
+		}
+		if got.Amount() != tt.want {
+			t.Errorf("Div(%d, %d, %d) = %d, want %d", tt.amount, tt.n, tt.mode, got.Amount(), tt.want)
+		}
+	}
+
+	if _, err := New(100, USD).Div(0, RoundHalfEven); !errors.Is(err, ErrDivisionByZero) {
+		t.Errorf("Div by zero error = %v, want %v", err, ErrDivisionByZero)
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  int64
+		ratios  []int64
+		want    []int64
+		wantErr error
+	}{
+		{name: "even split", amount: 300, ratios: []int64{1, 1, 1}, want: []int64{100, 100, 100}},
+		{name: "remainder to earlier parts on ties", amount: 100, ratios: []int64{1, 1, 1}, want: []int64{34, 33, 33}},
+		{name: "two leftover cents", amount: 200, ratios: []int64{1, 1, 1}, want: []int64{67, 67, 66}},
+		{name: "remainder to largest fraction", amount: 100, ratios: []int64{1, 2}, want: []int64{33, 67}},
+		{name: "tied fractions", amount: 5, ratios: []int64{3, 7}, want: []int64{2, 3}},
+		{name: "zero ratio gets nothing", amount: 10, ratios: []int64{0, 1}, want: []int64{0, 10}},
+		{name: "single part", amount: 1999, ratios: []int64{5}, want: []int64{1999}},
+		{name: "zero amount", amount: 0, ratios: []int64{1, 1}, want: []int64{0, 0}},
+		{name: "negative amount", amount: -100, ratios: []int64{1, 1, 1}, want: []int64{-34, -33, -33}},
+		{name: "negative amount by weight", amount: -100, ratios: []int64{1, 2}, want: []int64{-33, -67}},
+		{name: "no ratios", amount: 100, ratios: nil, wantErr: ErrInvalidRatios},
+		{name: "negative ratio", amount: 100, ratios: []int64{1, -1}, wantErr: ErrInvalidRatios},
+		{name: "all zero ratios", amount: 100, ratios: []int64{0, 0}, wantErr: ErrInvalidRatios},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parts, err := New(tt.amount, EUR).Allocate(tt.ratios...)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Allocate error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Allocate unexpected error: %v", err)
+			}
+			if len(parts) != len(tt.want) {
+				t.Fatalf("Allocate returned %d parts, want %d", len(parts), len(tt.want))
+			}
+
+			var sum int64
+			for i, part := range parts {
+				if part.Amount() != tt.want[i] || part.Currency() != EUR {
+					t.Errorf("part %d = %s, want %d EUR minor units", i, part, tt.want[i])
+				}
+				sum += part.Amount()
+			}
+			if sum != tt.amount {
+				t.Errorf("parts add up to %d, want %d", sum, tt.amount)
+			}
+		})
+	}
+}
+
+func TestSplit(t *testing.T) {
+	parts, err := New(1000, USD).Split(3)
+	if err != nil {
+		t.Fatalf("Split unexpected error: %v", err)
+	}
+	want := []int64{334, 333, 333}
+	for i, part := range parts {
+		if part.Amount() != want[i] {
+			t.Errorf("part %d = %d, want %d", i, part.Amount(), want[i])
+		}
+	}
+
+	if _, err := New(1000, USD).Split(0); !errors.Is(err, ErrInvalidRatios) {
+		t.Errorf("Split(0) error = %v, want %v", err, ErrInvalidRatios)
+	}
+}
+
+func TestValue(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{money: New(1999, USD), want: "19.99"},
+		{money: New(5, USD), want: "0.05"},
+		{money: New(-5, USD), want: "-0.05"},
+		{money: New(0, USD), want: "0.00"},
+		{money: Money{}, want: "0.00"},
+		{money: New(1500, JPY), want: "1500"},
+		{money: New(-1500, JPY), want: "-1500"},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.money.Value()
+		if err != nil {
+			t.Fatalf("Value(%s) unexpected error: %v", tt.money, err)
+		}
+		if got != tt.want {
+			t.Errorf("Value(%d %s) = %v, want %q", tt.money.Amount(), tt.money.Currency(), got, tt.want)
+		}
+	}
+}
+
+func TestScan(t *testing.T) {
+	tests := []struct {
+		name     string
+		currency Currency
+		src      interface{}
+		want     int64
+		wantErr  bool
+	}{
+		{name: "bytes", src: []byte("19.99"), want: 1999},
+		{name: "string", src: "19.99", want: 1999},
+		{name: "extra decimals round half to even down", src: "19.985", want: 1998},
+		{name: "extra decimals round half to even up", src: "19.995", want: 2000},
+		{name: "float", src: 19.99, want: 1999},
+		{name: "integer", src: int64(5), want: 500},
+		{name: "null", src: nil, want: 0},
+		{name: "keeps the currency", currency: JPY, src: "1500.5", want: 1500},
+		{name: "zero decimal currency", currency: JPY, src: "1500.00", want: 1500},
+		{name: "not a number", src: "abc", wantErr: true},
+		{name: "unsupported type", src: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := Zero(tt.currency)
+			err := m.Scan(tt.src)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Scan(%v) = %s, want an error", tt.src, m)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Scan(%v) unexpected error: %v", tt.src, err)
+			}
+
+			currency := tt.currency
+			if currency == "" {
+				currency = DefaultCurrency
+			}
+			if want := New(tt.want, currency); !m.Equal(want) {
+				t.Errorf("Scan(%v) = %s, want %s", tt.src, m, want)
+			}
+		})
+	}
+}
+
+func TestValueScanRoundTrip(t *testing.T) {
+	for _, original := range []Money{New(1999, USD), New(-1, USD), New(1500, JPY), New(-1500, JPY)} {
+		value, err := original.Value()
+		if err != nil {
+			t.Fatalf("Value(%s) unexpected error: %v", original, err)
+		}
+		scanned := Zero(original.Currency())
+		if err := scanned.Scan(value); err != nil {
+			t.Fatalf("Scan(%v) unexpected error: %v", value, err)
+		}
+		if !scanned.Equal(original) {
+			t.Errorf("round trip of %s gave %s", original, scanned)
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	type price struct {
+		Price Money `json:"price"`
+	}
+
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{money: New(1999, USD), want: `{"price":19.99}`},
+		{money: New(-250, USD), want: `{"price":-2.50}`},
+		{money: Money{}, want: `{"price":0.00}`},
+	}
+
+	for _, tt := range tests {
+		data, err := json.Marshal(price{Price: tt.money})
+		if err != nil {
+			t.Fatalf("Marshal(%s) unexpected error: %v", tt.money, err)
+		}
+		if string(data) != tt.want {
+			t.Errorf("Marshal(%s) = %s, want %s", tt.money, data, tt.want)
+		}
+
+		var decoded price
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("Unmarshal(%s) unexpected error: %v", data, err)
+		}
+		if !decoded.Price.Equal(tt.money) {
+			t.Errorf("round trip of %s gave %s", tt.money, decoded.Price)
+		}
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		currency Currency
+		input    string
+		want     int64
+		wantErr  error
+	}{
+		{name: "number", input: `19.99`, want: 1999},
+		{name: "string", input: `"19.99"`, want: 1999},
+		{name: "integer", input: `20`, want: 2000},
+		{name: "null keeps the amount", input: `null`, want: 0},
+		{name: "keeps the currency", currency: JPY, input: `1500`, want: 1500},
+		{name: "too precise for the currency", currency: JPY, input: `1500.5`, wantErr: ErrTooPrecise},
+		{name: "too precise", input: `19.999`, wantErr: ErrTooPrecise},
+		{name: "not a number", input: `"abc"`, wantErr: ErrInvalidAmount},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := Zero(tt.currency)
+			err := json.Unmarshal([]byte(tt.input), &m)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Unmarshal(%s) error = %v, want %v", tt.input, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unmarshal(%s) unexpected error: %v", tt.input, err)
+			}
+			if want := New(tt.want, m.Currency()); !m.Equal(want) || (tt.currency != "" && m.Currency() != tt.currency) {
+				t.Errorf("Unmarshal(%s) = %s, want %d %s", tt.input, m, tt.want, tt.currency)
+			}
+		})
+	}
+}
+
+func TestAddCurrencyMismatch(t *testing.T) {
+	if _, err := New(100, USD).Add(New(100, EUR)); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Add error = %v, want %v", err, ErrCurrencyMismatch)
+	}
+}
@@ -0,0 +1,122 @@
+package money
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// rateDecimals is the precision rates are stored and printed with
+const rateDecimals = 8
+
+// Rate is an exact exchange rate. The zero value means no rate.
+type Rate struct {
+	rat *big.Rat
+}
+
+// ParseRate reads a positive decimal rate such as "0.92" or "48.75"
+func ParseRate(s string) (Rate, error) {
+	r, err := parseRat(s)
+	if err != nil {
+		return Rate{}, err
+	}
+	if r.Sign() <= 0 {
+		return Rate{}, ErrInvalidRate
+	}
+	return Rate{rat: r}, nil
+}
+
+// IdentityRate is the rate between a currency and itself
+func IdentityRate() Rate {
+	return Rate{rat: big.NewRat(1, 1)}
+}
+
+// IsZero reports whether the rate is unset
+func (r Rate) IsZero() bool {
+	return r.rat == nil || r.rat.Sign() == 0
+}
+
+// Rat returns a copy of the rate as a big.Rat
+func (r Rate) Rat() *big.Rat {
+	if r.rat == nil {
+		return new(big.Rat)
+	}
+	return new(big.Rat).Set(r.rat)
+}
+
+// Inverse returns the rate in the opposite direction
+func (r Rate) Inverse() (Rate, error) {
+	if r.IsZero() {
+		return Rate{}, ErrInvalidRate
+	}
+	return Rate{rat: new(big.Rat).Inv(r.rat)}, nil
+}
+
+// String formats the rate with up to eight decimals, e.g. "48.75"
+func (r Rate) String() string {
+	if r.rat == nil {
+		return "0"
+	}
+	s := r.rat.FloatString(rateDecimals)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// MarshalJSON encodes the rate as a JSON number, or null when unset
+func (r Rate) MarshalJSON() ([]byte, error) {
+	if r.IsZero() {
+		return []byte("null"), nil
+	}
+	return []byte(r.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a numeric string
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		*r = Rate{}
+		return nil
+	}
+	parsed, err := ParseRate(strings.Trim(s, `"`))
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+// Value stores the rate in a decimal column, or NULL when unset
+func (r Rate) Value() (driver.Value, error) {
+	if r.IsZero() {
+		return nil, nil
+	}
+	return r.rat.FloatString(rateDecimals), nil
+}
+
+// Scan reads a decimal column
+func (r *Rate) Scan(src interface{}) error {
+	var s string
+	switch v := src.(type) {
+	case nil:
+		*r = Rate{}
+		return nil
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	case int64:
+		s = strconv.FormatInt(v, 10)
+	default:
+		return fmt.Errorf("money: cannot scan %T into a rate", src)
+	}
+	rat, err := parseRat(s)
+	if err != nil {
+		return err
+	}
+	*r = Rate{rat: rat}
+	return nil
+}
@@ -3,21 +3,27 @@ package payment
 import (
 	"context"
 	"math/rand"
+	"sync"
 	"time"
 
 	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/logger"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
 	"go.uber.org/zap"
 )
 
 // PaymentInfo represents payment details
 type PaymentInfo struct {
-	OrderID     uint    `json:"order_id"`
-	Amount      float64 `json:"amount"`
-	Currency    string  `json:"currency"`
-	CardNumber  string  `json:"card_number"`
-	CardExpiry  string  `json:"card_expiry"`
-	CardCVC     string  `json:"card_cvc"`
-	Description string  `json:"description"`
+	OrderID     uint        `json:"order_id"`
+	Amount      money.Money `json:"amount"`
+	Currency    string      `json:"currency"`
+	CardNumber  string      `json:"card_number"`
+	CardExpiry  string      `json:"card_expiry"`
+	CardCVC     string      `json:"card_cvc"`
+	Description string      `json:"description"`
+	// Token of a stored payment method, charged instead of the card details
+	PaymentMethodToken string `json:"payment_method_token,omitempty"`
+	// Same for every attempt at the same charge, so a retry never charges twice
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
 }
 
 // PaymentResult represents the result of a payment processing attempt
@@ -27,19 +33,34 @@ type PaymentResult struct {
 	ErrorMessage  string `json:"error_message,omitempty"`
 }
 
+// RefundInfo represents a refund of a captured payment
+type RefundInfo struct {
+	OrderID       uint        `json:"order_id"`
+	TransactionID string      `json:"transaction_id"` // Transaction of the payment being refunded
+	Amount        money.Money `json:"amount"`
+	Currency      string      `json:"currency"`
+	Reason        string      `json:"reason"`
+	// Same for every attempt at the same refund, so a retry never refunds twice
+	IdempotencyKey string `json:"idempotency_key,omitempty"`
+}
+
 // Service defines the interface for payment processing
 type Service interface {
 	ProcessPayment(ctx context.Context, info PaymentInfo) (*PaymentResult, error)
+	RefundPayment(ctx context.Context, info RefundInfo) (*PaymentResult, error)
 }
 
 type mockService struct {
-	rng *rand.Rand
+	mu      sync.Mutex // Guards rng, which isn't safe for concurrent use, and results
+	rng     *rand.Rand
+	results map[string]*PaymentResult // Results of the requests made with an idempotency key
 }
 
 // NewMockService creates a new mock payment service
 func NewMockService() Service {
 	return &mockService{
-		rng: rand.New(rand.NewSource(time.Now().UnixNano())),
+		rng:     rand.New(rand.NewSource(time.Now().UnixNano())),
+		results: make(map[string]*PaymentResult),
 	}
 }
 
@@ -47,12 +68,16 @@ func NewMockService() Service {
 func (s *mockService) ProcessPayment(ctx context.Context, info PaymentInfo) (*PaymentResult, error) {
 	logger.Info(ctx, "Processing payment",
 		zap.Uint("order_id", info.OrderID),
-		zap.Float64("amount", info.Amount),
+		zap.String("amount", info.Amount.Decimal()),
 		zap.String("currency", info.Currency),
 	)
 
+	if result, ok := s.previousResult(info.IdempotencyKey); ok {
+		return result, nil
+	}
+
 	// Simulate processing delay (up to 3 seconds)
-	delay := time.Duration(s.rng.Intn(3000)) * time.Millisecond
+	delay := time.Duration(s.intn(3000)) * time.Millisecond
 	select {
 	case <-ctx.Done():
 		logger.Error(ctx, "Payment processing cancelled", zap.Error(ctx.Err()))
@@ -62,15 +87,15 @@ func (s *mockService) ProcessPayment(ctx context.Context, info PaymentInfo) (*Pa
 	}
 
 	// Simulate success rate (90% success)
-	if s.rng.Float64() < 0.9 {
+	if s.float64() < 0.9 {
 		result := &PaymentResult{
 			Success:       true,
-			TransactionID: generateTransactionID(s.rng),
+			TransactionID: s.transactionID(),
 		}
 		logger.Info(ctx, "Payment processed successfully",
 			zap.String("transaction_id", result.TransactionID),
 		)
-		return result, nil
+		return s.remember(info.IdempotencyKey, result), nil
 	}
 
 	// Simulate failure
@@ -79,17 +104,82 @@ func (s *mockService) ProcessPayment(ctx context.Context, info PaymentInfo) (*Pa
 		ErrorMessage: "Payment declined by issuer",
 	}
 	logger.Error(ctx, "Payment processing failed", zap.String("error", result.ErrorMessage))
-	return result, nil
+	return s.remember(info.IdempotencyKey, result), nil
+}
+
+// RefundPayment simulates refunding a payment, which always succeeds
+func (s *mockService) RefundPayment(ctx context.Context, info RefundInfo) (*PaymentResult, error) {
+	logger.Info(ctx, "Refunding payment",
+		zap.Uint("order_id", info.OrderID),
+		zap.String("transaction_id", info.TransactionID),
+		zap.String("amount", info.Amount.Decimal()),
+		zap.String("currency", info.Currency),
+	)
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if result, ok := s.previousResult(info.IdempotencyKey); ok {
+		return result, nil
+	}
+
+	return s.remember(info.IdempotencyKey, &PaymentResult{
+		Success:       true,
+		TransactionID: s.transactionID(),
+	}), nil
+}
+
+// previousResult returns the result of an earlier request with the same idempotency key
+func (s *mockService) previousResult(key string) (*PaymentResult, bool) {
+	if key == "" {
+		return nil, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result, ok := s.results[key]
+	return result, ok
 }
 
-// generateTransactionID creates a random transaction ID
-func generateTransactionID(rng *rand.Rand) string {
+// remember keeps the result of a request made with an idempotency key. If a concurrent
+// request with the same key finished first, its result wins.
+func (s *mockService) remember(key string, result *PaymentResult) *PaymentResult {
+	if key == "" {
+		return result
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if previous, ok := s.results[key]; ok {
+		return previous
+	}
+	s.results[key] = result
+	return result
+}
+
+func (s *mockService) intn(n int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rng.Intn(n)
+}
+
+func (s *mockService) float64() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rng.Float64()
+}
+
+// transactionID creates a random transaction ID
+func (s *mockService) transactionID() string {
 	const charset = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
 	const length = 12
 
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	b := make([]byte, length)
 	for i := range b {
-		b[i] = charset[rng.Intn(len(charset))]
+		b[i] = charset[s.rng.Intn(len(charset))]
 	}
 	return string(b)
 }
@@ -0,0 +1,314 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// memorySubscriptionBuffer is how many messages a memory subscription queues before
+// publishers wait for its reader
+const memorySubscriptionBuffer = 100
+
+// memoryRepository is an in-process stand-in for Redis. Everything lives in the memory of
+// a single process, so it suits tests and local runs without a Redis server, not several
+// instances sharing state.
+type memoryRepository struct {
+	mu            sync.Mutex
+	values        map[string]string
+	sortedSets    map[string]map[string]float64
+	expirations   map[string]time.Time
+	subscriptions map[*memorySubscription]bool
+}
+
+// NewMemoryRepository creates a Repository that keeps its data in memory instead of Redis
+func NewMemoryRepository() Repository {
+	return &memoryRepository{
+		values:        make(map[string]string),
+		sortedSets:    make(map[string]map[string]float64),
+		expirations:   make(map[string]time.Time),
+		subscriptions: make(map[*memorySubscription]bool),
+	}
+}
+
+func (r *memoryRepository) Get(ctx context.Context, key string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.expire(key)
+	value, ok := r.values[key]
+	if !ok {
+		return "", ErrNil
+	}
+	return value, nil
+}
+
+func (r *memoryRepository) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.delete(key)
+	r.values[key] = memoryString(value)
+	if expiration > 0 {
+		r.expirations[key] = time.Now().Add(expiration)
+	}
+	return nil
+}
+
+func (r *memoryRepository) Del(ctx context.Context, keys ...string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, key := range keys {
+		r.delete(key)
+	}
+	return nil
+}
+
+func (r *memoryRepository) DeleteByPattern(ctx context.Context, pattern string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key := range r.values {
+		if matched, _ := path.Match(pattern, key); matched {
+			r.delete(key)
+		}
+	}
+	for key := range r.sortedSets {
+		if matched, _ := path.Match(pattern, key); matched {
+			r.delete(key)
+		}
+	}
+	return nil
+}
+
+func (r *memoryRepository) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.expire(key)
+	if _, ok := r.values[key]; ok {
+		return false, nil
+	}
+	if _, ok := r.sortedSets[key]; ok {
+		return false, nil
+	}
+	r.values[key] = memoryString(value)
+	if expiration > 0 {
+		r.expirations[key] = time.Now().Add(expiration)
+	}
+	return true, nil
+}
+
+func (r *memoryRepository) DelIfValue(ctx context.Context, key string, value string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.expire(key)
+	if current, ok := r.values[key]; ok && current == value {
+		r.delete(key)
+	}
+	return nil
+}
+
+func (r *memoryRepository) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.expire(key)
+	_, isValue := r.values[key]
+	_, isSortedSet := r.sortedSets[key]
+	if isValue || isSortedSet {
+		r.expirations[key] = time.Now().Add(expiration)
+	}
+	return nil
+}
+
+func (r *memoryRepository) ZAdd(ctx context.Context, key string, score float64, member string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.expire(key)
+	if _, ok := r.values[key]; ok {
+		return fmt.Errorf("WRONGTYPE Operation against a key holding the wrong kind of value")
+	}
+	if r.sortedSets[key] == nil {
+		r.sortedSets[key] = make(map[string]float64)
+	}
+	r.sortedSets[key][member] = score
+	return nil
+}
+
+func (r *memoryRepository) ZRem(ctx context.Context, key string, members ...string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.expire(key)
+	for _, member := range members {
+		delete(r.sortedSets[key], member)
+	}
+	if len(r.sortedSets[key]) == 0 {
+		r.delete(key)
+	}
+	return nil
+}
+
+func (r *memoryRepository) ZCount(ctx context.Context, key string, min, max string) (int64, error) {
+	above, err := parseScoreBound(min, true)
+	if err != nil {
+		return 0, err
+	}
+	below, err := parseScoreBound(max, false)
+	if err != nil {
+		return 0, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.expire(key)
+	var count int64
+	for _, score := range r.sortedSets[key] {
+		if above(score) && below(score) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *memoryRepository) Publish(ctx context.Context, channel string, message interface{}) error {
+	r.mu.Lock()
+	var receivers []*memorySubscription
+	for sub := range r.subscriptions {
+		if sub.matches(channel) {
+			receivers = append(receivers, sub)
+		}
+	}
+	r.mu.Unlock()
+
+	msg := Message{Channel: channel, Payload: memoryString(message)}
+	for _, sub := range receivers {
+		sub.deliver(msg)
+	}
+	return nil
+}
+
+func (r *memoryRepository) PSubscribe(ctx context.Context, patterns ...string) (Subscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sub := &memorySubscription{
+		repo:     r,
+		patterns: patterns,
+		messages: make(chan Message, memorySubscriptionBuffer),
+		done:     make(chan struct{}),
+	}
+	r.subscriptions[sub] = true
+	return sub, nil
+}
+
+// expire removes a key whose expiration has passed; the caller holds r.mu
+func (r *memoryRepository) expire(key string) {
+	if expiresAt, ok := r.expirations[key]; ok && !time.Now().Before(expiresAt) {
+		r.delete(key)
+	}
+}
+
+// delete removes a key of any kind; the caller holds r.mu
+func (r *memoryRepository) delete(key string) {
+	delete(r.values, key)
+	delete(r.sortedSets, key)
+	delete(r.expirations, key)
+}
+
+type memorySubscription struct {
+	repo      *memoryRepository
+	patterns  []string
+	messages  chan Message
+	done      chan struct{}
+	mu        sync.RWMutex // Held for reading while delivering, so Close can wait for deliveries
+	closeOnce sync.Once
+}
+
+func (s *memorySubscription) Messages() <-chan Message {
+	return s.messages
+}
+
+func (s *memorySubscription) Close() error {
+	s.closeOnce.Do(func() {
+		s.repo.mu.Lock()
+		delete(s.repo.subscriptions, s)
+		s.repo.mu.Unlock()
+
+		// Let deliveries waiting on the reader give up, then close once they're done
+		close(s.done)
+		s.mu.Lock()
+		close(s.messages)
+		s.mu.Unlock()
+	})
+	return nil
+}
+
+func (s *memorySubscription) matches(channel string) bool {
+	for _, pattern := range s.patterns {
+		if matched, _ := path.Match(pattern, channel); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// deliver queues a message, waiting for the reader if the queue is full
+func (s *memorySubscription) deliver(msg Message) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Once closed, the messages channel may be closed too
+	select {
+	case <-s.done:
+		return
+	default:
+	}
+	select {
+	case <-s.done:
+	case s.messages <- msg:
+	}
+}
+
+// memoryString formats a value the way Redis stores it
+func memoryString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// parseScoreBound parses a ZCOUNT bound such as 10, (10, -inf or +inf into a check of
+// whether a score lies on the right side of it
+func parseScoreBound(bound string, lower bool) (func(float64) bool, error) {
+	exclusive := strings.HasPrefix(bound, "(")
+	value, err := strconv.ParseFloat(strings.TrimPrefix(bound, "("), 64)
+	if err != nil || math.IsNaN(value) {
+		return nil, fmt.Errorf("ERR min or max is not a float")
+	}
+
+	switch {
+	case lower && exclusive:
+		return func(score float64) bool { return score > value }, nil
+	case lower:
+		return func(score float64) bool { return score >= value }, nil
+	case exclusive:
+		return func(score float64) bool { return score < value }, nil
+	default:
+		return func(score float64) bool { return score <= value }, nil
+	}
+}
@@ -13,6 +13,27 @@ type Repository interface {
 	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
 	Del(ctx context.Context, keys ...string) error
 	DeleteByPattern(ctx context.Context, pattern string) error
+	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error)
+	DelIfValue(ctx context.Context, key string, value string) error
+	Expire(ctx context.Context, key string, expiration time.Duration) error
+	ZAdd(ctx context.Context, key string, score float64, member string) error
+	ZRem(ctx context.Context, key string, members ...string) error
+	ZCount(ctx context.Context, key string, min, max string) (int64, error)
+	Publish(ctx context.Context, channel string, message interface{}) error
+	PSubscribe(ctx context.Context, patterns ...string) (Subscription, error)
+}
+
+// Message is a message received on a channel
+type Message struct {
+	Channel string
+	Payload string
+}
+
+// Subscription receives the messages published to the channels it subscribed to
+type Subscription interface {
+	// Messages returns the channel messages arrive on. It is closed once the subscription is.
+	Messages() <-chan Message
+	Close() error
 }
 
 type repository struct {
@@ -61,3 +82,88 @@ func (r *repository) DeleteByPattern(ctx context.Context, pattern string) error
 
 	return nil
 }
+
+// SetNX stores a value only if the key doesn't exist yet and reports whether it did
+func (r *repository) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	return r.client.SetNX(ctx, key, value, expiration).Result()
+}
+
+// delIfValueScript deletes a key only while it still holds the given value
+var delIfValueScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// DelIfValue removes a key only if it still holds the given value, so a lock that expired
+// and was taken by someone else isn't released by its previous holder
+func (r *repository) DelIfValue(ctx context.Context, key string, value string) error {
+	return delIfValueScript.Run(ctx, r.client, []string{key}, value).Err()
+}
+
+// Expire sets a key to be removed after the expiration duration
+func (r *repository) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	return r.client.Expire(ctx, key, expiration).Err()
+}
+
+// ZAdd adds a member to a sorted set, or updates its score if it's already there
+func (r *repository) ZAdd(ctx context.Context, key string, score float64, member string) error {
+	return r.client.ZAdd(ctx, key, redis.Z{Score: score, Member: member}).Err()
+}
+
+// ZRem removes members from a sorted set
+func (r *repository) ZRem(ctx context.Context, key string, members ...string) error {
+	args := make([]interface{}, len(members))
+	for i, member := range members {
+		args[i] = member
+	}
+	return r.client.ZRem(ctx, key, args...).Err()
+}
+
+// ZCount counts the members of a sorted set with a score between min and max, which may
+// be -inf or +inf, or start with ( to leave the bound out
+func (r *repository) ZCount(ctx context.Context, key string, min, max string) (int64, error) {
+	return r.client.ZCount(ctx, key, min, max).Result()
+}
+
+// Publish sends a message to the subscribers of a channel
+func (r *repository) Publish(ctx context.Context, channel string, message interface{}) error {
+	return r.client.Publish(ctx, channel, message).Err()
+}
+
+// PSubscribe subscribes to the channels matching the patterns. It returns once Redis has
+// confirmed the subscription, so no message published after that is missed. The
+// subscription survives reconnects.
+func (r *repository) PSubscribe(ctx context.Context, patterns ...string) (Subscription, error) {
+	pubsub := r.client.PSubscribe(ctx, patterns...)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		return nil, err
+	}
+
+	sub := &subscription{
+		pubsub:   pubsub,
+		messages: make(chan Message),
+	}
+	go func() {
+		defer close(sub.messages)
+		for msg := range pubsub.Channel() {
+			sub.messages <- Message{Channel: msg.Channel, Payload: msg.Payload}
+		}
+	}()
+	return sub, nil
+}
+
+type subscription struct {
+	pubsub   *redis.PubSub
+	messages chan Message
+}
+
+func (s *subscription) Messages() <-chan Message {
+	return s.messages
+}
+
+func (s *subscription) Close() error {
+	return s.pubsub.Close()
+}
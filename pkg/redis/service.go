@@ -2,6 +2,8 @@ package redis
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"time"
 
@@ -15,6 +17,9 @@ type Service interface {
 	Cache(ctx context.Context, key string, value interface{}, expiration time.Duration) error
 	Invalidate(ctx context.Context, keys ...string) error
 	InvalidatePattern(ctx context.Context, pattern string) error
+	// Lock takes a lock shared by every instance of the service. It returns false if the
+	// lock is held elsewhere; otherwise the lock is held until released or ttl passes.
+	Lock(ctx context.Context, key string, ttl time.Duration) (release func(), acquired bool, err error)
 }
 
 type service struct {
@@ -98,3 +103,33 @@ func (s *service) InvalidatePattern(ctx context.Context, pattern string) error {
 	}
 	return nil
 }
+
+// Lock takes a lock on a key, tagged with a random token so only its holder releases it
+func (s *service) Lock(ctx context.Context, key string, ttl time.Duration) (func(), bool, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return nil, false, err
+	}
+	value := hex.EncodeToString(token)
+
+	acquired, err := s.repo.SetNX(ctx, key, value, ttl)
+	if err != nil {
+		logger.Error(ctx, "Failed to acquire lock",
+			zap.String("key", key),
+			zap.Error(err))
+		return nil, false, err
+	}
+	if !acquired {
+		return nil, false, nil
+	}
+
+	release := func() {
+		// Release even if the caller's context is done
+		if err := s.repo.DelIfValue(context.Background(), key, value); err != nil {
+			logger.Error(ctx, "Failed to release lock",
+				zap.String("key", key),
+				zap.Error(err))
+		}
+	}
+	return release, true, nil
+}
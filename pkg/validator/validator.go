@@ -2,15 +2,26 @@ package validator
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
 )
 
 var validate *validator.Validate
 
 func init() {
 	validate = validator.New()
+
+	// Validate money amounts by their minor units, so tags like gt=0 work on prices
+	validate.RegisterCustomTypeFunc(func(field reflect.Value) interface{} {
+		if m, ok := field.Interface().(money.Money); ok {
+			return m.Amount()
+		}
+		return nil
+	}, money.Money{})
 }
 
 // ValidationError represents a validation error
@@ -0,0 +1,262 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/logger"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/redis"
+)
+
+// Redis channels events are published on, one per kind of audience: ws:events:broadcast,
+// ws:events:user:<id>, ws:events:role:<role> and ws:events:topic:<topic>. Every instance
+// subscribes to all of them and delivers to its own connections.
+const (
+	channelPrefix    = "ws:events:"
+	broadcastChannel = channelPrefix + "broadcast"
+	userChannel      = channelPrefix + "user:"
+	roleChannel      = channelPrefix + "role:"
+	topicChannel     = channelPrefix + "topic:"
+)
+
+// Presence keys are sorted sets of the instances with a connection of the user, or a
+// subscriber of the topic, scored by when the entry expires unless renewed
+const (
+	userPresencePrefix  = "ws:presence:user:"
+	topicPresencePrefix = "ws:presence:topic:"
+)
+
+// presenceQueueSize bounds the presence changes waiting to be written to Redis
+const presenceQueueSize = 1024
+
+// ClusterConfig configures how a Manager shares its events and presence with the other
+// instances of the API
+type ClusterConfig struct {
+	NodeID      string        // Identifies this instance; random by default
+	PresenceTTL time.Duration // How long presence outlives an instance that stopped renewing it
+}
+
+type presenceChange struct {
+	key     string
+	present bool
+}
+
+// cluster connects a Manager to the other instances through Redis
+type cluster struct {
+	repo    redis.Repository
+	config  ClusterConfig
+	changes chan presenceChange
+}
+
+// NewClusterManager creates a WebSocket manager whose events reach the connections of
+// every instance sharing the Redis repository. SendToUser, Broadcast, BroadcastToRole and
+// Publish go through Redis channels, and each instance delivers what it receives to its
+// own connections. Events are only delivered locally while Redis can't be reached.
+func NewClusterManager(repo redis.Repository, config ClusterConfig) *Manager {
+	if config.NodeID == "" {
+		config.NodeID = uuid.New().String()
+	}
+	if config.PresenceTTL <= 0 {
+		config.PresenceTTL = 30 * time.Second
+	}
+
+	m := NewManager()
+	m.cluster = &cluster{
+		repo:    repo,
+		config:  config,
+		changes: make(chan presenceChange, presenceQueueSize),
+	}
+	return m
+}
+
+// IsOnline reports whether a user has a connection open on any instance
+func (m *Manager) IsOnline(ctx context.Context, userID uint) (bool, error) {
+	m.mu.RLock()
+	local := len(m.userConns[userID]) > 0
+	m.mu.RUnlock()
+
+	if local || m.cluster == nil {
+		return local, nil
+	}
+	return m.cluster.present(ctx, userPresenceKey(userID))
+}
+
+// fanOut publishes an event on a cluster channel. Without a cluster, or if Redis can't be
+// reached, the event is delivered to the local connections instead.
+func (m *Manager) fanOut(channel string, event Event, deliver func(Event)) {
+	if m.cluster == nil {
+		deliver(event)
+		return
+	}
+
+	ctx := context.Background()
+	if err := m.cluster.publish(ctx, channel, event); err != nil {
+		logger.Error(ctx, "Failed to publish WebSocket event, delivering to local connections only",
+			zap.Error(err),
+			zap.String("channel", channel),
+			zap.String("event_type", string(event.Type)))
+		deliver(event)
+	}
+}
+
+func (c *cluster) publish(ctx context.Context, channel string, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return c.repo.Publish(ctx, channel, data)
+}
+
+// receive delivers the events published by every instance to the local connections,
+// subscribing again if the subscription ends
+func (c *cluster) receive(m *Manager) {
+	ctx := context.Background()
+	for {
+		sub, err := c.repo.PSubscribe(ctx, channelPrefix+"*")
+		if err != nil {
+			logger.Error(ctx, "Failed to subscribe to WebSocket events, retrying", zap.Error(err))
+			time.Sleep(time.Second)
+			continue
+		}
+
+		for msg := range sub.Messages() {
+			if err := c.dispatch(m, msg); err != nil {
+				logger.Error(ctx, "Failed to deliver WebSocket event",
+					zap.Error(err),
+					zap.String("channel", msg.Channel))
+			}
+		}
+		sub.Close()
+	}
+}
+
+// dispatch delivers an event received on a channel to the local connections it is meant for
+func (c *cluster) dispatch(m *Manager, msg redis.Message) error {
+	// The payload is passed on as sent, rather than decoded and encoded again
+	var wire struct {
+		Type    EventType       `json:"type"`
+		Topic   string          `json:"topic"`
+		Payload json.RawMessage `json:"payload"`
+	}
+	if err := json.Unmarshal([]byte(msg.Payload), &wire); err != nil {
+		return err
+	}
+	event := Event{Type: wire.Type, Topic: wire.Topic, Payload: wire.Payload}
+
+	switch {
+	case msg.Channel == broadcastChannel:
+		m.broadcast <- event
+	case strings.HasPrefix(msg.Channel, userChannel):
+		userID, err := strconv.ParseUint(strings.TrimPrefix(msg.Channel, userChannel), 10, 32)
+		if err != nil {
+			return err
+		}
+		m.deliverToUser(uint(userID), event)
+	case strings.HasPrefix(msg.Channel, roleChannel):
+		m.deliverToRole(strings.TrimPrefix(msg.Channel, roleChannel), event)
+	case strings.HasPrefix(msg.Channel, topicChannel):
+		m.deliverToTopic(strings.TrimPrefix(msg.Channel, topicChannel), event)
+	default:
+		return fmt.Errorf("unknown channel %s", msg.Channel)
+	}
+	return nil
+}
+
+// track queues a presence change of this instance. Changes are dropped rather than hold
+// up the manager if Redis falls behind; renewals restore lost joins and lost leaves
+// expire.
+func (c *cluster) track(key string, present bool) {
+	select {
+	case c.changes <- presenceChange{key: key, present: present}:
+	default:
+		logger.Warn(context.Background(), "WebSocket presence queue is full, dropping change",
+			zap.String("key", key))
+	}
+}
+
+// maintainPresence writes this instance's presence changes to Redis and renews its
+// presence before it expires
+func (c *cluster) maintainPresence(m *Manager) {
+	ctx := context.Background()
+	ticker := time.NewTicker(c.config.PresenceTTL / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case change := <-c.changes:
+			var err error
+			if change.present {
+				err = c.join(ctx, change.key)
+			} else {
+				err = c.leave(ctx, change.key)
+			}
+			if err != nil {
+				logger.Error(ctx, "Failed to update WebSocket presence",
+					zap.Error(err),
+					zap.String("key", change.key))
+			}
+
+		case <-ticker.C:
+			for _, key := range m.presenceKeys() {
+				if err := c.join(ctx, key); err != nil {
+					logger.Error(ctx, "Failed to renew WebSocket presence",
+						zap.Error(err),
+						zap.String("key", key))
+					break
+				}
+			}
+		}
+	}
+}
+
+func (c *cluster) join(ctx context.Context, key string) error {
+	expiresAt := time.Now().Add(c.config.PresenceTTL)
+	if err := c.repo.ZAdd(ctx, key, float64(expiresAt.UnixMilli()), c.config.NodeID); err != nil {
+		return err
+	}
+	return c.repo.Expire(ctx, key, c.config.PresenceTTL)
+}
+
+func (c *cluster) leave(ctx context.Context, key string) error {
+	return c.repo.ZRem(ctx, key, c.config.NodeID)
+}
+
+// present reports whether any instance has an unexpired entry under a presence key
+func (c *cluster) present(ctx context.Context, key string) (bool, error) {
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	count, err := c.repo.ZCount(ctx, key, now, "+inf")
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// presenceKeys returns the presence keys of this instance's users and topics
+func (m *Manager) presenceKeys() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	keys := make([]string, 0, len(m.userConns)+len(m.topics))
+	for userID := range m.userConns {
+		keys = append(keys, userPresenceKey(userID))
+	}
+	for topic := range m.topics {
+		keys = append(keys, topicPresenceKey(topic))
+	}
+	return keys
+}
+
+func userPresenceKey(userID uint) string {
+	return userPresencePrefix + strconv.FormatUint(uint64(userID), 10)
+}
+
+func topicPresenceKey(topic string) string {
+	return topicPresencePrefix + topic
+}
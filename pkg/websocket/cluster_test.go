@@ -0,0 +1,356 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/logger"
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/redis"
+)
+
+// waitTimeout bounds how long a test waits for an event or a state to be reached
+const waitTimeout = 2 * time.Second
+
+// eventWarmUp is broadcast until every manager of a test cluster receives it, so tests
+// start once all of them are subscribed to the cluster channels
+const eventWarmUp EventType = "warm_up"
+
+func TestMain(m *testing.M) {
+	logger.Init("test")
+	os.Exit(m.Run())
+}
+
+// testConn is the far end of a client's connection, as a browser would hold it
+type testConn struct {
+	conn   *websocket.Conn
+	events chan Event
+}
+
+// next returns the next event the connection receives, skipping warm-up broadcasts
+func (c *testConn) next(t *testing.T) Event {
+	t.Helper()
+	timeout := time.After(waitTimeout)
+	for {
+		select {
+		case event, ok := <-c.events:
+			if !ok {
+				t.Fatal("connection closed while waiting for an event")
+			}
+			if event.Type != eventWarmUp {
+				return event
+			}
+		case <-timeout:
+			t.Fatal("timed out waiting for an event")
+		}
+	}
+}
+
+func (c *testConn) expect(t *testing.T, eventType EventType) {
+	t.Helper()
+	if event := c.next(t); event.Type != eventType {
+		t.Fatalf("received %s event, want %s", event.Type, eventType)
+	}
+}
+
+func (c *testConn) close() {
+	c.conn.Close()
+}
+
+// newCluster starts managers sharing a memory repository, the way instances share Redis
+func newCluster(t *testing.T, repo redis.Repository, size int, ttl time.Duration) []*Manager {
+	t.Helper()
+	managers := make([]*Manager, size)
+	warmUps := make([]*testConn, size)
+	for i := range managers {
+		managers[i] = NewClusterManager(repo, ClusterConfig{PresenceTTL: ttl})
+		go managers[i].Start()
+		_, warmUps[i] = connect(t, managers[i], 0, "")
+	}
+
+	// A broadcast published before a manager subscribed is lost, so keep broadcasting
+	// until every manager delivers one
+	received := make([]bool, size)
+	deadline := time.Now().Add(waitTimeout)
+	for waiting := size; waiting > 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the managers to subscribe")
+		}
+		managers[0].Broadcast(Event{Type: eventWarmUp})
+		time.Sleep(10 * time.Millisecond)
+		for i, conn := range warmUps {
+			select {
+			case <-conn.events:
+				if !received[i] {
+					received[i] = true
+					waiting--
+				}
+			default:
+			}
+		}
+	}
+	for _, conn := range warmUps {
+		conn.close()
+	}
+	return managers
+}
+
+// accept opens a connection for a user and registers it with the manager, without
+// listening for the user's messages
+func accept(t *testing.T, m *Manager, userID uint, role string) (*Client, *testConn) {
+	t.Helper()
+	upgrader := websocket.Upgrader{}
+	clients := make(chan *Client, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		clients <- &Client{UserID: userID, Role: role, Conn: conn, Manager: m}
+	}))
+	t.Cleanup(server.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	peer := &testConn{conn: conn, events: make(chan Event, 100)}
+	go func() {
+		defer close(peer.events)
+		for {
+			var event Event
+			if err := conn.ReadJSON(&event); err != nil {
+				return
+			}
+			peer.events <- event
+		}
+	}()
+
+	client := <-clients
+	m.Register <- client
+	eventually(t, "client registered", func() bool {
+		m.mu.RLock()
+		defer m.mu.RUnlock()
+		return m.clients[client]
+	})
+	return client, peer
+}
+
+// connect opens a connection for a user the way the WebSocket handler does
+func connect(t *testing.T, m *Manager, userID uint, role string) (*Client, *testConn) {
+	t.Helper()
+	client, peer := accept(t, m, userID, role)
+	go m.Listen(context.Background(), client, nil)
+	return client, peer
+}
+
+// eventually fails the test unless the condition holds within waitTimeout
+func eventually(t *testing.T, what string, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(waitTimeout)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func isOnline(t *testing.T, m *Manager, userID uint) bool {
+	t.Helper()
+	online, err := m.IsOnline(context.Background(), userID)
+	if err != nil {
+		t.Fatalf("IsOnline unexpected error: %v", err)
+	}
+	return online
+}
+
+func TestClusterDelivery(t *testing.T) {
+	managers := newCluster(t, redis.NewMemoryRepository(), 2, time.Minute)
+	a, b := managers[0], managers[1]
+
+	customerOnA, customerA := connect(t, a, 1, "customer")
+	_, customerB := connect(t, b, 1, "customer")
+	_, otherCustomerB := connect(t, b, 2, "customer")
+	_, adminB := connect(t, b, 3, "admin")
+	a.Subscribe(customerOnA, "order:1")
+	everyone := []*testConn{customerA, customerB, otherCustomerB, adminB}
+
+	tests := []struct {
+		name      string
+		send      func(event Event)
+		receivers []*testConn
+	}{
+		{
+			name:      "user on both instances",
+			send:      func(event Event) { a.SendToUser(1, event) },
+			receivers: []*testConn{customerA, customerB},
+		},
+		{
+			name:      "user on the other instance",
+			send:      func(event Event) { a.SendToUser(2, event) },
+			receivers: []*testConn{otherCustomerB},
+		},
+		{
+			name:      "role",
+			send:      func(event Event) { a.BroadcastToRole("admin", event) },
+			receivers: []*testConn{adminB},
+		},
+		{
+			name:      "topic",
+			send:      func(event Event) { b.Publish("order:1", event) },
+			receivers: []*testConn{customerA},
+		},
+		{
+			name:      "everyone",
+			send:      func(event Event) { b.Broadcast(event) },
+			receivers: everyone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eventType := EventType(tt.name)
+			tt.send(Event{Type: eventType, Payload: map[string]string{"case": tt.name}})
+			// Events reach each connection in the order they're published, so the marker
+			// shows who didn't receive the event
+			marker := EventType("after " + tt.name)
+			a.Broadcast(Event{Type: marker})
+
+			for _, conn := range everyone {
+				receiver := false
+				for _, r := range tt.receivers {
+					receiver = receiver || r == conn
+				}
+				if receiver {
+					event := conn.next(t)
+					if event.Type != eventType {
+						t.Fatalf("received %s event, want %s", event.Type, eventType)
+					}
+					payload, _ := json.Marshal(event.Payload)
+					if want := `{"case":"` + tt.name + `"}`; string(payload) != want {
+						t.Errorf("payload = %s, want %s", payload, want)
+					}
+				}
+				conn.expect(t, marker)
+			}
+		})
+	}
+}
+
+func TestClusterPresenceCounting(t *testing.T) {
+	repo := redis.NewMemoryRepository()
+	managers := newCluster(t, repo, 2, time.Minute)
+	a, b := managers[0], managers[1]
+	ctx := context.Background()
+
+	// Each instance with a connection of the user counts once, however many it has
+	instances := func() int64 {
+		count, err := repo.ZCount(ctx, userPresenceKey(5), "-inf", "+inf")
+		if err != nil {
+			t.Fatalf("ZCount unexpected error: %v", err)
+		}
+		return count
+	}
+
+	first, _ := connect(t, a, 5, "customer")
+	_, second := connect(t, a, 5, "customer")
+	eventually(t, "presence on one instance", func() bool { return instances() == 1 && isOnline(t, b, 5) })
+
+	_, onB := connect(t, b, 5, "customer")
+	eventually(t, "presence on two instances", func() bool { return instances() == 2 })
+
+	first.Conn.Close()
+	second.close()
+	eventually(t, "presence on the other instance only", func() bool { return instances() == 1 })
+	if !isOnline(t, a, 5) {
+		t.Error("user is offline while connected to the other instance")
+	}
+
+	onB.close()
+	eventually(t, "user to go offline", func() bool { return !isOnline(t, a, 5) && !isOnline(t, b, 5) })
+	if count := instances(); count != 0 {
+		t.Errorf("presence left on %d instances", count)
+	}
+
+	client, _ := connect(t, b, 6, "customer")
+	b.Subscribe(client, "product:1")
+	eventually(t, "topic subscriber", func() bool { return a.HasSubscribers("product:1") })
+	b.Unsubscribe(client, "product:1")
+	eventually(t, "no topic subscribers", func() bool { return !a.HasSubscribers("product:1") })
+}
+
+func TestClusterPresenceExpiry(t *testing.T) {
+	const ttl = 300 * time.Millisecond
+	repo := redis.NewMemoryRepository()
+	managers := newCluster(t, repo, 2, ttl)
+	a, b := managers[0], managers[1]
+
+	// A running instance renews the presence of its connections
+	connect(t, a, 7, "customer")
+	eventually(t, "user to come online", func() bool { return isOnline(t, b, 7) })
+	time.Sleep(3 * ttl)
+	if !isOnline(t, b, 7) {
+		t.Error("presence of a connected user expired")
+	}
+
+	// An instance that stopped, without saying goodbye, stops renewing
+	stopped := NewClusterManager(repo, ClusterConfig{NodeID: "stopped", PresenceTTL: ttl})
+	if err := stopped.cluster.join(context.Background(), userPresenceKey(8)); err != nil {
+		t.Fatalf("join unexpected error: %v", err)
+	}
+	if !isOnline(t, b, 8) {
+		t.Fatal("user connected to the stopped instance is offline before the TTL")
+	}
+	eventually(t, "presence to expire", func() bool { return !isOnline(t, b, 8) })
+}
+
+func TestFailedWriteUnregistersClient(t *testing.T) {
+	managers := newCluster(t, redis.NewMemoryRepository(), 2, time.Minute)
+	a, b := managers[0], managers[1]
+
+	_, healthy := connect(t, a, 10, "customer")
+	// No one listens on the broken connection, so only the failed write unregisters it
+	broken, _ := accept(t, a, 11, "customer")
+	broken.Conn.Close()
+
+	tests := []struct {
+		name string
+		send func(event Event)
+	}{
+		{name: "broadcast", send: func(event Event) { b.Broadcast(event) }},
+		{name: "broadcast again", send: func(event Event) { b.Broadcast(event) }},
+		{name: "role", send: func(event Event) { b.BroadcastToRole("customer", event) }},
+		{name: "user", send: func(event Event) { b.SendToUser(11, event) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.send(Event{Type: EventType(tt.name)})
+			marker := EventType("after " + tt.name)
+			b.Broadcast(Event{Type: marker})
+			if tt.name != "user" {
+				healthy.expect(t, EventType(tt.name))
+			}
+			healthy.expect(t, marker)
+		})
+	}
+
+	eventually(t, "broken client to be unregistered", func() bool {
+		a.mu.RLock()
+		defer a.mu.RUnlock()
+		return !a.clients[broken]
+	})
+	if isOnline(t, a, 11) {
+		t.Error("user of the broken connection is still online")
+	}
+}
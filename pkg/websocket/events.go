@@ -1,22 +1,112 @@
 package websocket
 
+import (
+	"fmt"
+	"time"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/money"
+)
+
 // Event types
 const (
-	EventOrderCreated      EventType = "order_created"
-	EventOrderCancelled    EventType = "order_cancelled"
-	EventInventoryUpdated  EventType = "inventory_updated"
+	EventOrderCreated       EventType = "order_created"
+	EventOrderCancelled     EventType = "order_cancelled"
+	EventOrderUpdated       EventType = "order_updated"
+	EventInventoryUpdated   EventType = "inventory_updated"
+	EventLowStockAlert      EventType = "low_stock_alert"
+	EventBackorderAllocated EventType = "backorder_allocated"
+	EventOrderStatusChanged EventType = "order_status_changed"
+	EventProductStock       EventType = "product_stock"
+
+	EventDashboardSnapshot           EventType = "dashboard_snapshot"
+	EventDashboardOrderCreated       EventType = "dashboard_order_created"
+	EventDashboardOrderStatusChanged EventType = "dashboard_order_status_changed"
+)
+
+// Topics
+const (
+	// TopicAdminDashboard carries the admin dashboard feed: a snapshot on subscribing, then
+	// new orders, order status changes and low stock alerts
+	TopicAdminDashboard = "admin:dashboard"
+	// TopicAdminOrders carries the events of every order
+	TopicAdminOrders = "admin:orders"
 )
 
+// OrderTopic returns the topic carrying an order's events
+func OrderTopic(orderID uint) string {
+	return fmt.Sprintf("order:%d", orderID)
+}
+
+// ProductStockTopic returns the topic carrying a product's stock: a product_stock event on
+// subscribing, then one whenever it changes
+func ProductStockTopic(productID uint) string {
+	return fmt.Sprintf("product:%d:stock", productID)
+}
+
 // OrderEventPayload represents the payload for order-related events
 type OrderEventPayload struct {
-	OrderID     uint    `json:"order_id"`
-	Status      string  `json:"status"`
-	TotalAmount float64 `json:"total_amount"`
+	OrderID     uint        `json:"order_id"`
+	Status      string      `json:"status"`
+	TotalAmount money.Money `json:"total_amount"`
+	Currency    string      `json:"currency"`
 }
 
 // InventoryEventPayload represents the payload for inventory-related events
 type InventoryEventPayload struct {
-	ProductID uint    `json:"product_id"`
-	Quantity  int     `json:"quantity"`
-	Name      string  `json:"name"`
+	ProductID uint   `json:"product_id"`
+	Quantity  int    `json:"quantity"`
+	Name      string `json:"name"`
+}
+
+// ProductStockEventPayload represents a product's stock across warehouses
+type ProductStockEventPayload struct {
+	ProductID uint `json:"product_id"`
+	Available int  `json:"available"`
+	InStock   bool `json:"in_stock"`
+}
+
+// LowStockEventPayload represents the payload sent to admins when a product runs low
+type LowStockEventPayload struct {
+	AlertID                  uint   `json:"alert_id"`
+	ProductID                uint   `json:"product_id"`
+	Name                     string `json:"name"`
+	WarehouseID              uint   `json:"warehouse_id"`
+	StockLevel               int    `json:"stock_level"`
+	MinimumStock             int    `json:"minimum_stock"`
+	SuggestedReorderQuantity int    `json:"suggested_reorder_quantity"`
+}
+
+// DashboardTotals represents the admin dashboard's figures for the current UTC day
+type DashboardTotals struct {
+	Date           time.Time      `json:"date"`
+	Orders         int            `json:"orders"`           // Placed today, whatever their status
+	OrdersByStatus map[string]int `json:"orders_by_status"` // Orders placed today by their current status
+	Revenue        money.Money    `json:"revenue"`          // Today's bookings less cancellations, in the reporting currency
+	Currency       string         `json:"currency"`
+}
+
+// DashboardOrder represents an order on the admin dashboard
+type DashboardOrder struct {
+	OrderID     uint        `json:"order_id"`
+	UserID      uint        `json:"user_id"`
+	Status      string      `json:"status"`
+	TotalAmount money.Money `json:"total_amount"` // In the order currency
+	Currency    string      `json:"currency"`
+	CreatedAt   time.Time   `json:"created_at"`
+}
+
+// DashboardSnapshotPayload represents the state of the admin dashboard, sent when an admin
+// connects. The events that follow update it.
+type DashboardSnapshotPayload struct {
+	Today          DashboardTotals        `json:"today"`
+	RecentOrders   []DashboardOrder       `json:"recent_orders"`    // Latest first
+	LowStockAlerts []LowStockEventPayload `json:"low_stock_alerts"` // Open alerts, lowest stock first
+}
+
+// DashboardOrderPayload represents a new order or an order status change on the admin
+// dashboard, with today's totals after it
+type DashboardOrderPayload struct {
+	Order          DashboardOrder  `json:"order"`
+	PreviousStatus string          `json:"previous_status,omitempty"` // For status changes
+	Today          DashboardTotals `json:"today"`
 }
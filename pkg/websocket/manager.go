@@ -1,10 +1,15 @@
 package websocket
 
 import (
+	"context"
 	"encoding/json"
+	"strconv"
 	"sync"
 
 	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/logger"
 )
 
 // EventType represents the type of WebSocket event
@@ -14,26 +19,32 @@ type EventType string
 type Client struct {
 	ID       string
 	UserID   uint
+	Role     string // Role from the JWT claims, used for role channels such as admin
 	Conn     *websocket.Conn
 	Manager  *Manager
 	mu       sync.Mutex
 	isClosed bool
+	topics   map[string]bool    // Topics the client is subscribed to, guarded by the manager's lock
+	held     map[string][]Event // Topic events held back until the topic's snapshot is sent
 }
 
 // Event represents a WebSocket event
 type Event struct {
 	Type    EventType   `json:"type"`
+	Topic   string      `json:"topic,omitempty"` // Set on events published to a topic
 	Payload interface{} `json:"payload"`
 }
 
 // Manager handles WebSocket connections and broadcasting
 type Manager struct {
 	clients    map[*Client]bool
-	userConns  map[uint][]*Client // Map user ID to their connections
-	Register   chan *Client       // Channel for registering new clients
+	userConns  map[uint][]*Client          // Map user ID to their connections
+	topics     map[string]map[*Client]bool // Map topic to its subscribers
+	Register   chan *Client                // Channel for registering new clients
 	unregister chan *Client
 	broadcast  chan Event
 	mu         sync.RWMutex
+	cluster    *cluster // Shares events with the other instances; nil for a single instance
 }
 
 // NewManager creates a new WebSocket manager
@@ -41,6 +52,7 @@ func NewManager() *Manager {
 	return &Manager{
 		clients:    make(map[*Client]bool),
 		userConns:  make(map[uint][]*Client),
+		topics:     make(map[string]map[*Client]bool),
 		Register:   make(chan *Client),
 		unregister: make(chan *Client),
 		broadcast:  make(chan Event, 100), // Buffered channel to prevent blocking
@@ -49,23 +61,47 @@ func NewManager() *Manager {
 
 // Start starts the WebSocket manager
 func (m *Manager) Start() {
+	if m.cluster != nil {
+		go m.cluster.receive(m)
+		go m.cluster.maintainPresence(m)
+	}
+
 	for {
 		select {
 		case client := <-m.Register:
 			m.mu.Lock()
 			m.clients[client] = true
+			online := len(m.userConns[client.UserID]) > 0
 			m.userConns[client.UserID] = append(m.userConns[client.UserID], client)
 			m.mu.Unlock()
 
+			if !online && m.cluster != nil {
+				m.cluster.track(userPresenceKey(client.UserID), true)
+			}
+
 		case client := <-m.unregister:
+			var gone []string
 			m.mu.Lock()
 			if _, ok := m.clients[client]; ok {
 				delete(m.clients, client)
-				m.removeUserConnection(client)
+				if m.removeUserConnection(client) {
+					gone = append(gone, userPresenceKey(client.UserID))
+				}
 				client.Conn.Close()
 			}
+			for topic := range client.topics {
+				if m.removeFromTopic(client, topic) {
+					gone = append(gone, topicPresenceKey(topic))
+				}
+			}
 			m.mu.Unlock()
 
+			if m.cluster != nil {
+				for _, key := range gone {
+					m.cluster.track(key, false)
+				}
+			}
+
 		case event := <-m.broadcast:
 			m.broadcastEvent(event)
 		}
@@ -74,18 +110,134 @@ func (m *Manager) Start() {
 
 // SendToUser sends an event to a specific user's connections
 func (m *Manager) SendToUser(userID uint, event Event) {
+	m.fanOut(userChannel+strconv.FormatUint(uint64(userID), 10), event, func(event Event) {
+		m.deliverToUser(userID, event)
+	})
+}
+
+// Broadcast sends an event to all connected clients
+func (m *Manager) Broadcast(event Event) {
+	m.fanOut(broadcastChannel, event, func(event Event) {
+		m.broadcast <- event
+	})
+}
+
+// BroadcastToRole sends an event to every connection of users with the given role
+func (m *Manager) BroadcastToRole(role string, event Event) {
+	m.fanOut(roleChannel+role, event, func(event Event) {
+		m.deliverToRole(role, event)
+	})
+}
+
+// Subscribe adds a client to the subscribers of a topic
+func (m *Manager) Subscribe(client *Client, topic string) {
+	m.mu.Lock()
+	first := len(m.topics[topic]) == 0
+	if m.topics[topic] == nil {
+		m.topics[topic] = make(map[*Client]bool)
+	}
+	m.topics[topic][client] = true
+	if client.topics == nil {
+		client.topics = make(map[string]bool)
+	}
+	client.topics[topic] = true
+	m.mu.Unlock()
+
+	if first && m.cluster != nil {
+		m.cluster.track(topicPresenceKey(topic), true)
+	}
+}
+
+// SubscribeWithSnapshot subscribes a client to a topic and sends it the topic's current
+// state before any update. Events published to the topic while the snapshot is taken are
+// held back and sent right after it, so none of the changes it may miss are lost.
+func (m *Manager) SubscribeWithSnapshot(client *Client, topic string, snapshot func() (Event, error)) error {
+	client.hold(topic)
+	m.Subscribe(client, topic)
+
+	event, err := snapshot()
+	if err != nil {
+		m.Unsubscribe(client, topic)
+		client.release(topic, nil)
+		return err
+	}
+	event.Topic = topic
+	client.release(topic, &event)
+	return nil
+}
+
+// Unsubscribe removes a client from the subscribers of a topic
+func (m *Manager) Unsubscribe(client *Client, topic string) {
+	m.mu.Lock()
+	gone := m.removeFromTopic(client, topic)
+	m.mu.Unlock()
+
+	if gone && m.cluster != nil {
+		m.cluster.track(topicPresenceKey(topic), false)
+	}
+}
+
+// Publish sends an event to the subscribers of a topic
+func (m *Manager) Publish(topic string, event Event) {
+	event.Topic = topic
+	m.fanOut(topicChannel+topic, event, func(event Event) {
+		m.deliverToTopic(topic, event)
+	})
+}
+
+// HasSubscribers reports whether any client is subscribed to a topic, so publishers can
+// skip building events no one would receive. In a cluster it errs on the side of yes
+// when Redis can't be reached.
+func (m *Manager) HasSubscribers(topic string) bool {
+	m.mu.RLock()
+	local := len(m.topics[topic]) > 0
+	m.mu.RUnlock()
+
+	if local || m.cluster == nil {
+		return local
+	}
+
+	ctx := context.Background()
+	present, err := m.cluster.present(ctx, topicPresenceKey(topic))
+	if err != nil {
+		logger.Error(ctx, "Failed to check WebSocket topic subscribers",
+			zap.Error(err),
+			zap.String("topic", topic))
+		return true
+	}
+	return present
+}
+
+// deliverToUser sends an event to the user's connections on this instance
+func (m *Manager) deliverToUser(userID uint, event Event) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	clients := m.userConns[userID]
-	for _, client := range clients {
+	for _, client := range m.userConns[userID] {
 		client.sendEvent(event)
 	}
 }
 
-// Broadcast sends an event to all connected clients
-func (m *Manager) Broadcast(event Event) {
-	m.broadcast <- event
+// deliverToRole sends an event to the connections on this instance of users with the role
+func (m *Manager) deliverToRole(role string, event Event) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for client := range m.clients {
+		if client.Role == role {
+			client.sendEvent(event)
+		}
+	}
+}
+
+// deliverToTopic sends an event to the subscribers of a topic on this instance
+func (m *Manager) deliverToTopic(topic string, event Event) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for client := range m.topics[topic] {
+		client.sendTopicEvent(topic, event)
+	}
 }
 
 func (m *Manager) broadcastEvent(event Event) {
@@ -97,7 +249,8 @@ func (m *Manager) broadcastEvent(event Event) {
 	}
 }
 
-func (m *Manager) removeUserConnection(client *Client) {
+// removeUserConnection reports whether it removed the user's last connection
+func (m *Manager) removeUserConnection(client *Client) bool {
 	conns := m.userConns[client.UserID]
 	for i, c := range conns {
 		if c == client {
@@ -107,13 +260,71 @@ func (m *Manager) removeUserConnection(client *Client) {
 	}
 	if len(m.userConns[client.UserID]) == 0 {
 		delete(m.userConns, client.UserID)
+		return true
 	}
+	return false
+}
+
+// removeFromTopic reports whether it removed the topic's last subscriber
+func (m *Manager) removeFromTopic(client *Client, topic string) bool {
+	_, subscribed := m.topics[topic][client]
+	delete(m.topics[topic], client)
+	delete(client.topics, topic)
+	if subscribed && len(m.topics[topic]) == 0 {
+		delete(m.topics, topic)
+		return true
+	}
+	return false
 }
 
 func (c *Client) sendEvent(event Event) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	c.write(event)
+}
+
+// sendTopicEvent sends an event published to a topic, unless the topic is held back
+// while its snapshot is taken
+func (c *Client) sendTopicEvent(topic string, event Event) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if held, ok := c.held[topic]; ok {
+		c.held[topic] = append(held, event)
+		return
+	}
+	c.write(event)
+}
+
+// hold holds back the events published to a topic until release is called
+func (c *Client) hold(topic string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.held == nil {
+		c.held = make(map[string][]Event)
+	}
+	c.held[topic] = nil
+}
+
+// release sends the given event, if any, followed by the events held back for a topic
+func (c *Client) release(topic string, first *Event) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	held := c.held[topic]
+	delete(c.held, topic)
+	if first != nil {
+		c.write(*first)
+		for _, event := range held {
+			c.write(event)
+		}
+	}
+}
+
+// write sends an event over the connection; the caller holds c.mu
+func (c *Client) write(event Event) {
 	if c.isClosed {
 		return
 	}
@@ -125,6 +336,8 @@ func (c *Client) sendEvent(event Event) {
 
 	if err := c.Conn.WriteMessage(websocket.TextMessage, data); err != nil {
 		c.isClosed = true
-		c.Manager.unregister <- c
+		// Unregister from another goroutine: the caller holds c.mu and usually the manager's
+		// lock, and broadcasts are written by the manager loop that reads unregister
+		go func() { c.Manager.unregister <- c }()
 	}
 }
@@ -0,0 +1,146 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"go.uber.org/zap"
+
+	"github.com/Ahmed1monm/backend-golang-task-2025/pkg/logger"
+)
+
+// maxMessageSize caps the size of a message a client may send
+const maxMessageSize = 4096
+
+// maxTopicsPerClient caps how many topics a connection may subscribe to
+const maxTopicsPerClient = 100
+
+// Action is what a client asks for in a message
+type Action string
+
+// Client actions
+const (
+	ActionSubscribe   Action = "subscribe"
+	ActionUnsubscribe Action = "unsubscribe"
+	ActionPing        Action = "ping"
+)
+
+// Reply event types, sent in answer to a client message
+const (
+	EventSubscribed   EventType = "subscribed"
+	EventUnsubscribed EventType = "unsubscribed"
+	EventPong         EventType = "pong"
+	EventError        EventType = "error"
+)
+
+// Subscription errors a client is told about
+var (
+	ErrUnknownTopic    = errors.New("unknown topic")
+	ErrTopicNotAllowed = errors.New("not allowed to subscribe to this topic")
+	ErrTooManyTopics   = errors.New("too many subscriptions")
+)
+
+// ClientMessage represents a message sent by a client, such as
+// {"action": "subscribe", "topic": "order:42", "id": "1"}
+type ClientMessage struct {
+	Action Action `json:"action"`
+	Topic  string `json:"topic,omitempty"`
+	ID     string `json:"id,omitempty"` // Echoed back in the reply, to match it with the message
+}
+
+// ReplyPayload represents the payload of the reply to a client message
+type ReplyPayload struct {
+	ID    string `json:"id,omitempty"`
+	Topic string `json:"topic,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// SnapshotFunc returns the current state of a topic, sent to a new subscriber before any update
+type SnapshotFunc func() (Event, error)
+
+// TopicAuthorizer decides which topics a client may subscribe to
+type TopicAuthorizer interface {
+	// AuthorizeTopic returns ErrUnknownTopic or ErrTopicNotAllowed if the client may not
+	// subscribe to the topic. Otherwise it returns the topic's snapshot function, or nil if
+	// the topic has none.
+	AuthorizeTopic(ctx context.Context, client *Client, topic string) (SnapshotFunc, error)
+}
+
+// SubscribeAuthorized subscribes a client to a topic if the authorizer allows it, sending
+// the topic's snapshot first if it has one
+func (m *Manager) SubscribeAuthorized(ctx context.Context, client *Client, topic string, authorizer TopicAuthorizer) error {
+	m.mu.RLock()
+	full := len(client.topics) >= maxTopicsPerClient && !client.topics[topic]
+	m.mu.RUnlock()
+	if full {
+		return ErrTooManyTopics
+	}
+
+	snapshot, err := authorizer.AuthorizeTopic(ctx, client, topic)
+	if err != nil {
+		return err
+	}
+	if snapshot == nil {
+		m.Subscribe(client, topic)
+		return nil
+	}
+	return m.SubscribeWithSnapshot(client, topic, snapshot)
+}
+
+// Listen reads a client's messages until its connection closes, then unregisters it.
+// Clients subscribe to topics, unsubscribe from them and ping; each message is answered
+// with a subscribed, unsubscribed, pong or error event.
+func (m *Manager) Listen(ctx context.Context, client *Client, authorizer TopicAuthorizer) {
+	defer func() {
+		m.unregister <- client
+	}()
+
+	client.Conn.SetReadLimit(maxMessageSize)
+	for {
+		_, data, err := client.Conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		m.handleMessage(ctx, client, data, authorizer)
+	}
+}
+
+func (m *Manager) handleMessage(ctx context.Context, client *Client, data []byte, authorizer TopicAuthorizer) {
+	var msg ClientMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		client.sendEvent(Event{Type: EventError, Payload: ReplyPayload{Error: "invalid message"}})
+		return
+	}
+	reply := ReplyPayload{ID: msg.ID, Topic: msg.Topic}
+
+	switch msg.Action {
+	case ActionPing:
+		client.sendEvent(Event{Type: EventPong, Payload: ReplyPayload{ID: msg.ID}})
+
+	case ActionSubscribe:
+		err := m.SubscribeAuthorized(ctx, client, msg.Topic, authorizer)
+		switch {
+		case err == nil:
+			client.sendEvent(Event{Type: EventSubscribed, Payload: reply})
+		case errors.Is(err, ErrUnknownTopic), errors.Is(err, ErrTopicNotAllowed), errors.Is(err, ErrTooManyTopics):
+			reply.Error = err.Error()
+			client.sendEvent(Event{Type: EventError, Payload: reply})
+		default:
+			logger.Error(ctx, "Failed to subscribe WebSocket client",
+				zap.Error(err),
+				zap.Uint("user_id", client.UserID),
+				zap.String("topic", msg.Topic))
+			reply.Error = "failed to subscribe"
+			client.sendEvent(Event{Type: EventError, Payload: reply})
+		}
+
+	case ActionUnsubscribe:
+		m.Unsubscribe(client, msg.Topic)
+		client.sendEvent(Event{Type: EventUnsubscribed, Payload: reply})
+
+	default:
+		reply.Error = "unknown action"
+		client.sendEvent(Event{Type: EventError, Payload: reply})
+	}
+}